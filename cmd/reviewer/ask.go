@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"go-ai-reviewer/internal/app/history"
+	"go-ai-reviewer/internal/app/textenc"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// maxAskTurns 是一次追问对话的最大轮数，轮数越多每次请求携带的历史越长
+const maxAskTurns = 10
+
+// maxAskFiles 是不指定文件时背景中列出的文件数（按得分从低到高）
+const maxAskFiles = 30
+
+// askCmd 针对上次的审查结论追问
+var askCmd = &cobra.Command{
+	Use:   `ask "<question>" [file]`,
+	Short: "针对上次的审查结论向模型追问 (如“为什么这里有注入风险？”)",
+	Long: `读取审查历史 (history_backend) 中当前项目最近一次包含该文件的审查记录与文件的当前内容，向模型提问。
+在终端中运行时回答后可以继续追问 (直接回车结束，最多 10 轮)。
+
+不指定文件时以最近一次运行的整体结论为背景 (各文件的得分与问题)。
+
+  reviewer ask "为什么这里有注入风险？" internal/db/query.go
+  reviewer ask "第 2 个问题应该怎么修复？" main.go --report my-audit
+  reviewer ask "哪个模块最需要重构？"`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeAskArgs,
+	RunE:              executeAsk,
+}
+
+func init() {
+	rootCmd.AddCommand(askCmd)
+	askCmd.Flags().String("report", "", "使用指定报告的审查记录 (默认为最近一次包含该文件的记录)")
+	mustRegisterCompletion(askCmd, "report", completeReportNames)
+}
+
+// completeAskArgs 只为第二个参数（文件）补全路径
+func completeAskArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completePaths(cmd, args, toComplete)
+}
+
+// executeAsk 是 ask 命令的主执行函数
+func executeAsk(cmd *cobra.Command, args []string) error {
+	question := strings.TrimSpace(args[0])
+	if question == "" {
+		return errors.New("问题不能为空")
+	}
+	var file string
+	if len(args) > 1 {
+		file = args[1]
+		mergeProjectConfig(projectDir(args[1:]))
+	}
+	if err := validateConfig(); err != nil {
+		return err
+	}
+	if mode := viper.GetString("secret_scan"); !validSecretScan(mode) {
+		return errors.New(secretScanError(mode))
+	}
+
+	if err := validateHistory(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, _ := cmd.Flags().GetString("report")
+	run, record, err := loadAskHistory(ctx, report, file)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+	var background string
+	if record != nil {
+		if background, err = fileBackground(run, record, file, cfg); err != nil {
+			return err
+		}
+	} else {
+		background = runBackground(run)
+	}
+
+	client, err := newLLMClient(cfg)
+	if err != nil {
+		return fmt.Errorf("初始化 LLM 客户端失败: %w", err)
+	}
+
+	conv := client.NewConversation(background)
+	interactive := isInteractive()
+	input := bufio.NewScanner(os.Stdin)
+	for {
+		answer, err := conv.Ask(ctx, question)
+		if err != nil {
+			return fmt.Errorf("提问失败: %w", err)
+		}
+		fmt.Printf("\n%s\n\n", strings.TrimSpace(answer))
+
+		if !interactive {
+			return nil
+		}
+		if conv.Turns() >= maxAskTurns {
+			fmt.Printf("💬 对话已达到 %d 轮上限，如需继续请重新运行 reviewer ask\n", maxAskTurns)
+			return nil
+		}
+		fmt.Print("💬 追问 (直接回车结束): ")
+		if !input.Scan() {
+			fmt.Println()
+			return nil
+		}
+		if question = strings.TrimSpace(input.Text()); question == "" {
+			return nil
+		}
+	}
+}
+
+// loadAskHistory 从历史存储读取追问使用的审查记录：指定报告时只读取该报告，否则查找当前项目最近一次包含 file 的记录
+func loadAskHistory(ctx context.Context, report, file string) (*history.Run, *history.File, error) {
+	store, err := openHistory(ctx, false)
+	if store == nil && err == nil {
+		return nil, nil, fmt.Errorf("history_backend 为 %s，没有保存审查记录", history.BackendOff)
+	}
+
+	var run *history.Run
+	if store != nil {
+		defer store.Close()
+		dir := "."
+		if file != "" {
+			dir = filepath.Dir(file)
+		}
+		q := history.Query{Project: historyProject(dir), File: file}
+		if report != "" {
+			q.Name = strings.TrimSuffix(sanitizeReportName(report), ".md")
+		}
+		run, err = store.Find(ctx, q)
+	}
+	switch {
+	case errors.Is(err, history.ErrNoHistory) && report != "":
+		if file != "" {
+			return nil, nil, fmt.Errorf("报告 %s 中没有 %s 的审查结论 (或报告早于审查记录功能生成、记录已被删除)", report, file)
+		}
+		return nil, nil, fmt.Errorf("报告 %s 没有审查记录 (早于审查记录功能生成，或已被删除)", report)
+	case errors.Is(err, history.ErrNoHistory) && file == "":
+		return nil, nil, errors.New("没有找到审查记录，请先运行 reviewer run")
+	case errors.Is(err, history.ErrNoHistory):
+		return nil, nil, fmt.Errorf("没有找到 %s 的审查记录，请先运行 reviewer run 审查该文件", file)
+	case err != nil:
+		return nil, nil, err
+	}
+	if file == "" {
+		return run, nil, nil
+	}
+	record, ok := run.Record(file)
+	if !ok {
+		return nil, nil, fmt.Errorf("报告 %s 中没有 %s 的审查结论", run.Name, file)
+	}
+	return run, record, nil
+}
+
+// sanitizeReportName 将报告名称转换为文件名（补全 .md 后缀）
+func sanitizeReportName(name string) string {
+	if !strings.HasSuffix(strings.ToLower(name), ".md") {
+		name += ".md"
+	}
+	return filepath.Base(name)
+}
+
+// fileBackground 构建针对单个文件追问的背景：上次的审查结论与文件的当前内容（带行号）
+// 文件内容遵循 secret_scan 与 redact 设置
+func fileBackground(run *history.Run, record *history.File, file string, cfg reviewConfig) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+	content := string(textenc.ToUTF8(data))
+	if len(content) > llm.AskMaxContent {
+		// 退回到字符边界，避免截断多字节字符
+		cut := llm.AskMaxContent
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		content = content[:cut] + "\n... (已截断)"
+	}
+	if err := checkReviewSecrets(file, content, cfg.SecretScan); err != nil {
+		return "", err
+	}
+	if cfg.Redact {
+		if content, err = redactReviewInput(file, content, cfg.RedactPatterns); err != nil {
+			return "", err
+		}
+	}
+
+	if info, err := os.Stat(file); err == nil && info.ModTime().After(run.Created) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s 在上次审查 (%s) 之后有修改，审查结论中的行号可能已经过时\n", file, run.Created.Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(os.Stderr, "📄 审查记录: %s (%s)\n", run.Report, run.Created.Format("2006-01-02 15:04"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "上次的审查结论 (%s):\n", run.Created.Format(time.DateTime))
+	writeRecordBackground(&b, *record)
+	fmt.Fprintf(&b, "\n文件内容 (带行号):\n")
+	for i, line := range strings.Split(content, "\n") {
+		fmt.Fprintf(&b, "%d| %s\n", i+1, line)
+	}
+	return b.String(), nil
+}
+
+// runBackground 构建针对整次运行追问的背景：按得分从低到高列出各文件的结论
+func runBackground(run *history.Run) string {
+	fmt.Fprintf(os.Stderr, "📄 审查记录: %s (%s)\n", run.Report, run.Created.Format("2006-01-02 15:04"))
+
+	records := append([]history.File(nil), run.Files...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Review.Score < records[j].Review.Score
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "上次运行的审查结论 (%s，共 %d 个文件，按得分从低到高列出前 %d 个):\n", run.Created.Format(time.DateTime), len(records), min(len(records), maxAskFiles))
+	for _, rec := range records[:min(len(records), maxAskFiles)] {
+		b.WriteString("\n")
+		writeRecordBackground(&b, rec)
+	}
+	return b.String()
+}
+
+// writeRecordBackground 写入一个文件的审查结论
+func writeRecordBackground(b *strings.Builder, rec history.File) {
+	r := rec.Review
+	fmt.Fprintf(b, "文件: %s (得分 %d，重要性 %.1f)\n", rec.Path, r.Score, r.Importance)
+	if r.Summary != "" {
+		fmt.Fprintf(b, "总结: %s\n", r.Summary)
+	}
+	if len(r.Issues) > 0 {
+		b.WriteString("问题:\n")
+		for i, issue := range r.Issues {
+			fmt.Fprintf(b, "%d. %s\n", i+1, issue)
+		}
+	}
+	for _, f := range r.Findings {
+		fmt.Fprintf(b, "漏洞: %s %s (第 %d 行): %s\n", f.CWE, f.Title, f.Line, f.Description)
+	}
+	if r.Suggestion != "" {
+		fmt.Fprintf(b, "建议: %s\n", r.Suggestion)
+	}
+}
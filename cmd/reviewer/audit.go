@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// auditCmd 是 audit 子命令的定义（参数与 run 相同，在 run.go 的 init 中注册）
+var auditCmd = &cobra.Command{
+	Use:   "audit [path|file...] [level] [name] ...",
+	Short: "以安全审计模式审查，输出 CWE 分类的漏洞报告与 SARIF 文件",
+	Long: `与 reviewer run 相同地扫描与审查，但只关注安全漏洞：模型为每个漏洞给出漏洞类别 (CWE 编号)、
+可利用性 (高/中/低) 与修复方法。报告按漏洞类别汇总，每个文件列出漏洞的成因与修复方法，
+并在报告旁生成同名的 .sarif 文件 (SARIF 2.1.0，每个 CWE 对应一条规则)，可上传到 GitHub 代码扫描等平台。
+
+  reviewer audit ./src
+  reviewer audit ./api 4 api-audit --min-severity major
+  reviewer audit . --fix                             # 同时生成修复补丁`,
+	Args:              cobra.MinimumNArgs(0),
+	ValidArgsFunction: completePaths,
+	Run:               executeRun,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// baselineCmd 是 baseline 子命令的定义（参数与 run 相同，在 run.go 的 init 中注册）
+var baselineCmd = &cobra.Command{
+	Use:   "baseline [path|file...] [level] [name] ...",
+	Short: "审查并将当前发现的问题记录为基线",
+	Long: `与 reviewer run 相同地执行审查，并将各文件发现的问题写入基线文件 (默认 .review-baseline.json)。
+之后的 reviewer run 只报告基线中没有的新问题，并在报告中列出基线中已解决的问题，
+便于在遗留代码库中引入审查而不被已有问题淹没。
+
+基线按文件记录问题：只审查部分目录时仅更新这些文件的记录，其余文件保持不变。
+模型每次的措辞略有不同，对比时忽略行号、空白与标点并按文本相似度匹配。
+
+  reviewer baseline ./src
+  reviewer run ./src                               # 只报告新问题
+  reviewer run ./src --baseline-file ""            # 忽略基线，报告全部问题`,
+	Args:              cobra.MinimumNArgs(0),
+	ValidArgsFunction: completePaths,
+	Run:               executeRun,
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+}
+
+// loadRunBaseline 加载本次运行使用的基线：update 为 true 时（reviewer baseline）返回待更新的基线，
+// 文件不存在时新建；否则在基线文件存在时返回用于对比的基线，不存在或未配置时返回 nil
+func loadRunBaseline(update bool) (*reviewer.Baseline, error) {
+	path := viper.GetString("baseline_file")
+	if path == "" {
+		if update {
+			return nil, errors.New("baseline_file 为空，无法写入基线")
+		}
+		return nil, nil
+	}
+
+	b, err := reviewer.LoadBaseline(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if update {
+			return reviewer.NewBaseline(path), nil
+		}
+		return nil, nil
+	}
+	return b, err
+}
+
+// printBaselineNotice 在 reviewer baseline 结束后输出基线的规模
+func printBaselineNotice(b *reviewer.Baseline) {
+	files, issues := b.Counts()
+	fmt.Printf("📌 基线 %s 已记录 %d 个文件的 %d 个问题，之后的 reviewer run 只报告新问题\n", b.Path(), files, issues)
+}
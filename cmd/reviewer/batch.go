@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/logging"
+	"go-ai-reviewer/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runTasksParallel 在汇总界面中执行批量审查，按顺序启动任务，最多 n 个任务同时进行（n 为 1 时逐个执行）
+// 所有任务共享同一个限流器，总并发不超过全局上限，限流降速、暂停与手动调整对所有任务同时生效
+func runTasksParallel(ctx context.Context, tasks []ReviewTask, n int, shared runResources) error {
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+
+	shared.limiter = newRunLimiter(cfg)
+
+	names := make([]string, len(tasks))
+	for i, task := range tasks {
+		names[i] = fmt.Sprintf("%s (级别: %d)", task.ReportName, task.Level)
+	}
+	p := tea.NewProgram(ui.NewBatchModel(names, shared.limiter))
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 按任务顺序派发，保证 n 为 1 时与命令行中的顺序一致
+	outcomes := make([]taskOutcome, len(tasks))
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for i := range tasks {
+			select {
+			case next <- i:
+			case <-taskCtx.Done():
+				for ; i < len(tasks); i++ {
+					outcomes[i].err = taskCtx.Err()
+					p.Send(ui.TaskDoneMsg{Task: i, Err: errInterrupted})
+				}
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range min(n, len(tasks)) {
+		wg.Go(func() {
+			for i := range next {
+				outcomes[i] = runBatchTask(taskCtx, p, i, tasks[i], cfg, shared)
+			}
+		})
+	}
+
+	allDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(allDone)
+	}()
+
+	// 启动 TUI（阻塞），全部任务结束后自动退出；期间的日志暂存，退出后输出
+	logging.Pause()
+	finalModel, err := p.Run()
+	logging.Resume()
+	// 界面已退出，恢复被暂停的派发，避免后台任务一直等待
+	shared.limiter.SetPaused(false)
+	if err != nil {
+		cancel()
+		<-allDone
+		return fmt.Errorf("TUI 运行失败: %w", err)
+	}
+
+	// 用户在 TUI 中按下 Ctrl+C：停止所有任务，等待部分报告生成
+	if m, ok := finalModel.(ui.BatchModel); ok && m.Interrupted() {
+		cancel()
+	}
+	<-allDone
+
+	return summarizeOutcomes(tasks, outcomes)
+}
+
+// runBatchTask 执行批量中的单个任务，并将进度发送到汇总界面
+func runBatchTask(ctx context.Context, p *tea.Program, i int, task ReviewTask, cfg reviewConfig, shared runResources) taskOutcome {
+	pt, err := prepareReviewTask(ctx, task, cfg, shared)
+	if err != nil {
+		p.Send(ui.TaskDoneMsg{Task: i, Err: err})
+		shared.rollup.fail(task.ReportName, err)
+		return taskOutcome{err: err}
+	}
+
+	if len(pt.files) == 0 {
+		p.Send(ui.TaskDoneMsg{Task: i, Note: "没有需要审查的文件"})
+		return taskOutcome{}
+	}
+
+	pt.onEvent = func(ev reviewer.Event) {
+		if msg := eventMsg(ev); msg != nil {
+			p.Send(msg)
+		}
+	}
+
+	p.Send(ui.TaskStartMsg{Task: i, Total: len(pt.files)})
+	outcome := executeTask(ctx, pt, func(res reviewer.Result) {
+		p.Send(usageMsg(shared.usage, pt.pricing))
+		p.Send(ui.TaskProgressMsg{Task: i, FileResultMsg: fileResultMsg(res, pt.minSeverity)})
+	})
+
+	p.Send(ui.TaskDoneMsg{
+		Task:        i,
+		ReportPath:  outcome.reportPath,
+		Duration:    outcome.duration,
+		IssuesCount: outcome.issuesCount,
+		Err:         outcome.err,
+	})
+	if outcome.err != nil {
+		shared.rollup.fail(task.ReportName, outcome.err)
+	}
+	return outcome
+}
+
+// summarizeOutcomes 打印各任务的报告路径，任一任务被中断时返回 errInterrupted
+func summarizeOutcomes(tasks []ReviewTask, outcomes []taskOutcome) error {
+	var interrupted bool
+	for i, outcome := range outcomes {
+		switch {
+		case errors.Is(outcome.err, context.Canceled):
+			interrupted = true
+			fmt.Printf("⏭️  [%s] 未开始\n", tasks[i].ReportName)
+		case outcome.err != nil:
+			slog.Error("任务失败", "path", tasks[i].Path, "err", outcome.err)
+		case outcome.partial:
+			interrupted = true
+			fmt.Printf("📄 [%s] 部分报告: %s\n", tasks[i].ReportName, outcome.reportPath)
+		case outcome.reportPath != "":
+			fmt.Printf("📄 [%s] 报告: %s\n", tasks[i].ReportName, outcome.reportPath)
+		}
+	}
+
+	if interrupted {
+		return errInterrupted
+	}
+	return nil
+}
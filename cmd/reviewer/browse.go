@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/llm"
+	"go-ai-reviewer/internal/logging"
+	"go-ai-reviewer/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// browseResults 在审查完成后打开结果浏览界面，用户按 o 退出时用浏览器打开报告
+// 在界面中选中问题按 Enter 会暂停界面，在编辑器中打开文件并定位到问题引用的行
+func browseResults(results []reviewer.Result, reportPath string, minSeverity llm.Severity) error {
+	files := make([]ui.BrowseFile, 0, len(results))
+	for _, res := range results {
+		f := ui.BrowseFile{FileResultMsg: fileResultMsg(res, minSeverity)}
+		if res.Review != nil {
+			f.Summary = res.Review.Summary
+			f.Issues = res.Review.Issues
+		}
+		files = append(files, f)
+	}
+
+	logging.Pause()
+	finalModel, err := tea.NewProgram(ui.NewBrowserModel(files, reportPath, minSeverity, editorCommand), tea.WithAltScreen()).Run()
+	logging.Resume()
+	if err != nil {
+		return fmt.Errorf("结果浏览界面运行失败: %w", err)
+	}
+
+	if m, ok := finalModel.(ui.BrowserModel); !ok || !m.OpenReport() {
+		return nil
+	}
+	path, err := writeHTMLReport(reportPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🌐 正在打开 %s\n", path)
+	return openWithDefaultApp(path)
+}
+
+// isInteractive 判断标准输入与标准输出是否都连接到终端
+func isInteractive() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// cleanCmd 是 clean 子命令的定义
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "清理旧的审查报告，释放磁盘空间",
+	Long: `删除 reports/ 目录下修改时间早于 --older-than 的 Markdown 报告及其渲染出的 HTML 文件、修复补丁、SARIF 文件与 SonarQube 导入文件，
+并输出释放的磁盘空间。对应 Markdown 已不存在的附属文件总会被删除；审查历史 (json 后端写在报告旁的 .json 记录) 不会被删除。
+reviewer 不在本地保存结果缓存或断点文件，reports/ 是唯一会持续增长的目录。
+
+  reviewer clean                    # 删除 30 天前的报告
+  reviewer clean --older-than 7d
+  reviewer clean --all --dry-run    # 只列出将被删除的文件`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		olderThan, _ := cmd.Flags().GetString("older-than")
+
+		cutoff := time.Now()
+		if !all {
+			t, err := parseSince(olderThan, cutoff)
+			if err != nil || t.IsZero() {
+				return fmt.Errorf("无法解析 --older-than %q，支持 7d、2w、36h 或 2024-01-01 等格式", olderThan)
+			}
+			cutoff = t
+		}
+
+		files, err := staleReports(cutoff)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			fmt.Println("✨ 没有需要清理的报告")
+			return nil
+		}
+
+		var freed int64
+		removed := 0
+		for _, f := range files {
+			if !dryRun {
+				if err := os.Remove(f.path); err != nil {
+					fmt.Printf("⚠️  删除 %s 失败: %v\n", f.path, err)
+					continue
+				}
+			}
+			fmt.Printf("  🗑️  %s (%s, %s)\n", f.path, formatSize(f.size), f.modTime.Format("2006-01-02"))
+			freed += f.size
+			removed++
+		}
+
+		if dryRun {
+			fmt.Printf("🔍 将删除 %d 个文件，可释放 %s (--dry-run 未实际删除)\n", removed, formatSize(freed))
+			return nil
+		}
+		fmt.Printf("🧹 已删除 %d 个文件，释放 %s\n", removed, formatSize(freed))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+
+	cleanCmd.Flags().String("older-than", "30d", "删除修改时间早于该时间的报告 (如 7d、2w、2024-01-01)")
+	cleanCmd.Flags().Bool("all", false, "删除全部报告")
+	cleanCmd.Flags().Bool("dry-run", false, "只列出将被删除的文件与可释放的空间，不实际删除")
+}
+
+// reportFile 是待清理的报告文件
+type reportFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// reportSidecars 是报告旁的附属文件后缀，报告过期或已不存在时一并删除
+// 不包含 .json：json 后端的审查历史同样写在报告旁 (<报告名>.json)，清理报告时保留
+var reportSidecars = []string{".html", ".patch", ".sarif", ".sonar.json"}
+
+// staleReports 返回 reports/ 中需要清理的文件（按路径排序）：
+// 修改时间早于 cutoff 的 Markdown 报告及其 HTML、修复补丁、SARIF 与 SonarQube 导入文件，以及 Markdown 已不存在的附属文件
+func staleReports(cutoff time.Time) ([]reportFile, error) {
+	entries, err := os.ReadDir(reportsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取报告目录失败: %w", err)
+	}
+
+	infos := make(map[string]os.FileInfo, len(entries))
+	bases := make(map[string]string, len(entries)) // 文件名到报告名
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		base, ok := reportBase(entry.Name())
+		if !ok {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			infos[entry.Name()] = info
+			bases[entry.Name()] = base
+		}
+	}
+
+	var files []reportFile
+	for name, info := range infos {
+		md, ok := infos[bases[name]+".md"]
+		if !ok || md.ModTime().Before(cutoff) {
+			files = append(files, reportFile{path: filepath.Join(reportsDir, name), size: info.Size(), modTime: info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// reportBase 返回 Markdown 报告或其附属文件对应的报告名，其他文件（如审查历史）返回 false
+func reportBase(name string) (string, bool) {
+	if base, ok := strings.CutSuffix(name, ".md"); ok {
+		return base, true
+	}
+	for _, suffix := range reportSidecars {
+		if base, ok := strings.CutSuffix(name, suffix); ok {
+			return base, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/app/share"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// completionCmd 生成 Shell 补全脚本，替代 cobra 默认的英文 completion 命令
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "生成 Shell 补全脚本",
+	Long: `生成 Shell 补全脚本，支持子命令、参数、目录路径、Provider、报告名称与配置项的补全。
+
+  # bash (需要 bash-completion)
+  source <(reviewer completion bash)
+  reviewer completion bash > /etc/bash_completion.d/reviewer
+
+  # zsh
+  reviewer completion zsh > "${fpath[1]}/_reviewer"
+
+  # fish
+  reviewer completion fish > ~/.config/fish/completions/reviewer.fish
+
+  # PowerShell
+  reviewer completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+// mustRegisterCompletion 为参数注册动态补全，失败时 panic
+func mustRegisterCompletion(cmd *cobra.Command, name string, fn cobra.CompletionFunc) {
+	if err := cmd.RegisterFlagCompletionFunc(name, fn); err != nil {
+		panic(fmt.Sprintf("注册 %s 参数补全失败: %v", name, err))
+	}
+}
+
+// completeDirs 补全目录路径
+func completeDirs(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+// completePaths 补全文件与目录路径
+func completePaths(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
+// completeProviders 补全 LLM Provider
+func completeProviders(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{llm.ProviderMock + "\t离线模拟"}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSeverities 补全问题严重程度
+func completeSeverities(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		llm.SeverityCritical.String() + "\t仅严重问题",
+		llm.SeverityMajor.String() + "\t重要及以上",
+		llm.SeverityMinor.String() + "\t全部问题",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFocusAreas 补全审查重点，支持逗号分隔的多个值
+func completeFocusAreas(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	descriptions := map[string]string{
+		llm.FocusSecurity:    "安全",
+		llm.FocusPerformance: "性能",
+		llm.FocusCorrectness: "正确性",
+		llm.FocusStyle:       "风格",
+	}
+	chosen := strings.Split(prefix, ",")
+	var values []string
+	for _, area := range llm.FocusAreas() {
+		if !slices.Contains(chosen, area) {
+			values = append(values, prefix+area+"\t"+descriptions[area])
+		}
+	}
+	return values, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProgressModes 补全进度输出方式
+func completeProgressModes(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		progressTUI + "\t终端界面",
+		progressJSON + "\t每行一个 JSON 事件",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeOutputFormats 补全标准输出的问题格式
+func completeOutputFormats(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		formatRDJSON + "\treviewdog 诊断格式 (-f=rdjson)",
+		formatRDJSONL + "\t每行一个 reviewdog 诊断 (-f=rdjsonl)",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeNotifyModes 补全完成提醒方式
+func completeNotifyModes(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		notifyBell + "\t终端响铃",
+		notifyDesktop + "\t桌面通知",
+		notifyBoth + "\t响铃并发送桌面通知",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSecretScanModes 补全敏感信息检测策略
+func completeSecretScanModes(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		secretScanWarn + "\t在报告中列出，文件照常发送",
+		secretScanBlock + "\t包含疑似密钥的文件不发送",
+		secretScanOff + "\t不检测",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTaskSpec 补全 --task 的字段名
+func completeTaskSpec(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	return []string{prefix + "path=", prefix + "level=", prefix + "name="}, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigFiles 补全 YAML 配置文件
+func completeConfigFiles(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// completeReportNames 补全 reports/ 目录下已有的报告名称
+func completeReportNames(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	entries, err := os.ReadDir(reportsDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".md"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigKeys 补全已知配置项，只补全第一个参数
+func completeConfigKeys(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeShareProviders 补全上传目标
+func completeShareProviders(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		share.ProviderGist + "\tGitHub Gist",
+		share.ProviderPaste + "\tshare_url 指定的粘贴服务",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// configCmd 是 config 子命令的定义
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "查看和修改配置文件",
+	Long: `查看和修改配置文件，修改时保留文件中的注释与其他配置项。
+默认操作 ~/.code-review.yaml，--project 操作当前目录的 .code-review.yaml，--config 指定任意文件。
+嵌套配置项使用点号分隔，值按 YAML 解析（数字、布尔、列表保持类型）。
+
+使用示例:
+  reviewer config set level 4
+  reviewer config set include_exts '[".go", ".ts"]'
+  reviewer config get model
+  reviewer config unset triage_model
+  reviewer config list`,
+}
+
+// configSetCmd 设置配置项
+var configSetCmd = &cobra.Command{
+	Use:               "set <key> <value>",
+	Short:             "设置配置项",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := parseConfigValue(args[1])
+		if err != nil {
+			return err
+		}
+		if !isKnownConfigKey(args[0]) {
+			slog.Warn("未知配置项，仍然写入", "key", args[0])
+		} else if kind, ok := configSchema[strings.ToLower(args[0])]; ok {
+			if err := validateConfigValue(kind, value); err != nil {
+				return fmt.Errorf("配置项 %s %w，示例: reviewer config set %s %s", args[0], err, args[0], configKindExamples[kind])
+			}
+		}
+		if project, _ := cmd.Flags().GetBool("project"); project && cfgFile == "" && isKnownConfigKey(args[0]) &&
+			!projectConfigKeys[splitConfigKey(strings.ToLower(args[0]))[0]] {
+			return fmt.Errorf("配置项 %s 不能写入项目配置 (项目配置随仓库提交，只能设置影响审查行为的配置项)，请去掉 --project 写入 ~/.code-review.yaml，或使用环境变量 %s_%s",
+				args[0], envPrefix, strings.ToUpper(args[0]))
+		}
+		return editConfigFile(cmd, func(root *yaml.Node) error {
+			setConfigNode(root, splitConfigKey(args[0]), value)
+			return nil
+		})
+	},
+}
+
+// configUnsetCmd 删除配置项
+var configUnsetCmd = &cobra.Command{
+	Use:               "unset <key>",
+	Short:             "删除配置项（恢复默认值）",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editConfigFile(cmd, func(root *yaml.Node) error {
+			if !unsetConfigNode(root, splitConfigKey(args[0])) {
+				return fmt.Errorf("配置项 %s 未设置", args[0])
+			}
+			return nil
+		})
+	},
+}
+
+// configGetCmd 读取配置项
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "读取配置文件中的配置项",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configFilePath(cmd)
+		if err != nil {
+			return err
+		}
+		root, err := loadConfigNode(path)
+		if err != nil {
+			return err
+		}
+		node := findConfigNode(root, splitConfigKey(args[0]))
+		if node == nil {
+			return fmt.Errorf("配置项 %s 未在 %s 中设置", args[0], path)
+		}
+		fmt.Println(formatConfigValue(node))
+		return nil
+	},
+}
+
+// configListCmd 列出配置文件中的所有配置项
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出配置文件中的所有配置项",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		path, err := configFilePath(cmd)
+		if err != nil {
+			return err
+		}
+		root, err := loadConfigNode(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# %s\n", path)
+		listConfigNodes(root, "")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSetCmd, configGetCmd, configListCmd, configUnsetCmd)
+
+	configCmd.PersistentFlags().Bool("project", false, "操作当前目录的 .code-review.yaml")
+}
+
+// configFilePath 返回要操作的配置文件：--config > --project > 用户主目录
+func configFilePath(cmd *cobra.Command) (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	name := configFileName + "." + configFileType
+	if project, _ := cmd.Flags().GetBool("project"); project {
+		return filepath.Abs(name)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %w", err)
+	}
+	return filepath.Join(home, name), nil
+}
+
+// editConfigFile 修改 configFilePath 指定的配置文件并提示结果
+func editConfigFile(cmd *cobra.Command, edit func(root *yaml.Node) error) error {
+	path, err := configFilePath(cmd)
+	if err != nil {
+		return err
+	}
+	if err := updateConfigFile(path, edit); err != nil {
+		return err
+	}
+	fmt.Printf("✅ 已更新 %s\n", path)
+	return nil
+}
+
+// loadConfigNode 读取配置文件并返回顶层映射节点，文件不存在或为空时返回空映射
+func loadConfigNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("配置文件 %s 的顶层不是键值映射", path)
+	}
+
+	// 文件开头与结尾的注释挂在文档节点上，移到映射节点以便写回时保留
+	root.HeadComment = joinComments(doc.HeadComment, root.HeadComment)
+	root.FootComment = joinComments(root.FootComment, doc.FootComment)
+	return root, nil
+}
+
+// updateConfigFile 读取配置文件，执行修改后写回，保留注释与未修改的配置项
+// 先写入同目录下的临时文件再重命名，避免写入中断损坏原文件；配置文件可能包含 API Key，权限为 0600
+func updateConfigFile(path string, edit func(root *yaml.Node) error) error {
+	root, err := loadConfigNode(path)
+	if err != nil {
+		return err
+	}
+	if err := edit(root); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}); err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".code-review-*.tmp")
+	if err != nil {
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	return nil
+}
+
+// joinComments 合并两段注释，忽略空注释
+func joinComments(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "\n\n" + b
+}
+
+// splitConfigKey 将点号分隔的配置键拆分为路径
+func splitConfigKey(key string) []string {
+	return strings.Split(strings.TrimSpace(key), ".")
+}
+
+// isKnownConfigKey 判断配置键是否在 configSchema 中（model_prices 下的模型名不做限制）
+func isKnownConfigKey(key string) bool {
+	path := splitConfigKey(strings.ToLower(key))
+	kind, ok := configSchema[path[0]]
+	return ok && (len(path) == 1 || kind == kindPrices)
+}
+
+// parseConfigValue 将命令行值按 YAML 解析为节点，空值解析为空字符串
+func parseConfigValue(value string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, fmt.Errorf("无法解析值 %q: %w", value, err)
+	}
+	if len(doc.Content) == 0 {
+		return stringNode(value), nil
+	}
+	return doc.Content[0], nil
+}
+
+// stringNode 创建字符串值节点
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// findConfigNode 按路径查找值节点，不存在时返回 nil
+func findConfigNode(root *yaml.Node, path []string) *yaml.Node {
+	node := root
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		i := mappingIndex(node, key)
+		if i < 0 {
+			return nil
+		}
+		node = node.Content[i+1]
+	}
+	return node
+}
+
+// setConfigNode 按路径设置值，缺失的中间层级自动创建；替换已有值时保留行尾注释
+func setConfigNode(root *yaml.Node, path []string, value *yaml.Node) {
+	node := root
+	for depth, key := range path {
+		last := depth == len(path)-1
+		i := mappingIndex(node, key)
+
+		if i < 0 {
+			child := value
+			if !last {
+				child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			node.Content = append(node.Content, stringNode(key), child)
+			node = child
+			continue
+		}
+
+		if last {
+			value.LineComment = node.Content[i+1].LineComment
+			node.Content[i+1] = value
+			return
+		}
+		if node.Content[i+1].Kind != yaml.MappingNode {
+			node.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		node = node.Content[i+1]
+	}
+}
+
+// unsetConfigNode 按路径删除配置项，删除后为空的上级映射一并删除；不存在时返回 false
+func unsetConfigNode(root *yaml.Node, path []string) bool {
+	parent := findConfigNode(root, path[:len(path)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return false
+	}
+	i := mappingIndex(parent, path[len(path)-1])
+	if i < 0 {
+		return false
+	}
+	parent.Content = slices.Delete(parent.Content, i, i+2)
+
+	if len(parent.Content) == 0 && len(path) > 1 {
+		unsetConfigNode(root, path[:len(path)-1])
+	}
+	return true
+}
+
+// mappingIndex 返回映射节点中键节点的下标，不存在时返回 -1
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// secretConfigKeys 是 config list 中脱敏显示的配置项：API Key、群机器人的 Webhook 地址与签名密钥、serve 的访问令牌、
+// 历史数据库的连接字符串（可能包含密码）、Jira 与 Confluence 的 API Token、分享报告的 Token、通用 Webhook（可能包含签名密钥与认证请求头）
+var secretConfigKeys = map[string]bool{
+	"api_key":          true,
+	"serve_token":      true,
+	"history_dsn":      true,
+	"jira_token":       true,
+	"confluence_token": true,
+	"share_token":      true,
+	"slack_webhook":    true,
+	"dingtalk_webhook": true,
+	"dingtalk_secret":  true,
+	"feishu_webhook":   true,
+	"feishu_secret":    true,
+	"wecom_webhook":    true,
+	"webhooks":         true,
+}
+
+// listConfigNodes 以 key = value 的形式输出所有配置项，嵌套映射展开为点号路径，密钥类配置项脱敏显示
+func listConfigNodes(node *yaml.Node, prefix string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := prefix + node.Content[i].Value
+		value := node.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			listConfigNodes(value, key+".")
+			continue
+		}
+
+		text := formatConfigValue(value)
+		if secretConfigKeys[key] {
+			text = maskSecret(text)
+		}
+		fmt.Printf("%s = %s\n", key, text)
+	}
+}
+
+// formatConfigValue 将值节点格式化为单行文本，列表与映射使用 YAML 流式写法
+func formatConfigValue(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	flow := *node
+	flow.Style = yaml.FlowStyle
+	flow.HeadComment, flow.LineComment, flow.FootComment = "", "", ""
+	data, err := yaml.Marshal(&flow)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// maskSecret 隐藏密钥中间部分，仅保留首尾 4 个字符
+func maskSecret(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:4] + strings.Repeat("*", len(s)-8) + s[len(s)-4:]
+}
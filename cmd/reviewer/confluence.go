@@ -0,0 +1,103 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/confluence"
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/viper"
+)
+
+// defaultConfluenceTitle 是页面标题的默认模板：同一报告名每次更新同一页面，Confluence 保留历史版本
+const defaultConfluenceTitle = "AI 代码审查: {name}"
+
+// confluenceConfig 是审查完成后将报告发布到 Confluence 的配置，URL 为空时不发布
+type confluenceConfig struct {
+	confluence.Config
+	Title string // 页面标题模板，支持 {name}（报告名）、{project}（项目）与 {date}（日期）
+}
+
+// loadConfluenceConfig 读取 Confluence 配置（已由 validateConfluence 校验）
+func loadConfluenceConfig() confluenceConfig {
+	return confluenceConfig{
+		Config: confluence.Config{
+			URL:      viper.GetString("confluence_url"),
+			Email:    viper.GetString("confluence_email"),
+			Token:    viper.GetString("confluence_token"),
+			Space:    viper.GetString("confluence_space"),
+			ParentID: viper.GetString("confluence_parent_id"),
+		},
+		Title: cmp.Or(viper.GetString("confluence_title"), defaultConfluenceTitle),
+	}
+}
+
+// validateConfluence 校验 Confluence 配置，未设置 confluence_url 时不校验
+func validateConfluence() error {
+	rawURL := viper.GetString("confluence_url")
+	if rawURL == "" {
+		return nil
+	}
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("confluence_url 应为 https:// 开头的 Confluence 站点地址，如 https://acme.atlassian.net/wiki")
+	}
+	if viper.GetString("confluence_space") == "" {
+		return errors.New("设置 confluence_url 时需要设置 confluence_space (空间 Key，如 SEC)")
+	}
+	if viper.GetString("confluence_token") == "" {
+		return errors.New("设置 confluence_url 时需要设置 confluence_token (Confluence Cloud 的 API Token 或 Server 的个人访问令牌)")
+	}
+	if id := viper.GetString("confluence_parent_id"); strings.ContainsFunc(id, func(r rune) bool { return r < '0' || r > '9' }) {
+		return fmt.Errorf("confluence_parent_id 应为页面 ID (页面地址中的数字)，当前为 %q", id)
+	}
+	return nil
+}
+
+// confluenceTitle 展开页面标题模板，展开后为空白（如只有 {project} 而项目未知）时使用报告名
+func confluenceTitle(template, name, project string, now time.Time) string {
+	title := strings.NewReplacer("{name}", name, "{project}", project, "{date}", now.Format(time.DateOnly)).Replace(template)
+	return cmp.Or(strings.TrimSpace(title), name)
+}
+
+// publishConfluence 将报告渲染后发布到 Confluence 页面，失败时只记录警告，不影响审查结果
+// reportLink 是报告的 URL 或路径，写在页面末尾
+func publishConfluence(ctx context.Context, cfg confluenceConfig, project, name, reportPath, reportLink string) {
+	if cfg.URL == "" {
+		return
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		slog.Warn("发布到 Confluence 失败", "err", err)
+		return
+	}
+
+	now := time.Now()
+	var body strings.Builder
+	body.WriteString(reviewer.RenderHTMLBody(string(data)))
+	fmt.Fprintf(&body, "<hr />\n<p>由 AI 代码审查自动发布 (%s)", now.Format(time.DateTime))
+	if reportLink != "" {
+		fmt.Fprintf(&body, "，报告: %s", html.EscapeString(reportLink))
+	}
+	body.WriteString("</p>\n")
+
+	title := confluenceTitle(cfg.Title, name, project, now)
+	link, created, err := confluence.New(cfg.Config).Publish(ctx, title, body.String(), "AI 代码审查: "+name)
+	if err != nil {
+		slog.Warn("发布到 Confluence 失败", "title", title, "err", err)
+		return
+	}
+	if created {
+		slog.Info("已创建 Confluence 页面", "title", title, "url", link)
+	} else {
+		slog.Info("已更新 Confluence 页面", "title", title, "url", link)
+	}
+}
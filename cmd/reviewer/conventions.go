@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"go-ai-reviewer/internal/app/textenc"
+	"go-ai-reviewer/internal/llm"
+)
+
+// findConventions 从 dir 向上查找团队规范文件（CONVENTIONS.md、.review-guidelines.md），
+// 查找到仓库根目录（包含 .git 的目录）为止，返回最近的规范文件路径与内容；未找到时 path 为空
+func findConventions(dir string) (path, content string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+	for {
+		for _, name := range llm.ConventionFiles {
+			candidate := filepath.Join(dir, name)
+			data, err := os.ReadFile(candidate)
+			if err != nil {
+				continue
+			}
+			return candidate, string(textenc.ToUTF8(data))
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// conventionsOption 返回注入 dir 所在项目团队规范的客户端选项，以及规范文件在报告中的说明（如 "CONVENTIONS.md (已截断)"）
+// 未启用 conventions 或未找到规范文件时返回 nil
+func conventionsOption(dir string, cfg reviewConfig) (llm.ClientOption, string) {
+	if !cfg.Conventions {
+		return nil, ""
+	}
+	path, content := findConventions(dir)
+	if path == "" {
+		return nil, ""
+	}
+	note := displayPath(path)
+	if _, truncated := llm.TruncateConventions(content); truncated {
+		note += " (已截断)"
+		slog.Info("团队规范文件过长，只注入开头部分", "path", path, "limit", formatSize(llm.MaxConventionsSize))
+	}
+	return llm.WithConventions(filepath.Base(path), content), note
+}
+
+// displayPath 返回相对于执行目录的路径，无法计算时返回原路径
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
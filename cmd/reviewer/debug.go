@@ -0,0 +1,71 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net/http"
+	_ "net/http/pprof" // 注册 /debug/pprof/ 路由
+	"sync"
+
+	"go-ai-reviewer/internal/app/reviewer"
+)
+
+// engineRegistry 记录执行中的审查引擎，供 /debug/vars 输出实时指标；调试服务未开启时不登记
+var engineRegistry = struct {
+	sync.Mutex
+	enabled bool
+	seq     int
+	engines map[int]registeredEngine // 按登记序号索引，同名任务互不覆盖
+}{engines: make(map[int]registeredEngine)}
+
+// registeredEngine 是登记的引擎及其任务的报告名称
+type registeredEngine struct {
+	name   string
+	engine *reviewer.Engine
+}
+
+// registerEngine 在调试服务开启时登记引擎，返回任务结束时注销引擎的函数
+func registerEngine(name string, e *reviewer.Engine) (unregister func()) {
+	engineRegistry.Lock()
+	defer engineRegistry.Unlock()
+	if !engineRegistry.enabled {
+		return func() {}
+	}
+	engineRegistry.seq++
+	id := engineRegistry.seq
+	engineRegistry.engines[id] = registeredEngine{name: name, engine: e}
+	return func() {
+		engineRegistry.Lock()
+		delete(engineRegistry.engines, id)
+		engineRegistry.Unlock()
+	}
+}
+
+// engineMetrics 返回所有已登记引擎的指标快照，键为 "报告名称#登记序号"
+func engineMetrics() any {
+	engineRegistry.Lock()
+	defer engineRegistry.Unlock()
+
+	snapshots := make(map[string]reviewer.MetricsSnapshot, len(engineRegistry.engines))
+	for id, r := range engineRegistry.engines {
+		snapshots[fmt.Sprintf("%s#%d", r.name, id)] = r.engine.Metrics()
+	}
+	return snapshots
+}
+
+// startDebugServer 在 addr 上启动 pprof 与 expvar 调试服务（/debug/pprof/、/debug/vars）
+// 启动失败只打印警告，不影响审查
+func startDebugServer(addr string) {
+	engineRegistry.Lock()
+	engineRegistry.enabled = true
+	engineRegistry.Unlock()
+	expvar.Publish("reviewer_engines", expvar.Func(engineMetrics))
+
+	go func() {
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			slog.Warn("调试服务启动失败", "err", err)
+		}
+	}()
+	fmt.Printf("🔍 调试服务已启动: http://%s/debug/pprof/ （指标: /debug/vars）\n", addr)
+}
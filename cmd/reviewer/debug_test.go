@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/llm"
+)
+
+// setDebugEnabled 在测试期间切换调试服务的开启状态，结束后恢复并清空登记表
+func setDebugEnabled(t *testing.T, enabled bool) {
+	t.Helper()
+	engineRegistry.Lock()
+	old := engineRegistry.enabled
+	engineRegistry.enabled = enabled
+	engineRegistry.Unlock()
+	t.Cleanup(func() {
+		engineRegistry.Lock()
+		engineRegistry.enabled = old
+		clear(engineRegistry.engines)
+		engineRegistry.Unlock()
+	})
+}
+
+func registeredEngines() map[string]reviewer.MetricsSnapshot {
+	return engineMetrics().(map[string]reviewer.MetricsSnapshot)
+}
+
+func TestRegisterEngine(t *testing.T) {
+	client, err := llm.NewMockClient(llm.MockOptions{})
+	if err != nil {
+		t.Fatalf("NewMockClient: %v", err)
+	}
+	engine, err := reviewer.NewEngine(client, 1, defaultLevel)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	// 未开启调试服务时不登记，serve 与 watch 的任务不会累积
+	setDebugEnabled(t, false)
+	registerEngine("report", engine)()
+	if n := len(registeredEngines()); n != 0 {
+		t.Fatalf("未开启调试服务时登记了 %d 个引擎", n)
+	}
+
+	// 同名任务分别登记，任务结束后注销
+	setDebugEnabled(t, true)
+	unregisterA := registerEngine("report", engine)
+	unregisterB := registerEngine("report", engine)
+	if n := len(registeredEngines()); n != 2 {
+		t.Fatalf("同名任务登记了 %d 个引擎，want 2", n)
+	}
+	unregisterA()
+	if n := len(registeredEngines()); n != 1 {
+		t.Errorf("注销一个后剩余 %d 个引擎，want 1", n)
+	}
+	unregisterB()
+	if n := len(registeredEngines()); n != 0 {
+		t.Errorf("全部注销后剩余 %d 个引擎，want 0", n)
+	}
+}
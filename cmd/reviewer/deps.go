@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-ai-reviewer/internal/app/deps"
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/app/scanner"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+// depsCmd 审查依赖清单
+var depsCmd = &cobra.Command{
+	Use:   "deps [path] [name]",
+	Short: "审查依赖清单 (go.mod、package.json、requirements.txt)：过时或有风险的依赖与许可证兼容性",
+	Long: `扫描目录中的依赖清单 (go.mod、package.json、requirements*.txt)，生成依赖审查报告 reports/<name>.md：
+  - 本地规则：已知弃用、停止维护或发生过投毒事件的包，未锁定的版本、伪版本、Git/URL/本地路径来源，
+    以及依赖许可证与项目许可证的兼容性 (许可证从本机 Go 模块缓存与 node_modules 识别)
+  - 模型审查：每个清单发送给模型一次，补充过时的版本、已知漏洞与未能在本地识别的许可证
+
+报告名称默认为 "<目录名>-deps"。--local 只运行本地规则，不调用模型。
+
+  reviewer deps
+  reviewer deps ./web web-deps
+  reviewer deps --local`,
+	Args:              cobra.MaximumNArgs(2),
+	ValidArgsFunction: completePaths,
+	RunE:              executeDeps,
+}
+
+func init() {
+	rootCmd.AddCommand(depsCmd)
+	depsCmd.Flags().Bool("local", false, "只运行本地规则，不调用模型")
+}
+
+// executeDeps 是 deps 命令的主执行函数
+func executeDeps(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+	if !isValidPath(root) {
+		return fmt.Errorf("目录不存在: %s", root)
+	}
+	name := resolveDirectoryName(root) + "-deps"
+	if len(args) > 1 {
+		name = args[1]
+	}
+	mergeProjectConfig(projectDir([]string{root}))
+
+	local, _ := cmd.Flags().GetBool("local")
+	if !local {
+		if err := validateConfig(); err != nil {
+			return err
+		}
+	}
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+
+	manifests, err := findManifests(root, cfg)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		return errors.New("未找到依赖清单 (go.mod、package.json、requirements.txt)")
+	}
+
+	var client *llm.Client
+	if !local {
+		if client, err = newLLMClient(cfg); err != nil {
+			return fmt.Errorf("初始化 LLM 客户端失败: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	license := deps.ProjectLicense(root)
+	reviews := make([]deps.Review, 0, len(manifests))
+	for _, m := range manifests {
+		deps.ResolveLicenses(m)
+		review := deps.Review{Manifest: m, Findings: deps.Check(m, license)}
+		if client != nil {
+			fmt.Printf("🔍 正在审查 %s (%d 个依赖)...\n", m.Path, len(m.Deps))
+			reviewDependencies(ctx, client, &review, license)
+		}
+		reviews = append(reviews, review)
+	}
+
+	meta := reviewer.DependencyReportMeta{Name: name, ProjectLicense: license, LocalOnly: local}
+	if client != nil {
+		meta.Tokens = client.Usage().Total()
+	}
+	reportPath, err := reviewer.GenerateDependencyReport(reviews, time.Since(start), reportsDir, meta)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📄 报告: %s\n", reportPath)
+	return ctx.Err()
+}
+
+// findManifests 扫描目录中的依赖清单（遵循 .gitignore、.reviewignore 与排除目录），无法解析的清单记录警告后跳过
+func findManifests(root string, cfg reviewConfig) ([]*deps.Manifest, error) {
+	scn, err := scanner.NewScanner(root, deps.ManifestExts,
+		scanner.WithExcludeDirs(cfg.ExcludeDirs),
+		scanner.WithFollowSymlinks(cfg.FollowSymlinks),
+		scanner.WithMaxDepth(cfg.MaxDepth),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("初始化扫描器失败: %w", err)
+	}
+	files, err := scn.Scan()
+	if err != nil {
+		return nil, fmt.Errorf("扫描目录失败: %w", err)
+	}
+
+	var manifests []*deps.Manifest
+	for _, file := range files {
+		if deps.EcosystemOf(file) == "" {
+			continue
+		}
+		m, err := deps.Parse(file)
+		if err != nil {
+			slog.Warn("跳过无法解析的依赖清单", "path", file, "err", err)
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+// reviewDependencies 请模型审查一个依赖清单，并与本地规则的结果合并；失败时保留本地结果并记录原因
+func reviewDependencies(ctx context.Context, client *llm.Client, review *deps.Review, license string) {
+	m := review.Manifest
+	result, err := client.ReviewDependencies(ctx, llm.DependencyRequest{
+		FilePath: m.Path,
+		Content:  m.Content,
+		Context:  dependencyContext(review, license),
+	})
+	if err != nil {
+		review.Err = err
+		return
+	}
+
+	review.Summary = result.Summary
+	model := make([]deps.Finding, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		finding := deps.Finding{
+			Dependency: f.Dependency,
+			Category:   deps.NormalizeCategory(f.Category),
+			Severity:   f.Level(),
+			Message:    f.Message,
+			Suggestion: f.Suggestion,
+		}
+		if d, ok := m.Dependency(f.Dependency); ok {
+			finding.Line = d.Line
+		}
+		model = append(model, finding)
+	}
+	review.Findings = deps.Merge(review.Findings, model)
+}
+
+// dependencyContext 构建发送给模型的背景信息：项目许可证、开发依赖、本地识别的许可证与本地规则发现的问题
+func dependencyContext(review *deps.Review, license string) string {
+	var b strings.Builder
+	if license == "" {
+		license = "未声明 (按闭源项目处理)"
+	}
+	fmt.Fprintf(&b, "项目许可证: %s\n", license)
+
+	var dev, licenses []string
+	for _, d := range review.Manifest.Deps {
+		if d.Dev {
+			dev = append(dev, d.Name)
+		}
+		if d.License != "" {
+			licenses = append(licenses, d.Name+": "+d.License)
+		}
+	}
+	if len(dev) > 0 {
+		fmt.Fprintf(&b, "开发依赖: %s\n", strings.Join(dev, ", "))
+	}
+	if len(licenses) > 0 {
+		fmt.Fprintf(&b, "本地识别的依赖许可证:\n- %s\n", strings.Join(licenses, "\n- "))
+	}
+	if len(review.Findings) > 0 {
+		b.WriteString("本地规则已发现的问题 (不需要重复报告):\n")
+		for _, f := range review.Findings {
+			fmt.Fprintf(&b, "- %s [%s] %s\n", f.Dependency, f.Category, f.Message)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
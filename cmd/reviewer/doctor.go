@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/llm"
+	"go-ai-reviewer/internal/ui"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// 连通性检查的超时时间
+const doctorTimeout = 30 * time.Second
+
+// doctorCmd 是 doctor 子命令的定义
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [path]",
+	Short: "检查配置与 API 连通性，给出修复建议",
+	Long: `依次检查：配置文件格式与配置项类型、配置值是否合法、API Key 是否设置、
+API 是否可以连通（发送一次只生成 1 个 Token 的请求）、模型是否存在，并为每个问题给出修复建议。
+指定 path 时同时检查该目录（向上到仓库根目录）中的项目配置。存在错误时以非零状态退出。`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeDirs,
+	Run:               executeDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctor 汇总检查结果
+type doctor struct {
+	failures int
+	warnings int
+}
+
+// ok 输出通过的检查项
+func (d *doctor) ok(title, detail string) {
+	fmt.Printf("✅ %s: %s\n", title, detail)
+}
+
+// warn 输出警告项与修复建议
+func (d *doctor) warn(title, detail, fix string) {
+	d.warnings++
+	fmt.Printf("⚠️  %s: %s\n", title, detail)
+	if fix != "" {
+		fmt.Printf("   👉 %s\n", fix)
+	}
+}
+
+// fail 输出错误项与修复建议
+func (d *doctor) fail(title, detail, fix string) {
+	d.failures++
+	fmt.Printf("❌ %s: %s\n", title, detail)
+	if fix != "" {
+		fmt.Printf("   👉 %s\n", fix)
+	}
+}
+
+// executeDoctor 是 doctor 命令的主执行函数
+func executeDoctor(_ *cobra.Command, args []string) {
+	mergeProjectConfig(projectDir(args))
+
+	fmt.Println("🩺 检查配置与 API 连通性")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	d := &doctor{}
+	d.checkConfigFiles()
+	d.checkConfigValues()
+	d.checkTLS()
+
+	if cfg, ok := d.checkAPIKey(); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+		defer cancel()
+		d.checkAPI(ctx, cfg)
+	}
+
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	switch {
+	case d.failures > 0:
+		fmt.Printf("发现 %d 个错误、%d 个警告，请按提示修复后重新运行 reviewer doctor\n", d.failures, d.warnings)
+		os.Exit(1)
+	case d.warnings > 0:
+		fmt.Printf("没有错误，%d 个警告\n", d.warnings)
+	default:
+		fmt.Println("🎉 一切正常，可以开始审查: reviewer run .")
+	}
+}
+
+// checkConfigFiles 检查已加载配置文件的 YAML 格式、未知配置项、值类型与文件权限
+func (d *doctor) checkConfigFiles() {
+	files := loadedConfigs
+	if cfgFile != "" {
+		files = []string{cfgFile}
+	}
+	if len(files) == 0 {
+		d.warn("配置文件", "未找到配置文件，使用内置默认值与环境变量",
+			"运行 reviewer init 生成项目配置，或 reviewer config set <key> <value> 写入 ~/.code-review.yaml")
+		return
+	}
+
+	for _, path := range files {
+		title := "配置文件 " + path
+		if _, err := os.Stat(path); err != nil {
+			d.fail(title, "无法读取: "+err.Error(), "检查 --config 指定的路径")
+			continue
+		}
+		root, err := loadConfigNode(path)
+		if err != nil {
+			d.fail(title, err.Error(), "修复 YAML 语法 (注意缩进与冒号后的空格)，或删除文件后重新运行 reviewer init")
+			continue
+		}
+
+		problems := 0
+		project := isProjectConfig(path)
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			key := root.Content[i].Value
+			problems += d.checkConfigEntry(title, key, root.Content[i+1])
+			if _, known := configSchema[key]; project && known && !projectConfigKeys[key] {
+				problems++
+				d.warn(title, "项目配置中的 "+key+" 不会生效 (只能在用户主目录配置、环境变量或命令行中设置)",
+					fmt.Sprintf("移到 ~/.code-review.yaml，或使用环境变量 %s_%s", envPrefix, strings.ToUpper(key)))
+			}
+		}
+		if info, err := os.Stat(path); err == nil && findConfigNode(root, []string{"api_key"}) != nil && info.Mode().Perm()&0o077 != 0 {
+			problems++
+			d.warn(title, fmt.Sprintf("包含明文 API Key，但文件权限为 %#o", info.Mode().Perm()),
+				fmt.Sprintf("chmod 600 %s，或运行 reviewer config keyring 改为保存到系统钥匙串", path))
+		}
+		if problems == 0 {
+			d.ok(title, "格式正确")
+		}
+	}
+}
+
+// checkConfigEntry 检查单个顶层配置项，返回发现的问题数
+func (d *doctor) checkConfigEntry(title, key string, value *yaml.Node) int {
+	kind, ok := configSchema[key]
+	if !ok {
+		fix := "删除该配置项 (reviewer config unset " + key + ")"
+		if suggestion := suggestConfigKey(key); suggestion != "" {
+			fix = fmt.Sprintf("是否应为 %s？可运行 reviewer config unset %s 后 reviewer config set %s <value>", suggestion, key, suggestion)
+		}
+		d.warn(title, "未知配置项 "+key+" (不会生效)", fix)
+		return 1
+	}
+	if err := validateConfigValue(kind, value); err != nil {
+		d.fail(title, fmt.Sprintf("配置项 %s %v", key, err),
+			fmt.Sprintf("reviewer config set %s %s", key, configKindExamples[kind]))
+		return 1
+	}
+	return 0
+}
+
+// checkConfigValues 检查合并后（含环境变量与参数）的配置值是否在允许范围内
+func (d *doctor) checkConfigValues() {
+	before := d.failures + d.warnings
+
+	if level := viper.GetInt("level"); !isValidLevel(level) {
+		d.fail("配置值", fmt.Sprintf("level=%d 超出范围，将使用默认级别 %d", level, defaultLevel), "reviewer config set level 3 (可选 1-6)")
+	}
+	switch provider := viper.GetString("provider"); provider {
+	case "", "openai", llm.ProviderMock:
+	default:
+		d.fail("配置值", "不支持的 provider: "+provider, "删除 provider 配置使用 OpenAI 兼容接口，或设为 mock 离线测试")
+	}
+	if _, err := parseSince(viper.GetString("since"), time.Now()); err != nil {
+		d.fail("配置值", err.Error(), "使用 7d、36h 或 2024-01-01 格式，或 reviewer config unset since")
+	}
+	if _, err := llm.ParseSeverity(viper.GetString("min_severity")); err != nil {
+		d.fail("配置值", err.Error(), "reviewer config set min_severity major")
+	}
+	if err := checkMinConfidence(viper.GetFloat64("min_confidence")); err != nil {
+		d.fail("配置值", err.Error(), "reviewer config set min_confidence 0.6")
+	}
+	if path := viper.GetString("prompt_file"); path != "" {
+		if _, err := llm.LoadPromptFile(path); err != nil {
+			d.fail("配置值", err.Error(), "修复提示文件，或运行 reviewer config prompt > review-prompt.yaml 从内置提示重新开始")
+		}
+	}
+	if source := viper.GetString("api_key_source"); source != "" && source != apiKeySourceKeyring {
+		d.fail("配置值", "api_key_source 只支持 keyring，实际为 "+source, "reviewer config unset api_key_source")
+	}
+	if _, err := ui.LoadTheme(viper.GetString("theme"), viper.GetStringMapString("theme_colors")); err != nil {
+		d.warn("配置值", err.Error()+"，将使用默认主题", "reviewer config set theme "+strings.Join(ui.ThemeNames(), " / "))
+	}
+	if ratio := viper.GetFloat64("triage_ratio"); ratio <= 0 || ratio > 1 {
+		d.warn("配置值", fmt.Sprintf("triage_ratio=%g 应在 (0, 1] 之间", ratio), "reviewer config unset triage_ratio 恢复默认值")
+	}
+
+	if d.failures+d.warnings == before {
+		d.ok("配置值", "合法")
+	}
+}
+
+// tlsExpiryWarning 是客户端证书即将过期的提醒时间
+const tlsExpiryWarning = 30 * 24 * time.Hour
+
+// checkTLS 检查私有 CA 与客户端证书能否加载，以及客户端证书的有效期（未配置证书时跳过）
+func (d *doctor) checkTLS() {
+	files := configTLSFiles()
+	if files.Empty() {
+		return
+	}
+	cfg, err := llm.LoadTLSConfig(files)
+	if err != nil {
+		d.fail("TLS 证书", err.Error(), "检查 tls_ca_file、tls_cert_file 与 tls_key_file 指向的 PEM 文件")
+		return
+	}
+
+	var parts []string
+	if files.CAFile != "" {
+		parts = append(parts, "CA "+files.CAFile)
+	}
+	if len(cfg.Certificates) == 0 || cfg.Certificates[0].Leaf == nil {
+		d.ok("TLS 证书", strings.Join(parts, "，"))
+		return
+	}
+	leaf := cfg.Certificates[0].Leaf
+	parts = append(parts, fmt.Sprintf("客户端证书 %s (有效期至 %s)", leaf.Subject.CommonName, leaf.NotAfter.Format(time.DateOnly)))
+	detail := strings.Join(parts, "，")
+	switch now := time.Now(); {
+	case now.After(leaf.NotAfter):
+		d.fail("TLS 证书", detail+" 已过期", "向网关管理员申请新的客户端证书")
+	case now.Before(leaf.NotBefore):
+		d.fail("TLS 证书", detail+" 尚未生效", "检查系统时间，或确认证书的生效时间")
+	case leaf.NotAfter.Sub(now) < tlsExpiryWarning:
+		d.warn("TLS 证书", detail+" 即将过期", "尽快向网关管理员申请新的客户端证书")
+	default:
+		d.ok("TLS 证书", detail)
+	}
+}
+
+// checkAPIKey 检查 API Key 是否可用，返回用于连通性检查的配置
+func (d *doctor) checkAPIKey() (reviewConfig, bool) {
+	if err := loadKeyringAPIKey(); err != nil {
+		d.fail("API Key", err.Error(), "确认系统钥匙串可用，或运行 reviewer config keyring --revert 改回配置文件保存")
+		return reviewConfig{}, false
+	}
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		d.fail("配置", err.Error(), "按提示修正配置文件中的对应配置项")
+		return reviewConfig{}, false
+	}
+	switch {
+	case cfg.Provider == llm.ProviderMock:
+		d.ok("API Key", "Mock Provider 不需要 API Key")
+	case cfg.APIKey == "":
+		d.fail("API Key", "未设置", "运行 reviewer run 按提示配置，或设置环境变量 REVIEWER_API_KEY")
+		return cfg, false
+	default:
+		d.ok("API Key", fmt.Sprintf("%s (来源: %s)", maskSecret(cfg.APIKey), apiKeySource()))
+	}
+	return cfg, true
+}
+
+// apiKeySource 描述 API Key 的来源，便于排查"改了配置却不生效"的问题
+func apiKeySource() string {
+	switch {
+	case rootCmd.PersistentFlags().Changed("api-key"):
+		return "--api-key 参数"
+	case os.Getenv(envPrefix+"_API_KEY") != "":
+		return envPrefix + "_API_KEY 环境变量"
+	case os.Getenv("OPENAI_API_KEY") != "" && !viper.InConfig("api_key"):
+		return "OPENAI_API_KEY 环境变量"
+	case useKeyring() && !viper.InConfig("api_key"):
+		return "系统钥匙串"
+	default:
+		return "配置文件"
+	}
+}
+
+// checkAPI 发送一次最小请求检查连通性，并确认主模型与初筛模型存在
+func (d *doctor) checkAPI(ctx context.Context, cfg reviewConfig) {
+	client, err := newLLMClient(cfg)
+	if err != nil {
+		d.fail("API 连通性", err.Error(), "检查 provider 与 mock_response_file 配置")
+		return
+	}
+
+	target := cfg.BaseURL
+	if cfg.Provider == llm.ProviderMock {
+		target = "mock"
+	}
+	start := time.Now()
+	if err := client.Ping(ctx); err != nil {
+		d.fail("API 连通性", fmt.Sprintf("%s 请求失败: %v", target, err), pingFix(err, cfg))
+		return
+	}
+	d.ok("API 连通性", fmt.Sprintf("%s 响应正常 (%s)", target, time.Since(start).Round(time.Millisecond)))
+
+	models, err := client.ListModels(ctx)
+	if errors.Is(err, llm.ErrModelListUnsupported) {
+		d.ok("模型", client.Model()+" 可用 (接口不提供模型列表，已通过测试请求确认)")
+		return
+	}
+	if err != nil {
+		d.warn("模型", err.Error(), "测试请求已成功，可忽略；如审查时报模型不存在，请检查 model 配置")
+		return
+	}
+
+	checkModel := func(title, model string) {
+		if slices.Contains(models, model) {
+			d.ok(title, model+" 存在")
+			return
+		}
+		d.fail(title, model+" 不在接口提供的模型列表中", "可用模型: "+strings.Join(similarModels(models, model), ", "))
+	}
+	checkModel("模型", client.Model())
+	if cfg.TriageModel != "" && cfg.Provider != llm.ProviderMock {
+		checkModel("初筛模型", cfg.TriageModel)
+	}
+}
+
+// pingFix 根据连通性检查的错误类型给出修复建议
+func pingFix(err error, cfg reviewConfig) string {
+	switch {
+	case llm.IsUnauthorized(err):
+		return "API Key 无效或没有权限：确认 Key 属于 " + cfg.BaseURL + " 对应的服务，或重新运行 reviewer config set api_key <key>"
+	case llm.IsModelNotFound(err):
+		return "模型 " + cfg.Model + " 不存在：reviewer config set model <模型名>"
+	case llm.IsNotFound(err):
+		return "接口地址可能有误 (OpenAI 兼容接口通常以 /v1 结尾)：reviewer config set base_url https://api.deepseek.com/v1"
+	case llm.IsQuotaExhausted(err):
+		return "账户余额或配额不足，请充值或更换 API Key"
+	case llm.IsRateLimited(err):
+		return "请求被限流，稍后重试；批量审查时可降低 concurrency"
+	case llm.IsTransient(err):
+		return "无法连接 " + cfg.BaseURL + "：检查网络、代理 (HTTPS_PROXY) 与 base_url 配置"
+	default:
+		return "检查 base_url、api_key 与 model 配置，使用 -vv 查看详细日志"
+	}
+}
+
+// similarModels 返回与 model 名称相近的模型（包含相同前缀或互为子串），最多 10 个；没有相近模型时返回前 10 个
+func similarModels(models []string, model string) []string {
+	prefix, _, _ := strings.Cut(strings.ToLower(model), "-")
+	var similar []string
+	for _, m := range models {
+		lower := strings.ToLower(m)
+		if strings.Contains(lower, prefix) || strings.Contains(strings.ToLower(model), lower) {
+			similar = append(similar, m)
+		}
+	}
+	if len(similar) == 0 {
+		similar = models
+	}
+	return similar[:min(len(similar), 10)]
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// editorLineArgs 按编辑器名称生成“打开文件并定位到行”的参数，未列出的编辑器只打开文件
+var editorLineArgs = map[string]func(path, line string) []string{
+	"vi":            plusLineArgs,
+	"vim":           plusLineArgs,
+	"nvim":          plusLineArgs,
+	"nano":          plusLineArgs,
+	"emacs":         plusLineArgs,
+	"emacsclient":   plusLineArgs,
+	"micro":         plusLineArgs,
+	"kak":           plusLineArgs,
+	"code":          gotoLineArgs,
+	"code-insiders": gotoLineArgs,
+	"codium":        gotoLineArgs,
+	"cursor":        gotoLineArgs,
+	"windsurf":      gotoLineArgs,
+	"hx":            colonLineArgs,
+	"subl":          colonLineArgs,
+	"zed":           colonLineArgs,
+	"idea":          jetbrainsLineArgs,
+	"goland":        jetbrainsLineArgs,
+	"pycharm":       jetbrainsLineArgs,
+	"webstorm":      jetbrainsLineArgs,
+}
+
+func plusLineArgs(path, line string) []string      { return []string{"+" + line, path} }
+func gotoLineArgs(path, line string) []string      { return []string{"-g", path + ":" + line} }
+func colonLineArgs(path, line string) []string     { return []string{path + ":" + line} }
+func jetbrainsLineArgs(path, line string) []string { return []string{"--line", line, path} }
+
+// editorCommand 返回在编辑器中打开 path 第 line 行的命令（line 为 0 时只打开文件）
+// 编辑器依次取 editor 配置、$VISUAL、$EDITOR，都未设置时使用 vi（Windows 为 notepad）
+// editor 配置可以用 {file} 与 {line} 占位符自定义参数，如 "code -g {file}:{line}"
+func editorCommand(path string, line int) (*exec.Cmd, error) {
+	editor := viper.GetString("editor")
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor == "" {
+			editor = os.Getenv(env)
+		}
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return nil, errors.New("未配置编辑器，请设置 EDITOR 环境变量或 reviewer config set editor <命令>")
+	}
+	name, args := fields[0], fields[1:]
+
+	lineText := strconv.Itoa(max(line, 1))
+	if strings.Contains(editor, "{file}") {
+		for i, arg := range args {
+			args[i] = strings.NewReplacer("{file}", path, "{line}", lineText).Replace(arg)
+		}
+		return exec.Command(name, args...), nil
+	}
+
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if lineArgs, ok := editorLineArgs[base]; ok && line > 0 {
+		return exec.Command(name, append(args, lineArgs(path, lineText)...)...), nil
+	}
+	return exec.Command(name, append(args, path)...), nil
+}
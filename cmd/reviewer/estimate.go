@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-ai-reviewer/internal/app/textenc"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+// fileEstimate 是单个文件的审查开销估算
+type fileEstimate struct {
+	path       string
+	size       int64
+	requests   int // 超过单次上限的文件分段审查，每段一次请求
+	prompt     int64
+	completion int64
+}
+
+// estimateFile 估算审查单个文件的请求数与 Token 消耗
+func estimateFile(path string, size, maxFileSize int64, level int) fileEstimate {
+	requests := int64(1)
+	if maxFileSize > 0 && size > maxFileSize {
+		requests = (size + maxFileSize - 1) / maxFileSize
+	}
+
+	// 每段请求都带一份系统提示词
+	prompt, completion := llm.EstimateReviewTokens(level, size)
+	overhead, _ := llm.EstimateReviewTokens(level, 0)
+	return fileEstimate{
+		path:       path,
+		size:       size,
+		requests:   int(requests),
+		prompt:     prompt + overhead*(requests-1),
+		completion: completion * requests,
+	}
+}
+
+// estimateFiles 估算一组文件的审查开销，无法读取的文件忽略
+func estimateFiles(files []string, maxFileSize int64, level int) []fileEstimate {
+	estimates := make([]fileEstimate, 0, len(files))
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		estimates = append(estimates, estimateFile(path, info.Size(), maxFileSize, level))
+	}
+	return estimates
+}
+
+// estimateTotals 是一组文件估算的合计
+type estimateTotals struct {
+	files      int
+	size       int64
+	requests   int
+	prompt     int64
+	completion int64
+}
+
+// sumEstimates 合计文件估算
+func sumEstimates(estimates []fileEstimate) estimateTotals {
+	var t estimateTotals
+	for _, e := range estimates {
+		t.files++
+		t.size += e.size
+		t.requests += e.requests
+		t.prompt += e.prompt
+		t.completion += e.completion
+	}
+	return t
+}
+
+// describe 返回 Token 与费用估算的描述，未配置单价时不显示费用
+func (t estimateTotals) describe(pricing llm.Pricing) string {
+	text := fmt.Sprintf("输入 ~%s / 输出 ~%s Token", formatTokens(t.prompt), formatTokens(t.completion))
+	if pricing.Enabled() {
+		text += fmt.Sprintf("，预计费用 ~%.4f", pricing.Cost(t.prompt, t.completion))
+	}
+	return text
+}
+
+// formatTokens 将 Token 数格式化为 K/M 单位
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1e3)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// formatSize 将字节数格式化为 KB/MB
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+}
+
+// 默认的单次请求耗时估算，用于预估总耗时
+const defaultRequestLatency = 20 * time.Second
+
+// estimateCmd 是 estimate 子命令的定义
+var estimateCmd = &cobra.Command{
+	Use:   "estimate [path|file...]",
+	Short: "预估审查的 Token、请求数、耗时与各模型费用（不调用 LLM）",
+	Long: `按与 run 相同的过滤规则扫描并读取文件内容估算 Token，输出请求数、按当前并发预估的耗时，
+以及各候选模型的费用。候选模型默认为当前模型、初筛模型与 model_prices 中配置的模型，可用 --models 指定。
+
+  reviewer estimate ./src --l 4
+  reviewer estimate --models deepseek-chat,gpt-4o --concurrency 10`,
+	ValidArgsFunction: completePaths,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		bindScanFlags(cmd)
+		mustBindPFlag("concurrency", cmd.Flags().Lookup("concurrency"))
+	},
+	Run: executeEstimate,
+}
+
+func init() {
+	rootCmd.AddCommand(estimateCmd)
+
+	addScanFlags(estimateCmd)
+	estimateCmd.Flags().Int("concurrency", defaultConcurrency, "并发 Worker 数量")
+	estimateCmd.Flags().StringSlice("models", nil, "对比费用的候选模型 (逗号分隔)")
+	estimateCmd.Flags().Duration("request-latency", defaultRequestLatency, "单次请求的平均耗时，用于预估总耗时")
+}
+
+// executeEstimate 是 estimate 命令的主执行函数
+func executeEstimate(cmd *cobra.Command, args []string) {
+	mergeProjectConfig(projectDir(args))
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	latency, _ := cmd.Flags().GetDuration("request-latency")
+	models, _ := cmd.Flags().GetStringSlice("models")
+	if len(models) == 0 {
+		models = candidateModels(cfg)
+	}
+
+	for _, task := range parseTasksFromArgs(cmd, args) {
+		files, skipped, err := scanTaskFiles(task, cfg)
+		if err != nil {
+			slog.Error("扫描失败", "path", task.Path, "err", err)
+			os.Exit(1)
+		}
+
+		estimates := tokenizeFiles(files, cfg.MaxFileSize, task.Level)
+		totals := sumEstimates(estimates)
+		fmt.Printf("🧮 审查预估: %s (级别 %d，未调用 API)\n\n", task.ReportName, task.Level)
+		fmt.Printf("文件: %d 个，总大小 %s", totals.files, formatSize(totals.size))
+		if len(skipped) > 0 {
+			fmt.Printf("（另有 %d 个生成代码/第三方代码/压缩产物已跳过）", len(skipped))
+		}
+		fmt.Println()
+		if totals.files == 0 {
+			continue
+		}
+
+		fmt.Printf("请求: %d 次", totals.requests)
+		if chunked := totals.requests - totals.files; chunked > 0 {
+			fmt.Printf("（超过 %s 的文件分段审查，多出 %d 次）", formatSize(cfg.MaxFileSize), chunked)
+		}
+		fmt.Println()
+		fmt.Printf("Token: 输入 ~%s / 输出 ~%s\n", formatTokens(totals.prompt), formatTokens(totals.completion))
+		fmt.Printf("耗时: 并发 %d 时约 %s", cfg.Concurrency, estimateWallClock(totals.requests, cfg.Concurrency, latency))
+		if cfg.AdaptiveConcurrency && cfg.MaxConcurrency > cfg.Concurrency {
+			fmt.Printf("，自适应并发升至 %d 时约 %s", cfg.MaxConcurrency, estimateWallClock(totals.requests, cfg.MaxConcurrency, latency))
+		}
+		fmt.Printf("（按每次请求 %s 估算）\n", latency)
+
+		printModelCosts(totals, models, cfg)
+		fmt.Println("\n💡 未计入失败重试、低分复核与两阶段初筛的额外请求")
+	}
+}
+
+// tokenizeFiles 读取文件内容估算 Token（比按文件大小估算更准确，中文等非 ASCII 内容按字符计算）
+func tokenizeFiles(files []string, maxFileSize int64, level int) []fileEstimate {
+	estimates := make([]fileEstimate, 0, len(files))
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		e := estimateFile(path, int64(len(content)), maxFileSize, level)
+		overhead, _ := llm.EstimateReviewTokens(level, 0)
+		e.prompt = overhead*int64(e.requests) + int64(llm.EstimateTokenCount(string(textenc.ToUTF8(content))))
+		estimates = append(estimates, e)
+	}
+	return estimates
+}
+
+// estimateWallClock 按并发数与单次请求耗时估算总耗时
+func estimateWallClock(requests, concurrency int, latency time.Duration) time.Duration {
+	concurrency = max(concurrency, 1)
+	rounds := (requests + concurrency - 1) / concurrency
+	return time.Duration(rounds) * latency
+}
+
+// candidateModels 返回默认的候选模型：当前模型、初筛模型与 model_prices 中配置的模型
+func candidateModels(cfg reviewConfig) []string {
+	models := []string{cfg.Model}
+	if cfg.TriageModel != "" {
+		models = append(models, cfg.TriageModel)
+	}
+	priced := make([]string, 0, len(cfg.ModelPrices))
+	for model := range cfg.ModelPrices {
+		priced = append(priced, model)
+	}
+	sort.Strings(priced)
+
+	var result []string
+	for _, model := range append(models, priced...) {
+		if !slices.ContainsFunc(result, func(m string) bool { return strings.EqualFold(m, model) }) {
+			result = append(result, model)
+		}
+	}
+	return result
+}
+
+// pricingFor 返回模型的单价：优先使用 model_prices，当前模型回退到 price_input / price_output
+func (c reviewConfig) pricingFor(model string) llm.Pricing {
+	if p, ok := c.ModelPrices[strings.ToLower(model)]; ok {
+		return p
+	}
+	if strings.EqualFold(model, c.Model) {
+		return c.Pricing
+	}
+	return llm.Pricing{}
+}
+
+// printModelCosts 输出各候选模型的费用估算
+func printModelCosts(totals estimateTotals, models []string, cfg reviewConfig) {
+	fmt.Println("\n💰 各模型费用:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	missing := false
+	for _, model := range models {
+		pricing := cfg.pricingFor(model)
+		if !pricing.Enabled() {
+			missing = true
+			fmt.Fprintf(w, "  %s\t(未配置单价)\n", model)
+			continue
+		}
+		fmt.Fprintf(w, "  %s\t~%.4f\t(输入 %g / 输出 %g 每百万 Token)\n", model, pricing.Cost(totals.prompt, totals.completion), pricing.Input, pricing.Output)
+	}
+	w.Flush()
+	if missing {
+		fmt.Println("\n💡 在 model_prices 中配置各模型的单价 (每百万 Token) 后可对比费用")
+	}
+}
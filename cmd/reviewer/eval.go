@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"go-ai-reviewer/internal/app/eval"
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+// evalCmd 用标注了预期问题的样例集评估审查效果
+var evalCmd = &cobra.Command{
+	Use:   "eval <dir>",
+	Short: "用标注了预期问题的样例集评估审查效果 (精确率 / 召回率)",
+	Long: `审查样例集目录中 eval.yaml 列出的文件，将结果与标注的预期问题匹配，输出每个样例的漏报与误报，
+以及整体的精确率、召回率与 F1，用于在修改提示词 (prompt_file)、模型或审查级别后验证效果。
+
+  reviewer eval testdata/eval
+  reviewer eval testdata/eval --l 5 --model gpt-4o
+  reviewer eval testdata/eval --min-recall 0.8 --json   # 召回率低于 0.8 时以非零状态码退出
+
+eval.yaml 的格式：
+
+  level: 3                  # 审查级别 (可选，--l 优先)
+  audit: false              # 以安全审计模式审查 (可选，--audit 同样生效)
+  cases:
+    - file: sqli.go         # 相对于样例集目录
+      expect:
+        - name: SQL 注入
+          keywords: [注入, injection, CWE-89]   # 问题文本包含任一关键字即视为发现
+          severity: major                       # 最低严重程度 (可选)
+          line: 12                              # 漏洞行号，允许 ±3 行误差 (可选)
+    - file: clean.go        # 没有 expect 的文件用于检验误报
+
+低于 --min-severity (默认 major) 且没有命中预期的问题不计为误报。`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completePaths,
+	RunE:              executeEval,
+}
+
+func init() {
+	rootCmd.AddCommand(evalCmd)
+	evalCmd.Flags().Int("l", 0, "审查严格级别 (1-6)，默认使用 eval.yaml 中的 level")
+	evalCmd.Flags().Bool("audit", false, "以安全审计模式审查")
+	evalCmd.Flags().String("min-severity", "major", "低于该严重程度的问题不计为误报: critical / major / minor")
+	evalCmd.Flags().Float64("min-precision", 0, "精确率低于该值时以非零状态码退出 (0-1)")
+	evalCmd.Flags().Float64("min-recall", 0, "召回率低于该值时以非零状态码退出 (0-1)")
+	evalCmd.Flags().Bool("json", false, "以 JSON 输出评估结果")
+
+	mustRegisterCompletion(evalCmd, "min-severity", completeSeverities)
+}
+
+// evalReport 是 eval --json 的输出
+type evalReport struct {
+	Level   int               `json:"level"`
+	Audit   bool              `json:"audit"`
+	Model   string            `json:"model"`
+	Tokens  int64             `json:"tokens"`
+	Summary eval.Summary      `json:"summary"`
+	Cases   []eval.CaseResult `json:"cases"`
+}
+
+// executeEval 是 eval 命令的主执行函数
+func executeEval(cmd *cobra.Command, args []string) error {
+	corpus, err := eval.Load(args[0])
+	if err != nil {
+		return err
+	}
+	minSeverityName, _ := cmd.Flags().GetString("min-severity")
+	minSeverity, err := llm.ParseSeverity(minSeverityName)
+	if err != nil {
+		return err
+	}
+	minPrecision, _ := cmd.Flags().GetFloat64("min-precision")
+	minRecall, _ := cmd.Flags().GetFloat64("min-recall")
+	if minPrecision < 0 || minPrecision > 1 || minRecall < 0 || minRecall > 1 {
+		return fmt.Errorf("--min-precision 与 --min-recall 必须在 0 到 1 之间")
+	}
+	if err := validateConfig(); err != nil {
+		return err
+	}
+
+	level, _ := cmd.Flags().GetInt("l")
+	if level == 0 {
+		level = corpus.Level
+	}
+	level = getValidLevel(level)
+	audit, _ := cmd.Flags().GetBool("audit")
+	audit = audit || corpus.Audit
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+	engine, client, err := newEvalEngine(cfg, level, audit)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	files := make([]string, len(corpus.Cases))
+	for i, tc := range corpus.Cases {
+		files[i] = corpus.Path(tc)
+	}
+	asJSON, _ := cmd.Flags().GetBool("json")
+	if !asJSON {
+		mode := ""
+		if audit {
+			mode = "，安全审计"
+		}
+		fmt.Fprintf(os.Stderr, "🧪 评估 %d 个样例 (级别 %d%s，模型 %s)...\n", len(files), level, mode, cfg.Model)
+	}
+	reviews := make(map[string]reviewer.Result, len(files))
+	for res := range engine.Start(ctx, files) {
+		reviews[filepath.Clean(res.FilePath)] = res
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("评估被中断")
+	}
+
+	results := make([]eval.CaseResult, 0, len(corpus.Cases))
+	for _, tc := range corpus.Cases {
+		res, ok := reviews[filepath.Clean(corpus.Path(tc))]
+		switch {
+		case !ok:
+			results = append(results, eval.Failed(tc, "没有审查结果"))
+		case res.Error != nil:
+			results = append(results, eval.Failed(tc, res.Error.Error()))
+		case res.SkipReason != "":
+			results = append(results, eval.Failed(tc, "已跳过: "+string(res.SkipReason)))
+		case res.Review == nil:
+			results = append(results, eval.Failed(tc, "没有审查结果"))
+		default:
+			results = append(results, eval.Score(tc, res.Review, minSeverity))
+		}
+	}
+	summary := eval.Summarize(results)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(evalReport{Level: level, Audit: audit, Model: cfg.Model, Tokens: client.Usage().Total(), Summary: summary, Cases: results})
+		if err != nil {
+			return err
+		}
+	} else {
+		printEvalResults(results, summary, client.Usage().Total())
+	}
+
+	if summary.Precision < minPrecision {
+		return fmt.Errorf("精确率 %.1f%% 低于 --min-precision %.1f%%", summary.Precision*100, minPrecision*100)
+	}
+	if summary.Recall < minRecall {
+		return fmt.Errorf("召回率 %.1f%% 低于 --min-recall %.1f%%", summary.Recall*100, minRecall*100)
+	}
+	return nil
+}
+
+// newEvalEngine 创建评估使用的审查引擎
+// 只启用影响审查结论的配置（提示词、复核、置信度过滤），不做初筛、去重与发送前遮盖，保证每个样例都经过模型审查
+func newEvalEngine(cfg reviewConfig, level int, audit bool) (*reviewer.Engine, *llm.Client, error) {
+	var clientOpts []llm.ClientOption
+	if cfg.PromptFile != "" {
+		prompt, err := llm.LoadPromptFile(cfg.PromptFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		clientOpts = append(clientOpts, llm.WithPrompt(prompt))
+	}
+	client, err := newLLMClient(cfg, clientOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("初始化 LLM 客户端失败: %w", err)
+	}
+
+	engineOpts := []reviewer.Option{
+		reviewer.WithMaxFileSize(cfg.MaxFileSize),
+		reviewer.WithFileTimeout(cfg.FileTimeout),
+		reviewer.WithLimiter(newRunLimiter(cfg)),
+	}
+	if audit {
+		engineOpts = append(engineOpts, reviewer.WithAudit())
+	}
+	if cfg.Reverify {
+		engineOpts = append(engineOpts, reviewer.WithReverify(cfg.ReverifyScore, cfg.ReverifyConfidence))
+	}
+	if cfg.MinConfidence > 0 {
+		engineOpts = append(engineOpts, reviewer.WithMinConfidence(cfg.MinConfidence))
+	}
+	if cfg.RetryRounds > 0 {
+		engineOpts = append(engineOpts, reviewer.WithRetryQueue(cfg.RetryRounds, cfg.RetryBackoff))
+	}
+	if len(cfg.Rules) > 0 || len(cfg.SuppressRules) > 0 {
+		engineOpts = append(engineOpts, reviewer.WithRules(cfg.Rules, cfg.SuppressRules))
+	}
+
+	engine, err := reviewer.NewEngine(client, cfg.Concurrency, level, engineOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("初始化引擎失败: %w", err)
+	}
+	return engine, client, nil
+}
+
+// printEvalResults 输出每个样例的结果与整体统计
+func printEvalResults(results []eval.CaseResult, s eval.Summary, tokens int64) {
+	for _, r := range results {
+		expected := len(r.Detected) + len(r.Missed)
+		mark := "✅"
+		if !r.Passed() {
+			mark = "❌"
+		}
+		fmt.Printf("%s %s  发现 %d/%d，误报 %d\n", mark, r.File, len(r.Detected), expected, len(r.FalsePositives))
+		if r.Error != "" {
+			fmt.Printf("     审查失败: %s\n", r.Error)
+		}
+		if len(r.Missed) > 0 {
+			fmt.Printf("     漏报: %s\n", strings.Join(r.Missed, "、"))
+		}
+		for _, fp := range r.FalsePositives {
+			fmt.Printf("     误报: %s\n", fp)
+		}
+	}
+
+	fmt.Printf("\n📊 样例 %d 个，通过 %d 个", s.Cases, s.Passed)
+	if s.Errors > 0 {
+		fmt.Printf("，审查失败 %d 个", s.Errors)
+	}
+	fmt.Println()
+	fmt.Printf("   发现 %d / 漏报 %d / 误报 %d\n", s.TruePositives, s.FalseNegatives, s.FalsePositives)
+	fmt.Printf("   精确率 %.1f%%  召回率 %.1f%%  F1 %.3f\n", s.Precision*100, s.Recall*100, s.F1)
+	if tokens > 0 {
+		fmt.Printf("   Token 消耗: %s\n", formatTokens(tokens))
+	}
+}
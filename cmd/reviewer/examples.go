@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// exampleSpec 是配置文件 examples 列表中的一个少样本示例
+type exampleSpec struct {
+	Kind   string `mapstructure:"kind"`   // finding 或 false_positive
+	File   string `mapstructure:"file"`   // 示例代码的文件名，可省略
+	Code   string `mapstructure:"code"`   // 代码片段
+	Issue  string `mapstructure:"issue"`  // 应当报告的问题或不应报告的误报
+	Reason string `mapstructure:"reason"` // 判断理由，可省略
+}
+
+// example 转换为注入系统提示的示例，kind 不区分大小写
+func (s exampleSpec) example() llm.Example {
+	return llm.Example{
+		Kind:   strings.ToLower(strings.TrimSpace(s.Kind)),
+		File:   strings.TrimSpace(s.File),
+		Code:   s.Code,
+		Issue:  s.Issue,
+		Reason: s.Reason,
+	}
+}
+
+// loadExamples 读取并校验 examples 配置，未配置时返回空
+func loadExamples() ([]llm.Example, error) {
+	var specs []exampleSpec
+	if err := viper.UnmarshalKey("examples", &specs); err != nil {
+		return nil, fmt.Errorf("examples 格式错误: %w", err)
+	}
+	examples := make([]llm.Example, 0, len(specs))
+	for _, spec := range specs {
+		examples = append(examples, spec.example())
+	}
+	if err := llm.ValidateExamples(examples); err != nil {
+		return nil, fmt.Errorf("examples: %w", err)
+	}
+	return examples, nil
+}
+
+// validateExamples 校验 examples：每项为包含 kind、code、issue（必填）与 file、reason 的映射
+func validateExamples(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("应为 {kind, code, issue, reason} 形式的列表")
+	}
+	examples := make([]llm.Example, 0, len(node.Content))
+	for i, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			return fmt.Errorf("第 %d 项应为 {kind, code, issue, reason} 形式的映射", i+1)
+		}
+		var spec exampleSpec
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			key, value := item.Content[j].Value, item.Content[j+1]
+			if value.Kind != yaml.ScalarNode {
+				return fmt.Errorf("第 %d 项的 %s 应为字符串", i+1, key)
+			}
+			switch key {
+			case "kind":
+				spec.Kind = value.Value
+			case "file":
+				spec.File = value.Value
+			case "code":
+				spec.Code = value.Value
+			case "issue":
+				spec.Issue = value.Value
+			case "reason":
+				spec.Reason = value.Value
+			default:
+				return fmt.Errorf("第 %d 项包含未知字段 %s (应为 kind / file / code / issue / reason)", i+1, key)
+			}
+		}
+		examples = append(examples, spec.example())
+	}
+	return llm.ValidateExamples(examples)
+}
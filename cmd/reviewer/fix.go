@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-ai-reviewer/internal/app/patch"
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/cobra"
+)
+
+// fixCmd 是 fix 子命令的定义
+var fixCmd = &cobra.Command{
+	Use:   "fix [report]",
+	Short: "查看或应用审查生成的修复补丁",
+	Long: `显示 reports/ 中最新报告（或指定报告）旁的修复补丁 (.patch)。
+补丁由 reviewer run --fix 生成：模型在审查的同时给出修复问题的 unified diff，写入报告旁的同名 .patch 文件。
+--apply 逐个文件显示修改并确认后应用；文件在审查后被修改、补丁无法对应时跳过该文件。
+补丁也可以用 git apply reports/<报告名>.patch 直接应用。
+
+  reviewer fix                  # 列出最新报告的补丁并显示修改内容
+  reviewer fix --apply          # 逐个文件确认后应用
+  reviewer fix my-audit --apply --yes`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeReportNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		reportPath, err := resolveReport(name)
+		if err != nil {
+			return err
+		}
+
+		patchPath := reviewer.PatchPath(reportPath)
+		data, err := os.ReadFile(patchPath)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("报告 %s 没有修复补丁，请使用 reviewer run --fix 重新审查", reportPath)
+		}
+		if err != nil {
+			return fmt.Errorf("读取修复补丁失败: %w", err)
+		}
+		patches, err := patch.Parse(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", patchPath, err)
+		}
+
+		apply, _ := cmd.Flags().GetBool("apply")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !apply {
+			printPatches(patchPath, patches)
+			return nil
+		}
+		if !yes && !isInteractive() {
+			return errors.New("非交互环境中请使用 --yes 应用全部补丁")
+		}
+		return applyPatches(patches, yes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fixCmd)
+
+	fixCmd.Flags().Bool("apply", false, "逐个文件确认后应用补丁")
+	fixCmd.Flags().BoolP("yes", "y", false, "配合 --apply 使用，不逐个确认，直接应用全部补丁")
+}
+
+// printPatches 列出补丁涉及的文件并输出全部修改
+func printPatches(patchPath string, patches []patch.FilePatch) {
+	fmt.Printf("🩹 %s 包含 %d 个文件的修复补丁:\n", patchPath, len(patches))
+	for _, p := range patches {
+		added, removed := p.Stats()
+		fmt.Printf("  %s (+%d -%d)\n", p.Path, added, removed)
+	}
+	for _, p := range patches {
+		fmt.Printf("\n%s", patch.Format(p.Path, p.Hunks))
+	}
+	fmt.Println("\n💡 使用 reviewer fix --apply 逐个文件确认后应用")
+}
+
+// applyPatches 逐个文件应用补丁，all 为 false 时每个文件先显示修改并等待确认
+func applyPatches(patches []patch.FilePatch, all bool) error {
+	reader := bufio.NewReader(os.Stdin)
+	var applied, skipped, failed int
+
+	for i, p := range patches {
+		if !all {
+			fmt.Printf("\n[%d/%d] %s", i+1, len(patches), patch.Format(p.Path, p.Hunks))
+			fmt.Print("应用该文件的修改? [y]应用 [n]跳过 [a]应用剩余全部 [q]退出: ")
+			answer, _ := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+			case "a", "all":
+				all = true
+			case "q", "quit":
+				skipped += len(patches) - i
+				fmt.Printf("\n⏹️  已应用 %d 个文件，跳过 %d 个，失败 %d 个\n", applied, skipped, failed)
+				return nil
+			default:
+				skipped++
+				continue
+			}
+		}
+
+		if err := applyFilePatch(p); err != nil {
+			fmt.Printf("⚠️  %s: %v\n", p.Path, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ 已修改 %s\n", p.Path)
+		applied++
+	}
+
+	fmt.Printf("\n🩹 已应用 %d 个文件，跳过 %d 个，失败 %d 个\n", applied, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d 个文件的补丁无法应用", failed)
+	}
+	return nil
+}
+
+// applyFilePatch 将补丁应用到磁盘上的文件，保留原有的文件权限
+func applyFilePatch(p patch.FilePatch) error {
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	content, err := patch.Apply(string(data), p.Hunks)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(p.Path, []byte(content), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	return nil
+}
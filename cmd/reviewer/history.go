@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-ai-reviewer/internal/app/history"
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// historyDBName 是默认的 SQLite 历史数据库文件名，位于 reports/ 中
+const historyDBName = "history.db"
+
+// defaultHistoryLimit 是 reviewer history 默认列出的记录数
+const defaultHistoryLimit = 20
+
+// historyCmd 是 history 子命令的定义
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "列出审查历史：每次运行的评分、问题数与 Token 消耗",
+	Long: `列出保存在历史存储 (history_backend) 中的审查运行，按时间从新到旧排列。
+默认只列出当前项目 (git 仓库的 origin 远程地址，或 history_project) 的记录；
+团队共享 PostgreSQL / MySQL 时可用 --all 汇总所有仓库的审查。
+
+  reviewer history
+  reviewer history --all --limit 50
+  reviewer history --project github.com/acme/api --json`,
+	Args: cobra.NoArgs,
+	RunE: executeHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().String("project", "", "只列出该项目的记录 (默认为当前项目)")
+	historyCmd.Flags().Bool("all", false, "列出所有项目的记录")
+	historyCmd.Flags().Int("limit", defaultHistoryLimit, "最多列出的记录数 (0 不限制)")
+	historyCmd.Flags().Bool("json", false, "以 JSON 输出")
+}
+
+// executeHistory 是 history 命令的主执行函数
+func executeHistory(cmd *cobra.Command, _ []string) error {
+	mergeProjectConfig(projectDir([]string{"."}))
+	if err := validateHistory(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	store, err := openHistory(ctx, false)
+	if err != nil && !errors.Is(err, history.ErrNoHistory) {
+		return err
+	}
+	if store == nil && err == nil {
+		return fmt.Errorf("history_backend 为 %s，没有保存审查历史", history.BackendOff)
+	}
+
+	var runs []history.Run
+	if store != nil {
+		defer store.Close()
+		var q history.Query
+		all, _ := cmd.Flags().GetBool("all")
+		if q.Project, _ = cmd.Flags().GetString("project"); q.Project == "" && !all {
+			q.Project = historyProject(".")
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		if runs, err = store.List(ctx, q, limit); err != nil {
+			return err
+		}
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		if runs == nil {
+			runs = []history.Run{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(runs)
+	}
+	if len(runs) == 0 {
+		fmt.Println("📭 还没有审查记录，运行 reviewer run 后会自动保存")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "时间\t项目\t报告\t级别\t得分\t🔴 严重\t🟠 重要\t🟡 一般\t文件\tToken")
+	for _, r := range runs {
+		name := r.Name
+		if r.Partial {
+			name += " (部分)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.1f\t%d\t%d\t%d\t%d\t%d\n",
+			r.Created.Format("2006-01-02 15:04"), r.Project, name, r.Level, r.Score, r.Critical, r.Major, r.Minor, r.Reviewed, r.Tokens)
+	}
+	return w.Flush()
+}
+
+// validateHistory 校验历史存储配置
+func validateHistory() error {
+	backend := viper.GetString("history_backend")
+	if !slices.Contains(history.Backends, backend) {
+		return fmt.Errorf("不支持的历史存储后端 %q (可选: %s)", backend, strings.Join(history.Backends, " / "))
+	}
+	if (backend == history.BackendPostgres || backend == history.BackendMySQL) && viper.GetString("history_dsn") == "" {
+		return fmt.Errorf("history_backend 为 %s 时需要设置 history_dsn", backend)
+	}
+	return nil
+}
+
+// openHistory 打开配置的历史存储，history_backend 为 off 时返回 nil
+// sqlite 与 json 未设置 history_dsn 时分别使用 reports/history.db 与 reports/
+// create 为 false 时（只读取历史的命令）不创建不存在的 SQLite 数据库，返回 history.ErrNoHistory
+func openHistory(ctx context.Context, create bool) (history.Store, error) {
+	backend := viper.GetString("history_backend")
+	if backend == history.BackendOff {
+		return nil, nil
+	}
+	dsn := viper.GetString("history_dsn")
+	if dsn == "" {
+		switch backend {
+		case history.BackendSQLite:
+			dsn = filepath.Join(reportsDir, historyDBName)
+		case history.BackendJSON:
+			dsn = reportsDir
+		}
+	}
+	if backend == history.BackendSQLite && !create && !isValidFile(dsn) {
+		return nil, history.ErrNoHistory
+	}
+	return history.Open(ctx, backend, dsn)
+}
+
+// openRunHistory 打开审查运行保存记录使用的历史存储，失败时只输出警告，本次运行不保存记录
+func openRunHistory(ctx context.Context) history.Store {
+	store, err := openHistory(ctx, true)
+	if err != nil {
+		slog.Warn("打开审查历史失败，本次不保存审查记录", "backend", viper.GetString("history_backend"), "err", err)
+		return nil
+	}
+	return store
+}
+
+// newHistoryRun 将任务的审查结果转换为审查记录，只保存成功审查的文件
+func newHistoryRun(project string, results []reviewer.Result, meta reviewer.ReportMeta, reportPath string, duration time.Duration) *history.Run {
+	d := reviewer.NewRunDigest(results, meta)
+	run := &history.Run{
+		Project:  project,
+		Name:     strings.TrimSuffix(filepath.Base(reportPath), ".md"),
+		Report:   reportPath,
+		Created:  time.Now(),
+		Level:    meta.Level,
+		Audit:    meta.Audit,
+		Partial:  meta.Partial,
+		Score:    math.Round(d.Score*10) / 10,
+		Reviewed: d.Reviewed,
+		Critical: d.Critical,
+		Major:    d.Major,
+		Minor:    d.Minor,
+		Tokens:   meta.Tokens,
+		Duration: duration,
+	}
+	for _, res := range results {
+		if res.Error != nil || res.Review == nil {
+			continue
+		}
+		abs, err := filepath.Abs(res.FilePath)
+		if err != nil {
+			abs = res.FilePath
+		}
+		run.Files = append(run.Files, history.File{Path: res.FilePath, Abs: abs, Review: res.Review})
+	}
+	return run
+}
+
+// saveHistory 保存审查记录，失败时只输出警告，不影响审查结果
+// 运行被中断时 ctx 已取消，保存使用不随之取消的 context
+func saveHistory(ctx context.Context, store history.Store, run *history.Run) {
+	if err := store.Save(context.WithoutCancel(ctx), run); err != nil {
+		slog.Warn("保存审查记录失败", "report", run.Name, "err", err)
+	}
+}
+
+// historyProject 返回 dir 所属项目的标识：history_project 配置优先，其次为 git 仓库 origin 远程地址
+// （如 github.com/acme/api），都没有时返回空字符串
+func historyProject(dir string) string {
+	if project := viper.GetString("history_project"); project != "" {
+		return project
+	}
+	if remote := gitOriginURL(dir); remote != "" {
+		return normalizeRemote(remote)
+	}
+	return ""
+}
+
+// taskProject 返回任务审查记录所属的项目，无法识别仓库时为任务目录名
+func taskProject(dir string) string {
+	if project := historyProject(dir); project != "" {
+		return project
+	}
+	return resolveDirectoryName(dir)
+}
+
+// gitOriginURL 从 dir 向上查找 git 仓库，返回 .git/config 中 origin 远程的地址，找不到时返回空字符串
+func gitOriginURL(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		gitDir := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitDir); err == nil {
+			if !info.IsDir() {
+				// 工作树与子模块的 .git 是指向实际目录的文件: "gitdir: <path>"
+				data, err := os.ReadFile(gitDir)
+				if err != nil {
+					return ""
+				}
+				target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+				if !ok {
+					return ""
+				}
+				if gitDir = strings.TrimSpace(target); !filepath.IsAbs(gitDir) {
+					gitDir = filepath.Join(dir, gitDir)
+				}
+				// 工作树的配置在主仓库中
+				if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+					if c := strings.TrimSpace(string(common)); filepath.IsAbs(c) {
+						gitDir = c
+					} else {
+						gitDir = filepath.Join(gitDir, c)
+					}
+				}
+			}
+			return readOriginURL(filepath.Join(gitDir, "config"))
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readOriginURL 读取 git 配置文件中 [remote "origin"] 的 url
+func readOriginURL(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// normalizeRemote 将远程地址统一为 host/path 形式，去掉协议、用户名、密码、端口与 .git 后缀，
+// 使 https 与 ssh 克隆的同一仓库得到相同的项目标识
+func normalizeRemote(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Hostname() + "/" + strings.Trim(u.Path, "/")
+	}
+	// scp 形式: git@github.com:acme/api
+	if at := strings.Index(remote, "@"); at >= 0 {
+		remote = remote[at+1:]
+	}
+	if host, path, ok := strings.Cut(remote, ":"); ok && !strings.Contains(host, "/") {
+		return host + "/" + strings.Trim(path, "/")
+	}
+	return remote
+}
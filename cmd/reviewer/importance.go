@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// loadImportanceRules 读取 importance 配置：路径模式到固定重要性 (如 0.2) 或 {min, max} 范围的映射
+func loadImportanceRules() ([]reviewer.ImportanceRule, error) {
+	raw, ok := viper.Get("importance").(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	rules, err := parseImportanceRules(raw)
+	if err != nil {
+		return nil, fmt.Errorf("importance: %w", err)
+	}
+	return rules, nil
+}
+
+// parseImportanceRules 将 importance 映射转换为规则，按模式排序以保证错误信息稳定
+func parseImportanceRules(raw map[string]any) ([]reviewer.ImportanceRule, error) {
+	patterns := make([]string, 0, len(raw))
+	for pattern := range raw {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	rules := make([]reviewer.ImportanceRule, 0, len(raw))
+	for _, pattern := range patterns {
+		rule, err := parseImportanceRule(pattern, raw[pattern])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseImportanceRule 解析单条规则：数字为固定值，{min, max} 为范围（省略的一端为 0 或 1）
+func parseImportanceRule(pattern string, value any) (reviewer.ImportanceRule, error) {
+	rule := reviewer.ImportanceRule{Pattern: pattern, Min: 0, Max: 1}
+	switch v := value.(type) {
+	case map[string]any:
+		for key, bound := range v {
+			n, ok := toFloat(bound)
+			switch {
+			case !ok:
+				return rule, fmt.Errorf("%s.%s 应为数字，实际为 %v", pattern, key, bound)
+			case key == "min":
+				rule.Min = n
+			case key == "max":
+				rule.Max = n
+			default:
+				return rule, fmt.Errorf("%s 包含未知字段 %s (应为 min / max)", pattern, key)
+			}
+		}
+	default:
+		n, ok := toFloat(value)
+		if !ok {
+			return rule, fmt.Errorf("%s 应为 0-1 之间的数字或 {min, max}，实际为 %v", pattern, value)
+		}
+		rule.Min, rule.Max = n, n
+	}
+	return rule, rule.Validate()
+}
+
+// toFloat 将 YAML 解析出的整数或小数转换为 float64
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// validateImportance 校验配置文件中的 importance 节点
+func validateImportance(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("应为 路径模式: 重要性 形式的映射")
+	}
+	var raw map[string]any
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	_, err := parseImportanceRules(raw)
+	return err
+}
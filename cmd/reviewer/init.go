@@ -0,0 +1,300 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-ai-reviewer/internal/app/scanner"
+
+	"github.com/spf13/cobra"
+)
+
+// nonCodeLanguages 是检测语言时不计入审查范围的类型（文档、配置与样式）
+var nonCodeLanguages = []string{"Markdown", "YAML", "JSON", "HTML", "CSS", "Other"}
+
+// languageProfile 是按语言推荐的排除目录与忽略规则
+type languageProfile struct {
+	excludeDirs []string // 构建产物、依赖缓存等目录名
+	ignores     []string // .reviewignore 规则
+}
+
+// languageProfiles 是各语言的推荐配置，未收录的语言不额外排除
+var languageProfiles = map[string]languageProfile{
+	"Go": {
+		ignores: []string{"testdata/", "*_mock.go", "mock_*.go"},
+	},
+	"JavaScript": {
+		excludeDirs: []string{"coverage", "out", ".next", ".nuxt"},
+		ignores:     []string{"*.config.js", "public/"},
+	},
+	"TypeScript": {
+		excludeDirs: []string{"coverage", "out", ".next", ".nuxt"},
+		ignores:     []string{"*.d.ts", "*.config.ts"},
+	},
+	"Vue": {
+		excludeDirs: []string{"coverage", ".nuxt"},
+	},
+	"Python": {
+		excludeDirs: []string{".venv", "venv", ".tox", ".mypy_cache", ".pytest_cache"},
+		ignores:     []string{"migrations/", "*_pb2.py", "*_pb2_grpc.py"},
+	},
+	"Java": {
+		excludeDirs: []string{"target", ".gradle"},
+	},
+	"Kotlin": {
+		excludeDirs: []string{"target", ".gradle"},
+	},
+	"Rust": {
+		excludeDirs: []string{"target"},
+	},
+	"Ruby": {
+		excludeDirs: []string{".bundle"},
+		ignores:     []string{"db/schema.rb"},
+	},
+	"PHP": {
+		excludeDirs: []string{"storage"},
+	},
+	"C#": {
+		excludeDirs: []string{"bin", "obj"},
+		ignores:     []string{"*.Designer.cs"},
+	},
+	"C": {
+		excludeDirs: []string{"cmake-build-debug", "cmake-build-release"},
+	},
+	"C++": {
+		excludeDirs: []string{"cmake-build-debug", "cmake-build-release"},
+	},
+}
+
+// initCmd 是 init 子命令的定义
+var initCmd = &cobra.Command{
+	Use:   "init [path]",
+	Short: "为项目生成 .code-review.yaml（可选 .reviewignore）",
+	Long: `检测项目使用的语言，在项目目录生成带注释的 .code-review.yaml，
+包含审查的扩展名、排除目录、严格级别与报告名称，可随仓库提交供团队共用。
+--ignore 同时生成 .reviewignore（语法与 .gitignore 相同，只影响代码审查）。
+
+  reviewer init
+  reviewer init ./service --l 4 --ignore`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeDirs,
+	RunE:              executeInit,
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().Int("l", defaultLevel, "写入配置的审查严格级别 (1-6)")
+	initCmd.Flags().Bool("ignore", false, "同时生成 .reviewignore")
+	initCmd.Flags().Bool("force", false, "覆盖已存在的文件")
+}
+
+// projectLanguage 是检测到的项目语言
+type projectLanguage struct {
+	name  string
+	files int
+	exts  []string
+}
+
+// executeInit 是 init 命令的主执行函数
+func executeInit(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if !isValidPath(dir) {
+		return fmt.Errorf("目录不存在: %s", dir)
+	}
+
+	level, _ := cmd.Flags().GetInt("l")
+	if !isValidLevel(level) {
+		return fmt.Errorf("无效的严格级别 %d，取值范围为 %d-%d", level, minLevel, maxLevel)
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	withIgnore, _ := cmd.Flags().GetBool("ignore")
+
+	configPath := filepath.Join(dir, configFileName+"."+configFileType)
+	ignorePath := filepath.Join(dir, scanner.ReviewIgnoreFile)
+	if !force {
+		paths := []string{configPath}
+		if withIgnore {
+			paths = append(paths, ignorePath)
+		}
+		if err := checkNotExist(paths); err != nil {
+			return err
+		}
+	}
+
+	langs, err := detectLanguages(dir)
+	if err != nil {
+		return err
+	}
+	if len(langs) == 0 {
+		fmt.Println("⚠️ 未检测到代码文件，include_exts 留空（扫描所有文本文件）")
+	} else {
+		names := make([]string, 0, len(langs))
+		for _, lang := range langs {
+			names = append(names, fmt.Sprintf("%s (%d)", lang.name, lang.files))
+		}
+		fmt.Printf("🔍 检测到语言: %s\n", strings.Join(names, ", "))
+	}
+
+	if err := writeInitFile(configPath, renderProjectConfig(langs, level, resolveDirectoryName(dir))); err != nil {
+		return err
+	}
+	if withIgnore {
+		if err := writeInitFile(ignorePath, renderReviewIgnore(langs)); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("💡 执行 reviewer ls 预览将被审查的文件")
+	return nil
+}
+
+// detectLanguages 扫描目录（遵循忽略规则，跳过生成代码），按文件数降序返回检测到的代码语言
+func detectLanguages(dir string) ([]projectLanguage, error) {
+	scn, err := scanner.NewScanner(dir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("初始化扫描器失败: %w", err)
+	}
+	files, err := scn.Scan()
+	if err != nil {
+		return nil, fmt.Errorf("扫描目录失败: %w", err)
+	}
+
+	byName := make(map[string]*projectLanguage)
+	for _, path := range files {
+		name := languageOf(path)
+		if slices.Contains(nonCodeLanguages, name) {
+			continue
+		}
+		lang, ok := byName[name]
+		if !ok {
+			lang = &projectLanguage{name: name}
+			byName[name] = lang
+		}
+		lang.files++
+		if ext := strings.ToLower(filepath.Ext(path)); !slices.Contains(lang.exts, ext) {
+			lang.exts = append(lang.exts, ext)
+		}
+	}
+
+	langs := make([]projectLanguage, 0, len(byName))
+	for _, lang := range byName {
+		sort.Strings(lang.exts)
+		langs = append(langs, *lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if langs[i].files != langs[j].files {
+			return langs[i].files > langs[j].files
+		}
+		return langs[i].name < langs[j].name
+	})
+	return langs, nil
+}
+
+// checkNotExist 检查待生成的文件均不存在，避免覆盖已有配置
+func checkNotExist(paths []string) error {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s 已存在，使用 --force 覆盖", path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("检查 %s 失败: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// writeInitFile 写入生成的文件
+func writeInitFile(path, content string) error {
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", path, err)
+	}
+	fmt.Printf("✅ 已生成 %s\n", path)
+	return nil
+}
+
+// renderProjectConfig 生成带注释的项目级配置文件内容
+// 项目配置随仓库提交，不写入 API Key 等个人配置
+func renderProjectConfig(langs []projectLanguage, level int, reportName string) string {
+	var exts, excludes, names []string
+	for _, lang := range langs {
+		names = append(names, lang.name)
+		exts = append(exts, lang.exts...)
+		for _, dir := range languageProfiles[lang.name].excludeDirs {
+			if !slices.Contains(excludes, dir) {
+				excludes = append(excludes, dir)
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# Go AI Code Reviewer 项目配置\n")
+	b.WriteString("# 由 reviewer init 生成，覆盖 ~/.code-review.yaml 中的同名配置项\n")
+	b.WriteString("# 只有影响审查行为的配置项生效；API Key、接口地址、Webhook 等请放在 ~/.code-review.yaml 或环境变量中\n")
+	if len(names) > 0 {
+		fmt.Fprintf(&b, "# 检测到的语言: %s\n", strings.Join(names, ", "))
+	}
+
+	b.WriteString("\n# 审查级别 (1 宽松 - 6 极致)\n")
+	fmt.Fprintf(&b, "level: %d\n", level)
+
+	b.WriteString("\n# 包含的文件扩展名（留空扫描所有文本文件）\n")
+	fmt.Fprintf(&b, "include_exts: %s\n", yamlFlowList(exts))
+
+	b.WriteString("\n# 额外排除的目录名（node_modules、vendor、dist、build 等已默认排除）\n")
+	b.WriteString("# 按路径排除文件请使用 .reviewignore（语法与 .gitignore 相同）\n")
+	fmt.Fprintf(&b, "exclude_dirs: %s\n", yamlFlowList(excludes))
+
+	b.WriteString("\n# 跳过生成代码、第三方代码与压缩/打包产物\n")
+	b.WriteString("skip_generated: true\n")
+	b.WriteString("\n# 设为 false 跳过测试代码\n")
+	b.WriteString("include_tests: true\n")
+
+	b.WriteString("\n# 报告名称，报告输出到 reports/<report_name>.md（默认使用目录名）\n")
+	fmt.Fprintf(&b, "# report_name: %s\n", strconv.Quote(reportName))
+
+	b.WriteString("\n# 单次审查的最大文件大小，超过则分段审查\n")
+	b.WriteString("max_file_size: 32KB\n")
+	b.WriteString("\n# 只关注指定方面的问题 (security / performance / correctness / style)\n")
+	b.WriteString("# focus: [security, correctness]\n")
+	return b.String()
+}
+
+// renderReviewIgnore 生成 .reviewignore 文件内容
+func renderReviewIgnore(langs []projectLanguage) string {
+	var b strings.Builder
+	b.WriteString("# 只影响代码审查的忽略规则，语法与 .gitignore 相同\n")
+	b.WriteString("# .gitignore 中的文件已自动忽略，无需重复\n")
+	b.WriteString("\n# 测试夹具与示例数据\n")
+	b.WriteString("fixtures/\n")
+	b.WriteString("examples/\n")
+
+	for _, lang := range langs {
+		ignores := languageProfiles[lang.name].ignores
+		if len(ignores) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n# %s\n", lang.name)
+		for _, pattern := range ignores {
+			b.WriteString(pattern + "\n")
+		}
+	}
+	return b.String()
+}
+
+// yamlFlowList 将字符串列表格式化为 YAML 流式列表
+func yamlFlowList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = strconv.Quote(item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go-ai-reviewer/internal/app/jira"
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/app/secrets"
+	"go-ai-reviewer/internal/app/textenc"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/viper"
+)
+
+// 默认只为严重问题创建工单，每个任务最多处理 20 个问题
+const (
+	defaultJiraMinSeverity = "critical"
+	defaultJiraMaxIssues   = 20
+	defaultJiraIssueType   = "Bug"
+)
+
+// jiraSnippetContext 是工单代码片段中问题所在行前后各保留的行数
+const jiraSnippetContext = 3
+
+// jiraConfig 是审查完成后为严重问题创建 Jira 工单的配置，URL 为空时不创建
+type jiraConfig struct {
+	jira.Config
+	MinSeverity llm.Severity // 创建工单的最低严重程度
+	MaxIssues   int          // 每个任务最多处理的问题数，超出的问题只在日志中提示
+	Redact      []string     // 代码片段中额外遮盖的正则表达式 (redact_patterns)
+}
+
+// loadJiraConfig 读取 Jira 配置（已由 validateJira 校验）
+func loadJiraConfig() jiraConfig {
+	minSeverity, _ := llm.ParseSeverity(viper.GetString("jira_min_severity"))
+	return jiraConfig{
+		Config: jira.Config{
+			URL:       viper.GetString("jira_url"),
+			Email:     viper.GetString("jira_email"),
+			Token:     viper.GetString("jira_token"),
+			Project:   viper.GetString("jira_project"),
+			IssueType: cmp.Or(viper.GetString("jira_issue_type"), defaultJiraIssueType),
+			Labels:    viper.GetStringSlice("jira_labels"),
+		},
+		MinSeverity: max(minSeverity, llm.SeverityMinor),
+		MaxIssues:   viper.GetInt("jira_max_issues"),
+		Redact:      viper.GetStringSlice("redact_patterns"),
+	}
+}
+
+// validateJira 校验 Jira 配置，未设置 jira_url 时不校验
+func validateJira() error {
+	rawURL := viper.GetString("jira_url")
+	if rawURL == "" {
+		return nil
+	}
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("jira_url 应为 https:// 开头的 Jira 站点地址，如 https://acme.atlassian.net")
+	}
+	if viper.GetString("jira_project") == "" {
+		return errors.New("设置 jira_url 时需要设置 jira_project (项目 Key，如 SEC)")
+	}
+	if viper.GetString("jira_token") == "" {
+		return errors.New("设置 jira_url 时需要设置 jira_token (Jira Cloud 的 API Token 或 Server 的个人访问令牌)")
+	}
+	if _, err := llm.ParseSeverity(viper.GetString("jira_min_severity")); err != nil {
+		return fmt.Errorf("jira_min_severity: %w", err)
+	}
+	for _, label := range viper.GetStringSlice("jira_labels") {
+		if label == "" || strings.ContainsFunc(label, func(r rune) bool { return r == ' ' || r == '\t' }) {
+			return fmt.Errorf("jira_labels 中的标签 %q 不能为空或包含空格", label)
+		}
+	}
+	return nil
+}
+
+// fileJiraIssues 为达到阈值的问题创建或更新 Jira 工单，失败时只记录警告，不影响审查结果
+// root 是任务的审查目录，工单中的文件路径相对于它，保证在不同机器与目录中运行时去重一致
+func fileJiraIssues(ctx context.Context, cfg jiraConfig, project, root string, results []reviewer.Result, reportLink string) {
+	if cfg.URL == "" {
+		return
+	}
+	redactor, err := secrets.NewRedactor(cfg.Redact)
+	if err != nil {
+		slog.Warn("创建 Jira 工单失败", "err", err)
+		return
+	}
+	findings := jiraFindings(cfg.MinSeverity, project, root, results, redactor)
+	if len(findings) == 0 {
+		return
+	}
+	for i := range findings {
+		findings[i].Report = reportLink
+	}
+	if cfg.MaxIssues > 0 && len(findings) > cfg.MaxIssues {
+		slog.Warn("达到 Jira 工单数上限，其余问题不创建工单", "limit", cfg.MaxIssues, "skipped", len(findings)-cfg.MaxIssues)
+		findings = findings[:cfg.MaxIssues]
+	}
+
+	client := jira.New(cfg.Config)
+	var created, updated int
+	for _, f := range findings {
+		key, isNew, err := client.Sync(ctx, f)
+		if err != nil {
+			slog.Warn("同步 Jira 工单失败", "file", f.File, "err", err)
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		if isNew {
+			created++
+			slog.Info("已创建 Jira 工单", "key", key, "file", f.File)
+		} else {
+			updated++
+			slog.Info("已更新 Jira 工单", "key", key, "file", f.File)
+		}
+	}
+	if created+updated > 0 {
+		slog.Info("Jira 工单同步完成", "created", created, "updated", updated)
+	}
+}
+
+// jiraFindings 挑出不低于 minSeverity 的问题，按严重程度从高到低排列
+// 安全审计的问题使用漏洞的行号与修复方法，普通审查的问题使用文件的优化建议与修复补丁
+func jiraFindings(minSeverity llm.Severity, project, root string, results []reviewer.Result, redactor *secrets.Redactor) []jira.Finding {
+	var findings []jira.Finding
+	for _, res := range results {
+		if res.Error != nil || res.Review == nil {
+			continue
+		}
+		file := res.FilePath
+		if rel, err := filepath.Rel(root, res.FilePath); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+		file = filepath.ToSlash(file)
+
+		var lines []string
+		source := func() []string {
+			if lines == nil {
+				lines = readSnippetSource(res.FilePath, redactor)
+			}
+			return lines
+		}
+		add := func(severity llm.Severity, line int, text, detail, fix, patch string) {
+			if severity == llm.SeverityUnknown {
+				severity = llm.SeverityMajor
+			}
+			if severity < minSeverity {
+				return
+			}
+			findings = append(findings, jira.Finding{
+				Project:  project,
+				File:     file,
+				Line:     line,
+				Severity: severity,
+				Text:     text,
+				Detail:   detail,
+				Snippet:  snippetAround(source(), line),
+				Fix:      fix,
+				Patch:    patch,
+			})
+		}
+
+		if len(res.Review.Findings) > 0 {
+			for _, f := range res.Review.Findings {
+				text := f.Title
+				if f.CWE != "" {
+					text = fmt.Sprintf("[%s] %s", f.CWE, f.Title)
+				}
+				add(f.Level(), f.Line, text, f.Description, f.Remediation, "")
+			}
+			continue
+		}
+		for _, issue := range res.Review.Issues {
+			severity, text := llm.SplitIssue(issue)
+			add(severity, llm.IssueLine(text), text, "", res.Review.Suggestion, res.Review.Patch)
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b jira.Finding) int {
+		return cmp.Compare(b.Severity, a.Severity)
+	})
+	return findings
+}
+
+// readSnippetSource 读取文件并遮盖疑似密钥与邮箱（工单的读者通常多于代码仓库），失败时返回空切片
+func readSnippetSource(path string, redactor *secrets.Redactor) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{}
+	}
+	content, _ := redactor.Redact(string(textenc.ToUTF8(data)))
+	return strings.Split(content, "\n")
+}
+
+// snippetAround 返回 line 前后 jiraSnippetContext 行的代码（带行号，问题所在行以 > 标记），行号未知或超出范围时返回空字符串
+func snippetAround(lines []string, line int) string {
+	if line <= 0 || line > len(lines) {
+		return ""
+	}
+	start, end := max(line-jiraSnippetContext, 1), min(line+jiraSnippetContext, len(lines))
+	var b strings.Builder
+	for n := start; n <= end; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s%4d| %s\n", marker, n, lines[n-1])
+	}
+	return b.String()
+}
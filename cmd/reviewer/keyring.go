@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+	"go.yaml.in/yaml/v3"
+)
+
+// 系统钥匙串中保存 API Key 的服务名与账户名
+const (
+	keyringService = "go-ai-reviewer"
+	keyringUser    = "api_key"
+)
+
+// apiKeySourceKeyring 表示 API Key 保存在系统钥匙串中（api_key_source: keyring）
+const apiKeySourceKeyring = "keyring"
+
+// configKeyringCmd 在配置文件与系统钥匙串之间迁移 API Key
+var configKeyringCmd = &cobra.Command{
+	Use:   "keyring",
+	Short: "将配置文件中的 API Key 迁移到系统钥匙串",
+	Long: `将配置文件中的明文 api_key 保存到系统钥匙串（macOS Keychain、Windows 凭据管理器、Linux libsecret），
+并从配置文件中删除，改为 api_key_source: keyring。--revert 将 API Key 写回配置文件并从钥匙串删除。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if revert, _ := cmd.Flags().GetBool("revert"); revert {
+			return revertKeyring(cmd)
+		}
+		return migrateToKeyring(cmd)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configKeyringCmd)
+	configKeyringCmd.Flags().Bool("revert", false, "将 API Key 从钥匙串写回配置文件")
+}
+
+// useKeyring 判断配置是否指定从系统钥匙串读取 API Key
+func useKeyring() bool {
+	return viper.GetString("api_key_source") == apiKeySourceKeyring
+}
+
+// loadKeyringAPIKey 在 api_key_source 为 keyring 且未通过参数、环境变量或配置设置 api_key 时，从系统钥匙串读取
+// 钥匙串中没有保存时不报错，交由首次使用引导重新配置
+func loadKeyringAPIKey() error {
+	if !useKeyring() || viper.GetString("api_key") != "" {
+		return nil
+	}
+	key, err := keyring.Get(keyringService, keyringUser)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("从系统钥匙串读取 API Key 失败（可通过 --api-key 临时指定）: %w", err)
+	}
+	viper.Set("api_key", key)
+	return nil
+}
+
+// storeKeyringAPIKey 将 API Key 保存到系统钥匙串
+func storeKeyringAPIKey(key string) error {
+	if err := keyring.Set(keyringService, keyringUser, key); err != nil {
+		return fmt.Errorf("保存到系统钥匙串失败: %w", err)
+	}
+	return nil
+}
+
+// migrateToKeyring 将配置文件中的 api_key 保存到钥匙串，并从配置文件中删除
+func migrateToKeyring(cmd *cobra.Command) error {
+	path, err := configFilePath(cmd)
+	if err != nil {
+		return err
+	}
+	root, err := loadConfigNode(path)
+	if err != nil {
+		return err
+	}
+	node := findConfigNode(root, []string{"api_key"})
+	if node == nil || node.Value == "" {
+		return fmt.Errorf("%s 中没有明文 api_key", path)
+	}
+
+	// 先写入钥匙串，成功后再修改配置文件，避免丢失 API Key
+	if err := storeKeyringAPIKey(node.Value); err != nil {
+		return err
+	}
+	err = updateConfigFile(path, func(root *yaml.Node) error {
+		unsetConfigNode(root, []string{"api_key"})
+		setConfigNode(root, []string{"api_key_source"}, stringNode(apiKeySourceKeyring))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔐 API Key 已保存到系统钥匙串，并从 %s 中删除\n", path)
+	return nil
+}
+
+// revertKeyring 将钥匙串中的 API Key 写回配置文件，并从钥匙串删除
+func revertKeyring(cmd *cobra.Command) error {
+	path, err := configFilePath(cmd)
+	if err != nil {
+		return err
+	}
+	key, err := keyring.Get(keyringService, keyringUser)
+	if err != nil {
+		return fmt.Errorf("从系统钥匙串读取 API Key 失败: %w", err)
+	}
+
+	err = updateConfigFile(path, func(root *yaml.Node) error {
+		unsetConfigNode(root, []string{"api_key_source"})
+		setConfigNode(root, []string{"api_key"}, stringNode(key))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := keyring.Delete(keyringService, keyringUser); err != nil {
+		return fmt.Errorf("从系统钥匙串删除 API Key 失败: %w", err)
+	}
+
+	fmt.Printf("✅ API Key 已写回 %s，并从系统钥匙串删除\n", path)
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// lsCmd 是 ls 子命令的定义
+var lsCmd = &cobra.Command{
+	Use:   "ls [path|file...]",
+	Short: "列出将被审查的文件（不调用 LLM）",
+	Long: `应用与 run 相同的扫描与过滤规则，列出将被审查的文件及数量，并估算 Token 与费用。
+用于在正式审查前调整 include/exclude 规则。
+
+  reviewer ls ./src --skip-tests
+  reviewer ls -q | reviewer run --stdin-files`,
+	ValidArgsFunction: completePaths,
+	PreRun:            func(cmd *cobra.Command, _ []string) { bindScanFlags(cmd) },
+	Run:               executeLs,
+}
+
+// executeLs 是 ls 命令的主执行函数
+func executeLs(cmd *cobra.Command, args []string) {
+	mergeProjectConfig(projectDir(args))
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	for _, task := range parseTasksFromArgs(cmd, args) {
+		files, skipped, err := scanTaskFiles(task, cfg)
+		if err != nil {
+			slog.Error("扫描失败", "path", task.Path, "err", err)
+			os.Exit(1)
+		}
+
+		// 只输出路径，便于通过管道传给 run --stdin-files
+		if quiet {
+			for _, path := range files {
+				fmt.Println(path)
+			}
+			continue
+		}
+
+		estimates := estimateFiles(files, cfg.MaxFileSize, task.Level)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, e := range estimates {
+			note := ""
+			if e.requests > 1 {
+				note = fmt.Sprintf("分 %d 段", e.requests)
+			}
+			fmt.Fprintf(w, "%s\t%s\t~%s Token\t%s\n", e.path, formatSize(e.size), formatTokens(e.prompt+e.completion), note)
+		}
+		w.Flush()
+
+		totals := sumEstimates(estimates)
+		fmt.Printf("\n📂 %s: 将审查 %d 个文件 (%s，%d 次请求，级别 %d)\n", task.ReportName, totals.files, formatSize(totals.size), totals.requests, task.Level)
+		fmt.Printf("🪙 预计 %s\n", totals.describe(cfg.Pricing))
+		if len(skipped) > 0 {
+			fmt.Printf("⏭️  另有 %d 个生成代码/第三方代码/压缩产物已跳过\n", len(skipped))
+		}
+		if !cfg.Pricing.Enabled() {
+			fmt.Println("💡 配置 price_input / price_output (每百万 Token 单价) 后可显示费用估算")
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(lsCmd)
+
+	addScanFlags(lsCmd)
+	lsCmd.Flags().BoolP("quiet", "q", false, "只输出文件路径")
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-ai-reviewer/internal/app/lsp"
+	"go-ai-reviewer/internal/app/textenc"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// lspSource 是诊断在编辑器中显示的来源
+const lspSource = "ai-review"
+
+// defaultLSPDebounce 是保存后等待的静默时间：审查会调用模型，连续保存只审查最后一次
+const defaultLSPDebounce = 2 * time.Second
+
+// lspCmd 是 lsp 子命令的定义
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "以语言服务器 (LSP) 运行，在编辑器中以诊断显示审查问题",
+	Long: `通过标准输入输出提供 Language Server Protocol 服务：文件保存后（防抖）审查该文件，
+问题按严重程度作为错误 / 警告 / 提示显示在编辑器中，无需专门的插件。
+也可以通过代码操作“AI 审查当前文件”（或命令 reviewer.reviewFile）按需审查任意文件。
+
+保存时只审查 --root 下扩展名在 include_exts 中的文件，内容未变化时不重复审查。使用 --root 的项目配置。
+
+  Neovim:  vim.lsp.start({name = "reviewer", cmd = {"reviewer", "lsp"}, root_dir = vim.fn.getcwd()})
+  Helix:   [language-server.reviewer] command = "reviewer", args = ["lsp"]`,
+	Args: cobra.NoArgs,
+	RunE: executeLSP,
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+
+	lspCmd.Flags().String("root", ".", "项目目录，保存时只审查该目录下的文件")
+	lspCmd.Flags().Duration("debounce", defaultLSPDebounce, "文件保存后等待的静默时间，期间的多次保存合并为一次审查")
+	lspCmd.Flags().Bool("on-save", true, "文件保存时自动审查 (关闭后只通过代码操作按需审查)")
+
+	mustRegisterCompletion(lspCmd, "root", completeDirs)
+}
+
+// executeLSP 是 lsp 命令的主执行函数
+// 标准输出用于协议消息，日志输出到标准错误（编辑器的语言服务日志）
+func executeLSP(cmd *cobra.Command, _ []string) error {
+	root, _ := cmd.Flags().GetString("root")
+	absRoot, err := filepath.Abs(root)
+	if err == nil {
+		absRoot, err = filepath.EvalSymlinks(absRoot)
+	}
+	if err != nil || !isValidPath(absRoot) {
+		return fmt.Errorf("目录不存在: %s", root)
+	}
+	mergeProjectConfig(projectDir([]string{absRoot}))
+	if err := validateStdioConfig(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+	r := &lspReviewer{root: absRoot, cfg: cfg, level: getValidLevel(viper.GetInt("level"))}
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+	opts := []lsp.Option{
+		lsp.WithDebounce(debounce),
+		lsp.WithConcurrency(r.cfg.Concurrency),
+		lsp.WithSaveFilter(r.reviewable),
+	}
+	if onSave, _ := cmd.Flags().GetBool("on-save"); !onSave {
+		opts = append(opts, lsp.WithoutSaveReview())
+	}
+	server := lsp.NewServer(mcpServerName, buildVersion(), r.review, opts...)
+	slog.Info("LSP 服务已启动", "root", absRoot)
+	return server.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// lspReviewer 审查编辑器中的文件并将问题转换为诊断
+type lspReviewer struct {
+	root  string // 项目目录（绝对路径）
+	cfg   reviewConfig
+	level int
+}
+
+// reviewable 判断保存时是否审查该文件：位于项目目录下、不在 reports/ 中，且扩展名在 include_exts 中
+func (r *lspReviewer) reviewable(path string) bool {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	rel, err := filepath.Rel(r.root, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return false
+	}
+	if first, _, _ := strings.Cut(filepath.ToSlash(rel), "/"); first == reportsDir {
+		return false
+	}
+	if len(r.cfg.IncludeExts) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, include := range r.cfg.IncludeExts {
+		if strings.ToLower("."+strings.TrimPrefix(include, ".")) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// review 审查文件内容，将不低于 min_severity 的问题转换为诊断，问题引用的行号超出范围时显示在第一行
+func (r *lspReviewer) review(ctx context.Context, path, content string) ([]lsp.Diagnostic, error) {
+	content = string(textenc.ToUTF8([]byte(content)))
+	res, err := reviewSnippet(ctx, r.cfg, filepath.Dir(path), relPath(r.root, path), content, r.level)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := llm.FilterIssues(res.Review.Issues, r.cfg.MinSeverity)
+	diagnostics := make([]lsp.Diagnostic, 0, len(issues))
+	for _, issue := range issues {
+		severity, text := llm.SplitIssue(issue)
+		diagnostics = append(diagnostics, lsp.Diagnostic{
+			Range:    lsp.LineRange(content, llm.IssueLine(text)),
+			Severity: lspSeverity(severity),
+			Code:     llm.IssueRule(issue),
+			Source:   lspSource,
+			Message:  text,
+		})
+	}
+	return diagnostics, nil
+}
+
+// lspSeverity 返回严重程度对应的诊断级别：严重为错误，重要为警告，一般为提示信息
+func lspSeverity(s llm.Severity) int {
+	switch s {
+	case llm.SeverityCritical:
+		return lsp.SeverityError
+	case llm.SeverityMinor:
+		return lsp.SeverityInformation
+	default:
+		return lsp.SeverityWarning
+	}
+}
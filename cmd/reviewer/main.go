@@ -3,23 +3,38 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go-ai-reviewer/internal/logging"
+	"go-ai-reviewer/internal/ui"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
 // 配置文件名常量
 const (
 	configFileName = ".code-review"
 	configFileType = "yaml"
+	envPrefix      = "REVIEWER"
 	defaultModel   = "deepseek-chat"
 )
 
 // 配置文件路径（通过 --config 指定）
 var cfgFile string
 
+// logOptions 是日志参数（-v / --quiet / --log-file）
+var logOptions logging.Options
+
+// loadedConfigs 记录已合并的配置文件（绝对路径），避免同一文件重复合并
+var loadedConfigs []string
+
 // rootCmd 是根命令
 var rootCmd = &cobra.Command{
 	Use:   "reviewer",
@@ -42,16 +57,27 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initLogging, initConfig, initTheme)
 
 	// 全局 Flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "配置文件路径 (默认: $HOME/.code-review.yaml)")
-	rootCmd.PersistentFlags().String("api-key", "", "LLM API Key (或通过环境变量 OPENAI_API_KEY 设置)")
+	rootCmd.PersistentFlags().String("api-key", "", "LLM API Key (或通过环境变量 REVIEWER_API_KEY / OPENAI_API_KEY 设置)")
 	rootCmd.PersistentFlags().String("model", defaultModel, "使用的 LLM 模型")
+	rootCmd.PersistentFlags().String("provider", "", "LLM Provider (留空为 OpenAI 兼容接口，mock 为离线模拟)")
+	rootCmd.PersistentFlags().CountVarP(&logOptions.Verbosity, "verbose", "v", "输出更多日志 (-v 信息，-vv 调试)")
+	rootCmd.PersistentFlags().BoolVar(&logOptions.Quiet, "quiet", false, "只输出错误日志")
+	rootCmd.PersistentFlags().StringVar(&logOptions.File, "log-file", "", "将全部级别的日志追加写入文件 (TUI 清屏后仍可排查解析错误、API 错误)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "关闭界面颜色 (也可设置环境变量 NO_COLOR)")
 
 	// 绑定到 Viper（init 阶段失败应该 panic）
 	mustBindPFlag("api_key", rootCmd.PersistentFlags().Lookup("api-key"))
 	mustBindPFlag("model", rootCmd.PersistentFlags().Lookup("model"))
+	mustBindPFlag("provider", rootCmd.PersistentFlags().Lookup("provider"))
+	mustBindPFlag("no_color", rootCmd.PersistentFlags().Lookup("no-color"))
+
+	// 参数值补全
+	mustRegisterCompletion(rootCmd, "provider", completeProviders)
+	mustRegisterCompletion(rootCmd, "config", completeConfigFiles)
 }
 
 // mustBindPFlag 绑定 flag 到 viper，失败时 panic
@@ -61,33 +87,161 @@ func mustBindPFlag(key string, flag *pflag.Flag) {
 	}
 }
 
+// initLogging 按命令行参数初始化日志，需在读取配置之前执行
+func initLogging() {
+	if err := logging.Setup(logOptions); err != nil {
+		slog.Warn("日志初始化失败", "err", err)
+	}
+}
+
 // initConfig 初始化配置
 func initConfig() {
 	// 统一设置配置文件类型
 	viper.SetConfigType(configFileType)
 
+	// 自动读取带前缀的环境变量：配置键转为大写并加 REVIEWER_ 前缀，如 max_file_size -> REVIEWER_MAX_FILE_SIZE
+	// 不使用裸变量名，避免与其他工具的 MODEL、LEVEL 等环境变量冲突
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
+
+	// API Key 额外兼容 OpenAI SDK 的通用变量，REVIEWER_API_KEY 优先
+	if err := viper.BindEnv("api_key", envPrefix+"_API_KEY", "OPENAI_API_KEY"); err != nil {
+		slog.Warn("绑定环境变量失败", "err", err)
+	}
+
 	if cfgFile != "" {
-		// 使用指定的配置文件
+		// 使用指定的配置文件，不再自动发现
 		viper.SetConfigFile(cfgFile)
-	} else {
-		// 查找默认配置文件位置
-		if home, err := os.UserHomeDir(); err == nil {
-			viper.AddConfigPath(home)
+		if err := viper.ReadInConfig(); err != nil {
+			slog.Warn("配置文件读取失败", "err", err)
 		}
-		viper.AddConfigPath(".")
-		viper.SetConfigName(configFileName)
+		return
 	}
 
-	// 自动读取环境变量
-	viper.AutomaticEnv()
+	// 按优先级从低到高合并：用户主目录 < 当前目录（目标项目目录在 run 解析参数后合并）
+	// 当前目录与目标项目目录的配置文件随仓库提交，只合并 projectConfigKeys 中的配置项
+	if path, ok := userConfigPath(); ok {
+		mergeConfigFile(path, false)
+	}
+	mergeConfigFile(configFileName+"."+configFileType, true)
+}
+
+// userConfigPath 返回用户主目录的配置文件路径，无法确定主目录时返回 false
+func userConfigPath() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, configFileName+"."+configFileType), true
+}
+
+// isProjectConfig 判断已加载的配置文件是否为项目级配置（不是 --config 指定的文件，也不是用户主目录的配置）
+func isProjectConfig(path string) bool {
+	if cfgFile != "" {
+		return false
+	}
+	user, ok := userConfigPath()
+	return !ok || path != user
+}
 
-	// 读取配置文件（文件不存在不报错，但格式错误需要提示）
-	if err := viper.ReadInConfig(); err != nil {
-		// 只有当配置文件存在但读取失败时才报错
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			fmt.Fprintf(os.Stderr, "⚠️ 配置文件读取失败: %v\n", err)
+// initTheme 按配置应用界面主题；设置了 NO_COLOR 环境变量（任意非空值）或 no_color 时关闭颜色
+func initTheme() {
+	if viper.GetBool("no_color") || os.Getenv("NO_COLOR") != "" {
+		ui.DisableColor()
+	}
+
+	theme, err := ui.LoadTheme(viper.GetString("theme"), viper.GetStringMapString("theme_colors"))
+	if err != nil {
+		slog.Warn("界面主题无效，使用默认主题", "err", err)
+		return
+	}
+	ui.ApplyTheme(theme)
+}
+
+// mergeProjectConfig 从目标项目目录向上查找配置文件（到仓库根目录为止），合并到已有配置之上
+// 使用 --config 指定配置文件时不做自动发现
+func mergeProjectConfig(dir string) {
+	if cfgFile != "" || dir == "" {
+		return
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+
+	for {
+		if mergeConfigFile(filepath.Join(dir, configFileName+"."+configFileType), true) {
+			return
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+// mergeConfigFile 将配置文件合并到已有配置之上，文件不存在时返回 false
+// 已合并过的文件直接返回 true；格式错误时提示并跳过；project 为 true 时只合并项目配置允许的配置项
+func mergeConfigFile(path string, project bool) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	if slices.Contains(loadedConfigs, abs) {
+		return true
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return false
+	}
+
+	if project {
+		err = mergeProjectConfigFile(abs)
+	} else {
+		viper.SetConfigFile(abs)
+		err = viper.MergeInConfig()
+	}
+	if err != nil {
+		slog.Warn("配置文件读取失败", "err", err)
+		return false
+	}
+	slog.Debug("已合并配置文件", "path", abs)
+	loadedConfigs = append(loadedConfigs, abs)
+	return true
+}
+
+// mergeProjectConfigFile 合并项目级配置文件中 projectConfigKeys 允许的配置项，忽略其他配置项并提示
+// 项目配置可能来自不受信任的仓库，不能改变接口地址、凭据与证书（会把用户的 API Key 或客户端证书发送到其他主机），
+// 也不能开启执行外部命令的功能
+func mergeProjectConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	allowed := make(map[string]any, len(values))
+	var ignored []string
+	for key, value := range values {
+		if projectConfigKeys[strings.ToLower(key)] {
+			allowed[key] = value
+		} else if isKnownConfigKey(key) {
+			ignored = append(ignored, key)
 		}
 	}
+	if len(ignored) > 0 {
+		slices.Sort(ignored)
+		slog.Warn("项目配置中的以下配置项只能在用户主目录配置、环境变量或命令行中设置，已忽略",
+			"path", path, "keys", strings.Join(ignored, ", "))
+	}
+	return viper.MergeConfigMap(allowed)
 }
 
 func main() {
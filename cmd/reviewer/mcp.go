@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"go-ai-reviewer/internal/app/mcp"
+	"go-ai-reviewer/internal/app/patch"
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/app/textenc"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// mcpServerName 是 MCP 服务在客户端中显示的名称
+const mcpServerName = "go-ai-reviewer"
+
+// maxReportText 是 get_report 返回的报告内容上限，超过时截断，避免占满模型的上下文
+const maxReportText = 256 * 1024
+
+// mcpInstructions 是提供给 MCP 客户端（模型）的使用说明
+const mcpInstructions = `AI 代码审查工具。review_file 审查项目中的单个文件；review_diff 审查未提交或待合并的修改（传入 git diff 的输出，建议 -U20 提供更多上下文）；` +
+	`list_reports 与 get_report 读取 reviewer run 生成的完整审查报告。审查会调用模型，单个文件通常需要数十秒。`
+
+// mcpCmd 是 mcp 子命令的定义
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "以 MCP (Model Context Protocol) 服务运行，供 AI 助手与 IDE 直接调用审查",
+	Long: `通过标准输入输出提供 MCP 服务，AI 助手（Claude Desktop、Cursor 等）可以调用以下工具：
+
+  review_file    审查 --root 下的单个文件
+  review_diff    审查 unified diff 中修改后的代码
+  list_reports   列出 --root 下 reports/ 中的报告
+  get_report     读取报告内容 (默认最新的报告)
+
+工具只能访问 --root 下的文件，使用启动时的配置 (含 --root 的项目配置)。客户端配置示例:
+
+  {"mcpServers": {"reviewer": {"command": "reviewer", "args": ["mcp", "--root", "/path/to/project"]}}}`,
+	Args: cobra.NoArgs,
+	RunE: executeMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+
+	mcpCmd.Flags().String("root", ".", "工具允许访问的项目目录，工具参数中的相对路径相对于该目录")
+
+	mustRegisterCompletion(mcpCmd, "root", completeDirs)
+}
+
+// executeMCP 是 mcp 命令的主执行函数
+// 标准输出用于协议消息，日志与提示均输出到标准错误
+func executeMCP(cmd *cobra.Command, _ []string) error {
+	root, _ := cmd.Flags().GetString("root")
+	absRoot, err := filepath.Abs(root)
+	if err == nil {
+		absRoot, err = filepath.EvalSymlinks(absRoot)
+	}
+	if err != nil || !isValidPath(absRoot) {
+		return fmt.Errorf("目录不存在: %s", root)
+	}
+	mergeProjectConfig(projectDir([]string{absRoot}))
+	if err := validateStdioConfig(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+	t := &mcpTools{root: absRoot, cfg: cfg}
+	server := mcp.NewServer(mcpServerName, buildVersion(), mcpInstructions, t.tools()...)
+	slog.Info("MCP 服务已启动", "root", absRoot)
+	return server.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// validateStdioConfig 校验以标准输入输出提供服务的命令（mcp、lsp）使用的审查配置
+// 标准输入输出被协议占用，无法交互式配置 API Key
+func validateStdioConfig() error {
+	if err := loadKeyringAPIKey(); err != nil {
+		return err
+	}
+	if viper.GetString("provider") != llm.ProviderMock && viper.GetString("api_key") == "" {
+		return errors.New("未设置 API Key，请运行 reviewer config set api_key <key> 或在客户端配置中设置环境变量 REVIEWER_API_KEY")
+	}
+	return validateReviewConfig()
+}
+
+// buildVersion 返回构建时的模块版本，本地构建时为 dev
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// mcpTools 实现 MCP 服务提供的工具
+type mcpTools struct {
+	root string // 工具允许访问的目录（绝对路径）
+	cfg  reviewConfig
+}
+
+// levelSchema 是工具参数中审查级别的 JSON Schema
+var levelSchema = map[string]any{
+	"type":        "integer",
+	"minimum":     minLevel,
+	"maximum":     maxLevel,
+	"description": "审查严格级别 1-6 (1 只报告严重问题，6 最严格)，省略时使用配置中的 level",
+}
+
+// tools 返回工具列表
+func (t *mcpTools) tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "review_file",
+			Description: "审查项目中的单个源码文件，返回评分、总结、亮点与按严重程度标注的问题 (Markdown)。",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path":  map[string]any{"type": "string", "description": "文件路径，相对于项目目录"},
+					"level": levelSchema,
+				},
+				"required": []string{"path"},
+			},
+			Handler: t.reviewFile,
+		},
+		{
+			Name: "review_diff",
+			Description: "审查 unified diff (如 git diff 的输出) 中修改后的代码：每个文件只审查修改块中的上下文与新增行，删除的文件被忽略。" +
+				"返回每个文件的评分与问题 (Markdown)。",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"diff":  map[string]any{"type": "string", "description": "unified diff 文本"},
+					"level": levelSchema,
+				},
+				"required": []string{"diff"},
+			},
+			Handler: t.reviewDiff,
+		},
+		{
+			Name:        "list_reports",
+			Description: "列出项目 reports/ 目录中 reviewer run 生成的审查报告，按修改时间从新到旧排列。",
+			InputSchema: map[string]any{"type": "object", "properties": map[string]any{}},
+			Handler:     t.listReports,
+		},
+		{
+			Name:        "get_report",
+			Description: "读取项目 reports/ 目录中的审查报告 (Markdown)，包含综合评分、问题统计与逐文件的审查结果。",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{"type": "string", "description": "报告名称 (不含 .md)，省略时读取最新的报告"},
+				},
+			},
+			Handler: t.getReport,
+		},
+	}
+}
+
+// reviewFile 实现 review_file 工具
+func (t *mcpTools) reviewFile(ctx context.Context, call *mcp.Call) (string, error) {
+	var args struct {
+		Path  string `json:"path"`
+		Level int    `json:"level"`
+	}
+	if err := call.Bind(&args); err != nil {
+		return "", err
+	}
+	if args.Path == "" {
+		return "", errors.New("缺少 path")
+	}
+	level, err := requestLevel(args.Level)
+	if err != nil {
+		return "", err
+	}
+	abs, err := resolveUnder(t.root, args.Path)
+	if err != nil {
+		return "", err
+	}
+	if !isValidFile(abs) {
+		return "", fmt.Errorf("文件不存在: %s", args.Path)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	res, err := reviewSnippet(ctx, t.cfg, filepath.Dir(abs), relPath(t.root, abs), string(textenc.ToUTF8(data)), level)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	reviewer.WriteResultMarkdown(&b, res, t.cfg.MinSeverity)
+	return b.String(), nil
+}
+
+// reviewDiff 实现 review_diff 工具：并发审查 diff 中各文件修改后的片段，结果按 diff 中的顺序输出
+func (t *mcpTools) reviewDiff(ctx context.Context, call *mcp.Call) (string, error) {
+	var args struct {
+		Diff  string `json:"diff"`
+		Level int    `json:"level"`
+	}
+	if err := call.Bind(&args); err != nil {
+		return "", err
+	}
+	level, err := requestLevel(args.Level)
+	if err != nil {
+		return "", err
+	}
+	patches, err := patch.Parse(args.Diff)
+	if err != nil {
+		return "", err
+	}
+	// 删除的文件 (+++ /dev/null) 与路径不安全的文件被忽略
+	patches = slices.DeleteFunc(patches, func(p patch.FilePatch) bool { return !filepath.IsLocal(filepath.FromSlash(p.Path)) })
+	if len(patches) == 0 {
+		return "", errors.New("diff 中没有新增或修改的文件")
+	}
+
+	results := make([]reviewer.Result, len(patches))
+	errs := make([]error, len(patches))
+	sem := make(chan struct{}, max(t.cfg.Concurrency, 1))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done := 0
+	for i, p := range patches {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// 文件所在目录存在时按该目录查找团队规范
+			dir := filepath.Join(t.root, filepath.Dir(filepath.FromSlash(p.Path)))
+			if !isValidPath(dir) {
+				dir = t.root
+			}
+			results[i], errs[i] = reviewSnippet(ctx, t.cfg, dir, p.Path, p.Fragment(), level)
+
+			mu.Lock()
+			done++
+			call.Progress(done, len(patches), p.Path)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# diff 审查结果 (%d 个文件)\n\n", len(patches))
+	b.WriteString("> 只审查了修改块中的上下文与新增行，问题的行号相对于片段而非原文件。\n\n")
+	failed := 0
+	for i, res := range results {
+		if errs[i] != nil {
+			failed++
+			fmt.Fprintf(&b, "## ⚠️ %s\n\n%s\n\n", patches[i].Path, errs[i])
+			continue
+		}
+		reviewer.WriteResultMarkdown(&b, res, t.cfg.MinSeverity)
+	}
+	if failed == len(patches) {
+		return "", fmt.Errorf("全部 %d 个文件审查失败: %w", failed, errs[0])
+	}
+	return b.String(), nil
+}
+
+// listReports 实现 list_reports 工具
+func (t *mcpTools) listReports(context.Context, *mcp.Call) (string, error) {
+	dir := filepath.Join(t.root, reportsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("读取报告目录失败: %w", err)
+	}
+
+	type report struct {
+		name string
+		info os.FileInfo
+	}
+	var reports []report
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".md")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			reports = append(reports, report{name, info})
+		}
+	}
+	if len(reports) == 0 {
+		return fmt.Sprintf("%s 中还没有报告，请先执行 reviewer run", reportsDir), nil
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].info.ModTime().After(reports[j].info.ModTime()) })
+
+	var b strings.Builder
+	for _, r := range reports {
+		fmt.Fprintf(&b, "- %s (%s, %s)\n", r.name, r.info.ModTime().Format("2006-01-02 15:04"), formatSize(r.info.Size()))
+	}
+	return b.String(), nil
+}
+
+// getReport 实现 get_report 工具，只能读取 reports/ 中的报告
+func (t *mcpTools) getReport(_ context.Context, call *mcp.Call) (string, error) {
+	var args struct {
+		Name string `json:"name"`
+	}
+	if err := call.Bind(&args); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(t.root, reportsDir)
+	var path string
+	if args.Name == "" {
+		latest, err := latestReport(dir)
+		if err != nil {
+			return "", err
+		}
+		path = latest
+	} else {
+		name := strings.TrimSuffix(args.Name, ".md")
+		if !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+			return "", fmt.Errorf("无效的报告名称: %s", args.Name)
+		}
+		path = filepath.Join(dir, name+".md")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("找不到报告 %s，可用 list_reports 查看已有的报告", args.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("读取报告失败: %w", err)
+	}
+	if len(data) > maxReportText {
+		text := strings.ToValidUTF8(string(data[:maxReportText]), "")
+		return text + fmt.Sprintf("\n\n> ✂️ 报告过长，只返回了前 %s，完整报告见 %s\n", formatSize(maxReportText), path), nil
+	}
+	return string(data), nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// 完成提醒方式（notify 配置）
+const (
+	notifyBell    = "bell"    // 终端响铃
+	notifyDesktop = "desktop" // 桌面通知
+	notifyBoth    = "both"    // 响铃并发送桌面通知
+)
+
+// 完成提醒的默认耗时阈值：运行时间短于该值时不提醒
+const defaultNotifyAfter = time.Minute
+
+// validNotify 判断 notify 配置是否有效，空字符串表示不提醒
+func validNotify(mode string) bool {
+	switch mode {
+	case "", notifyBell, notifyDesktop, notifyBoth:
+		return true
+	}
+	return false
+}
+
+// notifyDone 在运行耗时达到 notify_after 时按 notify 配置响铃或发送桌面通知，用户切换到其他窗口时也能知道审查已结束
+func notifyDone(start time.Time, message string) {
+	mode := viper.GetString("notify")
+	elapsed := time.Since(start)
+	if mode == "" || elapsed < viper.GetDuration("notify_after") {
+		return
+	}
+	message = fmt.Sprintf("%s，耗时 %s", message, elapsed.Round(time.Second))
+
+	if mode == notifyBell || mode == notifyBoth {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+	if mode == notifyDesktop || mode == notifyBoth {
+		if err := sendDesktopNotification("Go AI Code Reviewer", message); err != nil {
+			slog.Warn("发送桌面通知失败", "err", err)
+		}
+	}
+}
+
+// sendDesktopNotification 使用系统自带的工具发送桌面通知
+func sendDesktopNotification(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, '%s', '%s', 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()`, psQuote(title), psQuote(message))
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	default:
+		cmd = exec.Command("notify-send", "--app-name=reviewer", title, message)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w %s", cmd.Path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// psQuote 转义 PowerShell 单引号字符串中的单引号
+func psQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/cobra"
+)
+
+// openCmd 是 open 子命令的定义
+var openCmd = &cobra.Command{
+	Use:   "open [report]",
+	Short: "在浏览器中打开审查报告",
+	Long: `打开 reports/ 目录下最新生成的报告，或打开指定名称/路径的报告。
+默认将 Markdown 渲染为 HTML（写入报告旁的同名 .html 文件，文件链接保持可用）后用默认浏览器打开；
+--raw 直接用系统默认程序（通常是编辑器）打开 Markdown 文件。
+
+  reviewer open
+  reviewer open my-audit
+  reviewer open reports/my-audit.md --raw`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeReportNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		path, err := resolveReport(name)
+		if err != nil {
+			return err
+		}
+
+		if raw, _ := cmd.Flags().GetBool("raw"); !raw {
+			if path, err = writeHTMLReport(path); err != nil {
+				return err
+			}
+		}
+		if printOnly, _ := cmd.Flags().GetBool("print"); printOnly {
+			fmt.Println(path)
+			return nil
+		}
+
+		fmt.Printf("🌐 正在打开 %s\n", path)
+		return openWithDefaultApp(path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().Bool("raw", false, "直接打开 Markdown 文件，不渲染为 HTML")
+	openCmd.Flags().Bool("print", false, "只输出文件路径，不启动浏览器")
+}
+
+// resolveReport 返回要打开的报告路径：name 为空时选择 reports/ 中最新的报告，
+// 否则依次尝试文件路径与 reports/ 下的报告名称
+func resolveReport(name string) (string, error) {
+	if name == "" {
+		return latestReport(reportsDir)
+	}
+	if info, err := os.Stat(name); err == nil && !info.IsDir() {
+		return name, nil
+	}
+
+	path := filepath.Join(reportsDir, strings.TrimSuffix(name, ".md")+".md")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("找不到报告 %s (已查找 %s)", name, path)
+	}
+	return path, nil
+}
+
+// latestReport 返回报告目录 dir 中修改时间最新的 Markdown 报告
+func latestReport(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("读取报告目录失败: %w", err)
+	}
+
+	var latest string
+	var latestInfo os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) {
+			latest, latestInfo = filepath.Join(dir, entry.Name()), info
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("%s 目录中还没有报告，请先执行 reviewer run", dir)
+	}
+	return latest, nil
+}
+
+// writeHTMLReport 将 Markdown 报告渲染为同目录下的同名 .html 文件并返回其路径
+// HTML 已存在且不比报告旧时直接复用
+func writeHTMLReport(mdPath string) (string, error) {
+	htmlPath := strings.TrimSuffix(mdPath, filepath.Ext(mdPath)) + ".html"
+
+	mdInfo, err := os.Stat(mdPath)
+	if err != nil {
+		return "", fmt.Errorf("读取报告失败: %w", err)
+	}
+	if info, err := os.Stat(htmlPath); err == nil && !info.ModTime().Before(mdInfo.ModTime()) {
+		return htmlPath, nil
+	}
+
+	data, err := os.ReadFile(mdPath)
+	if err != nil {
+		return "", fmt.Errorf("读取报告失败: %w", err)
+	}
+	title := strings.TrimSuffix(filepath.Base(mdPath), filepath.Ext(mdPath))
+	if err := os.WriteFile(htmlPath, []byte(reviewer.RenderHTMLReport(string(data), title)), 0644); err != nil {
+		return "", fmt.Errorf("写入 HTML 报告失败: %w", err)
+	}
+	return htmlPath, nil
+}
+
+// openWithDefaultApp 使用系统默认程序打开文件，不等待程序退出
+func openWithDefaultApp(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", abs)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", abs)
+	default:
+		cmd = exec.Command("xdg-open", abs)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("无法启动默认程序 (%s)，请手动打开 %s: %w", cmd.Path, abs, err)
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,686 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/duplicates"
+	"go-ai-reviewer/internal/app/history"
+	"go-ai-reviewer/internal/app/lint"
+	"go-ai-reviewer/internal/app/projectctx"
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/app/scanner"
+	"go-ai-reviewer/internal/app/secrets"
+	"go-ai-reviewer/internal/app/testgaps"
+	"go-ai-reviewer/internal/llm"
+	"go-ai-reviewer/internal/logging"
+	"go-ai-reviewer/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// 报告输出目录
+const reportsDir = "reports"
+
+// errInterrupted 表示审查被用户中断
+var errInterrupted = errors.New("审查已被用户中断")
+
+// errSelectCanceled 表示用户在文件选择界面取消了审查
+var errSelectCanceled = errors.New("已取消文件选择")
+
+// runResources 是同一次运行中各任务共享的资源
+type runResources struct {
+	limiter  *reviewer.Limiter // 并行任务共享的全局限流器，顺序执行时为空
+	usage    *llm.Usage        // 全部任务累计的 Token 消耗，用于全局预算
+	deadline time.Time         // 整个运行的截止时间 (--timeout)，为零值时不限制
+	progress *jsonProgress     // --progress json 时输出 NDJSON 进度事件，为空时使用 TUI
+
+	// baseline 是对比或更新的基线，为空时不使用基线
+	// updateBaseline 为 true 时（reviewer baseline）用审查结果更新基线，否则只报告基线中没有的新问题
+	baseline       *reviewer.Baseline
+	updateBaseline bool
+
+	// audit 为 true 时（reviewer audit）以安全审计模式审查，报告旁生成 SARIF 文件
+	audit bool
+
+	// history 保存每个任务的审查记录，为空时不保存 (history_backend: off 或打开失败)
+	history history.Store
+
+	// diagnostics 汇总问题并在结束时以 reviewdog 诊断格式写入标准输出 (--format)，为空时不输出
+	diagnostics *diagnosticOutput
+
+	// rollup 收集各仓库的结果，在结束时生成组织级汇总报告 (run --all)，为空时不生成
+	rollup *repoRollup
+}
+
+// flush 写入所有任务结束后才生成的输出（reviewdog 诊断与组织级汇总报告），os.Exit 前需显式调用
+func (r runResources) flush() {
+	r.diagnostics.flush()
+	r.rollup.write()
+}
+
+// preparedTask 表示已完成扫描、可以开始审查的任务
+type preparedTask struct {
+	task    ReviewTask
+	engine  *reviewer.Engine
+	files   []string
+	skipped []reviewer.Result // 扫描阶段跳过、需要写入报告的文件
+	usage   *llm.Usage        // 本任务的 Token 消耗（同时累加到全局统计）
+	pricing llm.Pricing       // 审查模型的单价，未配置时进度界面只显示 Token 数
+	limiter *reviewer.Limiter // 控制并发的限流器，进度界面通过它暂停派发或调整并发
+
+	minSeverity   llm.Severity       // 报告与问题计数只包含不低于该严重程度的问题
+	minConfidence float64            // 审查时丢弃置信度低于该值的问题，写入报告头部
+	focus         []string           // 审查重点，写入报告
+	frameworks    []string           // 识别到的项目框架，写入报告
+	conventions   string             // 注入系统提示的团队规范文件，写入报告
+	rules         []llm.Rule         // 团队规则，报告中按规则统计
+	duplicates    []duplicates.Clone // 本地检测到的重复代码，写入报告
+	browse        bool               // 完成后提示进入结果浏览界面（browse_results）
+	sonar         bool               // 在报告旁生成 SonarQube 导入文件（sonar_report）
+
+	// untested 是没有对应测试文件的源文件，审查完成后按重要性筛选写入报告（为空时不检测）
+	untested          []string
+	testGapImportance float64 // 写入报告的最低重要性
+	suggestTests      bool    // 询问模型最需要补充测试的函数
+
+	executiveSummary bool // 审查完成后请模型生成执行摘要，写在报告最前面
+
+	webhooks   webhookConfig    // 审查完成后发送运行摘要的群机器人与通用 Webhook 配置
+	jira       jiraConfig       // 审查完成后为严重问题创建 Jira 工单的配置
+	confluence confluenceConfig // 审查完成后将报告发布到 Confluence 的配置
+	outputDir  string           // 报告输出目录，为空时为 reportsDir（serve 中为任务的工作目录）
+
+	baseline       *reviewer.Baseline // 对比或更新的基线，为空时不使用基线
+	updateBaseline bool               // 用审查结果更新基线，而不是过滤已有问题
+	audit          bool               // 安全审计报告
+
+	history history.Store // 保存审查记录的存储，为空时不保存
+	project string        // 审查记录所属的项目
+
+	diagnostics *diagnosticOutput // 汇总 reviewdog 诊断输出 (--format)，为空时不输出
+	rollup      *repoRollup       // 收集组织级汇总报告 (run --all)，为空时不收集
+
+	// onEvent 接收引擎事件（配额暂停等），需在 executeTask 之前设置
+	onEvent func(reviewer.Event)
+}
+
+// handleEvent 将引擎事件转发给 onEvent
+func (pt *preparedTask) handleEvent(ev reviewer.Event) {
+	if pt.onEvent != nil {
+		pt.onEvent(ev)
+	}
+}
+
+// eventMsg 将引擎事件转换为 TUI 消息，无需展示的事件返回 nil
+func eventMsg(ev reviewer.Event) tea.Msg {
+	switch ev.Kind {
+	case reviewer.EventPaused:
+		return ui.PausedMsg{Until: ev.Until}
+	case reviewer.EventResumed:
+		return ui.ResumedMsg{}
+	case reviewer.EventRetrying:
+		return ui.RetryMsg{}
+	default:
+		return nil
+	}
+}
+
+// 进度界面中失败原因的最大显示长度（按字符）
+const maxFileNoteRunes = 60
+
+// fileResultMsg 将审查结果转换为 TUI 的文件结果消息
+func fileResultMsg(res reviewer.Result, minSeverity llm.Severity) ui.FileResultMsg {
+	msg := ui.FileResultMsg{Path: res.FilePath, Reused: res.DuplicateOf != ""}
+	switch {
+	case res.Review != nil:
+		msg.Status, msg.Icon, msg.Score = ui.FileReviewed, reviewer.ScoreEmoji(res.Review.Score), res.Review.Score
+		msg.Issues = len(llm.FilterIssues(res.Review.Issues, minSeverity))
+	case res.Triage != nil:
+		msg.Status, msg.Icon, msg.Note = ui.FileTriaged, "🔎", "仅初筛"
+	case res.SkipReason == reviewer.SkipReasonTimeout || res.SkipReason == reviewer.SkipReasonReadErr:
+		// 单文件超时与读取失败计入失败，在进度界面的错误面板中列出
+		msg.Status, msg.Icon, msg.Note = ui.FileFailed, "❌", res.SkipReason.Text()
+	case res.SkipReason != reviewer.SkipReasonNone && res.SkipReason.Text() != "":
+		msg.Status, msg.Icon, msg.Note = ui.FileSkipped, "⏭️ ", "跳过: "+res.SkipReason.Text()
+	default:
+		msg.Status, msg.Icon, msg.Note = ui.FileFailed, "❌", "审查失败"
+		if res.Error != nil {
+			msg.Note = shortError(res.Error)
+		}
+	}
+	return msg
+}
+
+// usageMsg 汇总当前的 Token 消耗与费用，供进度界面显示
+func usageMsg(usage *llm.Usage, pricing llm.Pricing) ui.UsageMsg {
+	msg := ui.UsageMsg{
+		Prompt:     usage.PromptTokens(),
+		Completion: usage.CompletionTokens(),
+		Priced:     pricing.Enabled(),
+	}
+	if msg.Priced {
+		msg.Cost = pricing.Cost(msg.Prompt, msg.Completion)
+	}
+	return msg
+}
+
+// shortError 返回错误信息的第一行，过长时截断
+func shortError(err error) string {
+	text, _, _ := strings.Cut(err.Error(), "\n")
+	if runes := []rune(text); len(runes) > maxFileNoteRunes {
+		text = string(runes[:maxFileNoteRunes]) + "…"
+	}
+	return text
+}
+
+// taskOutcome 表示审查任务的执行结果
+type taskOutcome struct {
+	reportPath  string
+	partial     bool
+	err         error
+	duration    time.Duration
+	issuesCount int
+
+	// results 与 meta 是生成报告所用的全部结果与报告元信息，供 serve 输出 JSON 结果
+	results []reviewer.Result
+	meta    reviewer.ReportMeta
+}
+
+// runReviewTask 执行单个审查任务
+func runReviewTask(ctx context.Context, task ReviewTask, shared runResources) error {
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+	pt, err := prepareReviewTask(ctx, task, cfg, shared)
+	if errors.Is(err, errSelectCanceled) {
+		fmt.Printf("🚫 已跳过 %s\n", task.ReportName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(pt.files) == 0 {
+		if shared.progress != nil {
+			shared.progress.runDone(task.ReportName, taskOutcome{}, 0, usageMsg(pt.usage, pt.pricing))
+		}
+		if len(task.Files) > 0 {
+			fmt.Println("🎉 文件列表中没有需要审查的文件")
+			return nil
+		}
+		fmt.Printf("🎉 目录 %s 中没有需要审查的文件\n", task.Path)
+		return nil
+	}
+
+	if shared.progress != nil {
+		return runWithJSON(ctx, pt, shared.progress)
+	}
+	// 启动 TUI 和后台任务
+	return runWithTUI(ctx, pt)
+}
+
+// newRunLimiter 创建审查使用的限流器：开启自适应并发时根据限流情况自动调整，否则固定为 concurrency
+// 两种模式都可以在进度界面中手动调整，最大为 max_concurrency
+func newRunLimiter(cfg reviewConfig) *reviewer.Limiter {
+	maxLimit := max(cfg.MaxConcurrency, cfg.Concurrency)
+	if cfg.AdaptiveConcurrency {
+		return reviewer.NewLimiter(cfg.Concurrency, maxLimit)
+	}
+	return reviewer.NewFixedLimiter(cfg.Concurrency, maxLimit)
+}
+
+// prepareReviewTask 扫描目录（或使用显式文件列表）并初始化审查引擎
+// shared 中的限流器与 Token 统计在同一次运行的所有任务之间共享
+func prepareReviewTask(ctx context.Context, task ReviewTask, cfg reviewConfig, shared runResources) (*preparedTask, error) {
+	// 无效的策略不能按 warn 处理，否则 block 拼写错误时会照常发送含密钥的代码
+	if !validSecretScan(cfg.SecretScan) {
+		return nil, errors.New(secretScanError(cfg.SecretScan))
+	}
+	pt := &preparedTask{
+		task:          task,
+		usage:         llm.NewUsage(shared.usage),
+		pricing:       cfg.pricingFor(cfg.Model),
+		minSeverity:   cfg.MinSeverity,
+		minConfidence: cfg.MinConfidence,
+		focus:         cfg.Focus,
+		rules:         cfg.Rules,
+		browse:        cfg.BrowseResults,
+		sonar:         cfg.Sonar,
+
+		executiveSummary: cfg.ExecutiveSummary,
+		webhooks:         cfg.Webhooks,
+		jira:             cfg.Jira,
+		confluence:       cfg.Confluence,
+
+		baseline:       shared.baseline,
+		updateBaseline: shared.updateBaseline,
+		audit:          shared.audit,
+
+		history: shared.history,
+		project: taskProject(task.Path),
+
+		diagnostics: shared.diagnostics,
+		rollup:      shared.rollup,
+	}
+
+	// repos 中的仓库可以在全局排除目录之外额外排除目录
+	if len(task.ExcludeDirs) > 0 {
+		cfg.ExcludeDirs = append(slices.Clone(cfg.ExcludeDirs), task.ExcludeDirs...)
+	}
+
+	// 1. 确定待审查文件
+	files, skipped, err := scanTaskFiles(task, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if task.Select && len(files) > 0 {
+		if files, err = selectTaskFiles(task, files); err != nil {
+			return nil, err
+		}
+	}
+	pt.files, pt.skipped = files, skipped
+	if len(files) == 0 {
+		return pt, nil
+	}
+
+	// 2. 初始化 LLM 客户端和引擎
+	clientOpts := []llm.ClientOption{llm.WithUsage(pt.usage)}
+	if opt, note := conventionsOption(task.Path, cfg); opt != nil {
+		clientOpts = append(clientOpts, opt)
+		pt.conventions = note
+	}
+	if cfg.PromptFile != "" {
+		prompt, err := llm.LoadPromptFile(cfg.PromptFile)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts = append(clientOpts, llm.WithPrompt(prompt))
+	}
+	client, err := newLLMClient(cfg, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("初始化 LLM 客户端失败: %w", err)
+	}
+
+	engineOpts := []reviewer.Option{
+		reviewer.WithMaxFileSize(cfg.MaxFileSize),
+		reviewer.WithFileTimeout(cfg.FileTimeout),
+		reviewer.WithEventHandler(pt.handleEvent),
+	}
+	pt.limiter = shared.limiter
+	if pt.limiter == nil {
+		pt.limiter = newRunLimiter(cfg)
+	}
+	engineOpts = append(engineOpts, reviewer.WithLimiter(pt.limiter))
+	if cfg.ProjectContext {
+		pc := projectctx.Build(task.Path, files)
+		pt.frameworks = pc.Frameworks()
+		engineOpts = append(engineOpts, reviewer.WithProjectContext(pc))
+	}
+	if cfg.StaticAnalysis {
+		engineOpts = append(engineOpts, reviewer.WithLint(lint.Run(ctx, task.Path, files)))
+	}
+	if cfg.DuplicateCode {
+		pt.duplicates = duplicates.Detect(files, cfg.DuplicateMinLines)
+	}
+	if cfg.MissingTests && len(task.Files) == 0 && !shared.audit {
+		tests, err := scanTestFiles(task.Path, cfg)
+		if err != nil {
+			return nil, err
+		}
+		pt.untested = testgaps.Untested(files, tests)
+		pt.testGapImportance, pt.suggestTests = cfg.MissingTestsImportance, cfg.SuggestTests
+	}
+	if cfg.Dedupe {
+		engineOpts = append(engineOpts, reviewer.WithDedupe())
+	}
+	if cfg.Minify {
+		engineOpts = append(engineOpts, reviewer.WithMinify())
+	}
+	if cfg.Fix {
+		engineOpts = append(engineOpts, reviewer.WithFix())
+	}
+	if shared.audit {
+		engineOpts = append(engineOpts, reviewer.WithAudit())
+	}
+	if cfg.SecretScan != secretScanOff {
+		engineOpts = append(engineOpts, reviewer.WithSecretScan(cfg.SecretScan == secretScanBlock))
+	}
+	if cfg.Redact {
+		redactor, err := secrets.NewRedactor(cfg.RedactPatterns)
+		if err != nil {
+			return nil, err
+		}
+		engineOpts = append(engineOpts, reviewer.WithRedaction(redactor))
+	}
+	if cfg.Prioritize {
+		engineOpts = append(engineOpts, reviewer.WithPrioritize())
+	}
+	if len(cfg.Importance) > 0 {
+		engineOpts = append(engineOpts, reviewer.WithImportanceRules(cfg.Importance))
+	}
+	if len(cfg.Rules) > 0 || len(cfg.SuppressRules) > 0 {
+		engineOpts = append(engineOpts, reviewer.WithRules(cfg.Rules, cfg.SuppressRules))
+	}
+	if cfg.Triage {
+		// 未配置初筛模型时沿用主模型
+		triageCfg := cfg
+		if cfg.TriageModel != "" {
+			triageCfg.Model = cfg.TriageModel
+		}
+		triageClient, err := newLLMClient(triageCfg, llm.WithUsage(pt.usage))
+		if err != nil {
+			return nil, fmt.Errorf("初始化初筛客户端失败: %w", err)
+		}
+		engineOpts = append(engineOpts, reviewer.WithTriage(triageClient, cfg.TriageRatio))
+	}
+	if cfg.RetryRounds > 0 {
+		engineOpts = append(engineOpts, reviewer.WithRetryQueue(cfg.RetryRounds, cfg.RetryBackoff))
+	}
+	if cfg.Reverify {
+		engineOpts = append(engineOpts, reviewer.WithReverify(cfg.ReverifyScore, cfg.ReverifyConfidence))
+	}
+	if cfg.MinConfidence > 0 {
+		engineOpts = append(engineOpts, reviewer.WithMinConfidence(cfg.MinConfidence))
+	}
+	if cfg.QuotaPause {
+		engineOpts = append(engineOpts, reviewer.WithQuotaPause(cfg.QuotaCooldown, cfg.QuotaMaxPauses))
+	}
+	if cfg.MaxTokensTotal > 0 && shared.usage != nil {
+		engineOpts = append(engineOpts, reviewer.WithTokenBudget(shared.usage, cfg.MaxTokensTotal))
+	}
+	if !shared.deadline.IsZero() {
+		engineOpts = append(engineOpts, reviewer.WithDeadline(shared.deadline))
+	}
+
+	pt.engine, err = reviewer.NewEngine(client, cfg.Concurrency, task.Level, engineOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("初始化引擎失败: %w", err)
+	}
+	return pt, nil
+}
+
+// scanTaskFiles 返回任务的待审查文件与扫描阶段跳过的文件
+// 显式文件列表不经过扫描器过滤
+func scanTaskFiles(task ReviewTask, cfg reviewConfig) ([]string, []reviewer.Result, error) {
+	if len(task.Files) > 0 {
+		return scanner.FilterFiles(task.Files), nil, nil
+	}
+
+	scn, err := scanner.NewScanner(task.Path, cfg.IncludeExts,
+		scanner.WithExcludeDirs(cfg.ExcludeDirs),
+		scanner.WithSkipGenerated(cfg.SkipGenerated),
+		scanner.WithFollowSymlinks(cfg.FollowSymlinks),
+		scanner.WithModifiedSince(cfg.Since),
+		scanner.WithMaxDepth(cfg.MaxDepth),
+		scanner.WithIncludeHidden(cfg.IncludeHidden),
+		scanner.WithSkipTests(!cfg.IncludeTests),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("初始化扫描器失败: %w", err)
+	}
+	files, err := scn.Scan()
+	if err != nil {
+		return nil, nil, fmt.Errorf("扫描目录失败: %w", err)
+	}
+	return files, scannerSkips(scn.Skipped()), nil
+}
+
+// scanTestFiles 扫描目录中的测试代码，用于识别缺少测试的文件
+// 不受 since、max_depth 与 include_tests 的影响：这些设置缩小的是审查范围，而不是已有的测试
+func scanTestFiles(root string, cfg reviewConfig) ([]string, error) {
+	scn, err := scanner.NewScanner(root, testgaps.SourceExts(),
+		scanner.WithExcludeDirs(cfg.ExcludeDirs),
+		scanner.WithFollowSymlinks(cfg.FollowSymlinks),
+		scanner.WithIncludeHidden(cfg.IncludeHidden),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("初始化扫描器失败: %w", err)
+	}
+	files, err := scn.Scan()
+	if err != nil {
+		return nil, fmt.Errorf("扫描测试代码失败: %w", err)
+	}
+
+	var tests []string
+	for _, file := range files {
+		if rel, err := filepath.Rel(root, file); err == nil && scanner.IsTestFile(rel) {
+			tests = append(tests, file)
+		}
+	}
+	return tests, nil
+}
+
+// selectTaskFiles 显示文件选择界面，返回用户勾选的文件
+func selectTaskFiles(task ReviewTask, files []string) ([]string, error) {
+	root := task.Path
+	if len(task.Files) > 0 {
+		root = "."
+	}
+
+	logging.Pause()
+	finalModel, err := tea.NewProgram(ui.NewSelectModel(root, files), tea.WithAltScreen()).Run()
+	logging.Resume()
+	if err != nil {
+		return nil, fmt.Errorf("文件选择界面运行失败: %w", err)
+	}
+
+	m, ok := finalModel.(ui.SelectModel)
+	if !ok || !m.Confirmed() {
+		return nil, errSelectCanceled
+	}
+	selected := m.Selected()
+	if len(selected) < len(files) {
+		fmt.Printf("☑️  已选择 %d/%d 个文件\n", len(selected), len(files))
+	}
+	return selected, nil
+}
+
+// scannerSkips 将扫描阶段跳过的文件转换为审查结果，以便在报告中说明
+func scannerSkips(skipped []scanner.SkippedFile) []reviewer.Result {
+	results := make([]reviewer.Result, 0, len(skipped))
+	for _, file := range skipped {
+		reason := reviewer.SkipReasonGenerated
+		switch file.Reason {
+		case scanner.SkipVendored:
+			reason = reviewer.SkipReasonVendored
+		case scanner.SkipMinified:
+			reason = reviewer.SkipReasonMinified
+		}
+		var size int64
+		if info, err := os.Stat(file.Path); err == nil {
+			size = info.Size()
+		}
+		results = append(results, reviewer.Result{FilePath: file.Path, FileSize: size, SkipReason: reason})
+	}
+	return results
+}
+
+// executeTask 执行审查并生成报告，onResult 在每个结果到达时回调
+// ctx 被取消时基于已完成的结果生成部分报告
+func executeTask(ctx context.Context, pt *preparedTask, onResult func(reviewer.Result)) taskOutcome {
+	startTime := time.Now()
+	defer registerEngine(pt.task.ReportName, pt.engine)()
+	results := pt.engine.Start(ctx, pt.files)
+
+	allResults := append([]reviewer.Result{}, pt.skipped...)
+	var issuesCount int
+
+	// 对比基线时只保留基线中没有的新问题
+	var baseline *reviewer.BaselineSummary
+	if pt.baseline != nil && !pt.updateBaseline {
+		baseline = &reviewer.BaselineSummary{Path: pt.baseline.Path()}
+	}
+
+	for res := range results {
+		if baseline != nil {
+			res = pt.baseline.Filter(res, baseline)
+		}
+		onResult(res)
+		allResults = append(allResults, res)
+		if res.Review != nil {
+			issuesCount += len(llm.FilterIssues(res.Review.Issues, pt.minSeverity))
+		}
+	}
+
+	// 挑出缺少测试的重要文件，按需询问模型测试重点（被中断时不再询问）
+	var testGaps []reviewer.TestGap
+	if len(pt.untested) > 0 {
+		testGaps = reviewer.FindTestGaps(allResults, pt.untested, pt.testGapImportance)
+		if pt.suggestTests && len(testGaps) > 0 && ctx.Err() == nil {
+			pt.engine.SuggestTests(ctx, testGaps)
+		}
+	}
+
+	duration := time.Since(startTime)
+	metrics := pt.engine.Metrics()
+
+	if pt.updateBaseline {
+		pt.baseline.Update(allResults)
+		if err := pt.baseline.Save(); err != nil {
+			slog.Error("更新基线失败", "err", err)
+		}
+	}
+
+	// 生成报告（被中断时标记为部分报告）
+	partial := ctx.Err() != nil
+	meta := reviewer.ReportMeta{
+		Name:       pt.task.ReportName,
+		Level:      pt.engine.GetLevel(),
+		Partial:    partial,
+		Unreviewed: len(pt.files) + len(pt.skipped) - len(allResults),
+		Metrics:    &metrics,
+		Baseline:   baseline,
+
+		MinSeverity:   pt.minSeverity,
+		MinConfidence: pt.minConfidence,
+		Focus:         pt.focus,
+		Frameworks:    pt.frameworks,
+		Conventions:   pt.conventions,
+		Rules:         pt.rules,
+		Duplicates:    pt.duplicates,
+		TestGaps:      testGaps,
+		Audit:         pt.audit,
+		Sonar:         pt.sonar,
+	}
+
+	// 请模型根据汇总数据生成执行摘要（被中断时不生成，失败时报告照常生成）
+	if pt.executiveSummary && !partial {
+		summary, err := pt.engine.ExecutiveSummary(ctx, allResults, meta)
+		if err != nil {
+			slog.Warn("生成执行摘要失败", "err", err)
+		}
+		meta.Summary = summary
+		duration = time.Since(startTime)
+	}
+	meta.Tokens = pt.usage.Total()
+
+	outputDir := pt.outputDir
+	if outputDir == "" {
+		outputDir = reportsDir
+	}
+	reportPath, err := reviewer.GenerateMarkdownReport(allResults, duration, outputDir, meta)
+	if err == nil && pt.history != nil {
+		saveHistory(ctx, pt.history, newHistoryRun(pt.project, allResults, meta, reportPath, duration))
+	}
+	if err == nil && pt.diagnostics != nil {
+		pt.diagnostics.add(allResults, meta)
+	}
+	if err == nil {
+		pt.rollup.add(pt.task.Path, allResults, meta, reportPath)
+	}
+	if err == nil && !partial {
+		postRunSummary(ctx, pt.webhooks, pt.project, allResults, meta, duration, reportPath)
+		fileJiraIssues(ctx, pt.jira, pt.project, pt.task.Path, allResults, pt.webhooks.reportLink(reportPath))
+		publishConfluence(ctx, pt.confluence, pt.project, pt.task.ReportName, reportPath, pt.webhooks.reportLink(reportPath))
+	}
+
+	return taskOutcome{
+		reportPath:  reportPath,
+		partial:     partial,
+		err:         err,
+		duration:    duration,
+		issuesCount: issuesCount,
+		results:     allResults,
+		meta:        meta,
+	}
+}
+
+// runWithTUI 启动 TUI 界面并执行审查
+// 用户中断（Ctrl+C 或 SIGINT/SIGTERM）时停止引擎，并基于已完成的结果生成部分报告
+func runWithTUI(ctx context.Context, pt *preparedTask) error {
+	// 只有在终端中交互运行时才提示浏览结果，避免在管道或 CI 中等待按键
+	p := tea.NewProgram(ui.NewModel(len(pt.files), pt.limiter, pt.browse && isInteractive()))
+	doneCh := make(chan taskOutcome, 1)
+	var results []reviewer.Result // 审查结果，供完成后的浏览界面使用（在 doneCh 返回后读取）
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pt.onEvent = func(ev reviewer.Event) {
+		if msg := eventMsg(ev); msg != nil {
+			p.Send(msg)
+		}
+	}
+
+	// 后台执行审查逻辑
+	go func() {
+		outcome := executeTask(taskCtx, pt, func(res reviewer.Result) {
+			results = append(results, res)
+			p.Send(usageMsg(pt.usage, pt.pricing))
+			p.Send(fileResultMsg(res, pt.minSeverity))
+		})
+
+		reportMsg := outcome.reportPath
+		if outcome.err != nil {
+			reportMsg = fmt.Sprintf("报告生成失败: %v", outcome.err)
+		}
+
+		p.Send(ui.DoneMsg{
+			Duration:    outcome.duration,
+			ReportPath:  reportMsg,
+			IssuesCount: outcome.issuesCount,
+		})
+
+		doneCh <- outcome
+	}()
+
+	// 启动 TUI（阻塞），期间的日志暂存，退出后输出
+	logging.Pause()
+	finalModel, err := p.Run()
+	logging.Resume()
+	// 界面已退出，恢复被暂停的派发，避免后台任务一直等待
+	pt.limiter.SetPaused(false)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("TUI 运行失败: %w", err)
+	}
+
+	// 用户在 TUI 中按下 Ctrl+C：停止引擎，等待部分报告生成
+	m, _ := finalModel.(ui.Model)
+	if m.Interrupted() {
+		cancel()
+	}
+
+	// 引擎会响应 ctx 取消并尽快收尾，因此这里总能等到报告生成完毕
+	outcome := <-doneCh
+	if outcome.err != nil {
+		return outcome.err
+	}
+	if outcome.partial {
+		fmt.Printf("📄 已根据已完成的结果生成部分报告: %s\n", outcome.reportPath)
+		return errInterrupted
+	}
+	if m.Browse() {
+		return browseResults(results, outcome.reportPath, pt.minSeverity)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/ui"
+)
+
+// 进度输出模式（--progress）
+const (
+	progressTUI  = "tui"  // 终端界面（默认）
+	progressJSON = "json" // 每行一个 JSON 事件 (NDJSON)，供 IDE 插件与脚本读取
+)
+
+// fileStatusNames 是 JSON 事件中的文件状态名称
+var fileStatusNames = map[ui.FileStatus]string{
+	ui.FileReviewed: "reviewed",
+	ui.FileFailed:   "failed",
+	ui.FileSkipped:  "skipped",
+	ui.FileTriaged:  "triaged",
+}
+
+// progressEvent 是 --progress json 输出的一行事件，不同事件只填写相关字段
+type progressEvent struct {
+	Event string    `json:"event"` // file_started / file_retrying / file_done / paused / resumed / run_done
+	Time  time.Time `json:"time"`
+	Task  string    `json:"task"` // 任务名称（报告名）
+
+	// file_started / file_retrying / file_done
+	Path    string `json:"path,omitempty"`
+	Attempt int    `json:"attempt,omitempty"` // 已重试的次数
+	Status  string `json:"status,omitempty"`  // reviewed / failed / skipped / triaged
+	Score   *int   `json:"score,omitempty"`   // 仅 reviewed
+	Issues  *int   `json:"issues,omitempty"`  // 仅 reviewed，已按 min_severity 过滤
+	Note    string `json:"note,omitempty"`    // 失败、跳过或重试的原因
+	Reused  bool   `json:"reused,omitempty"`  // 内容与其他文件相同，复用了其审查结果
+
+	// file_done / run_done
+	Completed int `json:"completed,omitempty"`
+	Total     int `json:"total,omitempty"`
+
+	// paused
+	Until *time.Time `json:"until,omitempty"`
+
+	// run_done
+	Report           string   `json:"report,omitempty"`
+	Partial          bool     `json:"partial,omitempty"`
+	DurationMS       int64    `json:"duration_ms,omitempty"`
+	IssuesTotal      *int     `json:"issues_total,omitempty"`
+	PromptTokens     int64    `json:"prompt_tokens,omitempty"`
+	CompletionTokens int64    `json:"completion_tokens,omitempty"`
+	Cost             *float64 `json:"cost,omitempty"` // 仅配置了模型单价时输出
+	Error            string   `json:"error,omitempty"`
+}
+
+// jsonProgress 将进度事件逐行写入文件描述符，可在多个 goroutine 中并发使用
+type jsonProgress struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// newJSONProgress 创建写入文件描述符 fd 的进度输出
+// fd 为标准输出时，其余提示信息改为输出到标准错误，保证标准输出中只有 JSON 事件
+func newJSONProgress(fd int) (*jsonProgress, error) {
+	if fd == int(os.Stdout.Fd()) {
+		w := os.Stdout
+		os.Stdout = os.Stderr
+		return &jsonProgress{enc: json.NewEncoder(w)}, nil
+	}
+
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd%d", fd))
+	if f == nil {
+		return nil, fmt.Errorf("无效的文件描述符 %d", fd)
+	}
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("文件描述符 %d 不可用: %w", fd, err)
+	}
+	return &jsonProgress{enc: json.NewEncoder(f)}, nil
+}
+
+// emit 写入一行事件，写入失败（如读取方已退出）只记录日志，不影响审查
+func (p *jsonProgress) emit(ev progressEvent) {
+	ev.Time = time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.enc.Encode(ev); err != nil {
+		slog.Debug("写入进度事件失败", "event", ev.Event, "err", err)
+	}
+}
+
+// engineEvent 输出引擎事件：开始审查文件、配额暂停与恢复
+func (p *jsonProgress) engineEvent(task string, ev reviewer.Event) {
+	switch ev.Kind {
+	case reviewer.EventFileStarted:
+		p.emit(progressEvent{Event: "file_started", Task: task, Path: ev.Path, Attempt: ev.Attempt})
+	case reviewer.EventRetrying:
+		p.emit(progressEvent{Event: "file_retrying", Task: task, Path: ev.Path, Attempt: ev.Attempt, Note: shortError(ev.Err)})
+	case reviewer.EventPaused:
+		p.emit(progressEvent{Event: "paused", Task: task, Until: &ev.Until})
+	case reviewer.EventResumed:
+		p.emit(progressEvent{Event: "resumed", Task: task})
+	}
+}
+
+// fileDone 输出文件处理完毕事件，completed 为本任务已完成的文件数
+func (p *jsonProgress) fileDone(task string, msg ui.FileResultMsg, completed, total int) {
+	ev := progressEvent{
+		Event:     "file_done",
+		Task:      task,
+		Path:      msg.Path,
+		Status:    fileStatusNames[msg.Status],
+		Note:      msg.Note,
+		Completed: completed,
+		Total:     total,
+	}
+	if msg.Status == ui.FileReviewed {
+		ev.Score, ev.Issues = &msg.Score, &msg.Issues
+	}
+	ev.Reused = msg.Reused
+	p.emit(ev)
+}
+
+// runDone 输出任务结束事件（每个任务一条），包含报告路径、问题数与 Token 消耗
+func (p *jsonProgress) runDone(task string, outcome taskOutcome, total int, usage ui.UsageMsg) {
+	ev := progressEvent{
+		Event:            "run_done",
+		Task:             task,
+		Total:            total,
+		Report:           outcome.reportPath,
+		Partial:          outcome.partial,
+		DurationMS:       outcome.duration.Milliseconds(),
+		IssuesTotal:      &outcome.issuesCount,
+		PromptTokens:     usage.Prompt,
+		CompletionTokens: usage.Completion,
+	}
+	if usage.Priced {
+		ev.Cost = &usage.Cost
+	}
+	if outcome.err != nil {
+		ev.Error = outcome.err.Error()
+	}
+	p.emit(ev)
+}
+
+// runWithJSON 执行审查并以 NDJSON 输出进度事件（--progress json），不启动 TUI
+// 收到 SIGINT/SIGTERM 时停止引擎，并基于已完成的结果生成部分报告
+func runWithJSON(ctx context.Context, pt *preparedTask, out *jsonProgress) error {
+	name, total := pt.task.ReportName, len(pt.files)
+	pt.onEvent = func(ev reviewer.Event) {
+		out.engineEvent(name, ev)
+	}
+
+	var completed int
+	outcome := executeTask(ctx, pt, func(res reviewer.Result) {
+		completed++
+		out.fileDone(name, fileResultMsg(res, pt.minSeverity), completed, total)
+	})
+	out.runDone(name, outcome, total, usageMsg(pt.usage, pt.pricing))
+
+	if outcome.err != nil {
+		return outcome.err
+	}
+	if outcome.partial {
+		fmt.Printf("📄 已根据已完成的结果生成部分报告: %s\n", outcome.reportPath)
+		return errInterrupted
+	}
+	return nil
+}
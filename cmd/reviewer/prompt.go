@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+)
+
+// promptFileHeader 是导出的提示文件开头的说明，列出模板中可用的变量
+const promptFileHeader = `# 自定义审查提示 (在配置中设置 prompt_file: <本文件路径> 后生效)
+# system: 系统提示模板 (Go text/template 语法)，省略时使用内置模板
+#   {{.Level}}             审查严格级别 (1-6)
+#   {{.LevelDescription}}  当前级别的描述 (来自下方 levels 或内置描述)
+#   {{.FilePath}}          待审查文件的路径
+#   {{.Focus}}             --focus 的审查重点说明 (未设置时为空)；模板中未引用时自动追加
+#   {{.Language}}          识别出的文件语言，如 "TypeScript、React" (未识别时为空)
+#   {{.LanguageGuide}}     按文件语言注入的语言要点 (来自下方 languages 或内置要点)；模板中未引用时自动追加
+#   {{.Conventions}}       项目根目录 CONVENTIONS.md / .review-guidelines.md 中的团队规范 (未找到时为空)；模板中未引用时自动追加
+#   {{.Rules}}             配置项 rules 与规则目录中适用于当前文件的团队规则 (没有适用的规则时为空)；模板中未引用时自动追加
+#   {{.Examples}}          配置项 examples 中的少样本示例 (未配置时为空)；模板中未引用时自动追加
+#   {{.OutputFormat}}      JSON 输出格式要求；模板中未引用时自动追加到末尾，保证结果可以解析
+# 可用函数: ext、lower、contains、hasPrefix、hasSuffix，例如按语言追加要求:
+#   {{if eq (ext .FilePath) ".go"}}错误必须用 %w 包装后返回{{end}}
+# levels: 按级别覆盖描述，可只写需要修改的级别
+# languages: 按语言覆盖语言要点，可只写需要修改的语言，写为空字符串时不注入该语言的要点
+`
+
+// configPromptCmd 输出内置提示，作为自定义提示文件的起点
+var configPromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "输出内置的审查提示，作为自定义提示文件的起点",
+	Long: `以提示文件格式输出内置的系统提示模板与各级别描述，重定向到文件后按团队的审查理念修改，
+再通过 prompt_file 配置项启用 (只能写入用户主目录配置，项目配置中的设置被忽略)：
+
+  reviewer config prompt > review-prompt.yaml
+  reviewer config set prompt_file "$PWD/review-prompt.yaml"`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		data, err := llm.DefaultPromptFile()
+		if err != nil {
+			return fmt.Errorf("导出内置提示失败: %w", err)
+		}
+		fmt.Print(promptFileHeader + string(data))
+		return nil
+	},
+}
+
+// configResultSchemaCmd 输出审查结果的 JSON Schema
+var configResultSchemaCmd = &cobra.Command{
+	Use:   "result-schema",
+	Short: "输出审查结果 (模型响应) 的 JSON Schema",
+	Long: `输出模型响应必须满足的 JSON Schema：必填字段、score 0-100 的整数、importance 与 confidence 0-1、
+问题的严重程度标注等。每个响应都按它校验，不符合时请模型修正一次，修正后仍越界的数值修正到范围内。
+编写自定义提示 (prompt_file) 或用其他工具处理 reviewer review --json 的输出时可以参考：
+
+  reviewer config result-schema > review-result.schema.json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		fmt.Println(llm.ReviewSchema())
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPromptCmd)
+	configCmd.AddCommand(configResultSchemaCmd)
+}
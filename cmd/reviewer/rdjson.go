@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"go-ai-reviewer/internal/app/reviewer"
+)
+
+// 标准输出的问题格式（--format）
+const (
+	formatRDJSON  = "rdjson"  // Reviewdog Diagnostic Format：一个 JSON 对象，reviewdog -f=rdjson
+	formatRDJSONL = "rdjsonl" // 每行一个诊断，reviewdog -f=rdjsonl
+)
+
+// validateOutputFormat 校验 --format；标准输出被问题占用时不能同时输出 JSON 进度事件
+func validateOutputFormat(format, progress string, progressFD int) error {
+	switch format {
+	case "", formatRDJSON, formatRDJSONL:
+	default:
+		return fmt.Errorf("不支持的输出格式 %q (可选: %s / %s)", format, formatRDJSON, formatRDJSONL)
+	}
+	if format != "" && progress == progressJSON && progressFD == int(os.Stdout.Fd()) {
+		return fmt.Errorf("--format %s 与 --progress json 都输出到标准输出，请用 --progress-fd 将进度事件写入其他文件描述符", format)
+	}
+	return nil
+}
+
+// diagnosticOutput 汇总所有任务的问题，以 reviewdog 诊断格式写入标准输出
+// 标准输出只包含诊断，界面与提示信息改为输出到标准错误，便于 reviewer run --format rdjson | reviewdog
+type diagnosticOutput struct {
+	format string
+	w      io.Writer
+
+	mu          sync.Mutex
+	diagnostics []reviewer.RDDiagnostic
+	flushed     bool
+}
+
+// newDiagnosticOutput 接管标准输出，之后写入 os.Stdout 的内容输出到标准错误
+func newDiagnosticOutput(format string) *diagnosticOutput {
+	w := os.Stdout
+	os.Stdout = os.Stderr
+	return &diagnosticOutput{format: format, w: w}
+}
+
+// add 加入任务报告中显示的问题（部分报告同样加入）
+func (o *diagnosticOutput) add(results []reviewer.Result, meta reviewer.ReportMeta) {
+	diagnostics := reviewer.NewRDDiagnostics(results, meta)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.diagnostics = append(o.diagnostics, diagnostics...)
+}
+
+// flush 写入已汇总的诊断，只写入一次；o 为空时不输出
+func (o *diagnosticOutput) flush() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.flushed {
+		return
+	}
+	o.flushed = true
+
+	write := reviewer.WriteRDJSON
+	if o.format == formatRDJSONL {
+		write = reviewer.WriteRDJSONL
+	}
+	if err := write(o.w, o.diagnostics); err != nil {
+		slog.Error("写入诊断输出失败", "err", err)
+	}
+}
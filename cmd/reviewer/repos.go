@@ -0,0 +1,344 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultRollupName 是 run --all 组织级汇总报告的默认名称（可用 --report-name 修改）
+const defaultRollupName = "rollup"
+
+// repoSpec 是配置文件 repos 列表中的一个仓库：本地路径或 Git 地址，各自可以设置级别与排除目录
+type repoSpec struct {
+	Path        string   `mapstructure:"path"`         // 本地目录
+	URL         string   `mapstructure:"url"`          // Git 地址，审查前浅克隆到缓存目录
+	Ref         string   `mapstructure:"ref"`          // 分支或标签，为空时为远程默认分支
+	Name        string   `mapstructure:"name"`         // 报告名称，为空时取目录名或仓库名
+	Level       int      `mapstructure:"level"`        // 0 表示使用默认级别
+	ExcludeDirs []string `mapstructure:"exclude_dirs"` // 在 exclude_dirs 之外额外排除的目录名
+}
+
+// source 返回仓库的来源，写入汇总报告
+func (s repoSpec) source() string {
+	return cmp.Or(s.URL, s.Path)
+}
+
+// scpRemoteRegex 匹配 scp 形式的 Git 地址，如 git@github.com:acme/api.git
+var scpRemoteRegex = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// isGitURL 判断是否为 Git 地址（https://、ssh://、file:// 或 scp 形式）
+func isGitURL(s string) bool {
+	return strings.Contains(s, "://") || scpRemoteRegex.MatchString(s)
+}
+
+// configRepoSpecs 读取配置文件中的 repos 列表，未配置时返回 nil
+func configRepoSpecs() ([]repoSpec, error) {
+	var specs []repoSpec
+	if err := viper.UnmarshalKey("repos", &specs); err != nil {
+		return nil, fmt.Errorf("repos 配置格式错误: %w", err)
+	}
+	return specs, nil
+}
+
+// validateRepos 校验 repos：每项为包含 path 或 url（二选一）、ref、name、level、exclude_dirs 的映射
+func validateRepos(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("应为 {path | url, ref, name, level, exclude_dirs} 形式的列表")
+	}
+	for i, repo := range node.Content {
+		if repo.Kind != yaml.MappingNode {
+			return fmt.Errorf("第 %d 项应为 {path | url, ref, name, level, exclude_dirs} 形式的映射", i+1)
+		}
+		hasPath, hasURL := mappingIndex(repo, "path") >= 0, mappingIndex(repo, "url") >= 0
+		if hasPath == hasURL {
+			return fmt.Errorf("第 %d 项应设置 path (本地目录) 或 url (Git 地址) 之一", i+1)
+		}
+		for j := 0; j+1 < len(repo.Content); j += 2 {
+			key, value := repo.Content[j].Value, repo.Content[j+1]
+			switch key {
+			case "path", "url", "ref", "name":
+				if value.Kind != yaml.ScalarNode {
+					return fmt.Errorf("第 %d 项的 %s 应为字符串", i+1, key)
+				}
+			case "level":
+				if value.Tag != "!!int" {
+					return fmt.Errorf("第 %d 项的 level 应为整数，实际为 %q", i+1, value.Value)
+				}
+			case "exclude_dirs":
+				if value.Kind != yaml.SequenceNode && value.Kind != yaml.ScalarNode {
+					return fmt.Errorf("第 %d 项的 exclude_dirs 应为列表", i+1)
+				}
+			default:
+				return fmt.Errorf("第 %d 项包含未知字段 %s (应为 path / url / ref / name / level / exclude_dirs)", i+1, key)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRepoSpecs 校验仓库定义：path 与 url 二选一、级别范围、报告名称不重复（避免互相覆盖，也不能与汇总报告同名）
+// url 与 ref 不能以 - 开头，否则会被 git 当作选项（如 --upload-pack 可执行任意命令）
+func checkRepoSpecs(specs []repoSpec, rollupName string) error {
+	names := make(map[string]int, len(specs))
+	for i, spec := range specs {
+		label := fmt.Sprintf("仓库 %d", i+1)
+		if (spec.Path == "") == (spec.URL == "") {
+			return fmt.Errorf("%s 应设置 path (本地目录) 或 url (Git 地址) 之一", label)
+		}
+		label += " (" + spec.source() + ")"
+		if spec.URL != "" && (!isGitURL(spec.URL) || strings.HasPrefix(spec.URL, "-")) {
+			return fmt.Errorf("%s 的 url 不是 Git 地址 (如 https://github.com/acme/api.git 或 git@github.com:acme/api.git)", label)
+		}
+		if strings.HasPrefix(spec.Ref, "-") {
+			return fmt.Errorf("%s 的 ref %q 不是分支或标签名 (不能以 - 开头)", label, spec.Ref)
+		}
+		if spec.Level != 0 && !isValidLevel(spec.Level) {
+			return fmt.Errorf("%s 的 level=%d 超出范围 (%d-%d)", label, spec.Level, minLevel, maxLevel)
+		}
+
+		name := repoReportName(spec)
+		if name == rollupName {
+			return fmt.Errorf("%s 的报告名称与汇总报告都是 %q，请用 name 或 --report-name 区分", label, name)
+		}
+		if j, ok := names[name]; ok {
+			return fmt.Errorf("%s 与仓库 %d 的报告名称都是 %q，请用 name 区分", label, j+1, name)
+		}
+		names[name] = i
+	}
+	return nil
+}
+
+// repoReportName 返回仓库的报告名称：未设置 name 时取本地目录名，或 Git 地址中的仓库名
+func repoReportName(spec repoSpec) string {
+	switch {
+	case spec.Name != "":
+		return spec.Name
+	case spec.URL != "":
+		return path.Base(normalizeRemote(spec.URL))
+	default:
+		return resolveDirectoryName(spec.Path)
+	}
+}
+
+// reposCacheDir 返回克隆 Git 仓库的缓存目录（repos_cache_dir，默认在用户缓存目录下）
+func reposCacheDir() (string, error) {
+	if dir := viper.GetString("repos_cache_dir"); dir != "" {
+		return dir, nil
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("无法确定缓存目录，请设置 repos_cache_dir: %w", err)
+	}
+	return filepath.Join(cache, "go-ai-reviewer", "repos"), nil
+}
+
+// repoTasks 读取 repos 列表并转换为审查任务 (run --all)，返回收集各仓库结果的汇总
+// 汇总报告名称为 --report-name，未指定时为 defaultRollupName
+func repoTasks(cmd *cobra.Command) ([]ReviewTask, *repoRollup, error) {
+	specs, err := configRepoSpecs()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(specs) == 0 {
+		return nil, nil, errors.New("--all 需要在配置文件中设置 repos 列表")
+	}
+	rn, _ := cmd.Flags().GetString("rn")
+	rollup := newRepoRollup(cmp.Or(viper.GetString("report_name"), rn, defaultRollupName))
+	tasks, err := buildRepoTasks(specs, rollup)
+	return tasks, rollup, err
+}
+
+// buildRepoTasks 将仓库定义转换为审查任务，Git 地址先浅克隆（或更新）到缓存目录
+// 单个仓库获取失败时记录到汇总报告中并继续其他仓库，只有配置错误时返回 error
+func buildRepoTasks(specs []repoSpec, rollup *repoRollup) ([]ReviewTask, error) {
+	if err := checkRepoSpecs(specs, rollup.name); err != nil {
+		return nil, err
+	}
+
+	defaultLvl := getValidLevel(viper.GetInt("level"))
+	tasks := make([]ReviewTask, 0, len(specs))
+	for _, spec := range specs {
+		task := ReviewTask{
+			Path:        spec.Path,
+			ReportName:  repoReportName(spec),
+			Level:       cmp.Or(spec.Level, defaultLvl),
+			ExcludeDirs: spec.ExcludeDirs,
+		}
+		rollup.register(task.ReportName, spec.source())
+
+		if spec.URL != "" {
+			dir, err := syncRepo(spec)
+			if err != nil {
+				slog.Error("获取仓库失败", "url", spec.URL, "err", err)
+				rollup.fail(task.ReportName, err)
+				continue
+			}
+			task.Path = dir
+		} else if !isValidPath(spec.Path) {
+			slog.Error("仓库目录不存在", "path", spec.Path)
+			rollup.fail(task.ReportName, errors.New("目录不存在"))
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// syncRepo 将 Git 仓库浅克隆到缓存目录，已克隆时拉取最新提交，返回工作目录
+// 缓存目录按仓库地址与 ref 区分（如 github.com/acme/api@release%2F1.2），重复运行时只下载增量
+func syncRepo(spec repoSpec) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", errors.New("需要安装 git 才能审查 Git 地址")
+	}
+	cache, err := reposCacheDir()
+	if err != nil {
+		return "", err
+	}
+	// Clean 前加上 / 可去除地址中的 ..，保证目录位于缓存目录之内
+	dir := filepath.Join(cache, filepath.FromSlash(path.Clean("/"+normalizeRemote(spec.URL))))
+	// 同一地址的不同 ref 使用各自的工作目录，避免互相覆盖；ref 转义后不含路径分隔符
+	if spec.Ref != "" {
+		dir += "@" + url.PathEscape(spec.Ref)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		fmt.Printf("📥 正在更新 %s ...\n", spec.URL)
+		ref := cmp.Or(spec.Ref, "HEAD")
+		// 参数前加 -- 防止地址或 ref 被当作选项
+		if err := runGit(dir, "remote", "set-url", "--", "origin", spec.URL); err != nil {
+			return "", err
+		}
+		if err := runGit(dir, "fetch", "--depth", "1", "--", "origin", ref); err != nil {
+			return "", err
+		}
+		if err := runGit(dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return "", err
+		}
+		return dir, runGit(dir, "clean", "-fdx")
+	}
+
+	fmt.Printf("📥 正在克隆 %s ...\n", spec.URL)
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	// 之前克隆失败可能留下不完整的目录
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	args := []string{"clone", "--depth", "1", "--quiet"}
+	if spec.Ref != "" {
+		args = append(args, "--branch", spec.Ref)
+	}
+	return dir, runGit("", append(args, "--", spec.URL, dir)...)
+}
+
+// runGit 执行 git 命令，失败时返回 git 输出的最后一行
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	// 不弹出凭据输入提示，避免在 CI 中挂起
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		return fmt.Errorf("git %s 失败: %s", args[0], cmp.Or(lines[len(lines)-1], err.Error()))
+	}
+	return nil
+}
+
+// repoRollup 收集 run --all 中各仓库的审查结果，所有任务结束（或中断）后生成组织级汇总报告
+type repoRollup struct {
+	name  string
+	start time.Time
+
+	mu      sync.Mutex
+	entries []reviewer.RollupEntry // 按配置中的顺序
+	written bool
+}
+
+// newRepoRollup 创建汇总，name 为汇总报告的名称
+func newRepoRollup(name string) *repoRollup {
+	return &repoRollup{name: name, start: time.Now()}
+}
+
+// register 按配置顺序登记仓库
+func (r *repoRollup) register(name, source string) {
+	r.entries = append(r.entries, reviewer.RollupEntry{Name: name, Source: source})
+}
+
+// entry 返回报告名称对应的仓库，调用方需持有锁
+func (r *repoRollup) entry(name string) *reviewer.RollupEntry {
+	for i := range r.entries {
+		if r.entries[i].Name == name {
+			return &r.entries[i]
+		}
+	}
+	return nil
+}
+
+// add 记录仓库的审查报告与统计（部分报告同样记录），主要问题的文件路径改为相对仓库目录 root；r 为空时不记录
+func (r *repoRollup) add(root string, results []reviewer.Result, meta reviewer.ReportMeta, reportPath string) {
+	if r == nil {
+		return
+	}
+	digest := reviewer.NewRunDigest(results, meta)
+	for i, issue := range digest.TopIssues {
+		if rel, err := filepath.Rel(root, issue.File); err == nil && !strings.HasPrefix(rel, "..") {
+			digest.TopIssues[i].File = filepath.ToSlash(rel)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e := r.entry(meta.Name); e != nil {
+		e.Digest, e.ReportPath, e.Partial = &digest, reportPath, meta.Partial
+	}
+}
+
+// fail 记录仓库获取或审查失败的原因；r 为空时不记录
+func (r *repoRollup) fail(name string, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e := r.entry(name); e != nil {
+		e.Error = err.Error()
+	}
+}
+
+// write 生成组织级汇总报告，只生成一次；r 为空时不生成
+func (r *repoRollup) write() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.written {
+		return
+	}
+	r.written = true
+
+	reportPath, err := reviewer.GenerateRollupReport(r.entries, time.Since(r.start), reportsDir, r.name)
+	if err != nil {
+		slog.Error("生成汇总报告失败", "err", err)
+		return
+	}
+	fmt.Printf("🏢 组织级汇总报告: %s\n", reportPath)
+}
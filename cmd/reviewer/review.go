@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/app/textenc"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// stdinName 是从标准输入读取的代码在提示词与输出中使用的文件名
+const stdinName = "stdin"
+
+// langExts 是 --lang 常用写法到扩展名的映射，其余语言直接作为扩展名（如 java、php）
+var langExts = map[string]string{
+	"python":     ".py",
+	"golang":     ".go",
+	"javascript": ".js",
+	"typescript": ".ts",
+	"kotlin":     ".kt",
+	"rust":       ".rs",
+	"ruby":       ".rb",
+	"csharp":     ".cs",
+	"c#":         ".cs",
+	"c++":        ".cpp",
+	"shell":      ".sh",
+	"bash":       ".sh",
+}
+
+// reviewCmd 是 review 子命令的定义
+var reviewCmd = &cobra.Command{
+	Use:   "review [file]",
+	Short: "审查单个文件或标准输入中的代码片段，结果输出到标准输出",
+	Long: `审查单个文件，或通过 --stdin 从管道读取代码片段，结果以 Markdown（或 --json）输出到标准输出，
+不生成报告文件，便于在编辑器与其他脚本中调用。--lang 告知模型代码的语言，--name 指定提示词中的文件名。
+
+  cat foo.py | reviewer review --stdin --lang python
+  git show HEAD:main.go | reviewer review --stdin --name main.go --json
+  reviewer review internal/app/scanner.go --l 4`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completePaths,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		mustBindPFlag("level", cmd.Flags().Lookup("l"))
+		mustBindPFlag("min_severity", cmd.Flags().Lookup("min-severity"))
+		mustBindPFlag("min_confidence", cmd.Flags().Lookup("min-confidence"))
+		mustBindPFlag("focus", cmd.Flags().Lookup("focus"))
+		mustBindPFlag("secret_scan", cmd.Flags().Lookup("secret-scan"))
+		mustBindPFlag("redact", cmd.Flags().Lookup("redact"))
+	},
+	RunE: executeReview,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+
+	reviewCmd.Flags().Bool("stdin", false, "从标准输入读取代码")
+	reviewCmd.Flags().String("lang", "", "代码的语言 (如 python、go、ts)，用于 --stdin")
+	reviewCmd.Flags().String("name", "", "提示词与输出中使用的文件名 (如 main.go)，用于 --stdin")
+	reviewCmd.Flags().Int("l", defaultLevel, "审查严格级别 (1-6)")
+	reviewCmd.Flags().String("min-severity", "", "只输出不低于该严重程度的问题: critical / major / minor")
+	reviewCmd.Flags().Float64("min-confidence", 0, "丢弃模型置信度低于该值的问题 (0-1，0 表示不过滤)")
+	reviewCmd.Flags().StringSlice("focus", nil, "审查重点，可多选: security,performance,correctness,style")
+	reviewCmd.Flags().String("secret-scan", secretScanWarn, "发送前检测疑似密钥: warn 在标准错误中列出 / block 拒绝发送 / off 不检测")
+	reviewCmd.Flags().Bool("redact", false, "发送前遮盖疑似密钥、邮箱与 redact_patterns 匹配的内容")
+	reviewCmd.Flags().Bool("json", false, "以 JSON 输出审查结果")
+
+	mustRegisterCompletion(reviewCmd, "min-severity", completeSeverities)
+	mustRegisterCompletion(reviewCmd, "focus", completeFocusAreas)
+	mustRegisterCompletion(reviewCmd, "secret-scan", completeSecretScanModes)
+}
+
+// executeReview 是 review 命令的主执行函数
+func executeReview(cmd *cobra.Command, args []string) error {
+	fromStdin, _ := cmd.Flags().GetBool("stdin")
+	if fromStdin == (len(args) > 0) {
+		return errors.New("请指定一个文件，或使用 --stdin 从管道读取代码")
+	}
+
+	path, content, err := readReviewInput(cmd, args, fromStdin)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		mergeProjectConfig(projectDir(args))
+	}
+	// 标准输入已被代码占用，无法交互式配置 API Key
+	if fromStdin {
+		if err := requireAPIKey(); err != nil {
+			return err
+		}
+	} else if err := validateConfig(); err != nil {
+		return err
+	}
+	minSeverity, err := llm.ParseSeverity(viper.GetString("min_severity"))
+	if err != nil {
+		return err
+	}
+	if _, err := llm.ParseFocus(viper.GetStringSlice("focus")); err != nil {
+		return err
+	}
+	if err := checkMinConfidence(viper.GetFloat64("min_confidence")); err != nil {
+		return err
+	}
+	if mode := viper.GetString("secret_scan"); !validSecretScan(mode) {
+		return errors.New(secretScanError(mode))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 标准输入的代码按执行目录所在的项目查找团队规范
+	dir := "."
+	if len(args) > 0 {
+		dir = filepath.Dir(args[0])
+	}
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		return err
+	}
+	res, err := reviewSnippet(ctx, cfg, dir, path, content, getValidLevel(viper.GetInt("level")))
+	if err != nil {
+		return err
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		out := *res.Review
+		out.Issues = append([]string{}, llm.FilterIssues(out.Issues, minSeverity)...)
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	reviewer.WriteResultMarkdown(os.Stdout, res, minSeverity)
+	return nil
+}
+
+// reviewSnippet 不经过扫描与引擎，直接请模型审查一段代码（reviewer review 与 MCP 工具使用）
+// 发送前按配置检测密钥与遮盖，dir 用于查找团队规范，返回的结果已过滤低置信度问题
+func reviewSnippet(ctx context.Context, cfg reviewConfig, dir, path, content string, level int) (reviewer.Result, error) {
+	if limit := max(cfg.MaxFileSize, reviewer.DefaultMaxFileSize); int64(len(content)) > limit {
+		return reviewer.Result{}, fmt.Errorf("代码过大 (%s > %s)，请调大 max_file_size 或使用 reviewer run 分段审查", formatSize(int64(len(content))), formatSize(limit))
+	}
+
+	if err := checkReviewSecrets(path, content, cfg.SecretScan); err != nil {
+		return reviewer.Result{}, err
+	}
+	if cfg.Redact {
+		var err error
+		if content, err = redactReviewInput(path, content, cfg.RedactPatterns); err != nil {
+			return reviewer.Result{}, err
+		}
+	}
+
+	clientOpts := []llm.ClientOption{}
+	if opt, _ := conventionsOption(dir, cfg); opt != nil {
+		clientOpts = append(clientOpts, opt)
+	}
+	if cfg.PromptFile != "" {
+		prompt, err := llm.LoadPromptFile(cfg.PromptFile)
+		if err != nil {
+			return reviewer.Result{}, err
+		}
+		clientOpts = append(clientOpts, llm.WithPrompt(prompt))
+	}
+	client, err := newLLMClient(cfg, clientOpts...)
+	if err != nil {
+		return reviewer.Result{}, fmt.Errorf("初始化 LLM 客户端失败: %w", err)
+	}
+
+	if cfg.FileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.FileTimeout)
+		defer cancel()
+	}
+
+	suppressed := reviewer.RuleSuppressions(content, cfg.SuppressRules)
+	review, err := client.ReviewCode(ctx, llm.ReviewRequest{
+		FilePath: path,
+		Content:  content,
+		Level:    level,
+		Rules:    reviewer.MatchRules(cfg.Rules, path, suppressed),
+	})
+	if err != nil {
+		return reviewer.Result{}, fmt.Errorf("审查失败: %w", err)
+	}
+	reviewer.SuppressRuleIssues(review, suppressed)
+
+	return reviewer.FilterLowConfidence(reviewer.Result{FilePath: path, Review: review}, cfg.MinConfidence), nil
+}
+
+// readReviewInput 读取待审查的代码，返回提示词中使用的文件名与 UTF-8 内容
+func readReviewInput(cmd *cobra.Command, args []string, fromStdin bool) (string, string, error) {
+	if !fromStdin {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return "", "", fmt.Errorf("读取文件失败: %w", err)
+		}
+		return args[0], string(textenc.ToUTF8(data)), nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", "", fmt.Errorf("读取标准输入失败: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", "", errors.New("标准输入为空")
+	}
+
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		lang, _ := cmd.Flags().GetString("lang")
+		name = stdinName + langExt(lang)
+	}
+	return filepath.ToSlash(name), string(textenc.ToUTF8(data)), nil
+}
+
+// langExt 返回 --lang 对应的扩展名，未指定时返回空字符串
+func langExt(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return ""
+	}
+	if ext, ok := langExts[lang]; ok {
+		return ext
+	}
+	return "." + strings.TrimPrefix(lang, ".")
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultRulesDir 是默认的规则目录，不存在时忽略
+const defaultRulesDir = ".review-rules"
+
+// ruleSpec 是配置文件 rules 列表或规则目录中的一条团队规则
+type ruleSpec struct {
+	ID           string   `mapstructure:"id" yaml:"id"`
+	Description  string   `mapstructure:"description" yaml:"description"`
+	Paths        []string `mapstructure:"paths" yaml:"paths"` // 适用的路径模式，省略时适用于所有文件
+	Instructions string   `mapstructure:"instructions" yaml:"instructions"`
+	Severity     string   `mapstructure:"severity" yaml:"severity"` // 违反时的默认严重程度，可省略
+}
+
+// rule 转换为注入系统提示的规则
+func (s ruleSpec) rule() llm.Rule {
+	return llm.Rule{
+		ID:           strings.TrimSpace(s.ID),
+		Description:  strings.TrimSpace(s.Description),
+		Paths:        s.Paths,
+		Instructions: s.Instructions,
+		Severity:     s.Severity,
+	}
+}
+
+// loadRules 读取配置中的 rules 与规则目录 (rules_dir) 中的规则并校验，规则目录中的规则排在后面
+func loadRules() ([]llm.Rule, error) {
+	var specs []ruleSpec
+	if err := viper.UnmarshalKey("rules", &specs); err != nil {
+		return nil, fmt.Errorf("rules 格式错误: %w", err)
+	}
+	rules := make([]llm.Rule, 0, len(specs))
+	for _, spec := range specs {
+		rules = append(rules, spec.rule())
+	}
+
+	dir := viper.GetString("rules_dir")
+	fromDir, err := loadRulesDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	rules = append(rules, fromDir...)
+
+	if err := llm.ValidateRules(rules); err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+	return rules, nil
+}
+
+// loadRulesDir 读取规则目录中的 .yaml / .yml 文件，每个文件为一条规则或规则列表；
+// 单条规则省略 id 时使用文件名（不含扩展名）。目录为默认目录且不存在时返回空
+func loadRulesDir(dir string) ([]llm.Rule, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) && dir == defaultRulesDir {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取规则目录失败: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var rules []llm.Rule
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("规则文件 %s 格式错误: %w", path, err)
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		root := node.Content[0]
+		if err := validateRuleSpecs(root); err != nil {
+			return nil, fmt.Errorf("规则文件 %s: %w", path, err)
+		}
+
+		var specs []ruleSpec
+		if root.Kind == yaml.SequenceNode {
+			err = root.Decode(&specs)
+		} else {
+			var spec ruleSpec
+			err = root.Decode(&spec)
+			if spec.ID == "" {
+				spec.ID = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			specs = append(specs, spec)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("规则文件 %s 格式错误: %w", path, err)
+		}
+		for _, spec := range specs {
+			rules = append(rules, spec.rule())
+		}
+	}
+	return rules, nil
+}
+
+// validateRules 校验配置中的 rules：每项为包含 id 与 description / instructions 的映射
+func validateRules(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("应为 {id, description, paths, instructions} 形式的列表")
+	}
+	if err := validateRuleSpecs(node); err != nil {
+		return err
+	}
+	var specs []ruleSpec
+	if err := node.Decode(&specs); err != nil {
+		return err
+	}
+	rules := make([]llm.Rule, 0, len(specs))
+	for _, spec := range specs {
+		rules = append(rules, spec.rule())
+	}
+	return llm.ValidateRules(rules)
+}
+
+// validateRuleSpecs 检查规则（或规则列表）中的字段名与字段类型
+func validateRuleSpecs(node *yaml.Node) error {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	for i, item := range items {
+		if item.Kind != yaml.MappingNode {
+			return fmt.Errorf("第 %d 项应为 {id, description, paths, instructions} 形式的映射", i+1)
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			key, value := item.Content[j].Value, item.Content[j+1]
+			switch key {
+			case "id", "description", "instructions", "severity":
+				if value.Kind != yaml.ScalarNode {
+					return fmt.Errorf("第 %d 项的 %s 应为字符串", i+1, key)
+				}
+			case "paths":
+				if value.Kind != yaml.SequenceNode {
+					return fmt.Errorf("第 %d 项的 paths 应为路径模式列表", i+1)
+				}
+			default:
+				return fmt.Errorf("第 %d 项包含未知字段 %s (应为 id / description / paths / instructions / severity)", i+1, key)
+			}
+		}
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -12,14 +14,17 @@ import (
 	"syscall"
 	"time"
 
+	"go-ai-reviewer/internal/app/duplicates"
+	"go-ai-reviewer/internal/app/history"
 	"go-ai-reviewer/internal/app/reviewer"
 	"go-ai-reviewer/internal/app/scanner"
+	"go-ai-reviewer/internal/app/secrets"
+	"go-ai-reviewer/internal/app/share"
 	"go-ai-reviewer/internal/llm"
-	"go-ai-reviewer/internal/ui"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
 // 常量定义
@@ -35,42 +40,203 @@ type ReviewTask struct {
 	Path       string
 	ReportName string
 	Level      int
+	Files      []string // 显式指定的文件列表，非空时跳过目录扫描
+	Select     bool     // 扫描后先在界面中勾选要审查的文件
+
+	ExcludeDirs []string // 在 exclude_dirs 之外额外排除的目录名（repos 中的单个仓库设置）
 }
 
 // runCmd 是 run 子命令的定义
 var runCmd = &cobra.Command{
-	Use:   "run [path] [level] [name] ...",
+	Use:   "run [path|file...] [level] [name] ...",
 	Short: "启动代码审查",
 	Long: `扫描指定目录，根据规则过滤文件，并发送给 AI 进行分析。
-支持批量模式: reviewer run ./path1 5 report1 ./path2 3 report2`,
-	Args: cobra.MinimumNArgs(0),
-	Run:  executeRun,
+支持批量模式: reviewer run ./path1 5 report1 ./path2 3 report2
+支持直接审查文件: reviewer run main.go utils.go
+支持指定文件列表: reviewer run --files-from list.txt 或 git diff --name-only | reviewer run --stdin-files`,
+	Args:              cobra.MinimumNArgs(0),
+	ValidArgsFunction: completePaths,
+	Run:               executeRun,
 }
 
 // executeRun 是 run 命令的主执行函数
 func executeRun(cmd *cobra.Command, args []string) {
-	// 1. 前置配置校验
-	if err := validateConfig(); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ 配置错误: %v\n", err)
+	// 显式任务定义（--task）与位置参数不能混用
+	specs, err := parseTaskFlags(cmd)
+	if err == nil && len(specs) > 0 && len(args) > 0 {
+		err = errors.New("--task 不能与位置参数同时使用")
+	}
+	all, _ := cmd.Flags().GetBool("all")
+	if err == nil && all && (len(specs) > 0 || len(args) > 0) {
+		err = errors.New("--all 审查配置文件中的 repos，不能与位置参数或 --task 同时使用")
+	}
+	if err != nil {
+		slog.Error("任务定义错误", "err", err)
+		os.Exit(1)
+	}
+
+	// 0. 合并目标项目目录下的配置文件（优先级高于用户主目录与当前目录）
+	mergeProjectConfig(projectDir(append(args, taskSpecPaths(specs)...)))
+
+	// 1. 前置配置校验（标准输入已被文件列表占用时无法交互式配置 API Key）
+	validate := validateConfig
+	if readsStdinFileList(cmd) {
+		validate = requireAPIKey
+	}
+	if err := validate(); err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+
+	if err := validateReviewConfig(); err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	if mode := viper.GetString("notify"); !validNotify(mode) {
+		slog.Error("配置错误", "err", fmt.Sprintf("不支持的完成提醒 %q (可选: %s / %s / %s)", mode, notifyBell, notifyDesktop, notifyBoth))
+		os.Exit(1)
+	}
+	progress := viper.GetString("progress")
+	if progress != progressTUI && progress != progressJSON {
+		slog.Error("配置错误", "err", fmt.Sprintf("不支持的进度输出 %q (可选: %s / %s)", progress, progressTUI, progressJSON))
+		os.Exit(1)
+	}
+	format := viper.GetString("output_format")
+	progressFD, _ := cmd.Flags().GetInt("progress-fd")
+	if err := validateOutputFormat(format, progress, progressFD); err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+
+	// 2. 解析任务列表（指定了文件列表时跳过目录扫描）
+	tasks, err := parseFileListTask(cmd, args)
+	if err != nil {
+		slog.Error("读取文件列表失败", "err", err)
+		os.Exit(1)
+	}
+	if tasks != nil && len(specs) > 0 {
+		slog.Error("任务定义错误", "err", "--task 不能与 --files-from / --stdin-files 同时使用")
+		os.Exit(1)
+	}
+	if tasks != nil && all {
+		slog.Error("任务定义错误", "err", "--all 不能与 --files-from / --stdin-files 同时使用")
 		os.Exit(1)
 	}
 
-	// 2. 解析任务列表
-	tasks := parseTasksFromArgs(cmd, args)
+	// --all 审查配置文件中 repos 列出的所有仓库，结束后生成组织级汇总报告
+	var rollup *repoRollup
+	if all {
+		if tasks, rollup, err = repoTasks(cmd); err != nil {
+			slog.Error("任务定义错误", "err", err)
+			os.Exit(1)
+		}
+		if len(tasks) == 0 {
+			rollup.write()
+			slog.Error("没有可执行的任务", "err", "所有仓库都获取失败")
+			os.Exit(1)
+		}
+	}
+
+	// 没有任何参数时使用配置文件中的 tasks 列表
+	if tasks == nil && len(specs) == 0 && len(args) == 0 {
+		if specs, err = configTaskSpecs(); err != nil {
+			slog.Error("任务定义错误", "err", err)
+			os.Exit(1)
+		}
+	}
+	if tasks == nil && len(specs) > 0 {
+		if tasks, err = buildSpecTasks(specs); err != nil {
+			slog.Error("任务定义错误", "err", err)
+			os.Exit(1)
+		}
+	}
+	if tasks == nil {
+		tasks = parseTasksFromArgs(cmd, args)
+	}
 	if len(tasks) == 0 {
-		fmt.Fprintln(os.Stderr, "❌ 没有可执行的任务")
+		slog.Error("没有可执行的任务")
 		os.Exit(1)
 	}
 
+	// 交互式选择文件需要独占终端，并行批量模式与 JSON 进度输出下不可用
+	if selectFiles, _ := cmd.Flags().GetBool("select"); selectFiles {
+		if progress == progressJSON {
+			slog.Warn("--progress json 不支持 --select，已忽略")
+		} else if viper.GetInt("parallel_tasks") > 1 && len(tasks) > 1 {
+			slog.Warn("并行批量模式不支持 --select，已忽略")
+		} else {
+			for i := range tasks {
+				tasks[i].Select = true
+			}
+		}
+	}
+
 	// 3. 创建全局 context（只创建一次，避免信号处理泄漏）
+	start := time.Now()
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	// 4. 顺序执行任务
+	// 可选的 pprof / 指标调试服务
+	if addr := viper.GetString("pprof"); addr != "" {
+		startDebugServer(addr)
+	}
+
+	// 所有任务共享 Token 统计与截止时间，用于全局预算与总时限
+	shared := runResources{usage: llm.NewUsage(nil), updateBaseline: cmd.Name() == "baseline", audit: cmd.Name() == "audit", rollup: rollup}
+	defer printBudgetNotice(shared.usage)
+	if shared.baseline, err = loadRunBaseline(shared.updateBaseline); err != nil {
+		slog.Error("加载基线失败", "err", err)
+		os.Exit(1)
+	}
+	if shared.updateBaseline {
+		defer printBaselineNotice(shared.baseline)
+	}
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		shared.deadline = time.Now().Add(timeout)
+		defer printDeadlineNotice(shared.deadline, timeout)
+	}
+	if shared.history = openRunHistory(ctx); shared.history != nil {
+		defer shared.history.Close()
+	}
+
+	// reviewdog 诊断输出：标准输出中只输出诊断；诊断与汇总报告在所有任务完成（或中断）后写入
+	if format != "" {
+		shared.diagnostics = newDiagnosticOutput(format)
+	}
+	defer shared.flush()
+
+	// JSON 进度输出：标准输出（或 --progress-fd）中只输出事件，任务逐个执行
+	if progress == progressJSON {
+		if shared.progress, err = newJSONProgress(progressFD); err != nil {
+			slog.Error("进度输出初始化失败", "err", err)
+			os.Exit(1)
+		}
+	}
+
+	// 4. 多个任务在同一个汇总界面中执行（可并行，共享全局限流）；交互式选择文件时逐个任务执行
+	if len(tasks) > 1 && !tasks[0].Select && shared.progress == nil {
+		if err := runTasksParallel(ctx, tasks, max(viper.GetInt("parallel_tasks"), 1), shared); err != nil {
+			if errors.Is(err, errInterrupted) || ctx.Err() != nil {
+				fmt.Println("🛑 审查已被用户中断")
+				shared.flush()
+				os.Exit(130)
+			}
+			slog.Error("批量任务失败", "err", err)
+			notifyDone(start, "批量审查失败")
+			shared.flush()
+			os.Exit(1)
+		}
+		notifyDone(start, fmt.Sprintf("%d 个审查任务已完成", len(tasks)))
+		return
+	}
+
+	// 5. 单个任务或需要交互式选择文件时，逐个任务使用独立的界面
+	var failed int
 	for i, task := range tasks {
 		// 检查是否已被用户中断
 		if ctx.Err() != nil {
 			fmt.Println("\n🛑 审查已被用户中断")
+			shared.flush()
 			os.Exit(130)
 		}
 
@@ -78,34 +244,70 @@ func executeRun(cmd *cobra.Command, args []string) {
 			fmt.Printf("\n🚀 批量任务 (%d/%d): %s (级别: %d)\n", i+1, len(tasks), task.ReportName, task.Level)
 		}
 
-		if err := runReviewTask(ctx, task); err != nil {
-			fmt.Fprintf(os.Stderr, "\n❌ 任务失败 [%s]: %v\n", task.Path, err)
-			// 如果是用户中断，立即退出
-			if ctx.Err() != nil {
+		if err := runReviewTask(ctx, task, shared); err != nil {
+			// 如果是用户中断，部分报告已生成，立即退出
+			if errors.Is(err, errInterrupted) || ctx.Err() != nil {
 				fmt.Println("🛑 审查已被用户中断")
+				shared.flush()
 				os.Exit(130)
 			}
 			// 否则继续下一个任务
+			slog.Error("任务失败", "path", task.Path, "err", err)
+			shared.rollup.fail(task.ReportName, err)
+			failed++
 		}
 	}
+	switch {
+	case failed > 0:
+		notifyDone(start, fmt.Sprintf("%d 个审查任务中 %d 个失败", len(tasks), failed))
+	case len(tasks) > 1:
+		notifyDone(start, fmt.Sprintf("%d 个审查任务已完成", len(tasks)))
+	default:
+		notifyDone(start, tasks[0].ReportName+" 审查完成")
+	}
+}
+
+// printBudgetNotice 在达到全局 Token 预算时提示用户，未审查的文件已在报告中列出
+func printBudgetNotice(usage *llm.Usage) {
+	limit := viper.GetInt64("max_tokens_total")
+	if limit > 0 && usage.Total() >= limit {
+		fmt.Printf("💰 已达到 Token 预算 (%d / %d)，剩余文件未审查，详见报告中的跳过列表\n", usage.Total(), limit)
+	}
+}
+
+// printDeadlineNotice 在超过运行时限时提示用户，未审查的文件已在报告中列出
+func printDeadlineNotice(deadline time.Time, timeout time.Duration) {
+	if !time.Now().Before(deadline) {
+		fmt.Printf("⏰ 已达到运行时限 (%s)，剩余文件未审查，详见报告中的跳过列表\n", timeout)
+	}
 }
 
 // validateConfig 校验必要的配置项，缺失时引导用户交互式配置
 func validateConfig() error {
+	// Mock Provider 不需要 API Key
+	if viper.GetString("provider") == llm.ProviderMock {
+		return nil
+	}
+
+	// api_key_source: keyring 时从系统钥匙串读取
+	if err := loadKeyringAPIKey(); err != nil {
+		return err
+	}
+
 	apiKey := viper.GetString("api_key")
 	if apiKey != "" {
 		return nil
 	}
 
-	// 配置缺失，引导用户交互式输入
-	fmt.Println("🔧 首次使用，需要配置 API 信息")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	// 配置缺失，引导用户交互式输入（提示输出到标准错误，标准输出可能是 rdjson 等机器可读结果）
+	fmt.Fprintln(os.Stderr, "🔧 首次使用，需要配置 API 信息")
+	fmt.Fprintln(os.Stderr, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	reader := bufio.NewReader(os.Stdin)
 
 	// 输入 Base URL（可选，有默认值）
 	defaultBaseURL := "https://api.deepseek.com/v1"
-	fmt.Printf("📡 API Base URL [%s]: ", defaultBaseURL)
+	fmt.Fprintf(os.Stderr, "📡 API Base URL [%s]: ", defaultBaseURL)
 	baseURL, _ := reader.ReadString('\n')
 	baseURL = strings.TrimSpace(baseURL)
 	if baseURL == "" {
@@ -113,15 +315,27 @@ func validateConfig() error {
 	}
 
 	// 输入 API Key（必填）
-	fmt.Print("🔑 API Key (必填): ")
+	fmt.Fprint(os.Stderr, "🔑 API Key (必填): ")
 	apiKey, _ = reader.ReadString('\n')
 	apiKey = strings.TrimSpace(apiKey)
 	if apiKey == "" {
 		return fmt.Errorf("API Key 不能为空")
 	}
 
+	// 优先保存到系统钥匙串，不可用时回退为明文保存
+	fmt.Fprint(os.Stderr, "🔐 保存到系统钥匙串而不是明文配置文件? [Y/n]: ")
+	answer, _ := reader.ReadString('\n')
+	inKeyring := false
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer == "" || answer == "y" || answer == "yes" {
+		if err := storeKeyringAPIKey(apiKey); err != nil {
+			slog.Warn("改为保存到配置文件", "err", err)
+		} else {
+			inKeyring = true
+		}
+	}
+
 	// 保存配置到 ~/.code-review.yaml
-	if err := saveConfig(baseURL, apiKey); err != nil {
+	if err := saveConfig(baseURL, apiKey, inKeyring); err != nil {
 		return fmt.Errorf("保存配置失败: %w", err)
 	}
 
@@ -129,15 +343,90 @@ func validateConfig() error {
 	viper.Set("api_key", apiKey)
 	viper.Set("base_url", baseURL)
 
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("✅ 配置已保存到 ~/.code-review.yaml")
-	fmt.Println()
+	fmt.Fprintln(os.Stderr, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintln(os.Stderr, "✅ 配置已保存到 ~/.code-review.yaml")
+	fmt.Fprintln(os.Stderr)
 
 	return nil
 }
 
+// requireAPIKey 校验已配置 API Key（或系统钥匙串中的 API Key），缺失时返回错误而不是交互式引导
+// 用于标准输入已被代码或文件列表占用的场景
+func requireAPIKey() error {
+	if err := loadKeyringAPIKey(); err != nil {
+		return err
+	}
+	if viper.GetString("provider") != llm.ProviderMock && viper.GetString("api_key") == "" {
+		return errors.New("未设置 API Key，请运行 reviewer config set api_key <key> 或设置环境变量 REVIEWER_API_KEY")
+	}
+	return nil
+}
+
+// validateReviewConfig 校验影响审查行为的配置项，run、watch、serve、lsp、mcp 在开始审查前调用
+func validateReviewConfig() error {
+	if _, err := parseSince(viper.GetString("since"), time.Now()); err != nil {
+		return err
+	}
+	if _, err := llm.ParseSeverity(viper.GetString("min_severity")); err != nil {
+		return err
+	}
+	if _, err := llm.ParseFocus(viper.GetStringSlice("focus")); err != nil {
+		return err
+	}
+	if err := checkMinConfidence(viper.GetFloat64("min_confidence")); err != nil {
+		return err
+	}
+	if n := viper.GetInt("duplicate_min_lines"); n < duplicates.MinLines {
+		return fmt.Errorf("duplicate_min_lines=%d 应不小于 %d", n, duplicates.MinLines)
+	}
+	if v := viper.GetFloat64("missing_tests_importance"); v < 0 || v > 1 {
+		return fmt.Errorf("missing_tests_importance=%g 应在 0 到 1 之间", v)
+	}
+	if path := viper.GetString("prompt_file"); path != "" {
+		if _, err := llm.LoadPromptFile(path); err != nil {
+			return err
+		}
+	}
+	if _, err := llm.LoadTLSConfig(configTLSFiles()); err != nil {
+		return err
+	}
+	if mode := viper.GetString("secret_scan"); !validSecretScan(mode) {
+		return errors.New(secretScanError(mode))
+	}
+	if _, err := secrets.NewRedactor(viper.GetStringSlice("redact_patterns")); err != nil {
+		return err
+	}
+	if _, err := loadImportanceRules(); err != nil {
+		return err
+	}
+	if _, err := loadExamples(); err != nil {
+		return err
+	}
+	if _, err := loadRules(); err != nil {
+		return err
+	}
+	if err := validateWebhooks(); err != nil {
+		return err
+	}
+	if err := validateHistory(); err != nil {
+		return err
+	}
+	if err := validateJira(); err != nil {
+		return err
+	}
+	return validateConfluence()
+}
+
+// readsStdinFileList 判断文件列表是否从标准输入读取（--stdin-files 或 --files-from -）
+func readsStdinFileList(cmd *cobra.Command) bool {
+	filesFrom, _ := cmd.Flags().GetString("files-from")
+	stdinFiles, _ := cmd.Flags().GetBool("stdin-files")
+	return stdinFiles || filesFrom == "-"
+}
+
 // saveConfig 将配置保存到用户主目录下的配置文件
-func saveConfig(baseURL, apiKey string) error {
+// inKeyring 为 true 时 API Key 已保存到系统钥匙串，配置文件只记录 api_key_source: keyring
+func saveConfig(baseURL, apiKey string, inKeyring bool) error {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("获取用户主目录失败: %w", err)
@@ -145,13 +434,33 @@ func saveConfig(baseURL, apiKey string) error {
 
 	configPath := filepath.Join(home, ".code-review.yaml")
 
+	// 配置文件已存在时只更新 API 配置，保留其他配置项与注释
+	if _, err := os.Stat(configPath); err == nil {
+		return updateConfigFile(configPath, func(root *yaml.Node) error {
+			setConfigNode(root, []string{"base_url"}, stringNode(baseURL))
+			if inKeyring {
+				unsetConfigNode(root, []string{"api_key"})
+				setConfigNode(root, []string{"api_key_source"}, stringNode(apiKeySourceKeyring))
+			} else {
+				unsetConfigNode(root, []string{"api_key_source"})
+				setConfigNode(root, []string{"api_key"}, stringNode(apiKey))
+			}
+			return nil
+		})
+	}
+
+	apiKeyLine := fmt.Sprintf("api_key: %q", apiKey)
+	if inKeyring {
+		apiKeyLine = "api_key_source: " + apiKeySourceKeyring + " # API Key 保存在系统钥匙串中"
+	}
+
 	// 构建配置内容
 	configContent := fmt.Sprintf(`# Go AI Code Reviewer 配置文件
 # 由工具自动生成
 
 # API 配置
 base_url: "%s"
-api_key: "%s"
+%s
 
 # 模型配置
 model: "deepseek-chat"
@@ -187,7 +496,7 @@ include_exts:
   - .pl
   - .sh
   - .sql
-`, baseURL, apiKey)
+`, baseURL, apiKeyLine)
 
 	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
 		return fmt.Errorf("写入配置文件失败: %w", err)
@@ -207,15 +516,88 @@ func parseTasksFromArgs(cmd *cobra.Command, args []string) []ReviewTask {
 	}
 
 	// 单参数：单个目录
-	if len(args) == 1 {
+	if len(args) == 1 && !isValidFile(args[0]) {
 		reportName := getReportName(cmd, args[0])
 		return []ReviewTask{{Path: args[0], ReportName: reportName, Level: defaultLvl}}
 	}
 
+	// 全部参数都是文件：合并为一个任务，支持 --report-name
+	if allFiles(args) {
+		reportName := getReportName(cmd, filepath.Dir(args[0]))
+		return []ReviewTask{{Path: ".", ReportName: reportName, Level: defaultLvl, Files: args}}
+	}
+
 	// 多参数：批量模式解析
 	return parseMultiPathArgs(args, defaultLvl)
 }
 
+// parseFileListTask 解析 --files-from / --stdin-files 指定的文件列表，未指定时返回 nil
+// 列表中的相对路径基于当前目录，报告名称默认取当前目录名
+func parseFileListTask(cmd *cobra.Command, args []string) ([]ReviewTask, error) {
+	filesFrom, _ := cmd.Flags().GetString("files-from")
+	stdinFiles, _ := cmd.Flags().GetBool("stdin-files")
+	if filesFrom == "" && !stdinFiles {
+		return nil, nil
+	}
+	if filesFrom != "" && stdinFiles {
+		return nil, errors.New("--files-from 与 --stdin-files 不能同时使用")
+	}
+	if len(args) > 0 {
+		return nil, errors.New("指定文件列表时不能再传入目录参数")
+	}
+
+	input := os.Stdin
+	if filesFrom != "" && filesFrom != "-" {
+		f, err := os.Open(filesFrom)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	files, err := scanner.ReadFileList(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("文件列表为空")
+	}
+
+	return []ReviewTask{{
+		Path:       ".",
+		ReportName: getReportName(cmd, "."),
+		Level:      getValidLevel(viper.GetInt("level")),
+		Files:      files,
+	}}, nil
+}
+
+// projectDir 返回参数中唯一的目标目录（文件参数取其所在目录），用于发现项目级配置
+// 批量审查多个不同目录时返回空字符串，只使用用户主目录与当前目录的配置
+func projectDir(args []string) string {
+	var dir string
+	for _, arg := range args {
+		var candidate string
+		switch {
+		case isValidPath(arg):
+			candidate = arg
+		case isValidFile(arg):
+			candidate = filepath.Dir(arg)
+		default:
+			continue
+		}
+		abs, err := filepath.Abs(candidate)
+		if err != nil {
+			continue
+		}
+		if dir != "" && dir != abs {
+			return ""
+		}
+		dir = abs
+	}
+	return dir
+}
+
 // taskParseResult 表示单个任务解析结果
 type taskParseResult struct {
 	task     ReviewTask
@@ -224,6 +606,7 @@ type taskParseResult struct {
 
 // parseMultiPathArgs 解析批量模式参数
 // 格式: path [level] [reportName] path [level] [reportName] ...
+// path 也可以是连续的多个文件，它们合并为一个任务
 func parseMultiPathArgs(args []string, defaultLvl int) []ReviewTask {
 	var tasks []ReviewTask
 
@@ -246,8 +629,18 @@ func parseSingleTask(args []string, defaultLvl int) taskParseResult {
 	path := args[0]
 	consumed := 1
 
+	// 连续的文件参数合并为一个任务，报告名称取第一个文件所在目录
+	var files []string
+	if isValidFile(path) {
+		for consumed < len(args) && isValidFile(args[consumed]) {
+			consumed++
+		}
+		files = args[:consumed]
+		path = filepath.Dir(files[0])
+	}
+
 	// 解析可选参数
-	opts := parseTaskOptions(args[1:], defaultLvl)
+	opts := parseTaskOptions(args[consumed:], defaultLvl)
 	consumed += opts.consumed
 
 	// 构建任务
@@ -256,14 +649,18 @@ func parseSingleTask(args []string, defaultLvl int) taskParseResult {
 		reportName = resolveDirectoryName(path)
 	}
 
-	return taskParseResult{
-		task: ReviewTask{
-			Path:       path,
-			ReportName: reportName,
-			Level:      opts.level,
-		},
-		consumed: consumed,
+	task := ReviewTask{
+		Path:       path,
+		ReportName: reportName,
+		Level:      opts.level,
 	}
+	if len(files) > 0 {
+		// 文件路径相对于当前目录
+		task.Path = "."
+		task.Files = files
+	}
+
+	return taskParseResult{task: task, consumed: consumed}
 }
 
 // taskOptions 表示任务的可选参数
@@ -283,8 +680,8 @@ func parseTaskOptions(args []string, defaultLvl int) taskOptions {
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
-		// 如果遇到有效路径，说明是下一个任务的开始
-		if isValidPath(arg) {
+		// 如果遇到有效路径或文件，说明是下一个任务的开始
+		if isValidPath(arg) || isValidFile(arg) {
 			break
 		}
 
@@ -327,140 +724,415 @@ func getValidLevel(level int) int {
 	return level
 }
 
-// runReviewTask 执行单个审查任务
-func runReviewTask(ctx context.Context, task ReviewTask) error {
-	// 1. 加载配置
-	cfg := loadReviewConfig()
-
-	// 2. 初始化扫描器
-	scn, err := scanner.NewScanner(task.Path, cfg.IncludeExts)
-	if err != nil {
-		return fmt.Errorf("初始化扫描器失败: %w", err)
+// newLLMClient 根据配置的 Provider 创建 LLM 客户端
+func newLLMClient(cfg reviewConfig, opts ...llm.ClientOption) (*llm.Client, error) {
+	if len(cfg.Focus) > 0 {
+		opts = append(opts, llm.WithFocus(cfg.Focus))
 	}
-
-	files, err := scn.Scan()
-	if err != nil {
-		return fmt.Errorf("扫描目录失败: %w", err)
+	if !cfg.LanguagePrompts {
+		opts = append(opts, llm.WithLanguagePrompts(false))
 	}
-
-	if len(files) == 0 {
-		fmt.Printf("🎉 目录 %s 中没有需要审查的文件\n", task.Path)
-		return nil
+	if len(cfg.Examples) > 0 {
+		opts = append(opts, llm.WithExamples(cfg.Examples))
 	}
-
-	// 3. 初始化 LLM 客户端和引擎
-	client, err := llm.NewClient(cfg.APIKey, cfg.Model, cfg.BaseURL)
-	if err != nil {
-		return fmt.Errorf("初始化 LLM 客户端失败: %w", err)
+	switch cfg.Provider {
+	case "", "openai":
+		tlsConfig, err := llm.LoadTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		return llm.NewClient(cfg.APIKey, cfg.Model, cfg.BaseURL, append(opts, llm.WithTLSConfig(tlsConfig))...)
+	case llm.ProviderMock:
+		return llm.NewMockClient(llm.MockOptions{
+			Latency:      cfg.MockLatency,
+			ResponseFile: cfg.MockResponseFile,
+		}, opts...)
+	default:
+		return nil, fmt.Errorf("不支持的 Provider: %s", cfg.Provider)
 	}
+}
 
-	engine, err := reviewer.NewEngine(client, cfg.Concurrency, task.Level)
-	if err != nil {
-		return fmt.Errorf("初始化引擎失败: %w", err)
+// configTLSFiles 读取访问 LLM 接口的证书文件配置（tls_ca_file、tls_cert_file、tls_key_file）
+func configTLSFiles() llm.TLSFiles {
+	return llm.TLSFiles{
+		CAFile:   viper.GetString("tls_ca_file"),
+		CertFile: viper.GetString("tls_cert_file"),
+		KeyFile:  viper.GetString("tls_key_file"),
 	}
-
-	// 4. 启动 TUI 和后台任务
-	return runWithTUI(ctx, engine, files, task)
 }
 
 // reviewConfig 封装审查配置
 type reviewConfig struct {
-	APIKey      string
-	Model       string
-	BaseURL     string
-	Concurrency int
-	IncludeExts []string
+	Provider       string
+	APIKey         string
+	Model          string
+	BaseURL        string
+	Concurrency    int
+	IncludeExts    []string
+	ExcludeDirs    []string // 额外排除的目录名
+	SkipGenerated  bool
+	FollowSymlinks bool          // 扫描时跟随符号链接
+	Since          time.Time     // 只审查该时间之后修改的文件，零值表示不过滤
+	MaxDepth       int           // 最大扫描深度，0 表示不限制
+	IncludeHidden  bool          // 扫描隐藏文件与目录
+	IncludeTests   bool          // 审查测试代码
+	MaxFileSize    int64         // 单次请求的最大文件大小（字节），超过则分段审查
+	FileTimeout    time.Duration // 单个文件的审查时限，0 表示不限制
+	Minify         bool          // 发送前去除注释与空行
+	Dedupe         bool          // 内容相同的文件只审查一次
+	Fix            bool          // 要求模型同时给出修复补丁
+	Sonar          bool          // 在报告旁生成 SonarQube 通用问题导入文件
+
+	// StaticAnalysis 在 LLM 审查前执行本地静态检查工具并合并结果
+	StaticAnalysis bool
+
+	// DuplicateCode 在本地检测文件之间的重复代码，作为项目级问题写入报告
+	DuplicateCode     bool
+	DuplicateMinLines int
+
+	// MissingTests 列出没有对应测试文件、重要性不低于 MissingTestsImportance 的文件，
+	// SuggestTests 同时询问模型其中最需要补充测试的函数
+	MissingTests           bool
+	MissingTestsImportance float64
+	SuggestTests           bool
+
+	// ExecutiveSummary 审查完成后将汇总数据发送给模型一次，生成执行摘要与行动项
+	ExecutiveSummary bool
+
+	// Conventions 将项目根目录的团队规范文件（CONVENTIONS.md 等）注入系统提示
+	Conventions bool
+
+	// Examples 是注入系统提示的少样本示例（应当报告的问题与误报）
+	Examples []llm.Example
+
+	// Webhooks 是审查完成后发送运行摘要的群机器人（Slack、钉钉、飞书、企业微信）与通用 Webhook 配置
+	Webhooks webhookConfig
+
+	// Jira 是审查完成后为严重问题创建工单的配置
+	Jira jiraConfig
+
+	// Confluence 是审查完成后将报告发布到 Confluence 页面的配置
+	Confluence confluenceConfig
+
+	// Rules 是团队规则（rules 与 rules_dir），SuppressRules 中的规则不注入且其问题被丢弃
+	Rules         []llm.Rule
+	SuppressRules []string
+
+	// 自适应并发
+	AdaptiveConcurrency bool
+	MaxConcurrency      int
+	Prioritize          bool
+	Importance          []reviewer.ImportanceRule // 按路径覆盖或限制模型给出的重要性
+	ProjectContext      bool
+	LanguagePrompts     bool
+
+	// 两阶段模式（廉价模型初筛 + 昂贵模型深度审查）
+	Triage      bool
+	TriageModel string
+	TriageRatio float64
+
+	// 企业网关的私有 CA 与双向 TLS 客户端证书
+	TLS llm.TLSFiles
+
+	// Mock Provider
+	MockLatency      time.Duration
+	MockResponseFile string
+
+	// 失败文件重试队列
+	RetryRounds  int
+	RetryBackoff time.Duration
+
+	// 低分/低置信度复核
+	Reverify           bool
+	ReverifyScore      int
+	ReverifyConfidence float64
+
+	// 全局 Token 预算（0 表示不限制）
+	MaxTokensTotal int64
+
+	// 报告中只输出不低于该严重程度的问题
+	MinSeverity llm.Severity
+
+	// 丢弃置信度低于该值的问题（0 表示不过滤）
+	MinConfidence float64
+
+	// 审查重点（security/performance/correctness/style），为空时全面审查
+	Focus []string
+
+	// 发送前的敏感信息检测策略: warn / block / off
+	SecretScan string
+
+	// 发送前遮盖疑似密钥、邮箱与自定义规则匹配的内容
+	Redact         bool
+	RedactPatterns []string
+
+	// 自定义系统提示模板与级别描述的文件，为空时使用内置提示
+	PromptFile string
+
+	// 审查完成后在终端中提示进入结果浏览界面
+	BrowseResults bool
+
+	// Token 单价（每百万 Token），用于 ls/stats/estimate 的费用估算
+	Pricing     llm.Pricing
+	ModelPrices map[string]llm.Pricing // 按模型配置的单价，用于 estimate 对比候选模型
+
+	// 配额耗尽时暂停派发，冷却后自动恢复
+	QuotaPause     bool
+	QuotaCooldown  time.Duration
+	QuotaMaxPauses int
 }
 
-// loadReviewConfig 从 Viper 加载配置
-func loadReviewConfig() reviewConfig {
+// checkMinConfidence 校验 min_confidence 的取值范围
+func checkMinConfidence(v float64) error {
+	if v < 0 || v > 1 {
+		return fmt.Errorf("min_confidence=%g 应在 0-1 之间 (0 表示不过滤)", v)
+	}
+	return nil
+}
+
+// loadReviewConfig 从 Viper 加载配置，since、min_severity、focus 或 importance / examples / rules 格式错误时返回错误
+func loadReviewConfig() (reviewConfig, error) {
 	concurrency := viper.GetInt("concurrency")
 	if concurrency <= 0 {
 		concurrency = defaultConcurrency
 	}
 
+	// 按模型配置的单价（viper 会将键名转为小写）
+	modelPrices := make(map[string]llm.Pricing)
+	if err := viper.UnmarshalKey("model_prices", &modelPrices); err != nil {
+		slog.Warn("model_prices 配置格式错误", "err", err)
+	}
+
+	// 按路径调整重要性的规则（viper 会将路径模式转为小写，匹配时不区分大小写）
+	importance, err := loadImportanceRules()
+	if err != nil {
+		return reviewConfig{}, err
+	}
+	examples, err := loadExamples()
+	if err != nil {
+		return reviewConfig{}, err
+	}
+	rules, err := loadRules()
+	if err != nil {
+		return reviewConfig{}, err
+	}
+
+	// 自适应并发上限默认为初始并发的 2 倍
+	maxConcurrency := viper.GetInt("max_concurrency")
+	if maxConcurrency <= 0 {
+		maxConcurrency = concurrency * 2
+	}
+
+	since, err := parseSince(viper.GetString("since"), time.Now())
+	if err != nil {
+		return reviewConfig{}, err
+	}
+	minSeverity, err := llm.ParseSeverity(viper.GetString("min_severity"))
+	if err != nil {
+		return reviewConfig{}, err
+	}
+	focus, err := llm.ParseFocus(viper.GetStringSlice("focus"))
+	if err != nil {
+		return reviewConfig{}, err
+	}
+
 	return reviewConfig{
+		Provider:    viper.GetString("provider"),
 		APIKey:      viper.GetString("api_key"),
 		Model:       viper.GetString("model"),
 		BaseURL:     viper.GetString("base_url"),
 		Concurrency: concurrency,
 		IncludeExts: viper.GetStringSlice("include_exts"),
-	}
-}
-
-// runWithTUI 启动 TUI 界面并执行审查
-func runWithTUI(ctx context.Context, engine *reviewer.Engine, files []string, task ReviewTask) error {
-	p := tea.NewProgram(ui.NewModel(len(files)))
-	doneCh := make(chan error, 1)
-
-	// 后台执行审查逻辑
-	go func() {
-		taskCtx, cancel := context.WithCancel(ctx)
-		defer cancel()
-
-		startTime := time.Now()
-		results := engine.Start(taskCtx, files)
-
-		var allResults []reviewer.Result
-		var issuesCount int
-
-		for res := range results {
-			p.Send(ui.CurrentFileMsg(res.FilePath))
-			allResults = append(allResults, res)
-			if res.Review != nil {
-				issuesCount += len(res.Review.Issues)
-			}
-		}
-
-		duration := time.Since(startTime)
-
-		// 生成报告
-		reportPath, err := reviewer.GenerateMarkdownReport(allResults, duration, "reports", task.ReportName, task.Level)
-		reportMsg := reportPath
-		if err != nil {
-			reportMsg = fmt.Sprintf("报告生成失败: %v", err)
-		}
-
-		p.Send(ui.DoneMsg{
-			Duration:    duration,
-			ReportPath:  reportMsg,
-			IssuesCount: issuesCount,
-		})
-
-		doneCh <- err
-	}()
-
-	// 启动 TUI（阻塞）
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("TUI 运行失败: %w", err)
-	}
+		ExcludeDirs: viper.GetStringSlice("exclude_dirs"),
+
+		SkipGenerated:  viper.GetBool("skip_generated"),
+		FollowSymlinks: viper.GetBool("follow_symlinks"),
+		Since:          since,
+		MaxDepth:       viper.GetInt("max_depth"),
+		IncludeHidden:  viper.GetBool("include_hidden"),
+		IncludeTests:   viper.GetBool("include_tests") && !viper.GetBool("skip_tests"),
+		MaxFileSize:    int64(viper.GetSizeInBytes("max_file_size")),
+		FileTimeout:    viper.GetDuration("file_timeout"),
+		Minify:         viper.GetBool("minify"),
+		Dedupe:         viper.GetBool("dedupe"),
+		Fix:            viper.GetBool("fix"),
+		Sonar:          viper.GetBool("sonar_report"),
+
+		StaticAnalysis: viper.GetBool("static_analysis"),
+
+		DuplicateCode:     viper.GetBool("duplicate_code"),
+		DuplicateMinLines: viper.GetInt("duplicate_min_lines"),
+
+		MissingTests:           viper.GetBool("missing_tests"),
+		MissingTestsImportance: viper.GetFloat64("missing_tests_importance"),
+		SuggestTests:           viper.GetBool("suggest_tests"),
+
+		ExecutiveSummary: viper.GetBool("executive_summary"),
+		Conventions:      viper.GetBool("conventions"),
+		Examples:         examples,
+		Rules:            rules,
+		SuppressRules:    viper.GetStringSlice("suppress_rules"),
+		Webhooks:         loadWebhookConfig(),
+		Jira:             loadJiraConfig(),
+		Confluence:       loadConfluenceConfig(),
+
+		AdaptiveConcurrency: viper.GetBool("adaptive_concurrency"),
+		MaxConcurrency:      maxConcurrency,
+		Prioritize:          viper.GetBool("prioritize"),
+		ProjectContext:      viper.GetBool("project_context"),
+		LanguagePrompts:     viper.GetBool("language_prompts"),
+
+		Triage:      viper.GetBool("triage"),
+		TriageModel: viper.GetString("triage_model"),
+		TriageRatio: viper.GetFloat64("triage_ratio"),
+
+		TLS: configTLSFiles(),
+
+		MockLatency:      viper.GetDuration("mock_latency"),
+		MockResponseFile: viper.GetString("mock_response_file"),
+
+		RetryRounds:  viper.GetInt("retry_rounds"),
+		RetryBackoff: viper.GetDuration("retry_backoff"),
+
+		Reverify:           viper.GetBool("reverify"),
+		ReverifyScore:      viper.GetInt("reverify_score"),
+		ReverifyConfidence: viper.GetFloat64("reverify_confidence"),
+
+		MaxTokensTotal: viper.GetInt64("max_tokens_total"),
+		MinSeverity:    minSeverity,
+		MinConfidence:  viper.GetFloat64("min_confidence"),
+		Focus:          focus,
+		SecretScan:     viper.GetString("secret_scan"),
+		Redact:         viper.GetBool("redact"),
+		RedactPatterns: viper.GetStringSlice("redact_patterns"),
+		PromptFile:     viper.GetString("prompt_file"),
+		BrowseResults:  viper.GetBool("browse_results"),
+
+		Pricing: llm.Pricing{
+			Input:  viper.GetFloat64("price_input"),
+			Output: viper.GetFloat64("price_output"),
+		},
+		ModelPrices: modelPrices,
+		Importance:  importance,
 
-	// 等待后台任务完成，同时监听 ctx 取消（防止阻塞）
-	select {
-	case err := <-doneCh:
-		return err
-	case <-ctx.Done():
-		return ctx.Err()
-	}
+		QuotaPause:     viper.GetBool("quota_pause"),
+		QuotaCooldown:  viper.GetDuration("quota_cooldown"),
+		QuotaMaxPauses: viper.GetInt("quota_max_pauses"),
+	}, nil
 }
 
 func init() {
 	rootCmd.AddCommand(runCmd)
 
-	// 注册命令行参数
-	runCmd.Flags().StringSlice("include", []string{}, "仅包含指定扩展名的文件")
+	// 注册命令行参数（文件选择相关参数与 ls/stats 共用）
+	addScanFlags(runCmd)
 	runCmd.Flags().Int("concurrency", defaultConcurrency, "并发 Worker 数量")
 	runCmd.Flags().String("base-url", "https://api.deepseek.com/v1", "API 地址")
 	runCmd.Flags().String("report-name", "", "自定义报告名称")
 	runCmd.Flags().String("rn", "", "--report-name 的别名")
-	runCmd.Flags().Int("l", defaultLevel, "审查严格级别 (1-6)")
+	runCmd.Flags().Bool("triage", false, "两阶段模式：廉价模型初筛，仅对高优先级文件深度审查")
+	runCmd.Flags().Bool("lint", false, "审查前执行本地静态检查 (gofmt/go vet/eslint/flake8)，结果合并到报告")
+	runCmd.Flags().Duration("file-timeout", reviewer.DefaultFileTimeout, "单个文件的审查时限，超时则跳过 (0 表示不限制)")
+	runCmd.Flags().Bool("minify", false, "发送前去除注释、许可证头与空行以节省 Token")
+	runCmd.Flags().Bool("fix", false, "要求模型同时给出修复补丁，写入报告旁的 .patch 文件 (使用 reviewer fix --apply 应用)")
+	runCmd.Flags().Bool("sonar", false, "在报告旁生成 SonarQube 通用问题导入格式的 .sonar.json 文件 (sonar.externalIssuesReportPaths)")
+	runCmd.Flags().Int64("max-tokens-total", 0, "整个运行的 Token 预算，达到后停止派发新文件 (0 表示不限制)")
+	runCmd.Flags().Duration("timeout", 0, "整个运行的时限，到期后停止派发新文件、等待进行中的审查完成并生成部分报告 (0 表示不限制)")
+	runCmd.Flags().String("pprof", "", "在指定地址启动 pprof 与指标调试服务 (如 :6060)")
+	runCmd.Flags().Int("parallel-tasks", 1, "批量模式下并行执行的任务数（共享全局并发上限）")
+	runCmd.Flags().String("files-from", "", "从文件读取待审查的文件列表（每行一个，- 表示标准输入），跳过目录扫描")
+	runCmd.Flags().Bool("stdin-files", false, "从标准输入读取待审查的文件列表，如 git diff --name-only | reviewer run --stdin-files")
+	runCmd.Flags().String("min-severity", "", "报告中只保留不低于该严重程度的问题 (critical/major/minor)")
+	runCmd.Flags().Float64("min-confidence", 0, "丢弃模型置信度低于该值的问题 (0-1，0 表示不过滤)，未标注置信度的问题总是保留")
+	runCmd.Flags().StringSlice("focus", nil, "审查重点，可多选: security,performance,correctness,style (默认全面审查)")
+	runCmd.Flags().Bool("all", false, "审查配置文件 repos 中列出的所有仓库 (本地目录或 Git 地址)，并生成组织级汇总报告")
+	runCmd.Flags().StringArray("task", nil, "显式定义任务，可重复: --task 'path=./a,level=5,name=backend' (替代位置参数的批量写法)")
+	runCmd.Flags().Bool("select", false, "扫描后在界面中勾选要审查的文件与目录，再开始审查")
+	runCmd.Flags().String("progress", progressTUI, "进度输出方式: tui 为终端界面，json 为每行一个 JSON 事件 (NDJSON)，供 IDE 插件与脚本读取")
+	runCmd.Flags().Int("progress-fd", 1, "--progress json 写入的文件描述符 (默认标准输出)")
+	runCmd.Flags().String("format", "", "在标准输出中输出问题: rdjson / rdjsonl 为 reviewdog 诊断格式 (报告照常生成)，如 reviewer run --format rdjson | reviewdog -f=rdjson")
+	runCmd.Flags().String("notify", "", "运行结束时提醒: bell 终端响铃 / desktop 桌面通知 / both (耗时短于 notify_after 时不提醒)")
+	runCmd.Flags().String("baseline-file", reviewer.DefaultBaselineFile, "基线文件：存在时只报告基线中没有的新问题 (留空表示不使用基线)")
+	runCmd.Flags().String("secret-scan", secretScanWarn, "发送前检测疑似密钥: warn 在报告中列出 / block 不发送包含密钥的文件 / off 不检测")
+	runCmd.Flags().Bool("redact", false, "发送前遮盖疑似密钥、邮箱与 redact_patterns 匹配的内容，报告中注明遮盖位置")
+	runCmd.Flags().Bool("suggest-tests", false, "询问模型缺少测试的重要文件中最需要补充测试的函数 (最多 10 个文件)")
 
 	// 绑定到 Viper
-	mustBindPFlag("include_exts", runCmd.Flags().Lookup("include"))
+	bindScanFlags(runCmd)
 	mustBindPFlag("concurrency", runCmd.Flags().Lookup("concurrency"))
 	mustBindPFlag("base_url", runCmd.Flags().Lookup("base-url"))
 	mustBindPFlag("report_name", runCmd.Flags().Lookup("report-name"))
-	mustBindPFlag("level", runCmd.Flags().Lookup("l"))
+	mustBindPFlag("triage", runCmd.Flags().Lookup("triage"))
+	mustBindPFlag("static_analysis", runCmd.Flags().Lookup("lint"))
+	mustBindPFlag("file_timeout", runCmd.Flags().Lookup("file-timeout"))
+	mustBindPFlag("minify", runCmd.Flags().Lookup("minify"))
+	mustBindPFlag("fix", runCmd.Flags().Lookup("fix"))
+	mustBindPFlag("sonar_report", runCmd.Flags().Lookup("sonar"))
+	mustBindPFlag("max_tokens_total", runCmd.Flags().Lookup("max-tokens-total"))
+	mustBindPFlag("timeout", runCmd.Flags().Lookup("timeout"))
+	mustBindPFlag("pprof", runCmd.Flags().Lookup("pprof"))
+	mustBindPFlag("parallel_tasks", runCmd.Flags().Lookup("parallel-tasks"))
+	mustBindPFlag("min_severity", runCmd.Flags().Lookup("min-severity"))
+	mustBindPFlag("min_confidence", runCmd.Flags().Lookup("min-confidence"))
+	mustBindPFlag("focus", runCmd.Flags().Lookup("focus"))
+	mustBindPFlag("progress", runCmd.Flags().Lookup("progress"))
+	mustBindPFlag("output_format", runCmd.Flags().Lookup("format"))
+	mustBindPFlag("notify", runCmd.Flags().Lookup("notify"))
+	mustBindPFlag("baseline_file", runCmd.Flags().Lookup("baseline-file"))
+	mustBindPFlag("secret_scan", runCmd.Flags().Lookup("secret-scan"))
+	mustBindPFlag("redact", runCmd.Flags().Lookup("redact"))
+	mustBindPFlag("suggest_tests", runCmd.Flags().Lookup("suggest-tests"))
+
+	// 参数值补全
+	mustRegisterCompletion(runCmd, "report-name", completeReportNames)
+	mustRegisterCompletion(runCmd, "rn", completeReportNames)
+	mustRegisterCompletion(runCmd, "files-from", completePaths)
+	mustRegisterCompletion(runCmd, "min-severity", completeSeverities)
+	mustRegisterCompletion(runCmd, "focus", completeFocusAreas)
+	mustRegisterCompletion(runCmd, "task", completeTaskSpec)
+	mustRegisterCompletion(runCmd, "progress", completeProgressModes)
+	mustRegisterCompletion(runCmd, "format", completeOutputFormats)
+	mustRegisterCompletion(runCmd, "notify", completeNotifyModes)
+	mustRegisterCompletion(runCmd, "baseline-file", completePaths)
+	mustRegisterCompletion(runCmd, "secret-scan", completeSecretScanModes)
+
+	// baseline、audit 命令与 run 共用全部参数（同一组 Flag，绑定的配置项相同）
+	baselineCmd.Flags().AddFlagSet(runCmd.Flags())
+	auditCmd.Flags().AddFlagSet(runCmd.Flags())
+
+	// 仅通过配置文件设置的选项默认值
+	viper.SetDefault("skip_generated", true)
+	viper.SetDefault("include_tests", true)
+	viper.SetDefault("adaptive_concurrency", true)
+	viper.SetDefault("prioritize", true)
+	viper.SetDefault("dedupe", true)
+	viper.SetDefault("duplicate_code", true)
+	viper.SetDefault("duplicate_min_lines", duplicates.DefaultMinLines)
+	viper.SetDefault("missing_tests", true)
+	viper.SetDefault("missing_tests_importance", reviewer.DefaultTestGapImportance)
+	viper.SetDefault("executive_summary", false)
+	viper.SetDefault("conventions", true)
+	viper.SetDefault("rules_dir", defaultRulesDir)
+	viper.SetDefault("project_context", true)
+	viper.SetDefault("language_prompts", true)
+	viper.SetDefault("triage_ratio", reviewer.DefaultTriageRatio)
+	viper.SetDefault("retry_rounds", reviewer.DefaultRetryRounds)
+	viper.SetDefault("retry_backoff", reviewer.DefaultRetryBackoff)
+	viper.SetDefault("quota_pause", true)
+	viper.SetDefault("quota_cooldown", reviewer.DefaultQuotaCooldown)
+	viper.SetDefault("quota_max_pauses", reviewer.DefaultQuotaMaxPauses)
+	viper.SetDefault("reverify", true)
+	viper.SetDefault("reverify_score", reviewer.DefaultReverifyScore)
+	viper.SetDefault("reverify_confidence", reviewer.DefaultReverifyConfidence)
+	viper.SetDefault("browse_results", true)
+	viper.SetDefault("notify_after", defaultNotifyAfter)
+	viper.SetDefault("history_backend", history.BackendSQLite)
+	viper.SetDefault("jira_issue_type", defaultJiraIssueType)
+	viper.SetDefault("jira_min_severity", defaultJiraMinSeverity)
+	viper.SetDefault("jira_max_issues", defaultJiraMaxIssues)
+	viper.SetDefault("jira_labels", []string{"ai-review"})
+	viper.SetDefault("confluence_title", defaultConfluenceTitle)
+	viper.SetDefault("share_provider", share.ProviderGist)
 }
 
 // isValidPath 检查参数是否是一个有效的目录路径
@@ -472,6 +1144,25 @@ func isValidPath(path string) bool {
 	return info.IsDir()
 }
 
+// isValidFile 检查参数是否是一个存在的普通文件
+func isValidFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular()
+}
+
+// allFiles 检查参数是否全部为文件
+func allFiles(args []string) bool {
+	for _, arg := range args {
+		if !isValidFile(arg) {
+			return false
+		}
+	}
+	return true
+}
+
 // resolveDirectoryName 解析目录路径为实际名称
 func resolveDirectoryName(path string) string {
 	if path == "." || path == "./" {
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// scanFlag 是影响文件选择与审查级别的命令行参数，run、ls、stats 共用
+type scanFlag struct {
+	name string // 参数名
+	key  string // Viper 配置键
+}
+
+// scanFlags 列出共用参数及其配置键
+var scanFlags = []scanFlag{
+	{"include", "include_exts"},
+	{"l", "level"},
+	{"max-file-size", "max_file_size"},
+	{"follow-symlinks", "follow_symlinks"},
+	{"since", "since"},
+	{"max-depth", "max_depth"},
+	{"include-hidden", "include_hidden"},
+	{"skip-tests", "skip_tests"},
+}
+
+// addScanFlags 为命令注册文件选择相关参数
+func addScanFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringSlice("include", []string{}, "仅包含指定扩展名的文件")
+	flags.Int("l", defaultLevel, "审查严格级别 (1-6)")
+	flags.String("max-file-size", "32KB", "单次审查的最大文件大小，超过则分段审查 (如 128KB)")
+	flags.Bool("follow-symlinks", false, "扫描时跟随符号链接目录（记录已访问目录防止循环）")
+	flags.String("since", "", "只审查指定时间之后修改的文件 (如 7d、36h、2024-01-01)")
+	flags.Int("max-depth", 0, "最大扫描深度，1 表示只扫描根目录下的文件 (0 表示不限制)")
+	flags.Bool("include-hidden", false, "扫描以 . 开头的隐藏文件与目录 (.git 等仍然排除)")
+	flags.Bool("skip-tests", false, "跳过测试代码 (*_test.go、*.spec.ts、__tests__/ 等)，等同于 include_tests: false")
+}
+
+// bindScanFlags 将命令的文件选择参数绑定到 Viper
+// 同一配置键只能绑定一个参数，因此 ls/stats 在执行前（PreRun）才绑定，覆盖 run 在 init 中的绑定
+func bindScanFlags(cmd *cobra.Command) {
+	for _, f := range scanFlags {
+		mustBindPFlag(f.key, cmd.Flags().Lookup(f.name))
+	}
+}
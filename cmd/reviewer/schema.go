@@ -0,0 +1,422 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/ui"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// configKind 是配置项的值类型
+type configKind int
+
+const (
+	kindString     configKind = iota
+	kindBool                  // true / false
+	kindInt                   // 整数
+	kindFloat                 // 整数或小数
+	kindDuration              // 时长，如 10s、5m
+	kindSize                  // 文件大小，如 32KB、1MB
+	kindList                  // 字符串列表
+	kindPrices                // 模型名到 {input, output} 单价的映射
+	kindTasks                 // {path, level, name} 任务列表
+	kindColors                // 颜色名到颜色值的映射
+	kindImportance            // 路径模式到重要性（或 {min, max} 范围）的映射
+	kindExamples              // {kind, code, issue, reason} 少样本示例列表
+	kindRules                 // {id, description, paths, instructions} 团队规则列表
+	kindHooks                 // {url, secret, headers} 通用 Webhook 列表
+	kindRepos                 // {path | url, ref, name, level, exclude_dirs} 仓库列表
+)
+
+// configKindExamples 是各类型的示例值（已按 Shell 参数转义），用于校验失败时的修复建议
+var configKindExamples = map[configKind]string{
+	kindString:     "text",
+	kindBool:       "true",
+	kindInt:        "5",
+	kindFloat:      "0.5",
+	kindDuration:   "10m",
+	kindSize:       "32KB",
+	kindList:       `'[".go", ".ts"]'`,
+	kindPrices:     "'{gpt-4o: {input: 2.5, output: 10}}'",
+	kindTasks:      "'[{path: ./backend, level: 5, name: backend}]'",
+	kindColors:     `'{accent: "#ff8800", muted: 245}'`,
+	kindImportance: `'{"cmd/**": 1.0, "examples/**": 0.2}'`,
+	kindRules:      `'[{id: SEC-001, description: 禁止拼接 SQL, paths: ["internal/db/**"]}]'`,
+	kindHooks:      `'[{url: "https://ci.example.com/hooks/review", secret: xxxx}]'`,
+	kindRepos:      `'[{url: "https://github.com/acme/api.git", level: 4}, {path: ../web, exclude_dirs: [dist]}]'`,
+	kindExamples:   `'[{kind: false_positive, code: "defer f.Close()", issue: "未检查 Close 的错误", reason: "只读文件"}]'`,
+}
+
+// configSchema 列出所有支持的配置项及其类型（新增配置项时需同步添加）
+var configSchema = map[string]configKind{
+	"api_key":        kindString,
+	"api_key_source": kindString,
+	"model":          kindString,
+	"base_url":       kindString,
+	"provider":       kindString,
+
+	"tls_ca_file":   kindString,
+	"tls_cert_file": kindString,
+	"tls_key_file":  kindString,
+
+	"concurrency":          kindInt,
+	"adaptive_concurrency": kindBool,
+	"max_concurrency":      kindInt,
+	"prioritize":           kindBool,
+	"importance":           kindImportance,
+	"project_context":      kindBool,
+	"language_prompts":     kindBool,
+	"parallel_tasks":       kindInt,
+	"level":                kindInt,
+	"report_name":          kindString,
+	"tasks":                kindTasks,
+	"pprof":                kindString,
+
+	"include_exts":    kindList,
+	"exclude_dirs":    kindList,
+	"skip_generated":  kindBool,
+	"follow_symlinks": kindBool,
+	"since":           kindString,
+	"max_depth":       kindInt,
+	"include_hidden":  kindBool,
+	"include_tests":   kindBool,
+	"skip_tests":      kindBool,
+
+	"file_timeout":      kindDuration,
+	"max_file_size":     kindSize,
+	"dedupe":            kindBool,
+	"static_analysis":   kindBool,
+	"minify":            kindBool,
+	"fix":               kindBool,
+	"sonar_report":      kindBool,
+	"baseline_file":     kindString,
+	"max_tokens_total":  kindInt,
+	"timeout":           kindDuration,
+	"min_severity":      kindString,
+	"min_confidence":    kindFloat,
+	"focus":             kindList,
+	"secret_scan":       kindString,
+	"redact":            kindBool,
+	"redact_patterns":   kindList,
+	"prompt_file":       kindString,
+	"browse_results":    kindBool,
+	"progress":          kindString,
+	"output_format":     kindString,
+	"editor":            kindString,
+	"notify":            kindString,
+	"notify_after":      kindDuration,
+	"slack_webhook":     kindString,
+	"slack_channel":     kindString,
+	"dingtalk_webhook":  kindString,
+	"dingtalk_secret":   kindString,
+	"feishu_webhook":    kindString,
+	"feishu_secret":     kindString,
+	"wecom_webhook":     kindString,
+	"notify_report_url": kindString,
+	"webhooks":          kindHooks,
+
+	"jira_url":          kindString,
+	"jira_email":        kindString,
+	"jira_token":        kindString,
+	"jira_project":      kindString,
+	"jira_issue_type":   kindString,
+	"jira_labels":       kindList,
+	"jira_min_severity": kindString,
+	"jira_max_issues":   kindInt,
+
+	"confluence_url":       kindString,
+	"confluence_email":     kindString,
+	"confluence_token":     kindString,
+	"confluence_space":     kindString,
+	"confluence_parent_id": kindString,
+	"confluence_title":     kindString,
+
+	"repos":           kindRepos,
+	"repos_cache_dir": kindString,
+
+	"share_provider": kindString,
+	"share_url":      kindString,
+	"share_token":    kindString,
+
+	"serve_addr":       kindString,
+	"serve_root":       kindString,
+	"serve_max_jobs":   kindInt,
+	"serve_max_upload": kindSize,
+	"serve_job_ttl":    kindDuration,
+	"serve_token":      kindString,
+
+	"history_backend": kindString,
+	"history_dsn":     kindString,
+	"history_project": kindString,
+
+	"duplicate_code":      kindBool,
+	"duplicate_min_lines": kindInt,
+
+	"missing_tests":            kindBool,
+	"missing_tests_importance": kindFloat,
+	"suggest_tests":            kindBool,
+	"executive_summary":        kindBool,
+	"conventions":              kindBool,
+	"examples":                 kindExamples,
+	"rules":                    kindRules,
+	"rules_dir":                kindString,
+	"suppress_rules":           kindList,
+
+	"theme":        kindString,
+	"theme_colors": kindColors,
+	"no_color":     kindBool,
+
+	"triage":       kindBool,
+	"triage_model": kindString,
+	"triage_ratio": kindFloat,
+
+	"retry_rounds":        kindInt,
+	"retry_backoff":       kindDuration,
+	"reverify":            kindBool,
+	"reverify_score":      kindInt,
+	"reverify_confidence": kindFloat,
+
+	"quota_pause":      kindBool,
+	"quota_cooldown":   kindDuration,
+	"quota_max_pauses": kindInt,
+
+	"price_input":  kindFloat,
+	"price_output": kindFloat,
+	"model_prices": kindPrices,
+
+	"mock_latency":       kindDuration,
+	"mock_response_file": kindString,
+}
+
+// projectConfigKeys 是项目级配置文件（当前目录与目标项目中的 .code-review.yaml）可以设置的配置项
+// 项目配置随被审查的仓库提交，可能来自不受信任的仓库或 PR，因此只允许影响审查行为的配置项；
+// 接口地址、凭据、TLS 证书、历史数据库、Webhook、外部 lint 工具与任务来源等只能在用户主目录配置、环境变量或命令行中设置；
+// 指向本地文件的配置项 (prompt_file、rules_dir、baseline_file) 可被用来把仓库之外的文件发送给模型，
+// 密钥检测与遮盖 (secret_scan、redact) 只能由用户关闭，同样不允许在项目配置中设置
+var projectConfigKeys = map[string]bool{
+	"model":            true,
+	"level":            true,
+	"report_name":      true,
+	"prioritize":       true,
+	"importance":       true,
+	"project_context":  true,
+	"language_prompts": true,
+
+	"include_exts":   true,
+	"exclude_dirs":   true,
+	"skip_generated": true,
+	"since":          true,
+	"max_depth":      true,
+	"include_hidden": true,
+	"include_tests":  true,
+	"skip_tests":     true,
+
+	"file_timeout":    true,
+	"max_file_size":   true,
+	"dedupe":          true,
+	"minify":          true,
+	"fix":             true,
+	"sonar_report":    true,
+	"min_severity":    true,
+	"min_confidence":  true,
+	"focus":           true,
+	"redact_patterns": true,
+
+	"jira_project":         true,
+	"jira_issue_type":      true,
+	"jira_labels":          true,
+	"jira_min_severity":    true,
+	"jira_max_issues":      true,
+	"confluence_space":     true,
+	"confluence_parent_id": true,
+	"confluence_title":     true,
+	"history_project":      true,
+
+	"duplicate_code":           true,
+	"duplicate_min_lines":      true,
+	"missing_tests":            true,
+	"missing_tests_importance": true,
+	"suggest_tests":            true,
+	"executive_summary":        true,
+	"conventions":              true,
+	"examples":                 true,
+	"rules":                    true,
+	"suppress_rules":           true,
+
+	"triage":              true,
+	"triage_model":        true,
+	"triage_ratio":        true,
+	"reverify":            true,
+	"reverify_score":      true,
+	"reverify_confidence": true,
+}
+
+// sizeRegex 匹配 viper 支持的文件大小写法（数字加可选的 KB/MB/GB 单位）
+var sizeRegex = regexp.MustCompile(`(?i)^\d+\s*([kmg]i?b?|b)?$`)
+
+// validateConfigValue 校验配置值节点是否符合类型
+func validateConfigValue(kind configKind, node *yaml.Node) error {
+	switch kind {
+	case kindList:
+		// 也接受逗号分隔的字符串
+		if node.Kind == yaml.SequenceNode || node.Kind == yaml.ScalarNode {
+			return nil
+		}
+		return fmt.Errorf("应为列表")
+	case kindPrices:
+		return validatePrices(node)
+	case kindTasks:
+		return validateTasks(node)
+	case kindColors:
+		return validateColors(node)
+	case kindImportance:
+		return validateImportance(node)
+	case kindExamples:
+		return validateExamples(node)
+	case kindRules:
+		return validateRules(node)
+	case kindHooks:
+		return validateHooks(node)
+	case kindRepos:
+		return validateRepos(node)
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("应为单个值")
+	}
+	switch kind {
+	case kindBool:
+		if node.Tag != "!!bool" {
+			return fmt.Errorf("应为 true 或 false，实际为 %q", node.Value)
+		}
+	case kindInt:
+		if node.Tag != "!!int" {
+			return fmt.Errorf("应为整数，实际为 %q", node.Value)
+		}
+	case kindFloat:
+		if node.Tag != "!!int" && node.Tag != "!!float" {
+			return fmt.Errorf("应为数字，实际为 %q", node.Value)
+		}
+	case kindDuration:
+		if _, err := time.ParseDuration(node.Value); err != nil && node.Value != "0" {
+			return fmt.Errorf("应为时长 (如 30s、10m)，实际为 %q", node.Value)
+		}
+	case kindSize:
+		if !sizeRegex.MatchString(strings.TrimSpace(node.Value)) {
+			return fmt.Errorf("应为文件大小 (如 32KB、1MB)，实际为 %q", node.Value)
+		}
+	}
+	return nil
+}
+
+// validatePrices 校验 model_prices：每个模型的值为包含 input / output 数字的映射
+func validatePrices(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("应为 模型: {input, output} 形式的映射")
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		model, price := node.Content[i].Value, node.Content[i+1]
+		if price.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s 的单价应为 {input, output} 形式的映射", model)
+		}
+		for j := 0; j+1 < len(price.Content); j += 2 {
+			key, value := price.Content[j].Value, price.Content[j+1]
+			if key != "input" && key != "output" {
+				return fmt.Errorf("%s 的单价包含未知字段 %s (应为 input / output)", model, key)
+			}
+			if value.Tag != "!!int" && value.Tag != "!!float" {
+				return fmt.Errorf("%s.%s 应为数字，实际为 %q", model, key, value.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTasks 校验 tasks：每项为包含 path（必填）、level、name 的映射
+func validateTasks(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("应为 {path, level, name} 形式的列表")
+	}
+	for i, task := range node.Content {
+		if task.Kind != yaml.MappingNode {
+			return fmt.Errorf("第 %d 项应为 {path, level, name} 形式的映射", i+1)
+		}
+		if mappingIndex(task, "path") < 0 {
+			return fmt.Errorf("第 %d 项缺少 path", i+1)
+		}
+		for j := 0; j+1 < len(task.Content); j += 2 {
+			key, value := task.Content[j].Value, task.Content[j+1]
+			switch key {
+			case "path", "name":
+			case "level":
+				if value.Tag != "!!int" {
+					return fmt.Errorf("第 %d 项的 level 应为整数，实际为 %q", i+1, value.Value)
+				}
+			default:
+				return fmt.Errorf("第 %d 项包含未知字段 %s (应为 path / level / name)", i+1, key)
+			}
+		}
+	}
+	return nil
+}
+
+// validateColors 校验 theme_colors：键为可覆盖的颜色名称，值为 ANSI 色号或十六进制颜色
+func validateColors(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("应为 颜色名: 颜色 形式的映射")
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if !slices.Contains(ui.ThemeColorKeys(), key) {
+			return fmt.Errorf("未知颜色 %s (可选: %s)", key, strings.Join(ui.ThemeColorKeys(), " / "))
+		}
+		if value.Kind != yaml.ScalarNode || !ui.ValidColor(value.Value) {
+			return fmt.Errorf("%s 应为 ANSI 色号 (如 211) 或十六进制颜色 (如 #ff8800)，实际为 %q", key, value.Value)
+		}
+	}
+	return nil
+}
+
+// suggestConfigKey 为未知配置项推荐最相近的已知配置项，没有足够接近的配置项时返回空字符串
+func suggestConfigKey(key string) string {
+	keys := make([]string, 0, len(configSchema))
+	for k := range configSchema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	best, bestDist := "", 3 // 编辑距离超过 2 的不推荐
+	for _, k := range keys {
+		if d := editDistance(strings.ToLower(key), k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的编辑距离（Levenshtein）
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"go-ai-reviewer/internal/app/secrets"
+)
+
+// 发送前的敏感信息检测策略（secret_scan 配置）
+const (
+	secretScanWarn  = "warn"  // 检测并在报告中列出，文件照常发送
+	secretScanBlock = "block" // 包含疑似密钥的文件不发送给模型
+	secretScanOff   = "off"   // 不检测
+)
+
+// validSecretScan 判断 secret_scan 配置是否有效
+func validSecretScan(mode string) bool {
+	switch mode {
+	case secretScanWarn, secretScanBlock, secretScanOff:
+		return true
+	}
+	return false
+}
+
+// secretScanError 返回 secret_scan 配置无效时的错误说明
+func secretScanError(mode string) string {
+	return fmt.Sprintf("不支持的敏感信息检测策略 %q (可选: %s / %s / %s)", mode, secretScanWarn, secretScanBlock, secretScanOff)
+}
+
+// checkReviewSecrets 在 reviewer review 发送代码前检测疑似密钥：warn 时在标准错误中列出，block 时拒绝发送
+func checkReviewSecrets(path, content, mode string) error {
+	if mode == secretScanOff {
+		return nil
+	}
+	found := secrets.Scan(content)
+	if len(found) == 0 {
+		return nil
+	}
+	for _, s := range found {
+		fmt.Fprintf(os.Stderr, "🔐 %s 第 %d 行: 疑似敏感信息 (%s) `%s`\n", path, s.Line, s.Rule, s.Preview)
+	}
+	if mode == secretScanBlock {
+		return fmt.Errorf("代码包含 %d 处疑似密钥，secret_scan 为 %s，未发送给模型", len(found), secretScanBlock)
+	}
+	return nil
+}
+
+// redactReviewInput 遮盖 reviewer review 待发送的代码，并在标准错误中列出遮盖位置
+func redactReviewInput(path, content string, patterns []string) (string, error) {
+	redactor, err := secrets.NewRedactor(patterns)
+	if err != nil {
+		return "", err
+	}
+	redacted, redactions := redactor.Redact(content)
+	for _, r := range redactions {
+		fmt.Fprintf(os.Stderr, "🙈 %s 第 %d 行: 已遮盖 (%s)\n", path, r.Line, r.Kind)
+	}
+	return redacted, nil
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// serve 的默认设置
+const (
+	defaultServeAddr      = "127.0.0.1:8080"
+	defaultServeMaxUpload = "50MB"
+	defaultServeJobTTL    = 24 * time.Hour
+)
+
+// jobsPath 是任务接口的路径前缀
+const jobsPath = "/api/v1/jobs/"
+
+// serveShutdownTimeout 是停止服务时等待进行中的请求完成的时限
+const serveShutdownTimeout = 10 * time.Second
+
+// serveCmd 是 serve 子命令的定义
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "以 HTTP API 服务运行，供其他工具提交审查任务并获取结果",
+	Long: `启动 REST API 服务：提交审查任务（--root 下的目录、unified diff 或上传的源码压缩包），
+查询任务状态，以 JSON 或 Markdown 获取结果。任务按提交顺序排队执行，使用启动时的配置。
+static_analysis 只对目录任务生效：lint 工具会执行被检查目录中的配置（如 eslint.config.js），diff 与压缩包任务始终跳过。
+
+  reviewer serve --addr 127.0.0.1:8080 --root /srv/repos
+  curl -X POST localhost:8080/api/v1/jobs -H "Content-Type: application/json" -d '{"path": "backend", "level": 3}'
+  curl -X POST localhost:8080/api/v1/jobs -F archive=@src.tar.gz
+  curl localhost:8080/api/v1/jobs/<id>/report`,
+	Args: cobra.NoArgs,
+	Run:  executeServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", defaultServeAddr, "监听地址")
+	serveCmd.Flags().String("root", ".", "path 任务允许审查的目录，请求中的路径相对于该目录")
+	serveCmd.Flags().Int("max-jobs", 1, "同时执行的审查任务数 (共享 concurrency / max_concurrency 全局并发上限)")
+	serveCmd.Flags().String("max-upload", defaultServeMaxUpload, "请求体 (diff 或压缩包) 的大小上限，压缩包解压后不超过其 10 倍")
+	serveCmd.Flags().Duration("job-ttl", defaultServeJobTTL, "已结束的任务与报告的保留时长，过期后删除 (0 表示一直保留到服务停止)")
+
+	mustBindPFlag("serve_addr", serveCmd.Flags().Lookup("addr"))
+	mustBindPFlag("serve_root", serveCmd.Flags().Lookup("root"))
+	mustBindPFlag("serve_max_jobs", serveCmd.Flags().Lookup("max-jobs"))
+	mustBindPFlag("serve_max_upload", serveCmd.Flags().Lookup("max-upload"))
+	mustBindPFlag("serve_job_ttl", serveCmd.Flags().Lookup("job-ttl"))
+
+	mustRegisterCompletion(serveCmd, "root", completeDirs)
+}
+
+// executeServe 是 serve 命令的主执行函数
+func executeServe(_ *cobra.Command, _ []string) {
+	root := viper.GetString("serve_root")
+	mergeProjectConfig(projectDir([]string{root}))
+
+	// 服务通常在 systemd 或容器中运行，没有可交互的终端，缺少 API Key 时直接退出
+	if err := requireAPIKey(); err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	if err := validateReviewConfig(); err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	if viper.GetSizeInBytes("serve_max_upload") == 0 {
+		slog.Error("配置错误", "err", fmt.Sprintf("无效的 serve_max_upload %q", viper.GetString("serve_max_upload")))
+		os.Exit(1)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err == nil {
+		absRoot, err = filepath.EvalSymlinks(absRoot)
+	}
+	if err != nil || !isValidPath(absRoot) {
+		slog.Error("目录不存在", "path", root)
+		os.Exit(1)
+	}
+
+	// 上传的源码与报告保存在临时目录中，服务停止时删除
+	workDir, err := os.MkdirTemp("", "reviewer-serve-")
+	if err != nil {
+		slog.Error("创建工作目录失败", "err", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(workDir)
+
+	addr := viper.GetString("serve_addr")
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		slog.Error("监听失败", "addr", addr, "err", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := newJobServer(absRoot, workDir, cfg)
+	if s.history = openRunHistory(ctx); s.history != nil {
+		defer s.history.Close()
+	}
+	var wg sync.WaitGroup
+	s.workers(ctx, viper.GetInt("serve_max_jobs"), &wg)
+	go s.expire(ctx)
+
+	srv := &http.Server{Handler: s.routes(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("服务异常退出", "err", err)
+			stop()
+		}
+	}()
+
+	fmt.Printf("🌐 审查服务已启动: http://%s%s (审查目录: %s)，按 Ctrl+C 停止\n", ln.Addr(), strings.TrimSuffix(jobsPath, "/"), absRoot)
+	if s.token == "" && !isLoopback(ln.Addr()) {
+		slog.Warn("未设置 serve_token，能访问该地址的任何人都可以提交审查任务")
+	}
+
+	<-ctx.Done()
+	fmt.Println("\n🛑 正在停止服务，进行中的任务将生成部分报告")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("停止服务超时", "err", err)
+	}
+	s.close()
+	wg.Wait()
+	fmt.Println("👋 审查服务已停止")
+}
+
+// isLoopback 判断监听地址是否只接受本机连接
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
+
+// routes 注册 API 路由
+func (s *jobServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("POST /api/v1/jobs", s.auth(s.handleSubmit))
+	mux.Handle("GET /api/v1/jobs", s.auth(s.handleList))
+	mux.Handle("GET /api/v1/jobs/{id}", s.auth(s.handleStatus))
+	mux.Handle("DELETE /api/v1/jobs/{id}", s.auth(s.handleDelete))
+	mux.Handle("GET /api/v1/jobs/{id}/result", s.auth(s.handleResult))
+	mux.Handle("GET /api/v1/jobs/{id}/report", s.auth(s.handleReport))
+	return mux
+}
+
+// auth 在设置了 serve_token 时校验请求头 Authorization: Bearer <token>
+func (s *jobServer) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, errors.New("缺少或错误的访问令牌"))
+				return
+			}
+		}
+		next(w, r)
+	})
+}
+
+// jobRequest 是以 JSON 提交任务的请求体，path 与 diff 必须且只能指定一个
+type jobRequest struct {
+	Path  string   `json:"path"`  // --root 下的目录（相对路径）
+	Files []string `json:"files"` // 只审查 path 下的这些文件（相对于 path）
+	Diff  string   `json:"diff"`  // unified diff，如 git diff 的输出
+	Level int      `json:"level"` // 审查级别 (1-6)，0 表示使用配置中的 level
+	Name  string   `json:"name"`  // 报告名称
+}
+
+// handleSubmit 提交审查任务：JSON 请求体指定目录或 diff，multipart 表单的 archive 字段上传压缩包
+func (s *jobServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxUpload)
+
+	var job *serveJob
+	var err error
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data":
+		job, err = s.submitArchive(r)
+	case "application/json", "":
+		job, err = s.submitJSON(r)
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("不支持的 Content-Type %q (可选: application/json、multipart/form-data)", mediaType))
+		return
+	}
+	if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("请求体超过 %s (serve_max_upload)", formatSize(s.maxUpload)))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.enqueue(job); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	s.mu.Lock()
+	view := job.view()
+	s.mu.Unlock()
+	w.Header().Set("Location", jobsPath+job.id)
+	writeJSON(w, http.StatusAccepted, view)
+}
+
+// submitJSON 根据 JSON 请求体创建目录或 diff 任务
+func (s *jobServer) submitJSON(r *http.Request) (*serveJob, error) {
+	var req jobRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("请求体不是有效的 JSON: %w", err)
+	}
+
+	level, err := requestLevel(req.Level)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case req.Path != "" && req.Diff != "":
+		return nil, errors.New("path 与 diff 不能同时指定")
+	case req.Diff != "":
+		if len(req.Files) > 0 {
+			return nil, errors.New("files 只能与 path 一起使用")
+		}
+		return s.diffJob(req.Diff, level, req.Name)
+	case req.Path != "":
+		return s.pathJob(req.Path, req.Files, level, req.Name)
+	default:
+		return nil, errors.New("请指定 path 或 diff，或以 multipart/form-data 上传 archive")
+	}
+}
+
+// submitArchive 根据 multipart 表单创建压缩包任务，表单字段: archive（文件）、level、name
+func (s *jobServer) submitArchive(r *http.Request) (*serveJob, error) {
+	file, header, err := r.FormFile("archive")
+	if err != nil {
+		if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+			return nil, err
+		}
+		return nil, errors.New("表单中缺少 archive 文件")
+	}
+	defer file.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	level := 0
+	if v := r.FormValue("level"); v != "" {
+		if level, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("无效的 level %q", v)
+		}
+	}
+	if level, err = requestLevel(level); err != nil {
+		return nil, err
+	}
+	name := r.FormValue("name")
+	if name == "" {
+		name = archiveName(header.Filename)
+	}
+
+	job, err := s.newJob(sourceArchive, ReviewTask{ReportName: name, Level: level})
+	if err != nil {
+		return nil, err
+	}
+	upload := filepath.Join(job.dir, "upload")
+	if err := saveUpload(file, upload); err != nil {
+		os.RemoveAll(job.dir)
+		return nil, err
+	}
+	if err := s.archiveJob(job, upload); err != nil {
+		os.RemoveAll(job.dir)
+		return nil, err
+	}
+	return job, nil
+}
+
+// saveUpload 将上传的文件保存到 path
+func saveUpload(r io.Reader, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("保存上传文件失败: %w", err)
+	}
+	return f.Close()
+}
+
+// requestLevel 校验请求中的审查级别，0 表示使用配置中的 level
+func requestLevel(level int) (int, error) {
+	if level == 0 {
+		return getValidLevel(viper.GetInt("level")), nil
+	}
+	if !isValidLevel(level) {
+		return 0, fmt.Errorf("无效的 level %d (可选: %d-%d)", level, minLevel, maxLevel)
+	}
+	return level, nil
+}
+
+// handleList 返回所有任务的状态
+func (s *jobServer) handleList(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"jobs": s.list()})
+}
+
+// handleStatus 返回任务的状态与进度
+func (s *jobServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errJobNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, job.view())
+}
+
+// handleDelete 取消排队中或正在执行的任务，删除已结束的任务
+// 正在执行的任务返回 202，停止派发并生成部分报告后结束
+func (s *jobServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	view, pending, ok := s.cancelOrDelete(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errJobNotFound)
+		return
+	}
+	status := http.StatusOK
+	if pending {
+		status = http.StatusAccepted
+	}
+	writeJSON(w, status, view)
+}
+
+// handleResult 以 JSON 返回已结束任务的结果
+func (s *jobServer) handleResult(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errJobNotFound)
+		return
+	}
+	if job.finished.IsZero() {
+		writeError(w, http.StatusConflict, fmt.Errorf("任务尚未结束 (%s)", job.status))
+		return
+	}
+	writeJSON(w, http.StatusOK, job.result())
+}
+
+// handleReport 返回已结束任务的 Markdown 报告
+func (s *jobServer) handleReport(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	job, ok := s.get(r.PathValue("id"))
+	var status, reportPath string
+	if ok {
+		status = job.status
+		if job.outcome != nil {
+			reportPath = job.outcome.reportPath
+		}
+	}
+	finished := ok && !job.finished.IsZero()
+	s.mu.Unlock()
+
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, errJobNotFound)
+	case !finished:
+		writeError(w, http.StatusConflict, fmt.Errorf("任务尚未结束 (%s)", status))
+	case reportPath == "":
+		writeError(w, http.StatusNotFound, errors.New("任务没有生成报告 (没有需要审查的文件或审查失败)"))
+	default:
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		http.ServeFile(w, r, reportPath)
+	}
+}
+
+// errJobNotFound 表示任务不存在（可能已过期删除）
+var errJobNotFound = errors.New("任务不存在")
+
+// writeJSON 以 JSON 输出响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		slog.Debug("写入响应失败", "err", err)
+	}
+}
+
+// writeError 以 {"error": "..."} 输出错误响应
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
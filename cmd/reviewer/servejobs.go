@@ -0,0 +1,551 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-ai-reviewer/internal/app/archive"
+	"go-ai-reviewer/internal/app/history"
+	"go-ai-reviewer/internal/app/patch"
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/viper"
+)
+
+// serve 任务的状态
+const (
+	jobQueued   = "queued"   // 排队等待执行
+	jobRunning  = "running"  // 正在审查
+	jobDone     = "done"     // 已完成，可以获取结果
+	jobFailed   = "failed"   // 扫描或生成报告失败
+	jobCanceled = "canceled" // 被取消（已开始的任务基于已完成的结果生成部分报告）
+)
+
+// serve 任务的来源
+const (
+	sourcePath    = "path"    // 服务端 --root 下的目录
+	sourceDiff    = "diff"    // 请求中的 unified diff
+	sourceArchive = "archive" // 上传的源码压缩包
+)
+
+// maxQueuedJobs 是排队中任务数的上限，超过时拒绝新任务
+const maxQueuedJobs = 100
+
+// archiveExpansion 是压缩包解压后大小相对于上传大小上限的倍数
+const archiveExpansion = 10
+
+// jobSourceDir 是上传任务在工作目录中存放源码的子目录
+const jobSourceDir = "src"
+
+// serveJob 是 serve 中的一个审查任务，字段在 jobServer.mu 保护下修改
+type serveJob struct {
+	id       string
+	source   string
+	task     ReviewTask
+	status   string
+	created  time.Time
+	started  time.Time
+	finished time.Time
+
+	completed int // 已完成的文件数
+	total     int // 待审查的文件数，扫描完成前为 0
+	err       string
+
+	dir     string             // 任务的工作目录：上传的源码与报告
+	root    string             // 结果中的文件路径相对于该目录
+	cancel  context.CancelFunc // 取消正在执行的任务
+	outcome *taskOutcome       // 任务结束后的审查结果，结束后不再修改
+}
+
+// jobView 是任务状态的 JSON 表示
+type jobView struct {
+	ID        string     `json:"id"`
+	Source    string     `json:"source"` // path / diff / archive
+	Name      string     `json:"name"`
+	Level     int        `json:"level"`
+	Status    string     `json:"status"` // queued / running / done / failed / canceled
+	Created   time.Time  `json:"created"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	Completed int        `json:"completed"`
+	Total     int        `json:"total"`
+	Partial   bool       `json:"partial,omitempty"` // 被取消时只包含已完成的文件
+	Error     string     `json:"error,omitempty"`
+	ResultURL string     `json:"result_url,omitempty"` // JSON 结果，任务结束后可用
+	ReportURL string     `json:"report_url,omitempty"` // Markdown 报告，任务结束且生成了报告时可用
+}
+
+// view 返回任务状态的快照，调用方需持有 jobServer.mu
+func (j *serveJob) view() jobView {
+	v := jobView{
+		ID:        j.id,
+		Source:    j.source,
+		Name:      j.task.ReportName,
+		Level:     j.task.Level,
+		Status:    j.status,
+		Created:   j.created,
+		Completed: j.completed,
+		Total:     j.total,
+		Error:     j.err,
+	}
+	if !j.started.IsZero() {
+		v.Started = &j.started
+	}
+	if !j.finished.IsZero() {
+		v.Finished = &j.finished
+		v.ResultURL = jobsPath + j.id + "/result"
+	}
+	if j.outcome != nil {
+		v.Partial = j.outcome.partial
+		if j.outcome.reportPath != "" {
+			v.ReportURL = jobsPath + j.id + "/report"
+		}
+	}
+	return v
+}
+
+// jobResult 是任务结果的 JSON 表示
+type jobResult struct {
+	Job     jobView       `json:"job"`
+	Summary resultSummary `json:"summary"`
+	Files   []fileResult  `json:"files"`
+}
+
+// resultSummary 是任务结果的统计，问题数只包含不低于 min_severity 的问题
+type resultSummary struct {
+	Score    float64 `json:"score"`
+	Files    int     `json:"files"`
+	Reviewed int     `json:"reviewed"`
+	Skipped  int     `json:"skipped"`
+	Critical int     `json:"critical"`
+	Major    int     `json:"major"`
+	Minor    int     `json:"minor"`
+	Tokens   int64   `json:"tokens"`
+	Summary  string  `json:"summary,omitempty"` // 模型生成的执行摘要
+}
+
+// fileResult 是单个文件的审查结果，路径相对于任务的目录
+type fileResult struct {
+	Path   string            `json:"path"`
+	Status string            `json:"status"` // reviewed / triaged / skipped / failed
+	Note   string            `json:"note,omitempty"`
+	Review *llm.ReviewResult `json:"review,omitempty"` // issues 已按 min_severity 过滤
+}
+
+// result 汇总已结束任务的结果，调用方需持有 jobServer.mu
+func (j *serveJob) result() jobResult {
+	res := jobResult{Job: j.view(), Files: []fileResult{}}
+	if j.outcome == nil {
+		return res
+	}
+
+	meta := j.outcome.meta
+	d := reviewer.NewRunDigest(j.outcome.results, meta)
+	res.Summary = resultSummary{
+		Score:    math.Round(d.Score*10) / 10,
+		Files:    d.Files,
+		Reviewed: d.Reviewed,
+		Skipped:  d.Skipped,
+		Critical: d.Critical,
+		Major:    d.Major,
+		Minor:    d.Minor,
+		Tokens:   meta.Tokens,
+		Summary:  d.Summary,
+	}
+
+	for _, r := range j.outcome.results {
+		msg := fileResultMsg(r, meta.MinSeverity)
+		f := fileResult{Path: relPath(j.root, r.FilePath), Status: fileStatusNames[msg.Status], Note: msg.Note}
+		if r.Review != nil {
+			review := *r.Review
+			review.Issues = append([]string{}, llm.FilterIssues(review.Issues, meta.MinSeverity)...)
+			f.Review = &review
+		}
+		res.Files = append(res.Files, f)
+	}
+	sort.Slice(res.Files, func(a, b int) bool { return res.Files[a].Path < res.Files[b].Path })
+	return res
+}
+
+// relPath 返回 path 相对于 root 的路径（使用 /），不在 root 下时原样返回
+func relPath(root, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	if rel, err := filepath.Rel(root, abs); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// jobServer 管理 serve 中的审查任务：排队、执行、查询与过期清理
+type jobServer struct {
+	root      string        // path 任务允许审查的目录（绝对路径）
+	workDir   string        // 各任务工作目录的父目录
+	token     string        // 访问令牌，为空时不校验
+	maxUpload int64         // 请求体的大小上限
+	ttl       time.Duration // 已结束任务的保留时长
+	limiter   *reviewer.Limiter
+	history   history.Store // 保存各任务的审查记录，为空时不保存
+
+	mu     sync.Mutex
+	jobs   map[string]*serveJob
+	queue  chan *serveJob
+	closed bool // 服务正在停止，不再接受新任务
+}
+
+// newJobServer 创建任务管理器，所有任务共享同一个限流器，总并发不超过全局上限
+func newJobServer(root, workDir string, cfg reviewConfig) *jobServer {
+	return &jobServer{
+		root:      root,
+		workDir:   workDir,
+		token:     viper.GetString("serve_token"),
+		maxUpload: int64(viper.GetSizeInBytes("serve_max_upload")),
+		ttl:       viper.GetDuration("serve_job_ttl"),
+		limiter:   newRunLimiter(cfg),
+		jobs:      make(map[string]*serveJob),
+		queue:     make(chan *serveJob, maxQueuedJobs),
+	}
+}
+
+// newJob 创建任务及其工作目录
+func (s *jobServer) newJob(source string, task ReviewTask) (*serveJob, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(s.workDir, id)
+	if err := os.MkdirAll(dir, reviewer.DirPermission); err != nil {
+		return nil, fmt.Errorf("创建任务目录失败: %w", err)
+	}
+	return &serveJob{id: id, source: source, task: task, status: jobQueued, created: time.Now(), dir: dir}, nil
+}
+
+// newJobID 返回随机的任务 ID
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// errQueueFull 表示排队中的任务已达上限
+var errQueueFull = errors.New("排队中的任务过多，请稍后重试")
+
+// errServerClosed 表示服务正在停止
+var errServerClosed = errors.New("服务正在停止")
+
+// enqueue 登记任务并加入队列，队列已满或服务正在停止时删除任务目录并返回错误
+func (s *jobServer) enqueue(job *serveJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		os.RemoveAll(job.dir)
+		return errServerClosed
+	}
+	select {
+	case s.queue <- job:
+		s.jobs[job.id] = job
+		return nil
+	default:
+		os.RemoveAll(job.dir)
+		return errQueueFull
+	}
+}
+
+// close 关闭任务队列，执行中的任务结束后 workers 退出
+func (s *jobServer) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.queue)
+}
+
+// pathJob 创建审查 --root 下目录的任务，files 非空时只审查其中的文件（相对于 path）
+func (s *jobServer) pathJob(path string, files []string, level int, name string) (*serveJob, error) {
+	dir, err := resolveUnder(s.root, path)
+	if err != nil {
+		return nil, err
+	}
+	if !isValidPath(dir) {
+		return nil, fmt.Errorf("目录不存在: %s", path)
+	}
+
+	task := ReviewTask{Path: dir, ReportName: name, Level: level}
+	if task.ReportName == "" {
+		task.ReportName = resolveDirectoryName(dir)
+	}
+	for _, file := range files {
+		abs, err := resolveUnder(s.root, filepath.Join(filepath.FromSlash(path), filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("路径不在服务允许的目录中: %s", file)
+		}
+		if !isValidFile(abs) {
+			return nil, fmt.Errorf("文件不存在: %s", file)
+		}
+		task.Files = append(task.Files, abs)
+	}
+
+	job, err := s.newJob(sourcePath, task)
+	if err != nil {
+		return nil, err
+	}
+	job.root = dir
+	return job, nil
+}
+
+// resolveUnder 返回 root 下 path 的绝对路径（解析符号链接），相对路径相对于 root，不在 root 下时返回错误
+// root 须为已解析符号链接的绝对路径（serve 与 mcp 的 --root）
+func resolveUnder(root, path string) (string, error) {
+	abs := filepath.FromSlash(path)
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, abs)
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = real
+	}
+	if rel, err := filepath.Rel(root, abs); err != nil || (rel != "." && !filepath.IsLocal(rel)) {
+		return "", fmt.Errorf("路径不在服务允许的目录中: %s", path)
+	}
+	return abs, nil
+}
+
+// diffJob 创建审查 unified diff 的任务：每个文件只有修改块中的上下文与新增行，
+// 按原路径写入任务目录后审查，删除的文件与路径不安全的文件被忽略
+func (s *jobServer) diffJob(diff string, level int, name string) (*serveJob, error) {
+	patches, err := patch.Parse(diff)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = sourceDiff
+	}
+
+	job, err := s.newJob(sourceDiff, ReviewTask{ReportName: name, Level: level})
+	if err != nil {
+		return nil, err
+	}
+	src := filepath.Join(job.dir, jobSourceDir)
+	for _, p := range patches {
+		rel := filepath.FromSlash(p.Path)
+		if !filepath.IsLocal(rel) {
+			continue
+		}
+		path := filepath.Join(src, rel)
+		if err := os.MkdirAll(filepath.Dir(path), reviewer.DirPermission); err != nil {
+			os.RemoveAll(job.dir)
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(p.Fragment()), 0644); err != nil {
+			os.RemoveAll(job.dir)
+			return nil, err
+		}
+		job.task.Files = append(job.task.Files, path)
+	}
+	if len(job.task.Files) == 0 {
+		os.RemoveAll(job.dir)
+		return nil, errors.New("diff 中没有新增或修改的文件")
+	}
+	job.task.Path, job.root = src, src
+	return job, nil
+}
+
+// archiveJob 创建审查源码压缩包的任务，upload 为已保存的压缩包（解压后删除）
+func (s *jobServer) archiveJob(job *serveJob, upload string) error {
+	src := filepath.Join(job.dir, jobSourceDir)
+	err := archive.Extract(upload, src, s.maxUpload*archiveExpansion)
+	os.Remove(upload)
+	if err != nil {
+		return err
+	}
+	job.task.Path, job.root = src, src
+	return nil
+}
+
+// workers 启动 n 个执行任务的 goroutine，ctx 取消后正在执行的任务生成部分报告，
+// 排队中的任务标记为已取消；队列关闭后 wg 结束
+func (s *jobServer) workers(ctx context.Context, n int, wg *sync.WaitGroup) {
+	for range max(n, 1) {
+		wg.Go(func() {
+			for job := range s.queue {
+				s.run(ctx, job)
+			}
+		})
+	}
+}
+
+// run 执行单个任务
+func (s *jobServer) run(ctx context.Context, job *serveJob) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	if job.status != jobQueued {
+		// 排队时已被取消
+		s.mu.Unlock()
+		return
+	}
+	if ctx.Err() != nil {
+		job.status, job.finished = jobCanceled, time.Now()
+		s.mu.Unlock()
+		return
+	}
+	job.status, job.started, job.cancel = jobRunning, time.Now(), cancel
+	s.mu.Unlock()
+	slog.Info("开始审查任务", "id", job.id, "source", job.source, "name", job.task.ReportName)
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		s.finish(job, nil, err)
+		return
+	}
+	cfg = jobReviewConfig(cfg, job.source)
+	shared := runResources{limiter: s.limiter, usage: llm.NewUsage(nil), history: s.history}
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		shared.deadline = time.Now().Add(timeout)
+	}
+
+	pt, err := prepareReviewTask(jobCtx, job.task, cfg, shared)
+	if err != nil {
+		s.finish(job, nil, err)
+		return
+	}
+	pt.outputDir = job.dir
+	if job.source != sourcePath {
+		// diff 与压缩包没有所在的仓库，以任务名称作为项目
+		pt.project = cmp.Or(historyProject(job.dir), job.task.ReportName)
+	}
+	s.mu.Lock()
+	job.total = len(pt.files)
+	s.mu.Unlock()
+
+	if len(pt.files) == 0 {
+		s.finish(job, &taskOutcome{results: pt.skipped, meta: reviewer.ReportMeta{Name: job.task.ReportName, MinSeverity: cfg.MinSeverity}}, nil)
+		return
+	}
+	outcome := executeTask(jobCtx, pt, func(reviewer.Result) {
+		s.mu.Lock()
+		job.completed++
+		s.mu.Unlock()
+	})
+	s.finish(job, &outcome, outcome.err)
+}
+
+// jobReviewConfig 返回任务使用的审查配置：上传的 diff 与压缩包来自客户端，
+// 外部 lint 工具会加载其中的配置文件（如 eslint.config.js）并执行代码，因此只对 --root 下的目录执行 static_analysis
+func jobReviewConfig(cfg reviewConfig, source string) reviewConfig {
+	if source != sourcePath {
+		cfg.StaticAnalysis = false
+	}
+	return cfg
+}
+
+// finish 记录任务的结束状态
+func (s *jobServer) finish(job *serveJob, outcome *taskOutcome, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.finished, job.outcome, job.cancel = time.Now(), outcome, nil
+	switch {
+	case err != nil:
+		job.status, job.err = jobFailed, err.Error()
+	case outcome.partial:
+		job.status = jobCanceled
+	default:
+		job.status = jobDone
+	}
+	slog.Info("审查任务结束", "id", job.id, "status", job.status, "duration", job.finished.Sub(job.started).Round(time.Second))
+}
+
+// cancelOrDelete 取消排队中或正在执行的任务；已结束的任务连同报告一起删除
+// 正在执行的任务停止派发后基于已完成的结果生成部分报告，状态随后变为 canceled（全部文件已审查完时为 done），
+// 此时 pending 为 true；任务不存在时 ok 为 false
+func (s *jobServer) cancelOrDelete(id string) (view jobView, pending, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return jobView{}, false, false
+	}
+	switch job.status {
+	case jobQueued:
+		job.status, job.finished = jobCanceled, time.Now()
+	case jobRunning:
+		job.cancel()
+		pending = true
+	default:
+		delete(s.jobs, id)
+		os.RemoveAll(job.dir)
+	}
+	return job.view(), pending, true
+}
+
+// get 返回任务，调用方需持有 s.mu
+func (s *jobServer) get(id string) (*serveJob, bool) {
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+// list 返回所有任务的状态，按创建时间排列
+func (s *jobServer) list() []jobView {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views := make([]jobView, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		views = append(views, job.view())
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Created.Before(views[j].Created) })
+	return views
+}
+
+// expire 定期删除结束时间超过保留时长的任务及其报告
+func (s *jobServer) expire(ctx context.Context) {
+	if s.ttl <= 0 {
+		return
+	}
+	ticker := time.NewTicker(min(s.ttl, time.Minute))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for id, job := range s.jobs {
+				if !job.finished.IsZero() && now.Sub(job.finished) > s.ttl {
+					delete(s.jobs, id)
+					os.RemoveAll(job.dir)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// archiveName 返回压缩包文件名去掉扩展名后的部分，作为默认的报告名称
+func archiveName(filename string) string {
+	name := filepath.Base(filepath.FromSlash(filename))
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
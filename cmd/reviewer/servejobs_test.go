@@ -0,0 +1,113 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/viper"
+)
+
+// setConfig 在测试期间覆盖配置项，结束后恢复
+func setConfig(t *testing.T, key string, value any) {
+	t.Helper()
+	old, set := viper.Get(key), viper.IsSet(key)
+	viper.Set(key, value)
+	t.Cleanup(func() {
+		if set {
+			viper.Set(key, old)
+		} else {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+// unformattedGo 是未按 gofmt 格式化的源码，开启 static_analysis 时 gofmt 会报告该文件
+const unformattedGo = "package main\n\nfunc  main() {}\n"
+
+// writeTar 将文件写入 tar 压缩包 path
+func writeTar(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lintFindings 返回任务结果中的 lint 问题数
+func lintFindings(t *testing.T, job *serveJob) int {
+	t.Helper()
+	if job.status != jobDone || job.outcome == nil {
+		t.Fatalf("任务 %s 状态为 %s: %s", job.source, job.status, job.err)
+	}
+	n := 0
+	for _, res := range job.outcome.results {
+		n += len(res.Lint)
+	}
+	return n
+}
+
+func TestServeJobStaticAnalysis(t *testing.T) {
+	setConfig(t, "provider", llm.ProviderMock)
+	setConfig(t, "static_analysis", true)
+	setConfig(t, "include_exts", []string{".go"})
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "proj"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "proj", "main.go"), []byte(unformattedGo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newJobServer(root, t.TempDir(), cfg)
+	ctx := context.Background()
+
+	// --root 下的目录由服务端管理员提供，执行静态检查
+	pathJob, err := s.pathJob("proj", nil, defaultLevel, "path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.run(ctx, pathJob)
+	if lintFindings(t, pathJob) == 0 {
+		t.Error("目录任务没有执行静态检查")
+	}
+
+	// 压缩包来自客户端，其中的 lint 配置可能执行任意代码，不执行静态检查
+	archiveJob, err := s.newJob(sourceArchive, ReviewTask{ReportName: "archive", Level: defaultLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upload := filepath.Join(archiveJob.dir, "upload")
+	writeTar(t, upload, map[string]string{
+		"main.go":          unformattedGo,
+		"eslint.config.js": "require('child_process').execSync('touch pwned')\n",
+	})
+	if err := s.archiveJob(archiveJob, upload); err != nil {
+		t.Fatal(err)
+	}
+	s.run(ctx, archiveJob)
+	if n := lintFindings(t, archiveJob); n != 0 {
+		t.Errorf("压缩包任务执行了静态检查，得到 %d 个 lint 问题", n)
+	}
+}
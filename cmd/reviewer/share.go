@@ -0,0 +1,137 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/share"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// shareCmd 是 share 子命令的定义
+var shareCmd = &cobra.Command{
+	Use:   "share [report]",
+	Short: "将审查报告上传为不公开的 GitHub Gist (或粘贴服务) 并输出链接",
+	Long: `上传 reports/ 目录下最新生成的报告（或指定名称/路径的报告），输出可分享的链接，
+便于与没有仓库权限的人分享审查结果。
+
+默认创建 secret gist：不出现在主页与搜索中，但任何持有链接的人都可以查看，分享前请确认报告中没有敏感代码。
+GitHub Token 依次取自 share_token、环境变量 GITHUB_TOKEN / GH_TOKEN 与 gh auth token，需要 gist 权限。
+share_provider: paste 时上传到 share_url 指定的粘贴服务。
+
+  reviewer share
+  reviewer share my-audit --description "支付服务安全审计"
+  reviewer share --service paste`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeReportNames,
+	RunE:              executeShare,
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+
+	shareCmd.Flags().String("service", "", "上传目标: gist (GitHub Gist) / paste (share_url 指定的粘贴服务)")
+	shareCmd.Flags().StringP("description", "d", "", "Gist 的描述 (默认为报告名称)")
+	shareCmd.Flags().Bool("public", false, "创建公开的 Gist (会出现在主页与搜索中)")
+
+	mustBindPFlag("share_provider", shareCmd.Flags().Lookup("service"))
+	mustRegisterCompletion(shareCmd, "service", completeShareProviders)
+}
+
+// executeShare 是 share 命令的主执行函数
+func executeShare(cmd *cobra.Command, args []string) error {
+	mergeProjectConfig(projectDir([]string{"."}))
+	if err := validateShare(); err != nil {
+		return err
+	}
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	path, err := resolveReport(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取报告失败: %w", err)
+	}
+
+	cfg := share.Config{
+		Provider: viper.GetString("share_provider"),
+		URL:      viper.GetString("share_url"),
+		Token:    viper.GetString("share_token"),
+	}
+	if cfg.Provider == share.ProviderGist && cfg.Token == "" {
+		if cfg.Token = githubToken(); cfg.Token == "" {
+			return errors.New("上传到 Gist 需要 GitHub Token (gist 权限)：设置 share_token 或环境变量 GITHUB_TOKEN，或先执行 gh auth login")
+		}
+	}
+
+	reportName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	description, _ := cmd.Flags().GetString("description")
+	public, _ := cmd.Flags().GetBool("public")
+	link, err := share.Upload(context.Background(), cfg, share.File{
+		Name:        filepath.Base(path),
+		Content:     string(data),
+		Description: cmp.Or(description, "AI 代码审查: "+reportName),
+		Public:      public,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "🔗 已上传 %s (任何持有链接的人都可以查看)\n", path)
+	fmt.Println(link)
+	return nil
+}
+
+// validateShare 校验上传目标：paste 需要 http(s) 的 share_url
+func validateShare() error {
+	provider := viper.GetString("share_provider")
+	rawURL := viper.GetString("share_url")
+	switch provider {
+	case share.ProviderGist:
+		if rawURL == "" {
+			return nil
+		}
+	case share.ProviderPaste:
+		if rawURL == "" {
+			return errors.New("share_provider 为 paste 时需要设置 share_url (粘贴服务的上传地址，如 https://paste.rs)")
+		}
+	default:
+		return fmt.Errorf("不支持的上传目标 %q (可选: %s / %s)", provider, share.ProviderGist, share.ProviderPaste)
+	}
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("share_url 应为 http(s) 地址，当前为 %q", rawURL)
+	}
+	return nil
+}
+
+// githubToken 依次从环境变量 GITHUB_TOKEN、GH_TOKEN 与已登录的 gh 命令行读取 GitHub Token，均没有时返回空字符串
+func githubToken() string {
+	if token := cmp.Or(os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN")); token != "" {
+		return token
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "gh", "auth", "token").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
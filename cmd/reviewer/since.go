@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sinceDateLayouts 是 --since 支持的绝对时间格式（按本地时区解析）
+var sinceDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// sinceUnits 是 --since 相对时间支持的单位（time.ParseDuration 不支持天和周）
+var sinceUnits = map[byte]time.Duration{
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// parseSince 解析 --since 参数，返回修改时间下限，空字符串返回零值（不过滤）
+// 支持相对时间（如 7d、2w、36h、90m）与日期（如 2024-01-01、2024-01-01 08:00）
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if n := len(value); n > 1 {
+		if unit, ok := sinceUnits[value[n-1]]; ok {
+			if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+				return now.Add(-time.Duration(count) * unit), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range sinceDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析 --since %q，支持 7d、2w、36h 或 2024-01-01 等格式", value)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-ai-reviewer/internal/app/history"
+	"go-ai-reviewer/internal/app/site"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultSiteLimit 是 reviewer site 默认读取的运行记录数
+const defaultSiteLimit = 500
+
+// defaultSiteMaxFindings 是看板中每个报告默认列出的问题数
+const defaultSiteMaxFindings = 50
+
+// siteCmd 是 site 子命令的定义
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "将审查历史生成为静态 HTML 看板 (评分趋势、模块得分与最新问题)",
+	Long: `读取历史存储 (history_backend) 中的审查记录，生成单个自包含的 index.html：
+每个报告的综合评分趋势、问题数变化、最近一次审查中各模块（顶层目录）的得分与问题列表。
+页面不依赖脚本与外部资源，可直接发布到 GitHub Pages 或内部 Web 服务器。
+
+默认只包含当前项目的记录，团队共享 PostgreSQL / MySQL 时可用 --all 汇总所有仓库。
+
+  reviewer site
+  reviewer site --all --out public
+  reviewer site --project github.com/acme/api --title "API 代码质量"`,
+	Args: cobra.NoArgs,
+	RunE: executeSite,
+}
+
+func init() {
+	rootCmd.AddCommand(siteCmd)
+
+	siteCmd.Flags().StringP("out", "o", filepath.Join(reportsDir, "site"), "输出目录，生成其中的 index.html")
+	siteCmd.Flags().String("project", "", "只包含该项目的记录 (默认为当前项目)")
+	siteCmd.Flags().Bool("all", false, "包含所有项目的记录")
+	siteCmd.Flags().Int("limit", defaultSiteLimit, "最多读取的运行记录数，从最新的开始 (0 不限制)")
+	siteCmd.Flags().Int("max-findings", defaultSiteMaxFindings, "每个报告最多列出的问题数 (0 不限制)")
+	siteCmd.Flags().String("title", "AI 代码审查看板", "看板标题")
+
+	mustRegisterCompletion(siteCmd, "out", completeDirs)
+}
+
+// executeSite 是 site 命令的主执行函数
+func executeSite(cmd *cobra.Command, _ []string) error {
+	mergeProjectConfig(projectDir([]string{"."}))
+	if err := validateHistory(); err != nil {
+		return err
+	}
+	minSeverity, err := llm.ParseSeverity(viper.GetString("min_severity"))
+	if err != nil {
+		return fmt.Errorf("min_severity: %w", err)
+	}
+
+	ctx := context.Background()
+	store, err := openHistory(ctx, false)
+	if err != nil && !errors.Is(err, history.ErrNoHistory) {
+		return err
+	}
+	if store == nil && err == nil {
+		return fmt.Errorf("history_backend 为 %s，没有保存审查历史", history.BackendOff)
+	}
+
+	var series []site.Series
+	if store != nil {
+		defer store.Close()
+		var q history.Query
+		all, _ := cmd.Flags().GetBool("all")
+		if q.Project, _ = cmd.Flags().GetString("project"); q.Project == "" && !all {
+			q.Project = historyProject(".")
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		runs, err := store.List(ctx, q, limit)
+		if err != nil {
+			return err
+		}
+		series = site.GroupRuns(runs)
+		for i, s := range series {
+			// 模块与问题来自最近一次完整运行（GroupRuns 已排除部分运行）
+			last := s.Runs[len(s.Runs)-1]
+			latest, err := store.Find(ctx, history.Query{Project: s.Project, Name: s.Name})
+			if err != nil && !errors.Is(err, history.ErrNoHistory) {
+				return err
+			}
+			if latest != nil && !latest.Partial && latest.Created.Equal(last.Created) {
+				series[i].Latest = latest
+			}
+		}
+	}
+
+	title, _ := cmd.Flags().GetString("title")
+	maxFindings, _ := cmd.Flags().GetInt("max-findings")
+	page := site.Render(series, site.Options{
+		Title:       title,
+		Generated:   time.Now(),
+		MinSeverity: minSeverity,
+		MaxFindings: maxFindings,
+	})
+
+	out, _ := cmd.Flags().GetString("out")
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	path := filepath.Join(out, "index.html")
+	if err := os.WriteFile(path, []byte(page), 0644); err != nil {
+		return fmt.Errorf("写入看板失败: %w", err)
+	}
+	fmt.Printf("📊 已生成看板: %s (%d 个报告)\n", path, len(series))
+	return nil
+}
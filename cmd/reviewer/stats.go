@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// 默认展示的最大文件数
+const defaultStatsTop = 10
+
+// languageByExt 是扩展名到语言名称的映射，未收录的扩展名归为 "Other"
+var languageByExt = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".cjs":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".vue":   "Vue",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".swift": "Swift",
+	".rs":    "Rust",
+	".rb":    "Ruby",
+	".php":   "PHP",
+	".cs":    "C#",
+	".c":     "C",
+	".h":     "C",
+	".cpp":   "C++",
+	".cc":    "C++",
+	".hpp":   "C++",
+	".lua":   "Lua",
+	".sh":    "Shell",
+	".sql":   "SQL",
+	".css":   "CSS",
+	".scss":  "CSS",
+	".html":  "HTML",
+	".md":    "Markdown",
+	".yaml":  "YAML",
+	".yml":   "YAML",
+	".json":  "JSON",
+}
+
+// languageOf 根据扩展名返回文件的语言
+func languageOf(path string) string {
+	if lang, ok := languageByExt[strings.ToLower(filepath.Ext(path))]; ok {
+		return lang
+	}
+	return "Other"
+}
+
+// languageStat 是单个语言的统计
+type languageStat struct {
+	name  string
+	files int
+	size  int64
+}
+
+// statsCmd 是 stats 子命令的定义
+var statsCmd = &cobra.Command{
+	Use:   "stats [path]",
+	Short: "统计项目规模并估算各级别的审查开销（不调用 LLM）",
+	Long: `按与 run 相同的过滤规则扫描项目，输出语言分布、文件数、总大小、最大的文件，
+以及各严格级别下的 Token 与费用估算，用于在正式审查前做规划。`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeDirs,
+	PreRun:            func(cmd *cobra.Command, _ []string) { bindScanFlags(cmd) },
+	Run:               executeStats,
+}
+
+// executeStats 是 stats 命令的主执行函数
+func executeStats(cmd *cobra.Command, args []string) {
+	mergeProjectConfig(projectDir(args))
+
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	task := parseTasksFromArgs(cmd, args)[0]
+	files, skipped, err := scanTaskFiles(task, cfg)
+	if err != nil {
+		slog.Error("扫描失败", "err", err)
+		os.Exit(1)
+	}
+
+	estimates := estimateFiles(files, cfg.MaxFileSize, task.Level)
+	totals := sumEstimates(estimates)
+	fmt.Printf("📊 项目统计: %s\n\n", task.ReportName)
+	fmt.Printf("文件: %d 个，总大小 %s", totals.files, formatSize(totals.size))
+	if len(skipped) > 0 {
+		fmt.Printf("（另有 %d 个生成代码/第三方代码/压缩产物已跳过）", len(skipped))
+	}
+	fmt.Println()
+	if totals.files == 0 {
+		return
+	}
+
+	printLanguages(estimates, totals.size)
+
+	top, _ := cmd.Flags().GetInt("top")
+	printLargestFiles(estimates, top)
+
+	printLevelCosts(estimates, cfg)
+}
+
+// printLanguages 按大小降序输出语言分布
+func printLanguages(estimates []fileEstimate, totalSize int64) {
+	byName := make(map[string]*languageStat)
+	for _, e := range estimates {
+		lang := languageOf(e.path)
+		stat, ok := byName[lang]
+		if !ok {
+			stat = &languageStat{name: lang}
+			byName[lang] = stat
+		}
+		stat.files++
+		stat.size += e.size
+	}
+
+	stats := make([]*languageStat, 0, len(byName))
+	for _, stat := range byName {
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].size != stats[j].size {
+			return stats[i].size > stats[j].size
+		}
+		return stats[i].name < stats[j].name
+	})
+
+	fmt.Println("\n🗂️  语言分布:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, stat := range stats {
+		pct := 0.0
+		if totalSize > 0 {
+			pct = float64(stat.size) / float64(totalSize) * 100
+		}
+		fmt.Fprintf(w, "  %s\t%d 个文件\t%s\t%.1f%%\n", stat.name, stat.files, formatSize(stat.size), pct)
+	}
+	w.Flush()
+}
+
+// printLargestFiles 输出最大的 top 个文件
+func printLargestFiles(estimates []fileEstimate, top int) {
+	if top <= 0 {
+		return
+	}
+	sorted := append([]fileEstimate(nil), estimates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].size > sorted[j].size })
+	sorted = sorted[:min(top, len(sorted))]
+
+	fmt.Printf("\n📦 最大的 %d 个文件:\n", len(sorted))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, e := range sorted {
+		note := ""
+		if e.requests > 1 {
+			note = fmt.Sprintf("分 %d 段", e.requests)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", e.path, formatSize(e.size), note)
+	}
+	w.Flush()
+}
+
+// printLevelCosts 输出各严格级别的审查开销估算
+func printLevelCosts(estimates []fileEstimate, cfg reviewConfig) {
+	fmt.Println("\n🪙 预计审查开销:")
+	for level := minLevel; level <= maxLevel; level++ {
+		var levelEstimates []fileEstimate
+		for _, e := range estimates {
+			levelEstimates = append(levelEstimates, estimateFile(e.path, e.size, cfg.MaxFileSize, level))
+		}
+		t := sumEstimates(levelEstimates)
+		fmt.Printf("  级别 %d: %d 次请求，%s\n", level, t.requests, t.describe(cfg.Pricing))
+	}
+	if !cfg.Pricing.Enabled() {
+		fmt.Println("\n💡 配置 price_input / price_output (每百万 Token 单价) 后可显示费用估算")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	addScanFlags(statsCmd)
+	statsCmd.Flags().Int("top", defaultStatsTop, "列出最大的文件数量")
+}
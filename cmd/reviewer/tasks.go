@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// taskSpec 是显式定义的审查任务，来自 --task 参数或配置文件的 tasks 列表
+// 与位置参数不同，每个字段都有明确的名称，不会把数字形式的报告名误认为级别
+type taskSpec struct {
+	Path  string `mapstructure:"path"`
+	Level int    `mapstructure:"level"` // 0 表示使用默认级别
+	Name  string `mapstructure:"name"`  // 为空时使用目录名
+}
+
+// parseTaskFlags 解析所有 --task 参数，格式为 path=./a,level=5,name=backend（首项可省略 path=）
+func parseTaskFlags(cmd *cobra.Command) ([]taskSpec, error) {
+	values, _ := cmd.Flags().GetStringArray("task")
+	specs := make([]taskSpec, 0, len(values))
+	for _, value := range values {
+		spec, err := parseTaskSpec(value)
+		if err != nil {
+			return nil, fmt.Errorf("--task %q: %w", value, err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// parseTaskSpec 解析单个 --task 参数
+func parseTaskSpec(value string) (taskSpec, error) {
+	var spec taskSpec
+	for i, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			if i > 0 {
+				return spec, fmt.Errorf("%q 缺少 =，格式为 key=value", field)
+			}
+			key, val = "path", field
+		}
+
+		switch key = strings.TrimSpace(key); key {
+		case "path":
+			spec.Path = strings.TrimSpace(val)
+		case "level", "l":
+			level, err := strconv.Atoi(strings.TrimSpace(val))
+			if err != nil {
+				return spec, fmt.Errorf("level 应为整数，实际为 %q", val)
+			}
+			spec.Level = level
+		case "name", "rn":
+			spec.Name = strings.TrimSpace(val)
+		default:
+			return spec, fmt.Errorf("未知字段 %q，可选 path、level、name", key)
+		}
+	}
+	return spec, nil
+}
+
+// configTaskSpecs 读取配置文件中的 tasks 列表，未配置时返回 nil
+func configTaskSpecs() ([]taskSpec, error) {
+	var specs []taskSpec
+	if err := viper.UnmarshalKey("tasks", &specs); err != nil {
+		return nil, fmt.Errorf("tasks 配置格式错误: %w", err)
+	}
+	return specs, nil
+}
+
+// taskSpecPaths 返回任务定义中的路径，用于发现项目级配置
+func taskSpecPaths(specs []taskSpec) []string {
+	paths := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		paths = append(paths, spec.Path)
+	}
+	return paths
+}
+
+// buildSpecTasks 校验任务定义并转换为审查任务
+// 路径必须已存在（指向文件时审查该文件），报告名称不能重复，避免互相覆盖
+func buildSpecTasks(specs []taskSpec) ([]ReviewTask, error) {
+	defaultLvl := getValidLevel(viper.GetInt("level"))
+	names := make(map[string]int, len(specs))
+	tasks := make([]ReviewTask, 0, len(specs))
+
+	for i, spec := range specs {
+		label := fmt.Sprintf("任务 %d", i+1)
+		if spec.Path == "" {
+			return nil, fmt.Errorf("%s 缺少 path", label)
+		}
+		label += " (" + spec.Path + ")"
+
+		task := ReviewTask{Path: spec.Path, ReportName: spec.Name, Level: defaultLvl}
+		switch {
+		case isValidPath(spec.Path):
+		case isValidFile(spec.Path):
+			task.Path, task.Files = ".", []string{spec.Path}
+		default:
+			return nil, fmt.Errorf("%s 的路径不存在", label)
+		}
+
+		if spec.Level != 0 {
+			if !isValidLevel(spec.Level) {
+				return nil, fmt.Errorf("%s 的 level=%d 超出范围 (%d-%d)", label, spec.Level, minLevel, maxLevel)
+			}
+			task.Level = spec.Level
+		}
+		if task.ReportName == "" {
+			task.ReportName = resolveDirectoryName(spec.Path)
+		}
+		if j, ok := names[task.ReportName]; ok {
+			return nil, fmt.Errorf("%s 与任务 %d 的报告名称都是 %q，请用 name 区分", label, j+1, task.ReportName)
+		}
+		names[task.ReportName] = i
+
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
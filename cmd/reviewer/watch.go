@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/llm"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// 默认的防抖间隔：编辑器保存时常连续触发多个事件
+const defaultWatchDebounce = 500 * time.Millisecond
+
+// watchCmd 是 watch 子命令的定义
+var watchCmd = &cobra.Command{
+	Use:   "watch [path]",
+	Short: "监听文件变化并持续审查",
+	Long: `监听目录中的待审查文件（过滤规则与 run 相同），文件保存后只重新审查该文件，
+并持续更新实时报告 reports/<name>-watch.md。`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeDirs,
+	PreRun:            func(cmd *cobra.Command, _ []string) { bindScanFlags(cmd) },
+	Run:               executeWatch,
+}
+
+// watchSession 保存一次监听会话的状态
+type watchSession struct {
+	task    ReviewTask
+	cfg     reviewConfig
+	shared  runResources
+	watcher *fsnotify.Watcher
+	started time.Time
+
+	tracked map[string]struct{}        // 通过过滤规则、需要审查的文件（绝对路径）
+	reports string                     // 报告目录（绝对路径），其中的变化不触发审查
+	results map[string]reviewer.Result // 每个文件最近一次的审查结果
+
+	// 最近一次生成的实时报告，停止监听时保存为审查记录
+	reportPath string
+	meta       reviewer.ReportMeta
+}
+
+// executeWatch 是 watch 命令的主执行函数
+func executeWatch(cmd *cobra.Command, args []string) {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+	mergeProjectConfig(projectDir([]string{root}))
+
+	if err := validateConfig(); err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	if err := validateReviewConfig(); err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	cfg, err := loadReviewConfig()
+	if err != nil {
+		slog.Error("配置错误", "err", err)
+		os.Exit(1)
+	}
+	if !isValidPath(root) {
+		slog.Error("目录不存在", "path", root)
+		os.Exit(1)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Error("创建文件监听失败", "err", err)
+		os.Exit(1)
+	}
+	defer watcher.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := &watchSession{
+		task: ReviewTask{
+			Path:       absRoot,
+			ReportName: getReportName(cmd, root) + "-watch",
+			Level:      getValidLevel(viper.GetInt("level")),
+		},
+		cfg:     cfg,
+		shared:  runResources{usage: llm.NewUsage(nil)},
+		watcher: watcher,
+		started: time.Now(),
+		results: make(map[string]reviewer.Result),
+	}
+	if s.shared.history = openRunHistory(ctx); s.shared.history != nil {
+		defer s.shared.history.Close()
+	}
+	if s.reports, err = filepath.Abs(reportsDir); err != nil {
+		s.reports = reportsDir
+	}
+	if _, err := s.refresh(); err != nil {
+		slog.Error("扫描失败", "err", err)
+		os.Exit(1)
+	}
+
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+	fmt.Printf("👀 正在监听 %s (%d 个文件)，保存文件后自动审查，按 Ctrl+C 退出\n", root, len(s.tracked))
+	s.loop(ctx, max(debounce, 0))
+	s.saveHistory(ctx)
+	fmt.Println("\n👋 已停止监听")
+}
+
+// saveHistory 将最后一次实时报告保存为审查记录（整个会话只保存一次）
+func (s *watchSession) saveHistory(ctx context.Context) {
+	if s.shared.history == nil || s.reportPath == "" {
+		return
+	}
+	results := make([]reviewer.Result, 0, len(s.results))
+	for _, res := range s.results {
+		results = append(results, res)
+	}
+	saveHistory(ctx, s.shared.history, newHistoryRun(taskProject(s.task.Path), results, s.meta, s.reportPath, time.Since(s.started)))
+}
+
+// refresh 重新扫描目录，更新需要审查的文件集合，并监听它们所在的目录
+// 返回本次新增的文件（例如新建目录中的文件，创建时目录尚未被监听）
+func (s *watchSession) refresh() ([]string, error) {
+	files, _, err := scanTaskFiles(s.task, s.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	tracked := make(map[string]struct{}, len(files))
+	dirs := map[string]struct{}{s.task.Path: {}}
+	for _, path := range files {
+		abs, err := filepath.Abs(path)
+		if err != nil || s.inReports(abs) {
+			continue
+		}
+		tracked[abs] = struct{}{}
+		if _, ok := s.tracked[abs]; !ok && s.tracked != nil {
+			added = append(added, abs)
+		}
+
+		// 监听文件所在目录及其到根目录之间的所有目录，以便发现新建的子目录
+		for dir := filepath.Dir(abs); dir != s.task.Path && len(dir) > len(s.task.Path); dir = filepath.Dir(dir) {
+			dirs[dir] = struct{}{}
+		}
+	}
+
+	s.tracked = tracked
+	for dir := range dirs {
+		if err := s.watcher.Add(dir); err != nil {
+			slog.Warn("无法监听目录", "dir", dir, "err", err)
+		}
+	}
+	return added, nil
+}
+
+// inReports 判断路径是否位于报告目录中（报告更新本身不应触发审查）
+func (s *watchSession) inReports(path string) bool {
+	rel, err := filepath.Rel(s.reports, path)
+	return err == nil && (rel == "." || !strings.HasPrefix(rel, ".."))
+}
+
+// loop 收集文件事件，静默 debounce 时间后批量审查变化的文件
+func (s *watchSession) loop(ctx context.Context, debounce time.Duration) {
+	changed := make(map[string]struct{})
+	rescan := false
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("文件监听错误", "err", err)
+
+		case ev, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			if (ev.Has(fsnotify.Chmod) && !ev.Has(fsnotify.Write)) || s.inReports(ev.Name) {
+				continue
+			}
+			changed[ev.Name] = struct{}{}
+			if _, ok := s.tracked[ev.Name]; !ok || ev.Has(fsnotify.Create) {
+				rescan = true
+			}
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			if rescan {
+				added, err := s.refresh()
+				if err != nil {
+					slog.Warn("重新扫描失败", "err", err)
+				}
+				for _, path := range added {
+					changed[path] = struct{}{}
+				}
+				rescan = false
+			}
+			s.review(ctx, changed)
+			changed = make(map[string]struct{})
+		}
+	}
+}
+
+// review 审查变化的文件并更新实时报告，已删除的文件从报告中移除
+func (s *watchSession) review(ctx context.Context, changed map[string]struct{}) {
+	var files []string
+	removed := false
+	for path := range changed {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			if _, ok := s.results[path]; ok {
+				delete(s.results, path)
+				removed = true
+			}
+			continue
+		}
+		if _, ok := s.tracked[path]; ok {
+			files = append(files, path)
+		}
+	}
+	if len(files) == 0 {
+		if removed {
+			s.writeReport()
+		}
+		return
+	}
+	sort.Strings(files)
+
+	task := s.task
+	task.Files = files
+	pt, err := prepareReviewTask(ctx, task, s.cfg, s.shared)
+	if err != nil {
+		slog.Error("准备审查失败", "err", err)
+		return
+	}
+	if len(pt.files) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🔄 %s 正在审查 %d 个文件...\n", time.Now().Format("15:04:05"), len(pt.files))
+	for res := range pt.engine.Start(ctx, pt.files) {
+		s.results[res.FilePath] = res
+		printWatchResult(s.task.Path, res, s.cfg.MinSeverity)
+	}
+	if ctx.Err() == nil {
+		s.writeReport()
+	}
+}
+
+// writeReport 基于所有文件最近一次的结果重新生成实时报告
+func (s *watchSession) writeReport() {
+	results := make([]reviewer.Result, 0, len(s.results))
+	for _, res := range s.results {
+		results = append(results, res)
+	}
+
+	meta := reviewer.ReportMeta{
+		Name:   s.task.ReportName,
+		Level:  s.task.Level,
+		Tokens: s.shared.usage.Total(),
+
+		MinSeverity:   s.cfg.MinSeverity,
+		MinConfidence: s.cfg.MinConfidence,
+		Focus:         s.cfg.Focus,
+	}
+	reportPath, err := reviewer.GenerateMarkdownReport(results, time.Since(s.started), reportsDir, meta)
+	if err != nil {
+		slog.Error("生成报告失败", "err", err)
+		return
+	}
+	s.reportPath, s.meta = reportPath, meta
+	fmt.Printf("📄 报告已更新: %s\n", reportPath)
+}
+
+// printWatchResult 输出单个文件的审查结论，问题数只统计不低于 minSeverity 的问题
+func printWatchResult(root string, res reviewer.Result, minSeverity llm.Severity) {
+	name := res.FilePath
+	if rel, err := filepath.Rel(root, res.FilePath); err == nil {
+		name = rel
+	}
+
+	switch {
+	case res.Error != nil:
+		fmt.Printf("  ❌ %s: %v\n", name, res.Error)
+	case res.Review != nil:
+		fmt.Printf("  ✅ %s 得分 %d，问题 %d 个\n", name, res.Review.Score, len(llm.FilterIssues(res.Review.Issues, minSeverity)))
+	default:
+		fmt.Printf("  ⏭️  %s 已跳过\n", name)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	addScanFlags(watchCmd)
+	watchCmd.Flags().Duration("debounce", defaultWatchDebounce, "文件保存后等待的静默时间，期间的多次修改合并为一次审查")
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/notify"
+	"go-ai-reviewer/internal/app/reviewer"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// webhookKeys 是群机器人 Webhook 的配置项，设置后审查完成时发送运行摘要
+var webhookKeys = []string{"slack_webhook", "dingtalk_webhook", "feishu_webhook", "wecom_webhook"}
+
+// webhookConfig 是审查完成后发送运行摘要的群机器人配置，Webhook 为空的渠道不发送
+type webhookConfig struct {
+	SlackWebhook string
+	SlackChannel string // 覆盖 Slack Webhook 默认的频道，为空时不覆盖
+
+	DingTalkWebhook string
+	DingTalkSecret  string // 钉钉机器人“加签”的密钥，为空时不签名
+
+	FeishuWebhook string
+	FeishuSecret  string // 飞书机器人“签名校验”的密钥，为空时不签名
+
+	WeComWebhook string
+
+	// Hooks 是以 JSON 接收运行摘要的通用 Webhook（webhooks），供下游自动化使用
+	Hooks []notify.Hook
+
+	ReportURL string // 报告所在目录的 URL（如 CI 产物地址），为空时消息中给出本地路径
+}
+
+// hookSpec 是配置文件 webhooks 列表中的一个通用 Webhook
+type hookSpec struct {
+	URL     string            `mapstructure:"url"`
+	Secret  string            `mapstructure:"secret"`  // 签名密钥，可省略
+	Headers map[string]string `mapstructure:"headers"` // 附加的请求头，可省略
+}
+
+// loadWebhookConfig 读取群机器人与通用 Webhook 配置（webhooks 已由 validateWebhooks 校验）
+func loadWebhookConfig() webhookConfig {
+	var specs []hookSpec
+	_ = viper.UnmarshalKey("webhooks", &specs)
+	hooks := make([]notify.Hook, 0, len(specs))
+	for _, spec := range specs {
+		hooks = append(hooks, notify.Hook{URL: spec.URL, Secret: spec.Secret, Headers: spec.Headers})
+	}
+	return webhookConfig{
+		Hooks:           hooks,
+		SlackWebhook:    viper.GetString("slack_webhook"),
+		SlackChannel:    viper.GetString("slack_channel"),
+		DingTalkWebhook: viper.GetString("dingtalk_webhook"),
+		DingTalkSecret:  viper.GetString("dingtalk_secret"),
+		FeishuWebhook:   viper.GetString("feishu_webhook"),
+		FeishuSecret:    viper.GetString("feishu_secret"),
+		WeComWebhook:    viper.GetString("wecom_webhook"),
+		ReportURL:       viper.GetString("notify_report_url"),
+	}
+}
+
+// validateWebhooks 校验已设置的群机器人 Webhook 地址与通用 Webhook 列表
+func validateWebhooks() error {
+	for _, key := range webhookKeys {
+		if webhook := viper.GetString(key); webhook != "" {
+			if err := notify.ValidateWebhook(key, webhook); err != nil {
+				return err
+			}
+		}
+	}
+	var specs []hookSpec
+	if err := viper.UnmarshalKey("webhooks", &specs); err != nil {
+		return fmt.Errorf("webhooks 格式错误: %w", err)
+	}
+	for i, spec := range specs {
+		if u, err := url.Parse(spec.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("webhooks 第 %d 项的 url 应为 https:// 开头的地址", i+1)
+		}
+		for name := range spec.Headers {
+			if !validHeaderName(name) {
+				return fmt.Errorf("webhooks 第 %d 项的请求头 %q 无效", i+1, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateHooks 校验 webhooks：每项为包含 url（必填）、secret 与 headers（请求头映射）的映射
+func validateHooks(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("应为 {url, secret, headers} 形式的列表")
+	}
+	for i, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			return fmt.Errorf("第 %d 项应为 {url, secret, headers} 形式的映射", i+1)
+		}
+		if mappingIndex(item, "url") < 0 {
+			return fmt.Errorf("第 %d 项缺少 url", i+1)
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			key, value := item.Content[j].Value, item.Content[j+1]
+			switch key {
+			case "url", "secret":
+				if value.Kind != yaml.ScalarNode {
+					return fmt.Errorf("第 %d 项的 %s 应为字符串", i+1, key)
+				}
+			case "headers":
+				if value.Kind != yaml.MappingNode {
+					return fmt.Errorf("第 %d 项的 headers 应为 请求头: 值 形式的映射", i+1)
+				}
+				for k := 0; k+1 < len(value.Content); k += 2 {
+					if name := value.Content[k].Value; !validHeaderName(name) || value.Content[k+1].Kind != yaml.ScalarNode {
+						return fmt.Errorf("第 %d 项的请求头 %q 无效", i+1, name)
+					}
+				}
+			default:
+				return fmt.Errorf("第 %d 项包含未知字段 %s (应为 url / secret / headers)", i+1, key)
+			}
+		}
+	}
+	return nil
+}
+
+// validHeaderName 判断是否为合法的 HTTP 请求头名称（不含空白、控制字符与分隔符）
+func validHeaderName(name string) bool {
+	return name != "" && !strings.ContainsFunc(name, func(r rune) bool {
+		return r <= ' ' || r >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r)
+	})
+}
+
+// senders 返回已配置的通知渠道
+func (c webhookConfig) senders() []notify.Sender {
+	var senders []notify.Sender
+	if c.SlackWebhook != "" {
+		senders = append(senders, notify.Slack{Webhook: c.SlackWebhook, Channel: c.SlackChannel})
+	}
+	if c.DingTalkWebhook != "" {
+		senders = append(senders, notify.DingTalk{Webhook: c.DingTalkWebhook, Secret: c.DingTalkSecret})
+	}
+	if c.FeishuWebhook != "" {
+		senders = append(senders, notify.Feishu{Webhook: c.FeishuWebhook, Secret: c.FeishuSecret})
+	}
+	if c.WeComWebhook != "" {
+		senders = append(senders, notify.WeCom{Webhook: c.WeComWebhook})
+	}
+	for _, hook := range c.Hooks {
+		senders = append(senders, hook)
+	}
+	return senders
+}
+
+// reportLink 返回消息中的报告链接：配置了 ReportURL 时拼接报告文件名，否则为报告的绝对路径
+func (c webhookConfig) reportLink(reportPath string) string {
+	if c.ReportURL != "" {
+		return strings.TrimRight(c.ReportURL, "/") + "/" + url.PathEscape(filepath.Base(reportPath))
+	}
+	if abs, err := filepath.Abs(reportPath); err == nil {
+		return abs
+	}
+	return reportPath
+}
+
+// postRunSummary 将任务的运行摘要发送到已配置的群机器人与通用 Webhook，失败时只记录警告，不影响审查结果
+func postRunSummary(ctx context.Context, cfg webhookConfig, project string, results []reviewer.Result, meta reviewer.ReportMeta, duration time.Duration, reportPath string) {
+	senders := cfg.senders()
+	if len(senders) == 0 {
+		return
+	}
+	digest := reviewer.NewRunDigest(results, meta)
+	digest.Name = strings.TrimSuffix(filepath.Base(reportPath), ".md")
+	run := notify.Run{Digest: digest, Duration: duration, ReportLink: cfg.reportLink(reportPath), Project: project, Tokens: meta.Tokens}
+	for _, sender := range senders {
+		if err := sender.Send(ctx, run); err != nil {
+			slog.Warn("发送运行摘要失败", "channel", sender.Name(), "err", err)
+			continue
+		}
+		slog.Info("已发送运行摘要", "channel", sender.Name(), "report", digest.Name)
+	}
+}
@@ -0,0 +1,193 @@
+// Package archive 解压上传的源码压缩包（zip、tar、tar.gz），防止路径穿越与解压炸弹
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxFiles 是压缩包中的最大条目数：文件、目录与被忽略的条目（如符号链接）都计入，
+// 避免由大量目录条目组成的压缩包绕过上限
+const MaxFiles = 20000
+
+// ErrTooLarge 表示解压后的内容超过了大小或文件数上限
+var ErrTooLarge = errors.New("压缩包解压后过大")
+
+// 压缩包格式的识别标志
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte{0x1f, 0x8b}
+	tarMagic  = []byte("ustar")
+)
+
+// tarMagicOffset 是 tar 头部中 "ustar" 标志的偏移
+const tarMagicOffset = 257
+
+// Extract 将压缩包 path 解压到目录 dest，按文件内容识别 zip、tar 与 tar.gz 格式
+// 只解压普通文件与目录，符号链接、设备文件等被忽略；条目路径不能是绝对路径或包含 ..，
+// 解压后的总大小超过 maxSize（大于 0 时）或条目数超过 MaxFiles 时返回 ErrTooLarge
+func Extract(path, dest string, maxSize int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	head := make([]byte, tarMagicOffset+len(tarMagic))
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	x := &extractor{dest: dest, maxSize: maxSize}
+	switch {
+	case bytes.HasPrefix(head, zipMagic):
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		return x.zip(f, info.Size())
+	case bytes.HasPrefix(head, gzipMagic):
+		gz, err := gzip.NewReader(bufio.NewReader(f))
+		if err != nil {
+			return fmt.Errorf("解压 gzip 失败: %w", err)
+		}
+		defer gz.Close()
+		return x.tar(gz)
+	case len(head) > tarMagicOffset && bytes.HasPrefix(head[tarMagicOffset:], tarMagic):
+		return x.tar(f)
+	default:
+		return errors.New("不支持的压缩包格式 (支持 zip、tar、tar.gz)")
+	}
+}
+
+// extractor 记录解压进度，累计大小与条目数用于限制解压炸弹
+type extractor struct {
+	dest    string
+	maxSize int64
+	size    int64
+	entries int
+}
+
+// entry 累计条目数，超过 MaxFiles 时返回 ErrTooLarge
+func (x *extractor) entry() error {
+	if x.entries++; x.entries > MaxFiles {
+		return fmt.Errorf("%w: 超过 %d 个条目", ErrTooLarge, MaxFiles)
+	}
+	return nil
+}
+
+// zip 解压 zip 压缩包
+func (x *extractor) zip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("读取 zip 失败: %w", err)
+	}
+	for _, file := range zr.File {
+		if err := x.entry(); err != nil {
+			return err
+		}
+		switch mode := file.Mode(); {
+		case mode.IsDir():
+			if err := x.dir(file.Name); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := file.Open()
+			if err != nil {
+				return fmt.Errorf("读取 %s 失败: %w", file.Name, err)
+			}
+			err = x.file(file.Name, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tar 解压 tar 压缩包
+func (x *extractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取 tar 失败: %w", err)
+		}
+		if err := x.entry(); err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := x.dir(hdr.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := x.file(hdr.Name, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// target 返回条目在 dest 中的路径，拒绝绝对路径与包含 .. 的路径
+func (x *extractor) target(name string) (string, error) {
+	name = strings.TrimPrefix(filepath.FromSlash(name), "."+string(filepath.Separator))
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("压缩包中的路径不安全: %s", name)
+	}
+	return filepath.Join(x.dest, name), nil
+}
+
+// dir 创建目录条目
+func (x *extractor) dir(name string) error {
+	path, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+// file 写入文件条目，累计大小超过上限时返回 ErrTooLarge
+func (x *extractor) file(name string, r io.Reader) error {
+	path, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if x.maxSize > 0 {
+		r = io.LimitReader(r, x.maxSize-x.size+1)
+	}
+	n, err := io.Copy(out, r)
+	x.size += n
+	if err != nil {
+		return fmt.Errorf("解压 %s 失败: %w", name, err)
+	}
+	if x.maxSize > 0 && x.size > x.maxSize {
+		return fmt.Errorf("%w: 超过 %d 字节", ErrTooLarge, x.maxSize)
+	}
+	return nil
+}
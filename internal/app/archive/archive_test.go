@@ -0,0 +1,241 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// archiveEntry 描述测试压缩包中的一个条目，link 非空时为指向 link 的符号链接
+type archiveEntry struct {
+	name string
+	body string
+	dir  bool
+	link string
+}
+
+// buildTar 生成 tar 压缩包，gz 为 true 时再用 gzip 压缩
+func buildTar(t *testing.T, entries []archiveEntry, gz bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var gw *gzip.Writer
+	tw := tar.NewWriter(&buf)
+	if gz {
+		gw = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gw)
+	}
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(e.body)), Format: tar.FormatUSTAR}
+		switch {
+		case e.dir:
+			hdr.Typeflag, hdr.Mode, hdr.Size = tar.TypeDir, 0755, 0
+		case e.link != "":
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.link, 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("写入 tar 头部失败: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatalf("写入 tar 内容失败: %v", err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("关闭 tar 失败: %v", err)
+	}
+	if gw != nil {
+		if err := gw.Close(); err != nil {
+			t.Fatalf("关闭 gzip 失败: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// buildZip 生成 zip 压缩包
+func buildZip(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		body := e.body
+		switch {
+		case e.dir:
+			hdr.SetMode(fs.ModeDir | 0755)
+		case e.link != "":
+			hdr.SetMode(fs.ModeSymlink | 0777)
+			body = e.link
+		default:
+			hdr.SetMode(0644)
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatalf("写入 zip 头部失败: %v", err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("写入 zip 内容失败: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("关闭 zip 失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// extract 将压缩包写入临时文件后解压到 root 下的 dest 目录，返回解压结果
+// root 中 dest 之外的文件用于检查是否写出了目标目录
+func extract(t *testing.T, data []byte, maxSize int64) (root, dest string, err error) {
+	t.Helper()
+	root = t.TempDir()
+	path := filepath.Join(root, "upload")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("写入压缩包失败: %v", err)
+	}
+	dest = filepath.Join(root, "dest")
+	if err := os.Mkdir(dest, 0755); err != nil {
+		t.Fatalf("创建解压目录失败: %v", err)
+	}
+	return root, dest, Extract(path, dest, maxSize)
+}
+
+// formats 是各压缩包格式的生成函数
+var formats = []struct {
+	name  string
+	build func(*testing.T, []archiveEntry) []byte
+}{
+	{"zip", buildZip},
+	{"tar", func(t *testing.T, e []archiveEntry) []byte { return buildTar(t, e, false) }},
+	{"tar.gz", func(t *testing.T, e []archiveEntry) []byte { return buildTar(t, e, true) }},
+}
+
+func TestExtract(t *testing.T) {
+	entries := []archiveEntry{
+		{name: "src/", dir: true},
+		{name: "src/main.go", body: "package main\n"},
+		{name: "./README.md", body: "# demo\n"},
+		{name: "empty/", dir: true},
+	}
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			_, dest, err := extract(t, format.build(t, entries), 1<<20)
+			if err != nil {
+				t.Fatalf("Extract: %v", err)
+			}
+			for name, want := range map[string]string{"src/main.go": "package main\n", "README.md": "# demo\n"} {
+				got, err := os.ReadFile(filepath.Join(dest, name))
+				if err != nil || string(got) != want {
+					t.Errorf("%s = %q, %v, want %q", name, got, err, want)
+				}
+			}
+			if info, err := os.Stat(filepath.Join(dest, "empty")); err != nil || !info.IsDir() {
+				t.Errorf("empty 目录未创建: %v", err)
+			}
+		})
+	}
+}
+
+func TestExtractUnsafePaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+	}{
+		{"上级目录", []archiveEntry{{name: "../evil.txt", body: "x"}}},
+		{"中间的上级目录", []archiveEntry{{name: "src/../../evil.txt", body: "x"}}},
+		{"上级目录的目录条目", []archiveEntry{{name: "../evil/", dir: true}}},
+		{"绝对路径", []archiveEntry{{name: "/tmp/evil.txt", body: "x"}}},
+	}
+	for _, format := range formats {
+		for _, tt := range tests {
+			t.Run(format.name+"/"+tt.name, func(t *testing.T) {
+				root, _, err := extract(t, format.build(t, tt.entries), 1<<20)
+				if err == nil || !strings.Contains(err.Error(), "不安全") {
+					t.Fatalf("Extract err = %v, want 路径不安全", err)
+				}
+				for _, name := range []string{"evil.txt", "evil"} {
+					if _, err := os.Lstat(filepath.Join(root, name)); err == nil {
+						t.Errorf("在解压目录之外创建了 %s", name)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestExtractSymlinks(t *testing.T) {
+	// 符号链接被忽略，之后经由同名路径写入的文件只会落在解压目录中
+	entries := []archiveEntry{
+		{name: "link", link: ".."},
+		{name: "abs", link: "/etc"},
+		{name: "link/evil.txt", body: "x"},
+	}
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			root, dest, err := extract(t, format.build(t, entries), 1<<20)
+			if err != nil {
+				t.Fatalf("Extract: %v", err)
+			}
+			for _, name := range []string{"link", "abs"} {
+				if info, err := os.Lstat(filepath.Join(dest, name)); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+					t.Errorf("创建了符号链接 %s", name)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(root, "evil.txt")); err == nil {
+				t.Error("经由符号链接写到了解压目录之外")
+			}
+			if _, err := os.Stat(filepath.Join(dest, "link", "evil.txt")); err != nil {
+				t.Errorf("link/evil.txt 未解压到解压目录中: %v", err)
+			}
+		})
+	}
+}
+
+func TestExtractLimits(t *testing.T) {
+	// 目录与被忽略的符号链接同样计入条目数
+	dirs := make([]archiveEntry, MaxFiles+1)
+	links := make([]archiveEntry, MaxFiles+1)
+	for i := range dirs {
+		dirs[i] = archiveEntry{name: "d/", dir: true}
+		links[i] = archiveEntry{name: "l", link: "target"}
+	}
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		maxSize int64
+		wantErr bool
+	}{
+		{"条目数等于上限", dirs[:MaxFiles], 0, false},
+		{"目录条目过多", dirs, 0, true},
+		{"符号链接条目过多", links, 0, true},
+		{"单个文件超过大小上限", []archiveEntry{{name: "big.txt", body: strings.Repeat("a", 101)}}, 100, true},
+		{"累计大小超过上限", []archiveEntry{{name: "a.txt", body: strings.Repeat("a", 60)}, {name: "b.txt", body: strings.Repeat("b", 60)}}, 100, true},
+		{"大小等于上限", []archiveEntry{{name: "a.txt", body: strings.Repeat("a", 50)}, {name: "b.txt", body: strings.Repeat("b", 50)}}, 100, false},
+		{"不限制大小", []archiveEntry{{name: "big.txt", body: strings.Repeat("a", 1000)}}, 0, false},
+	}
+	for _, format := range formats {
+		for _, tt := range tests {
+			t.Run(format.name+"/"+tt.name, func(t *testing.T) {
+				_, _, err := extract(t, format.build(t, tt.entries), tt.maxSize)
+				if got := errors.Is(err, ErrTooLarge); got != tt.wantErr {
+					t.Errorf("Extract err = %v, want ErrTooLarge=%v", err, tt.wantErr)
+				}
+				if !tt.wantErr && err != nil {
+					t.Errorf("Extract: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestExtractUnsupported(t *testing.T) {
+	if _, _, err := extract(t, []byte("plain text, not an archive"), 0); err == nil {
+		t.Fatal("Extract 接受了不支持的格式")
+	}
+}
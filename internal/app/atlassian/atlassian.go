@@ -0,0 +1,90 @@
+// Package atlassian 封装 Jira 与 Confluence 共用的 REST 请求：JSON 请求与响应、账号邮箱加 API Token 的 Basic 认证
+// (Cloud) 或个人访问令牌的 Bearer 认证 (Server / Data Center)，以及错误响应的解析
+package atlassian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/pkg/utils"
+)
+
+// maxResponseSize 是读取的响应体上限
+const maxResponseSize = 1 << 20
+
+// Client 发送 Atlassian REST API 请求
+type Client struct {
+	url   string
+	email string
+	token string
+	http  *http.Client
+}
+
+// New 创建客户端；email 为空时 token 作为个人访问令牌以 Bearer 认证发送
+func New(baseURL, email, token string, timeout time.Duration) *Client {
+	return &Client{
+		url:   strings.TrimRight(baseURL, "/"),
+		email: email,
+		token: token,
+		http:  &http.Client{Timeout: timeout},
+	}
+}
+
+// APIError 是非 2xx 的响应
+type APIError struct {
+	Status int
+	Detail string // 响应中的错误信息，无法解析时为截断的原文
+}
+
+// Error 返回状态码与错误信息
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Detail)
+}
+
+// Do 发送请求，body 不为空时以 JSON 发送，out 不为空时解析 JSON 响应；非 2xx 的响应返回 *APIError
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if resp.StatusCode/100 != 2 {
+		return &APIError{Status: resp.StatusCode, Detail: utils.ErrorDetail(data)}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package confluence 将审查报告发布到 Confluence 页面：空间中已有同名页面时更新（保留历史版本），否则在父页面下创建
+package confluence
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/atlassian"
+)
+
+// requestTimeout 是单个 Confluence 请求的时限（报告页面可能较大）
+const requestTimeout = 30 * time.Second
+
+// Config 是 Confluence 连接与页面位置配置
+type Config struct {
+	URL      string // 站点地址，Confluence Cloud 为 https://acme.atlassian.net/wiki
+	Email    string // Confluence Cloud 的账号邮箱，与 Token 组成 Basic 认证；为空时 Token 作为个人访问令牌 (Server / Data Center)
+	Token    string // API Token 或个人访问令牌
+	Space    string // 空间 Key，如 SEC
+	ParentID string // 新建页面的父页面 ID，为空时创建在空间根目录
+}
+
+// Client 调用 Confluence REST API (/rest/api/content，Cloud 与 Server / Data Center 均支持)
+type Client struct {
+	cfg Config
+	api *atlassian.Client
+}
+
+// New 创建 Confluence 客户端
+func New(cfg Config) *Client {
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	return &Client{cfg: cfg, api: atlassian.New(cfg.URL, cfg.Email, cfg.Token, requestTimeout)}
+}
+
+// page 是 content 接口返回的页面
+type page struct {
+	ID      string `json:"id"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// Publish 以存储格式 (XHTML) 的 body 发布页面，返回页面地址与是否为新建
+// message 写入页面的版本说明，更新时在页面历史中可见
+func (c *Client) Publish(ctx context.Context, title, body, message string) (link string, created bool, err error) {
+	existing, err := c.find(ctx, title)
+	if err != nil {
+		return "", false, err
+	}
+
+	content := map[string]any{
+		"type":  "page",
+		"title": title,
+		"space": map[string]string{"key": c.cfg.Space},
+		"body":  map[string]any{"storage": map[string]string{"value": body, "representation": "storage"}},
+	}
+	var resp page
+	if existing != nil {
+		content["id"] = existing.ID
+		content["version"] = map[string]any{"number": existing.Version.Number + 1, "message": message}
+		if err := c.api.Do(ctx, http.MethodPut, "/rest/api/content/"+url.PathEscape(existing.ID), content, &resp); err != nil {
+			return "", false, fmt.Errorf("更新页面 %s 失败: %w", existing.ID, err)
+		}
+	} else {
+		if c.cfg.ParentID != "" {
+			content["ancestors"] = []map[string]string{{"id": c.cfg.ParentID}}
+		}
+		if err := c.api.Do(ctx, http.MethodPost, "/rest/api/content", content, &resp); err != nil {
+			return "", false, fmt.Errorf("创建页面失败: %w", err)
+		}
+	}
+	return c.pageURL(resp), existing == nil, nil
+}
+
+// find 返回空间中标题为 title 的页面（Confluence 中同一空间的页面标题唯一），不存在时返回 nil
+func (c *Client) find(ctx context.Context, title string) (*page, error) {
+	query := url.Values{
+		"spaceKey": {c.cfg.Space},
+		"title":    {title},
+		"type":     {"page"},
+		"expand":   {"version"},
+	}
+	var resp struct {
+		Results []page `json:"results"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &resp); err != nil {
+		return nil, fmt.Errorf("查询已有页面失败: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return nil, nil
+	}
+	return &resp.Results[0], nil
+}
+
+// pageURL 返回页面的浏览地址，响应中没有链接时返回站点地址加页面 ID 的形式
+func (c *Client) pageURL(p page) string {
+	if p.Links.WebUI != "" {
+		base := p.Links.Base
+		if base == "" {
+			base = c.cfg.URL
+		}
+		return base + p.Links.WebUI
+	}
+	return c.cfg.URL + "/pages/viewpage.action?pageId=" + url.QueryEscape(p.ID)
+}
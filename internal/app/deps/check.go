@@ -0,0 +1,173 @@
+package deps
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// knownIssue 描述一个已知有问题的包
+type knownIssue struct {
+	category   string
+	severity   llm.Severity
+	message    string
+	suggestion string
+}
+
+// knownIssues 是已知弃用、停止维护或发生过投毒事件的包，按 "生态:包名" 索引
+var knownIssues = map[string]knownIssue{
+	"go:github.com/dgrijalva/jwt-go": {CategoryRisky, llm.SeverityMajor,
+		"已停止维护，存在 audience 校验绕过漏洞 (CVE-2020-26160)", "迁移到 github.com/golang-jwt/jwt/v5"},
+	"go:github.com/satori/go.uuid": {CategoryRisky, llm.SeverityMajor,
+		"已停止维护，部分版本生成的 UUID 随机性不足", "迁移到 github.com/google/uuid 或 github.com/gofrs/uuid"},
+	"go:github.com/golang/protobuf": {CategoryOutdated, llm.SeverityMinor,
+		"已被 google.golang.org/protobuf 取代", "迁移到 google.golang.org/protobuf"},
+	"go:github.com/pkg/errors": {CategoryOutdated, llm.SeverityMinor,
+		"仓库已归档，标准库的 errors 与 fmt.Errorf(\"%w\") 已覆盖其功能", "改用标准库 errors"},
+	"go:github.com/go-yaml/yaml": {CategoryOutdated, llm.SeverityMinor,
+		"仓库已归档不再维护", "迁移到 go.yaml.in/yaml/v3"},
+	"node:request": {CategoryOutdated, llm.SeverityMajor,
+		"已弃用且不再修复安全问题", "改用 Node.js 内置的 fetch、undici 或 axios"},
+	"node:node-uuid": {CategoryOutdated, llm.SeverityMinor,
+		"已更名为 uuid", "改用 uuid"},
+	"node:event-stream": {CategoryRisky, llm.SeverityCritical,
+		"曾发生投毒事件 (3.3.6 版本通过 flatmap-stream 窃取加密钱包)", "移除该依赖，改用 Node.js 内置的 stream"},
+	"node:colors": {CategoryRisky, llm.SeverityMajor,
+		"维护者在 1.4.44-liberty-2 等版本中植入无限循环", "锁定 1.4.0 或改用 picocolors / chalk"},
+	"node:faker": {CategoryRisky, llm.SeverityMajor,
+		"原作者删除了代码并发布恶意的 6.6.6 版本", "改用社区维护的 @faker-js/faker"},
+	"node:tslint": {CategoryOutdated, llm.SeverityMinor,
+		"已弃用", "迁移到 eslint 与 typescript-eslint"},
+	"node:node-sass": {CategoryOutdated, llm.SeverityMinor,
+		"已弃用 (LibSass 停止维护)", "改用 sass (Dart Sass)"},
+	"node:babel-eslint": {CategoryOutdated, llm.SeverityMinor,
+		"已更名为 @babel/eslint-parser", "改用 @babel/eslint-parser"},
+	"node:crypto": {CategoryRisky, llm.SeverityMinor,
+		"npm 上的 crypto 包已弃用，Node.js 已内置同名模块", "移除该依赖，直接使用内置的 crypto 模块"},
+	"python:pycrypto": {CategoryRisky, llm.SeverityMajor,
+		"已停止维护，存在堆溢出漏洞 (CVE-2013-7459)", "迁移到 pycryptodome 或 cryptography"},
+	"python:sklearn": {CategoryRisky, llm.SeverityMajor,
+		"是已弃用的占位包，安装时直接报错", "改用 scikit-learn"},
+	"python:nose": {CategoryOutdated, llm.SeverityMinor,
+		"已停止维护，不支持新版本 Python", "迁移到 pytest"},
+}
+
+// 版本写法
+var (
+	goPseudoVersionRegex = regexp.MustCompile(`-\d{14}-[0-9a-f]{12}(\+incompatible)?$`)
+	nodeRemoteRegex      = regexp.MustCompile(`^(git\+|git:|github:|gitlab:|bitbucket:|https?://)|^[\w.-]+/[\w.-]+(#.*)?$`)
+)
+
+// Check 用本地规则检查依赖清单：已知有问题的包、版本写法、来源，以及与项目许可证的兼容性
+// projectLicense 为项目的 SPDX 许可证标识，未声明时为空（按闭源处理）
+func Check(m *Manifest, projectLicense string) []Finding {
+	var findings []Finding
+	add := func(d Dependency, f Finding) {
+		f.Dependency, f.Line, f.Local = d.Name, d.Line, true
+		findings = append(findings, f)
+	}
+
+	for _, d := range m.Deps {
+		if known, ok := knownIssues[m.Ecosystem+":"+strings.ToLower(d.Name)]; ok {
+			add(d, Finding{Category: known.category, Severity: known.severity, Message: known.message, Suggestion: known.suggestion})
+		}
+		if f, ok := checkVersion(m.Ecosystem, d); ok {
+			add(d, f)
+		}
+		if d.License != "" && !d.Dev {
+			if f, ok := licenseConflict(projectLicense, d.License); ok {
+				f.Category = CategoryLicense
+				add(d, f)
+			}
+		}
+	}
+
+	for _, r := range m.Replaces {
+		if strings.HasPrefix(r.New, ".") || strings.HasPrefix(r.New, "/") {
+			findings = append(findings, Finding{Dependency: r.Old, Line: r.Line, Category: CategoryPinning, Severity: llm.SeverityMajor, Local: true,
+				Message:    "replace 指向本地目录 " + r.New + "，其他环境与依赖该模块的项目无法构建",
+				Suggestion: "发布修改后的版本，或改用 go.work 在本地开发时替换"})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+	return findings
+}
+
+// checkVersion 检查依赖的版本写法与来源
+func checkVersion(ecosystem string, d Dependency) (Finding, bool) {
+	v := d.Version
+	switch ecosystem {
+	case EcosystemGo:
+		if goPseudoVersionRegex.MatchString(v) {
+			return Finding{Category: CategoryPinning, Severity: llm.SeverityMinor,
+				Message:    "使用伪版本 " + v + "（未发布的提交）",
+				Suggestion: "升级到正式发布的版本，便于追踪安全公告"}, true
+		}
+	case EcosystemNode:
+		switch {
+		case v == "" || v == "*" || v == "latest" || v == "x":
+			return Finding{Category: CategoryPinning, Severity: llm.SeverityMajor,
+				Message:    "未锁定版本 (" + displayVersion(v) + ")，每次安装都可能引入破坏性变更或被投毒的新版本",
+				Suggestion: "指定版本范围 (如 ^1.2.3) 并提交锁文件"}, true
+		case strings.HasPrefix(v, "file:") || strings.HasPrefix(v, "link:"):
+			return Finding{Category: CategoryPinning, Severity: llm.SeverityMinor,
+				Message:    "依赖本地路径 " + v + "，在其他环境中可能不存在",
+				Suggestion: "改用 workspaces 或发布到私有仓库"}, true
+		case nodeRemoteRegex.MatchString(v):
+			return Finding{Category: CategoryRisky, Severity: llm.SeverityMajor,
+				Message:    "直接依赖 Git 仓库或 URL (" + v + ")，绕过了仓库的审计与完整性校验",
+				Suggestion: "改用已发布到 npm 的版本，或至少固定到具体的提交"}, true
+		case (strings.HasPrefix(v, ">") || strings.Contains(v, "||")) && !strings.Contains(v, "<"):
+			return Finding{Category: CategoryPinning, Severity: llm.SeverityMinor,
+				Message:    "版本范围 " + v + " 没有上限，可能自动升级到不兼容的大版本",
+				Suggestion: "使用 ^ 或 ~ 限定大版本"}, true
+		}
+	case EcosystemPython:
+		switch {
+		case strings.HasPrefix(v, "-e ") || strings.Contains(v, "://"):
+			return Finding{Category: CategoryRisky, Severity: llm.SeverityMajor,
+				Message:    "直接依赖 Git 仓库或 URL，绕过了 PyPI 的完整性校验",
+				Suggestion: "改用已发布的版本，或至少固定到具体的提交并使用 --require-hashes"}, true
+		case v == "":
+			return Finding{Category: CategoryPinning, Severity: llm.SeverityMinor,
+				Message:    "未指定版本，每次安装都可能得到不同的版本",
+				Suggestion: "使用 == 固定版本，或使用 pip-tools 等工具生成锁定文件"}, true
+		case strings.HasPrefix(v, ">") && !strings.Contains(v, "<") && !strings.Contains(v, "~="):
+			return Finding{Category: CategoryPinning, Severity: llm.SeverityMinor,
+				Message:    "版本约束 " + v + " 没有上限，可能自动升级到不兼容的大版本",
+				Suggestion: "增加上限 (如 >=2.0,<3) 或使用 ~= 兼容版本约束"}, true
+		}
+	}
+	return Finding{}, false
+}
+
+// Merge 合并本地规则与模型给出的问题：模型重复报告本地规则已发现的同一依赖、同一类别的问题时以本地规则为准
+func Merge(local, model []Finding) []Finding {
+	seen := make(map[string]struct{}, len(local))
+	for _, f := range local {
+		seen[strings.ToLower(f.Dependency)+":"+f.Category] = struct{}{}
+	}
+	merged := append([]Finding(nil), local...)
+	for _, f := range model {
+		if _, ok := seen[strings.ToLower(f.Dependency)+":"+f.Category]; !ok {
+			merged = append(merged, f)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Severity > merged[j].Severity
+	})
+	return merged
+}
+
+// displayVersion 返回版本的显示文本，未指定时为 "未指定"
+func displayVersion(v string) string {
+	if v == "" {
+		return "未指定"
+	}
+	return v
+}
@@ -0,0 +1,299 @@
+// Package deps 解析依赖清单（go.mod、package.json、requirements.txt），
+// 用本地规则检查依赖的版本写法、来源、已知有问题的包与许可证兼容性，供 reviewer deps 使用
+package deps
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// 支持的生态
+const (
+	EcosystemGo     = "go"
+	EcosystemNode   = "node"
+	EcosystemPython = "python"
+)
+
+// ecosystemLabels 是生态在报告中的显示名称
+var ecosystemLabels = map[string]string{
+	EcosystemGo:     "Go",
+	EcosystemNode:   "Node.js",
+	EcosystemPython: "Python",
+}
+
+// EcosystemLabel 返回生态的显示名称
+func EcosystemLabel(ecosystem string) string {
+	return ecosystemLabels[ecosystem]
+}
+
+// Dependency 是依赖清单中声明的一个依赖
+type Dependency struct {
+	Name     string
+	Version  string // 版本或版本约束，如 v1.9.1、^18.2.0、>=2.0；未指定时为空
+	Line     int    // 在清单中的行号，0 表示未知
+	Dev      bool   // 开发依赖（devDependencies、requirements-dev.txt），不随产品分发，不检查许可证
+	Indirect bool   // go.mod 中标记为 // indirect 的间接依赖
+	License  string // 从本地模块缓存或 node_modules 识别的许可证，未知时为空
+}
+
+// Replace 是 go.mod 中的 replace 指令
+type Replace struct {
+	Old  string
+	New  string // 替换目标：模块路径（可带版本）或本地目录
+	Line int
+}
+
+// Manifest 是解析后的依赖清单
+type Manifest struct {
+	Path      string
+	Ecosystem string
+	Content   string // 清单原文，发送给模型
+	Deps      []Dependency
+	Replaces  []Replace
+}
+
+// Dependency 按名称查找依赖
+func (m *Manifest) Dependency(name string) (Dependency, bool) {
+	for _, d := range m.Deps {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// 问题类别
+const (
+	CategoryOutdated = "outdated" // 版本过旧、已停止维护或已弃用
+	CategoryRisky    = "risky"    // 已知漏洞、投毒事件、不可信来源
+	CategoryLicense  = "license"  // 许可证与项目不兼容
+	CategoryPinning  = "pinning"  // 版本未锁定、伪版本等写法问题
+)
+
+// categoryLabels 是问题类别在报告中的显示名称
+var categoryLabels = map[string]string{
+	CategoryOutdated: "过时",
+	CategoryRisky:    "风险",
+	CategoryLicense:  "许可证",
+	CategoryPinning:  "版本写法",
+}
+
+// CategoryLabel 返回问题类别的显示名称
+func CategoryLabel(category string) string {
+	if label, ok := categoryLabels[category]; ok {
+		return label
+	}
+	return category
+}
+
+// NormalizeCategory 规范化模型给出的类别，无法识别时视为风险
+func NormalizeCategory(category string) string {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if _, ok := categoryLabels[category]; ok {
+		return category
+	}
+	return CategoryRisky
+}
+
+// Finding 是依赖审查发现的一个问题
+type Finding struct {
+	Dependency string // 依赖名称，针对整个清单时为空
+	Line       int
+	Category   string
+	Severity   llm.Severity
+	Message    string
+	Suggestion string
+	Local      bool // 由本地规则发现（否则由模型给出）
+}
+
+// Review 是一个依赖清单的审查结果
+type Review struct {
+	Manifest *Manifest
+	Summary  string // 模型给出的总结，只运行本地规则时为空
+	Findings []Finding
+	Err      error // 模型审查失败的原因，本地规则的结果仍然有效
+}
+
+// manifestNames 是支持的清单文件名，requirements 另外匹配 requirements-*.txt 等写法
+var manifestNames = map[string]string{
+	"go.mod":       EcosystemGo,
+	"package.json": EcosystemNode,
+}
+
+// requirementsRegex 匹配 requirements.txt、requirements-dev.txt、requirements/prod.txt 中的文件名部分
+var requirementsRegex = regexp.MustCompile(`(?i)^requirements([._-][\w.-]*)?\.txt$`)
+
+// EcosystemOf 返回清单文件的生态，不是支持的清单时返回空字符串
+func EcosystemOf(path string) string {
+	name := filepath.Base(path)
+	if ecosystem, ok := manifestNames[name]; ok {
+		return ecosystem
+	}
+	if requirementsRegex.MatchString(name) || filepath.Base(filepath.Dir(path)) == "requirements" && strings.HasSuffix(name, ".txt") {
+		return EcosystemPython
+	}
+	return ""
+}
+
+// ManifestExts 是清单文件的扩展名，用于扫描时预先筛选
+var ManifestExts = []string{".mod", ".json", ".txt"}
+
+// Parse 读取并解析依赖清单
+func Parse(path string) (*Manifest, error) {
+	ecosystem := EcosystemOf(path)
+	if ecosystem == "" {
+		return nil, fmt.Errorf("不支持的依赖清单: %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取依赖清单失败: %w", err)
+	}
+	m := &Manifest{Path: path, Ecosystem: ecosystem, Content: string(data)}
+	switch ecosystem {
+	case EcosystemGo:
+		m.Deps, m.Replaces = parseGoMod(m.Content)
+	case EcosystemNode:
+		if m.Deps, err = parsePackageJSON(m.Content); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+		}
+	case EcosystemPython:
+		m.Deps = parseRequirements(m.Content, isDevRequirements(path))
+	}
+	return m, nil
+}
+
+// parseGoMod 解析 go.mod 中的 require 与 replace 指令
+func parseGoMod(content string) ([]Dependency, []Replace) {
+	var deps []Dependency
+	var replaces []Replace
+	block := ""
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line, comment, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			fields = append([]string{block}, fields...)
+		} else if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+
+		switch fields[0] {
+		case "require":
+			if len(fields) >= 3 {
+				deps = append(deps, Dependency{Name: fields[1], Version: fields[2], Line: n, Indirect: strings.TrimSpace(comment) == "indirect"})
+			}
+		case "replace":
+			old, target, ok := strings.Cut(strings.Join(fields[1:], " "), "=>")
+			if ok {
+				oldFields := strings.Fields(old)
+				if len(oldFields) > 0 {
+					replaces = append(replaces, Replace{Old: oldFields[0], New: strings.TrimSpace(target), Line: n})
+				}
+			}
+		}
+	}
+	return deps, replaces
+}
+
+// parsePackageJSON 解析 package.json 中的运行时、对等与开发依赖，行号通过查找键名确定
+func parsePackageJSON(content string) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return nil, err
+	}
+	lines := strings.Split(content, "\n")
+	lineOf := func(name string) int {
+		key := `"` + name + `"`
+		for i, l := range lines {
+			if strings.Contains(l, key) {
+				return i + 1
+			}
+		}
+		return 0
+	}
+
+	var deps []Dependency
+	add := func(m map[string]string, dev bool) {
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			deps = append(deps, Dependency{Name: name, Version: strings.TrimSpace(m[name]), Line: lineOf(name), Dev: dev})
+		}
+	}
+	add(pkg.Dependencies, false)
+	add(pkg.PeerDependencies, false)
+	add(pkg.OptionalDependencies, false)
+	add(pkg.DevDependencies, true)
+	return deps, nil
+}
+
+// requirementRegex 匹配 requirements.txt 中的 "名称[extras] 版本约束"
+var requirementRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*([^;]*)`)
+
+// parseRequirements 解析 requirements.txt，-e 与 URL 形式的依赖以整行作为版本
+func parseRequirements(content string, dev bool) []Dependency {
+	var deps []Dependency
+	for i, line := range strings.Split(content, "\n") {
+		line, _, _ = strings.Cut(line, " #")
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "-e ") || strings.Contains(line, "://"):
+			deps = append(deps, Dependency{Name: requirementURLName(line), Version: line, Line: i + 1, Dev: dev})
+		case strings.HasPrefix(line, "-"):
+			// -r、-c、--index-url 等 pip 选项
+		default:
+			if m := requirementRegex.FindStringSubmatch(line); m != nil {
+				deps = append(deps, Dependency{Name: m[1], Version: strings.TrimSpace(m[2]), Line: i + 1, Dev: dev})
+			}
+		}
+	}
+	return deps
+}
+
+// requirementURLName 返回 URL 依赖的名称：优先使用 #egg= 或 "名称 @ URL" 中的名称，否则使用 URL 最后一段
+func requirementURLName(line string) string {
+	if _, egg, ok := strings.Cut(line, "#egg="); ok {
+		return strings.Fields(egg)[0]
+	}
+	if name, _, ok := strings.Cut(line, " @ "); ok {
+		return strings.TrimSpace(name)
+	}
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "-e "), "/")
+	return strings.TrimSuffix(line[strings.LastIndex(line, "/")+1:], ".git")
+}
+
+// isDevRequirements 判断 requirements 文件是否只包含开发依赖（如 requirements-dev.txt、requirements/test.txt）
+func isDevRequirements(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	for _, marker := range []string{"dev", "test", "lint", "doc"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
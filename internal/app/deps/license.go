@@ -0,0 +1,214 @@
+package deps
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// LicenseKind 是许可证的类别，决定与项目许可证的兼容性
+type LicenseKind int
+
+// 许可证类别
+const (
+	LicenseUnknown         LicenseKind = iota
+	LicensePermissive                  // MIT、BSD、Apache-2.0、ISC 等宽松许可证
+	LicenseWeakCopyleft                // LGPL、MPL、EPL：修改依赖本身时需要公开修改
+	LicenseStrongCopyleft              // GPL：分发时整个作品需要以 GPL 公开源码
+	LicenseNetworkCopyleft             // AGPL：通过网络提供服务也需要公开源码
+)
+
+// ClassifyLicense 按 SPDX 标识（或常见写法）判断许可证类别
+func ClassifyLicense(id string) LicenseKind {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	switch {
+	case id == "":
+		return LicenseUnknown
+	case strings.Contains(id, "AGPL"):
+		return LicenseNetworkCopyleft
+	case strings.Contains(id, "LGPL"), strings.HasPrefix(id, "MPL"), strings.HasPrefix(id, "EPL"), strings.HasPrefix(id, "CDDL"):
+		return LicenseWeakCopyleft
+	case strings.Contains(id, "GPL"):
+		return LicenseStrongCopyleft
+	}
+	for _, prefix := range []string{"MIT", "BSD", "APACHE", "ISC", "0BSD", "UNLICENSE", "ZLIB", "CC0", "PYTHON", "PSF", "BSL-1.0", "WTFPL"} {
+		if strings.HasPrefix(id, prefix) {
+			return LicensePermissive
+		}
+	}
+	return LicenseUnknown
+}
+
+// licenseMarkers 按许可证正文中的特征语句识别许可证，顺序即优先级（LGPL/AGPL 须在 GPL 之前）
+var licenseMarkers = []struct {
+	id      string
+	markers []string // 全部出现时匹配（已统一为小写并合并空白）
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license"}},
+	{"MPL-2.0", []string{"mozilla public license"}},
+	{"EPL-2.0", []string{"eclipse public license"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software"}},
+	{"Unlicense", []string{"this is free and unencumbered software"}},
+}
+
+// DetectLicenseText 根据许可证正文识别 SPDX 标识，无法识别时返回空字符串
+func DetectLicenseText(text string) string {
+	text = strings.Join(strings.FieldsFunc(strings.ToLower(text), unicode.IsSpace), " ")
+	for _, l := range licenseMarkers {
+		matched := true
+		for _, marker := range l.markers {
+			if !strings.Contains(text, marker) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return l.id
+		}
+	}
+	return ""
+}
+
+// licenseFileNames 是常见的许可证文件名
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "LICENCE.md", "COPYING", "COPYING.md", "license", "license.md"}
+
+// dirLicense 识别目录中许可证文件的许可证
+func dirLicense(dir string) string {
+	for _, name := range licenseFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if id := DetectLicenseText(string(data)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// packageJSONLicense 读取 package.json 的 license 字段（兼容旧的 {"type": ...} 写法）
+func packageJSONLicense(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		License json.RawMessage `json:"license"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.License) == 0 {
+		return ""
+	}
+	var id string
+	if json.Unmarshal(pkg.License, &id) == nil {
+		return id
+	}
+	var typed struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(pkg.License, &typed)
+	return typed.Type
+}
+
+// ProjectLicense 识别项目的许可证：优先读取根目录的许可证文件，其次读取 package.json 的 license 字段
+func ProjectLicense(root string) string {
+	if id := dirLicense(root); id != "" {
+		return id
+	}
+	return packageJSONLicense(filepath.Join(root, "package.json"))
+}
+
+// ResolveLicenses 从本机已下载的依赖中识别许可证：Go 模块缓存与清单旁的 node_modules
+// Python 依赖没有可靠的本地来源，留给模型判断
+func ResolveLicenses(m *Manifest) {
+	for i := range m.Deps {
+		d := &m.Deps[i]
+		switch m.Ecosystem {
+		case EcosystemGo:
+			if dir := goModuleDir(d.Name, d.Version); dir != "" {
+				d.License = dirLicense(dir)
+			}
+		case EcosystemNode:
+			pkgDir := filepath.Join(filepath.Dir(m.Path), "node_modules", filepath.FromSlash(d.Name))
+			if d.License = packageJSONLicense(filepath.Join(pkgDir, "package.json")); d.License == "" {
+				d.License = dirLicense(pkgDir)
+			}
+		}
+	}
+}
+
+// goModuleDir 返回模块在本机模块缓存中的目录，未下载时返回空字符串
+func goModuleDir(module, version string) string {
+	cache := os.Getenv("GOMODCACHE")
+	if cache == "" {
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return ""
+			}
+			gopath = filepath.Join(home, "go")
+		}
+		cache = filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	dir := filepath.Join(cache, filepath.FromSlash(escapeModulePath(module))+"@"+escapeModulePath(version))
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// escapeModulePath 按模块缓存的规则转义大写字母（"A" 转为 "!a"）
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// licenseConflict 判断依赖许可证与项目许可证的兼容性，兼容时返回 ok=false
+// 项目未声明许可证时按闭源处理，与宽松许可证的项目适用相同的规则
+func licenseConflict(project, dep string) (f Finding, ok bool) {
+	projectKind := ClassifyLicense(project)
+	subject := "项目的 " + project + " 许可证"
+	if project == "" {
+		subject = "未声明许可证 (按闭源处理) 的项目"
+	}
+	switch ClassifyLicense(dep) {
+	case LicenseNetworkCopyleft:
+		if projectKind == LicenseNetworkCopyleft {
+			return f, false
+		}
+		return Finding{Severity: llm.SeverityMajor, Message: dep + " 要求通过网络提供服务时也公开完整源码，与" + subject + "不兼容",
+			Suggestion: "替换为宽松许可证的实现，或取得商业授权"}, true
+	case LicenseStrongCopyleft:
+		if projectKind == LicenseStrongCopyleft || projectKind == LicenseNetworkCopyleft {
+			return f, false
+		}
+		return Finding{Severity: llm.SeverityMajor, Message: dep + " 要求分发时整个作品以 GPL 公开源码，与" + subject + "不兼容",
+			Suggestion: "替换为宽松许可证的实现，或确认只在内部使用、不分发"}, true
+	case LicenseWeakCopyleft:
+		if projectKind != LicensePermissive && projectKind != LicenseUnknown {
+			return f, false
+		}
+		return Finding{Severity: llm.SeverityMinor, Message: dep + " 为弱 copyleft 许可证：修改该依赖本身的代码时需要按原许可证公开修改",
+			Suggestion: "不修改依赖源码即可正常使用；静态链接 LGPL 库时需允许用户替换该库"}, true
+	}
+	return f, false
+}
@@ -0,0 +1,179 @@
+// Package duplicates 在本地检测扫描文件之间（及文件内部）的复制粘贴代码，
+// 作为项目级的问题写入报告，弥补逐文件 LLM 审查看不到其他文件的不足
+package duplicates
+
+import (
+	"bytes"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/app/textenc"
+)
+
+// 检测限制
+const (
+	// DefaultMinLines 是默认的最小重复行数（不含空行与注释行）
+	DefaultMinLines = 10
+	// MinLines 是允许配置的最小重复行数，更短的片段误报过多
+	MinLines = 3
+
+	// minTokensPerLine 是重复片段平均每行至少包含的词法单元数，
+	// 排除 import 列表、右括号等只由少量单元组成的常见片段
+	minTokensPerLine = 4
+	// maxFileSize 是参与检测的最大文件大小，更大的文件多为数据或生成代码
+	maxFileSize = 1 << 20
+	// maxBucket 是同一片段出现次数的上限，超过时视为样板代码不再逐对比较
+	maxBucket = 50
+)
+
+// Block 是重复片段在一个文件中的位置
+type Block struct {
+	File      string
+	StartLine int // 起始行号（从 1 开始）
+	EndLine   int // 结束行号（含）
+}
+
+// Clone 表示两处内容相同的代码片段（忽略缩进、空白与注释）
+type Clone struct {
+	A, B   Block
+	Lines  int // 重复的代码行数（不含空行与注释行）
+	Tokens int // 重复片段的词法单元数
+}
+
+// line 是规范化后的一行代码
+type line struct {
+	text   string // 以空格连接的词法单元
+	no     int    // 原始行号
+	tokens int
+}
+
+// location 是某个窗口在文件中的位置
+type location struct {
+	file  int
+	index int // 规范化行的下标
+}
+
+// tokenRegex 将一行代码切分为标识符、数字、字符串与单个符号
+var tokenRegex = regexp.MustCompile(`[\p{L}_][\p{L}\p{N}_]*|\d[\w.]*|"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\S`)
+
+// commentPrefixes 是整行注释的开头，这些行不参与比较
+var commentPrefixes = []string{"//", "#", "/*", "*", "<!--", "--"}
+
+// Detect 检测 files 中至少 minLines 行（不含空行与注释行）的重复片段，按重复行数降序返回
+// 无法读取、过大或二进制的文件被忽略；同一文件内互不重叠的重复片段同样会报告
+func Detect(files []string, minLines int) []Clone {
+	minLines = max(minLines, MinLines)
+
+	contents := make([][]line, len(files))
+	windows := make([][]uint64, len(files))
+	index := make(map[uint64][]location)
+	for i, file := range files {
+		contents[i] = readLines(file)
+		windows[i] = hashWindows(contents[i], minLines)
+		for j, h := range windows[i] {
+			index[h] = append(index[h], location{file: i, index: j})
+		}
+	}
+
+	var clones []Clone
+	for a := range files {
+		for i, h := range windows[a] {
+			bucket := index[h]
+			if len(bucket) > maxBucket {
+				continue
+			}
+			for _, loc := range bucket {
+				b, j := loc.file, loc.index
+				if b < a || (b == a && j < i+minLines) {
+					continue
+				}
+				if c, ok := match(contents[a], contents[b], i, j, a == b, minLines); ok {
+					c.A.File, c.B.File = files[a], files[b]
+					clones = append(clones, c)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(clones, func(i, j int) bool {
+		return clones[i].Lines > clones[j].Lines
+	})
+	return clones
+}
+
+// match 从 a[i] 与 b[j] 开始向后扩展相同的代码行，返回完整的重复片段
+// 前一行也相同时该位置不是片段的起点（由更早的位置报告），返回 false
+func match(a, b []line, i, j int, sameFile bool, minLines int) (Clone, bool) {
+	if i > 0 && j > 0 && a[i-1].text == b[j-1].text {
+		return Clone{}, false
+	}
+	n, tokens := 0, 0
+	for i+n < len(a) && j+n < len(b) && a[i+n].text == b[j+n].text {
+		if sameFile && i+n >= j {
+			break
+		}
+		tokens += a[i+n].tokens
+		n++
+	}
+	if n < minLines || tokens < n*minTokensPerLine {
+		return Clone{}, false
+	}
+	return Clone{
+		A:      Block{StartLine: a[i].no, EndLine: a[i+n-1].no},
+		B:      Block{StartLine: b[j].no, EndLine: b[j+n-1].no},
+		Lines:  n,
+		Tokens: tokens,
+	}, true
+}
+
+// hashWindows 计算每 size 个连续规范化行的哈希
+func hashWindows(lines []line, size int) []uint64 {
+	if len(lines) < size {
+		return nil
+	}
+	hashes := make([]uint64, 0, len(lines)-size+1)
+	for i := 0; i+size <= len(lines); i++ {
+		h := fnv.New64a()
+		for _, l := range lines[i : i+size] {
+			h.Write([]byte(l.text))
+			h.Write([]byte{'\n'})
+		}
+		hashes = append(hashes, h.Sum64())
+	}
+	return hashes
+}
+
+// readLines 读取文件并规范化：去除空行与整行注释，每行切分为词法单元后以空格连接
+func readLines(path string) []line {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxFileSize {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || textenc.IsBinary(data) {
+		return nil
+	}
+	var lines []line
+	for i, raw := range bytes.Split(textenc.ToUTF8(data), []byte("\n")) {
+		text := strings.TrimSpace(string(raw))
+		if text == "" || isComment(text) {
+			continue
+		}
+		tokens := tokenRegex.FindAllString(text, -1)
+		lines = append(lines, line{text: strings.Join(tokens, " "), no: i + 1, tokens: len(tokens)})
+	}
+	return lines
+}
+
+// isComment 判断一行是否为整行注释
+func isComment(text string) bool {
+	for _, prefix := range commentPrefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+// Package eval 读取标注了预期问题的样例集，将审查结果与预期逐一匹配并计算精确率与召回率，
+// 供 reviewer eval 在修改提示词或审查级别后验证效果
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ManifestName 是样例集目录中描述预期问题的文件名
+const ManifestName = "eval.yaml"
+
+// lineTolerance 是预期行号与模型给出的行号之间允许的误差
+const lineTolerance = 3
+
+// Expectation 是样例文件中预期应被发现的一个问题
+type Expectation struct {
+	Name     string   `yaml:"name"`     // 显示名称，为空时使用第一个关键字
+	Keywords []string `yaml:"keywords"` // 问题文本包含任一关键字（不区分大小写）即视为匹配
+	Severity string   `yaml:"severity"` // 最低严重程度，为空时不限
+	Line     int      `yaml:"line"`     // 问题所在行号，0 表示不检查；只对给出行号的漏洞生效
+
+	minimum llm.Severity
+}
+
+// Case 是样例集中的一个文件；没有预期问题的文件用于检验误报
+type Case struct {
+	File   string        `yaml:"file"` // 相对于样例集目录的路径
+	Expect []Expectation `yaml:"expect"`
+}
+
+// Corpus 是一个样例集
+type Corpus struct {
+	Dir   string `yaml:"-"`
+	Level int    `yaml:"level"` // 审查级别，0 表示由命令行决定
+	Audit bool   `yaml:"audit"` // 以安全审计模式审查
+	Cases []Case `yaml:"cases"`
+}
+
+// Load 读取目录中的 eval.yaml 并校验每个样例
+func Load(dir string) (*Corpus, error) {
+	path := filepath.Join(dir, ManifestName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s 中没有 %s", dir, ManifestName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := Corpus{Dir: dir}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s 格式错误: %w", path, err)
+	}
+	if len(c.Cases) == 0 {
+		return nil, fmt.Errorf("%s 中没有样例 (cases)", path)
+	}
+
+	seen := make(map[string]bool, len(c.Cases))
+	for i := range c.Cases {
+		tc := &c.Cases[i]
+		if tc.File == "" {
+			return nil, fmt.Errorf("%s: 第 %d 个样例缺少 file", path, i+1)
+		}
+		tc.File = filepath.ToSlash(filepath.Clean(tc.File))
+		if seen[tc.File] {
+			return nil, fmt.Errorf("%s: 样例 %s 重复", path, tc.File)
+		}
+		seen[tc.File] = true
+		if _, err := os.Stat(filepath.Join(dir, tc.File)); err != nil {
+			return nil, fmt.Errorf("%s: 样例文件 %s 不存在", path, tc.File)
+		}
+		for j := range tc.Expect {
+			exp := &tc.Expect[j]
+			if len(exp.Keywords) == 0 {
+				return nil, fmt.Errorf("%s: %s 的第 %d 个预期问题缺少 keywords", path, tc.File, j+1)
+			}
+			if exp.Name == "" {
+				exp.Name = exp.Keywords[0]
+			}
+			if exp.minimum, err = llm.ParseSeverity(exp.Severity); err != nil {
+				return nil, fmt.Errorf("%s: %s 的预期问题 %s: %w", path, tc.File, exp.Name, err)
+			}
+		}
+	}
+	return &c, nil
+}
+
+// Path 返回样例文件的路径（相对于执行目录）
+func (c *Corpus) Path(tc Case) string {
+	return filepath.Join(c.Dir, filepath.FromSlash(tc.File))
+}
+
+// reported 是审查结果中的一个问题
+type reported struct {
+	severity llm.Severity
+	text     string
+	line     int // 0 表示未知
+}
+
+// reportedIssues 返回审查结果中的问题；安全审计的问题由漏洞生成，此时直接使用漏洞（带行号）
+func reportedIssues(review *llm.ReviewResult) []reported {
+	var out []reported
+	if len(review.Findings) > 0 {
+		for _, f := range review.Findings {
+			out = append(out, reported{f.Level(), strings.Join([]string{f.CWE, f.Title, f.Description}, " "), f.Line})
+		}
+		return out
+	}
+	for _, issue := range review.Issues {
+		s, text := llm.SplitIssue(issue)
+		if s == llm.SeverityUnknown {
+			s = llm.SeverityMajor
+		}
+		out = append(out, reported{severity: s, text: text})
+	}
+	return out
+}
+
+// matches 判断问题是否命中预期
+func (exp Expectation) matches(r reported) bool {
+	if r.severity < exp.minimum {
+		return false
+	}
+	if exp.Line > 0 && r.line > 0 && (r.line < exp.Line-lineTolerance || r.line > exp.Line+lineTolerance) {
+		return false
+	}
+	text := strings.ToLower(r.text)
+	for _, kw := range exp.Keywords {
+		if strings.Contains(text, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CaseResult 是一个样例的评估结果
+type CaseResult struct {
+	File           string   `json:"file"`
+	Detected       []string `json:"detected"`        // 已发现的预期问题
+	Missed         []string `json:"missed"`          // 漏报的预期问题
+	FalsePositives []string `json:"false_positives"` // 没有命中任何预期的问题（误报）
+	Error          string   `json:"error,omitempty"` // 审查失败或被跳过的原因，此时全部预期问题计为漏报
+}
+
+// Passed 判断样例是否没有漏报与误报
+func (r CaseResult) Passed() bool {
+	return r.Error == "" && len(r.Missed) == 0 && len(r.FalsePositives) == 0
+}
+
+// Score 将审查结果与样例的预期问题匹配
+// 命中任一预期的问题不计为误报（重复报告同一问题不扣分）；低于 minSeverity 的其他问题不计入误报
+func Score(tc Case, review *llm.ReviewResult, minSeverity llm.Severity) CaseResult {
+	res := CaseResult{File: tc.File, Detected: []string{}, Missed: []string{}, FalsePositives: []string{}}
+	issues := reportedIssues(review)
+	for _, exp := range tc.Expect {
+		found := false
+		for _, r := range issues {
+			if exp.matches(r) {
+				found = true
+				break
+			}
+		}
+		if found {
+			res.Detected = append(res.Detected, exp.Name)
+		} else {
+			res.Missed = append(res.Missed, exp.Name)
+		}
+	}
+
+	for _, r := range issues {
+		if r.severity < minSeverity {
+			continue
+		}
+		matched := false
+		for _, exp := range tc.Expect {
+			if exp.matches(r) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			res.FalsePositives = append(res.FalsePositives, llm.FormatIssue(r.severity, r.text))
+		}
+	}
+	return res
+}
+
+// Failed 返回审查失败的样例结果，全部预期问题计为漏报
+func Failed(tc Case, reason string) CaseResult {
+	res := CaseResult{File: tc.File, Detected: []string{}, Missed: []string{}, FalsePositives: []string{}, Error: reason}
+	for _, exp := range tc.Expect {
+		res.Missed = append(res.Missed, exp.Name)
+	}
+	return res
+}
+
+// Summary 是整个样例集的评估统计
+type Summary struct {
+	Cases          int     `json:"cases"`
+	Passed         int     `json:"passed"`
+	Errors         int     `json:"errors"`
+	TruePositives  int     `json:"true_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	FalsePositives int     `json:"false_positives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+}
+
+// Summarize 汇总各样例的结果
+// 精确率 = 发现的预期问题 / (发现的预期问题 + 误报)，召回率 = 发现的预期问题 / 全部预期问题；分母为 0 时记为 1
+func Summarize(results []CaseResult) Summary {
+	s := Summary{Cases: len(results)}
+	for _, r := range results {
+		s.TruePositives += len(r.Detected)
+		s.FalseNegatives += len(r.Missed)
+		s.FalsePositives += len(r.FalsePositives)
+		if r.Error != "" {
+			s.Errors++
+		}
+		if r.Passed() {
+			s.Passed++
+		}
+	}
+	s.Precision = ratio(s.TruePositives, s.TruePositives+s.FalsePositives)
+	s.Recall = ratio(s.TruePositives, s.TruePositives+s.FalseNegatives)
+	if s.Precision+s.Recall > 0 {
+		s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+	}
+	return s
+}
+
+// ratio 返回 n/d，d 为 0 时返回 1
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 1
+	}
+	return float64(n) / float64(d)
+}
@@ -0,0 +1,104 @@
+// Package history 保存审查运行的历史记录（运行统计与每个文件的审查结论），供 reviewer ask 追问与 reviewer history 汇总
+// 存储后端可以是本地的 SQLite（默认）、团队共享的 PostgreSQL / MySQL，或报告旁的 JSON 文件
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// 支持的存储后端
+const (
+	BackendSQLite   = "sqlite"   // 本地 SQLite 数据库文件（默认）
+	BackendPostgres = "postgres" // PostgreSQL，适合多个仓库共享
+	BackendMySQL    = "mysql"    // MySQL，适合多个仓库共享
+	BackendJSON     = "json"     // 每次运行的记录写入报告旁的 <报告名>.json
+	BackendOff      = "off"      // 不记录历史
+)
+
+// Backends 是 history_backend 的可选值
+var Backends = []string{BackendSQLite, BackendPostgres, BackendMySQL, BackendJSON, BackendOff}
+
+// ErrNoHistory 表示没有找到符合条件的审查记录
+var ErrNoHistory = errors.New("没有找到审查记录")
+
+// File 是一个文件的审查结论
+type File struct {
+	Path   string            `json:"path"` // 审查时的文件路径（相对于执行目录）
+	Abs    string            `json:"abs"`  // 绝对路径，用于在其他目录中查找
+	Review *llm.ReviewResult `json:"review"`
+}
+
+// Run 是一次运行（一个任务）的审查记录
+type Run struct {
+	ID      int64     `json:"-"`                 // 数据库中的记录 ID，JSON 后端为 0
+	Project string    `json:"project,omitempty"` // 所属项目（仓库），用于在共享的数据库中区分
+	Name    string    `json:"name,omitempty"`    // 报告名称（不含 .md）
+	Report  string    `json:"report"`            // 报告路径
+	Created time.Time `json:"created"`
+	Level   int       `json:"level"`
+	Audit   bool      `json:"audit,omitempty"`
+	Partial bool      `json:"partial,omitempty"` // 被中断，只包含已完成的文件
+
+	Score    float64       `json:"score"`              // 综合评分
+	Reviewed int           `json:"reviewed"`           // 成功审查的文件数
+	Tokens   int64         `json:"tokens"`             // 消耗的 Token
+	Duration time.Duration `json:"duration,omitempty"` // 审查耗时
+
+	// 各严重程度的问题数（只统计不低于 min_severity 的问题）
+	Critical int `json:"critical"`
+	Major    int `json:"major"`
+	Minor    int `json:"minor"`
+
+	Files []File `json:"files,omitempty"` // List 返回的记录不含文件
+}
+
+// Record 返回文件的审查结论，file 可以是相对于执行目录的路径或绝对路径
+func (r *Run) Record(file string) (*File, bool) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	for i, f := range r.Files {
+		if f.Abs == abs || filepath.Clean(f.Path) == filepath.Clean(file) {
+			return &r.Files[i], true
+		}
+	}
+	return nil, false
+}
+
+// Query 是查询条件，为空的字段不参与过滤
+type Query struct {
+	Project string // 项目（JSON 后端的记录本就按目录存放，忽略该条件）
+	Name    string // 报告名称（不含 .md）
+	File    string // 包含该文件的审查结论（相对于执行目录的路径或绝对路径）
+}
+
+// Store 是审查历史的存储后端，实现须可被多个 goroutine 同时使用
+type Store interface {
+	// Save 保存一次运行的记录
+	Save(ctx context.Context, run *Run) error
+	// Find 返回符合条件的最新一次运行（含文件的审查结论），没有时返回 ErrNoHistory
+	Find(ctx context.Context, q Query) (*Run, error)
+	// List 按时间从新到旧返回符合条件的运行（不含文件），limit 不大于 0 时不限制数量
+	List(ctx context.Context, q Query, limit int) ([]Run, error)
+	Close() error
+}
+
+// Open 打开存储后端：sqlite 的 dsn 为数据库文件路径，postgres / mysql 为驱动的连接字符串，json 为报告目录
+// 数据库后端在首次连接时自动建表
+func Open(ctx context.Context, backend, dsn string) (Store, error) {
+	switch backend {
+	case BackendJSON:
+		return &jsonStore{dir: dsn}, nil
+	case BackendSQLite, BackendPostgres, BackendMySQL:
+		return openSQL(ctx, backend, dsn)
+	default:
+		return nil, fmt.Errorf("不支持的历史存储后端 %q", backend)
+	}
+}
@@ -0,0 +1,160 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jsonVersion 是审查记录文件的格式版本
+const jsonVersion = 1
+
+// jsonStore 将每次运行的记录写入报告旁的 <报告名>.json，查询时扫描报告目录
+type jsonStore struct {
+	dir string // 报告目录
+}
+
+// jsonRun 是审查记录文件的内容
+type jsonRun struct {
+	Version int `json:"version"`
+	Run
+}
+
+// Save 将记录写入报告旁的 .json 文件
+func (s *jsonStore) Save(_ context.Context, run *Run) error {
+	data, err := json.MarshalIndent(jsonRun{Version: jsonVersion, Run: *run}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化审查记录失败: %w", err)
+	}
+	path := strings.TrimSuffix(run.Report, filepath.Ext(run.Report)) + ".json"
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("写入审查记录失败: %w", err)
+	}
+	return nil
+}
+
+// Find 按时间从新到旧查找报告目录中的审查记录，返回第一个符合条件的记录
+func (s *jsonStore) Find(_ context.Context, q Query) (*Run, error) {
+	if q.Name != "" {
+		run, err := loadJSONRun(filepath.Join(s.dir, filepath.Base(q.Name)+".json"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoHistory
+		}
+		if err != nil {
+			return nil, err
+		}
+		if q.File != "" {
+			if _, ok := run.Record(q.File); !ok {
+				return nil, ErrNoHistory
+			}
+		}
+		return run, nil
+	}
+
+	runs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if q.File == "" {
+			return run, nil
+		}
+		if _, ok := run.Record(q.File); ok {
+			return run, nil
+		}
+	}
+	return nil, ErrNoHistory
+}
+
+// List 返回报告目录中的审查记录
+func (s *jsonStore) List(ctx context.Context, q Query, limit int) ([]Run, error) {
+	var runs []*Run
+	if q.Name != "" {
+		run, err := s.Find(ctx, q)
+		if errors.Is(err, ErrNoHistory) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		runs = []*Run{run}
+	} else {
+		var err error
+		if runs, err = s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	var list []Run
+	for _, run := range runs {
+		if q.File != "" {
+			if _, ok := run.Record(q.File); !ok {
+				continue
+			}
+		}
+		r := *run
+		r.Files = nil
+		list = append(list, r)
+		if limit > 0 && len(list) >= limit {
+			break
+		}
+	}
+	return list, nil
+}
+
+// Close 没有需要释放的资源
+func (s *jsonStore) Close() error { return nil }
+
+// load 读取报告目录中的所有审查记录，按修改时间从新到旧排列，跳过不是审查记录或已损坏的文件
+func (s *jsonStore) load() ([]*Run, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	modTimes := make(map[string]time.Time, len(paths))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			modTimes[p] = info.ModTime()
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return modTimes[paths[i]].After(modTimes[paths[j]])
+	})
+
+	var runs []*Run
+	for _, p := range paths {
+		if run, err := loadJSONRun(p); err == nil {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// loadJSONRun 读取审查记录文件
+func loadJSONRun(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r jsonRun
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("审查记录 %s 格式错误: %w", path, err)
+	}
+	if r.Version == 0 {
+		return nil, fmt.Errorf("%s 不是审查记录", path)
+	}
+	if r.Version > jsonVersion {
+		return nil, fmt.Errorf("审查记录 %s 的版本 %d 高于当前支持的版本 %d，请升级 reviewer", path, r.Version, jsonVersion)
+	}
+	if r.Name == "" {
+		// 早期的记录没有报告名称
+		r.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	return &r.Run, nil
+}
@@ -0,0 +1,292 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // MySQL 驱动
+	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL 驱动
+	_ "modernc.org/sqlite"             // SQLite 驱动（纯 Go，无需 cgo）
+)
+
+// connectTimeout 是打开数据库时连接与建表的时限
+const connectTimeout = 10 * time.Second
+
+// dialect 是各数据库在驱动名、建表语句与占位符上的差异
+type dialect struct {
+	driver    string
+	schema    []string
+	dollar    bool // 占位符为 $1、$2（PostgreSQL），否则为 ?
+	returning bool // 插入时用 RETURNING 取得自增 ID（PostgreSQL 的驱动不支持 LastInsertId）
+}
+
+// dialects 是各数据库后端的方言，建表语句均可重复执行
+var dialects = map[string]dialect{
+	BackendSQLite: {
+		driver: "sqlite",
+		schema: []string{
+			`CREATE TABLE IF NOT EXISTS review_runs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				project TEXT NOT NULL, name TEXT NOT NULL, report TEXT NOT NULL,
+				created BIGINT NOT NULL, level INTEGER NOT NULL, audit BOOLEAN NOT NULL, partial BOOLEAN NOT NULL,
+				score DOUBLE PRECISION NOT NULL, reviewed INTEGER NOT NULL, critical INTEGER NOT NULL, major INTEGER NOT NULL, minor INTEGER NOT NULL,
+				tokens BIGINT NOT NULL, duration_ms BIGINT NOT NULL)`,
+			`CREATE INDEX IF NOT EXISTS review_runs_project ON review_runs (project, created)`,
+			`CREATE TABLE IF NOT EXISTS review_files (
+				run_id INTEGER NOT NULL REFERENCES review_runs (id) ON DELETE CASCADE,
+				path TEXT NOT NULL, abs TEXT NOT NULL, score INTEGER NOT NULL, review TEXT NOT NULL)`,
+			`CREATE INDEX IF NOT EXISTS review_files_run ON review_files (run_id)`,
+		},
+	},
+	BackendPostgres: {
+		driver: "pgx",
+		schema: []string{
+			`CREATE TABLE IF NOT EXISTS review_runs (
+				id BIGSERIAL PRIMARY KEY,
+				project TEXT NOT NULL, name TEXT NOT NULL, report TEXT NOT NULL,
+				created BIGINT NOT NULL, level INTEGER NOT NULL, audit BOOLEAN NOT NULL, partial BOOLEAN NOT NULL,
+				score DOUBLE PRECISION NOT NULL, reviewed INTEGER NOT NULL, critical INTEGER NOT NULL, major INTEGER NOT NULL, minor INTEGER NOT NULL,
+				tokens BIGINT NOT NULL, duration_ms BIGINT NOT NULL)`,
+			`CREATE INDEX IF NOT EXISTS review_runs_project ON review_runs (project, created)`,
+			`CREATE TABLE IF NOT EXISTS review_files (
+				run_id BIGINT NOT NULL REFERENCES review_runs (id) ON DELETE CASCADE,
+				path TEXT NOT NULL, abs TEXT NOT NULL, score INTEGER NOT NULL, review TEXT NOT NULL)`,
+			`CREATE INDEX IF NOT EXISTS review_files_run ON review_files (run_id)`,
+		},
+		dollar:    true,
+		returning: true,
+	},
+	BackendMySQL: {
+		driver: "mysql",
+		// MySQL 不支持 CREATE INDEX IF NOT EXISTS，索引在建表时定义
+		schema: []string{
+			`CREATE TABLE IF NOT EXISTS review_runs (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				project VARCHAR(255) NOT NULL, name VARCHAR(255) NOT NULL, report TEXT NOT NULL,
+				created BIGINT NOT NULL, level INT NOT NULL, audit BOOLEAN NOT NULL, partial BOOLEAN NOT NULL,
+				score DOUBLE NOT NULL, reviewed INT NOT NULL, critical INT NOT NULL, major INT NOT NULL, minor INT NOT NULL,
+				tokens BIGINT NOT NULL, duration_ms BIGINT NOT NULL,
+				INDEX review_runs_project (project, created)
+			) DEFAULT CHARSET = utf8mb4`,
+			`CREATE TABLE IF NOT EXISTS review_files (
+				run_id BIGINT NOT NULL,
+				path TEXT NOT NULL, abs TEXT NOT NULL, score INT NOT NULL, review MEDIUMTEXT NOT NULL,
+				INDEX review_files_run (run_id),
+				FOREIGN KEY (run_id) REFERENCES review_runs (id) ON DELETE CASCADE
+			) DEFAULT CHARSET = utf8mb4`,
+		},
+	},
+}
+
+// runColumns 是 review_runs 中除 id 外的列，与 List 中 Scan 的顺序一致
+const runColumns = "project, name, report, created, level, audit, partial, score, reviewed, critical, major, minor, tokens, duration_ms"
+
+// sqlStore 将审查记录保存在 SQL 数据库中
+type sqlStore struct {
+	db *sql.DB
+	d  dialect
+}
+
+// openSQL 连接数据库并建表；SQLite 数据库文件所在的目录不存在时自动创建
+func openSQL(ctx context.Context, backend, dsn string) (*sqlStore, error) {
+	d := dialects[backend]
+	if dsn == "" {
+		return nil, fmt.Errorf("历史存储后端 %s 需要设置 history_dsn", backend)
+	}
+	if backend == BackendSQLite {
+		if err := os.MkdirAll(filepath.Dir(dsn), 0755); err != nil {
+			return nil, fmt.Errorf("创建历史数据库目录失败: %w", err)
+		}
+		dsn = sqliteDSN(dsn)
+	}
+
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("打开历史数据库失败: %w", err)
+	}
+	if backend == BackendSQLite {
+		// SQLite 同一时间只允许一个写入者，并行任务共用一个连接，避免 SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	for _, stmt := range d.schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("初始化历史数据库失败: %w", err)
+		}
+	}
+	return &sqlStore{db: db, d: d}, nil
+}
+
+// sqliteDSN 为数据库文件路径加上忙等待与外键约束的参数
+func sqliteDSN(path string) string {
+	if strings.HasPrefix(path, "file:") {
+		return path
+	}
+	return "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"
+}
+
+// bind 将语句中的 ? 占位符替换为方言的占位符
+func (s *sqlStore) bind(query string) string {
+	if !s.d.dollar {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Save 在一个事务中写入运行与各文件的审查结论
+func (s *sqlStore) Save(ctx context.Context, run *Run) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("保存审查记录失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert := "INSERT INTO review_runs (" + runColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	args := []any{
+		run.Project, run.Name, run.Report, run.Created.UnixMilli(), run.Level, run.Audit, run.Partial,
+		run.Score, run.Reviewed, run.Critical, run.Major, run.Minor, run.Tokens, run.Duration.Milliseconds(),
+	}
+	if s.d.returning {
+		err = tx.QueryRowContext(ctx, s.bind(insert+" RETURNING id"), args...).Scan(&run.ID)
+	} else {
+		var res sql.Result
+		if res, err = tx.ExecContext(ctx, s.bind(insert), args...); err == nil {
+			run.ID, err = res.LastInsertId()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("保存审查记录失败: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, s.bind("INSERT INTO review_files (run_id, path, abs, score, review) VALUES (?, ?, ?, ?, ?)"))
+	if err != nil {
+		return fmt.Errorf("保存审查记录失败: %w", err)
+	}
+	defer stmt.Close()
+	for _, f := range run.Files {
+		if f.Review == nil {
+			continue
+		}
+		review, err := json.Marshal(f.Review)
+		if err != nil {
+			return fmt.Errorf("序列化审查记录失败: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx, run.ID, f.Path, f.Abs, f.Review.Score, string(review)); err != nil {
+			return fmt.Errorf("保存审查记录失败: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("保存审查记录失败: %w", err)
+	}
+	return nil
+}
+
+// Find 返回符合条件的最新一次运行及其文件的审查结论
+func (s *sqlStore) Find(ctx context.Context, q Query) (*Run, error) {
+	runs, err := s.List(ctx, q, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, ErrNoHistory
+	}
+	run := &runs[0]
+
+	rows, err := s.db.QueryContext(ctx, s.bind("SELECT path, abs, review FROM review_files WHERE run_id = ? ORDER BY path"), run.ID)
+	if err != nil {
+		return nil, fmt.Errorf("查询审查记录失败: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f File
+		var review string
+		if err := rows.Scan(&f.Path, &f.Abs, &review); err != nil {
+			return nil, fmt.Errorf("查询审查记录失败: %w", err)
+		}
+		if err := json.Unmarshal([]byte(review), &f.Review); err != nil {
+			return nil, fmt.Errorf("审查记录 %d 中 %s 的结论格式错误: %w", run.ID, f.Path, err)
+		}
+		run.Files = append(run.Files, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询审查记录失败: %w", err)
+	}
+	return run, nil
+}
+
+// List 按时间从新到旧返回符合条件的运行
+func (s *sqlStore) List(ctx context.Context, q Query, limit int) ([]Run, error) {
+	var conds []string
+	var args []any
+	if q.Project != "" {
+		conds, args = append(conds, "project = ?"), append(args, q.Project)
+	}
+	if q.Name != "" {
+		conds, args = append(conds, "name = ?"), append(args, q.Name)
+	}
+	if q.File != "" {
+		abs, err := filepath.Abs(q.File)
+		if err != nil {
+			abs = q.File
+		}
+		conds = append(conds, "EXISTS (SELECT 1 FROM review_files f WHERE f.run_id = review_runs.id AND (f.abs = ? OR f.path = ?))")
+		args = append(args, abs, filepath.Clean(q.File))
+	}
+
+	query := "SELECT id, " + runColumns + " FROM review_runs"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY created DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.bind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询审查记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		var r Run
+		var created, durationMs int64
+		if err := rows.Scan(&r.ID, &r.Project, &r.Name, &r.Report, &created, &r.Level, &r.Audit, &r.Partial,
+			&r.Score, &r.Reviewed, &r.Critical, &r.Major, &r.Minor, &r.Tokens, &durationMs); err != nil {
+			return nil, fmt.Errorf("查询审查记录失败: %w", err)
+		}
+		r.Created = time.UnixMilli(created)
+		r.Duration = time.Duration(durationMs) * time.Millisecond
+		runs = append(runs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询审查记录失败: %w", err)
+	}
+	return runs, nil
+}
+
+// Close 关闭数据库连接
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,221 @@
+// Package jira 为达到严重程度阈值的审查问题创建（或更新）Jira 工单
+// 每个工单带有由项目与文件路径生成的标签，再次发现同一问题时更新已有的未关闭工单，而不是重复创建
+package jira
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-ai-reviewer/internal/app/atlassian"
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/llm"
+	"go-ai-reviewer/pkg/utils"
+)
+
+// requestTimeout 是单个 Jira 请求的时限
+const requestTimeout = 15 * time.Second
+
+// maxSummaryText 是工单标题中问题描述的最大字符数（Jira 标题上限为 255）
+const maxSummaryText = 180
+
+// maxPatchLength 是工单中修复补丁的最大字符数，过长的补丁截断
+const maxPatchLength = 8000
+
+// labelPrefix 是去重标签的前缀，标签为前缀加项目与文件路径的摘要
+const labelPrefix = "ai-review-"
+
+// summaryPrefix 是工单标题的前缀
+const summaryPrefix = "[AI 审查] "
+
+// severityNames 是各严重程度在工单中的显示文本（不使用 emoji，部分 Jira Server 的数据库不支持 4 字节字符）
+var severityNames = map[llm.Severity]string{
+	llm.SeverityCritical: "严重 (critical)",
+	llm.SeverityMajor:    "重要 (major)",
+	llm.SeverityMinor:    "一般 (minor)",
+}
+
+// Config 是 Jira 连接与工单配置
+type Config struct {
+	URL       string   // 站点地址，如 https://acme.atlassian.net
+	Email     string   // Jira Cloud 的账号邮箱，与 Token 组成 Basic 认证；为空时 Token 作为个人访问令牌 (Server / Data Center)
+	Token     string   // API Token 或个人访问令牌
+	Project   string   // 项目 Key，如 SEC
+	IssueType string   // 工单类型，如 Bug
+	Labels    []string // 工单附加的标签
+}
+
+// Finding 是一个需要创建工单的问题
+type Finding struct {
+	Project  string // 所属项目（仓库），参与去重
+	File     string // 相对于审查目录的路径，参与去重
+	Line     int    // 问题所在行号，0 表示未知
+	Severity llm.Severity
+	Text     string // 问题描述（不含严重程度标注）
+	Detail   string // 详细说明（如安全审计漏洞的成因与攻击方式），为空时不写入
+	Snippet  string // 问题所在位置的代码片段（带行号），为空时不写入
+	Fix      string // 修复建议，为空时不写入
+	Patch    string // 修复补丁 (unified diff)，为空时不写入
+	Report   string // 报告的 URL 或路径，为空时不写入
+}
+
+// Client 调用 Jira REST API v2（Jira Cloud 与 Server / Data Center 均支持），可被多个 goroutine 同时使用
+type Client struct {
+	cfg Config
+	api *atlassian.Client
+
+	mu           sync.Mutex
+	legacySearch bool // 站点不支持 /search/jql（Server / Data Center），使用旧的 /search
+}
+
+// New 创建 Jira 客户端
+func New(cfg Config) *Client {
+	return &Client{cfg: cfg, api: atlassian.New(cfg.URL, cfg.Email, cfg.Token, requestTimeout)}
+}
+
+// Sync 为问题创建工单，已有同一问题的未关闭工单时更新其描述，返回工单 Key 与是否为新建
+// 同一问题的工单已关闭时视为问题再次出现，创建新工单并引用旧工单
+func (c *Client) Sync(ctx context.Context, f Finding) (key string, created bool, err error) {
+	label := fileLabel(f.Project, f.File)
+	tickets, err := c.search(ctx, label)
+	if err != nil {
+		return "", false, err
+	}
+
+	var reopened string
+	for _, t := range tickets {
+		text, ok := strings.CutPrefix(t.Fields.Summary, summaryPrefix+f.File+": ")
+		if !ok || !reviewer.SameIssue(text, summaryText(f)) {
+			continue
+		}
+		if t.Fields.Status.StatusCategory.Key != "done" {
+			body := map[string]any{"fields": map[string]any{"description": description(f, "")}}
+			if err := c.api.Do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(t.Key), body, nil); err != nil {
+				return "", false, fmt.Errorf("更新工单 %s 失败: %w", t.Key, err)
+			}
+			return t.Key, false, nil
+		}
+		if reopened == "" {
+			reopened = t.Key
+		}
+	}
+
+	fields := map[string]any{
+		"project":     map[string]string{"key": c.cfg.Project},
+		"issuetype":   map[string]string{"name": c.cfg.IssueType},
+		"summary":     summaryPrefix + f.File + ": " + summaryText(f),
+		"description": description(f, reopened),
+		"labels":      append(append([]string{}, c.cfg.Labels...), label),
+	}
+	var resp struct {
+		Key string `json:"key"`
+	}
+	if err := c.api.Do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]any{"fields": fields}, &resp); err != nil {
+		return "", false, fmt.Errorf("创建工单失败: %w", err)
+	}
+	return resp.Key, true, nil
+}
+
+// ticket 是搜索结果中的工单
+type ticket struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			StatusCategory struct {
+				Key string `json:"key"` // new / indeterminate / done
+			} `json:"statusCategory"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// search 返回项目中带有该标签的工单，从新到旧排列
+func (c *Client) search(ctx context.Context, label string) ([]ticket, error) {
+	query := url.Values{
+		"jql":        {fmt.Sprintf(`project = %q AND labels = %q ORDER BY created DESC`, c.cfg.Project, label)},
+		"fields":     {"summary,status"},
+		"maxResults": {"100"},
+	}
+	var resp struct {
+		Issues []ticket `json:"issues"`
+	}
+
+	c.mu.Lock()
+	legacy := c.legacySearch
+	c.mu.Unlock()
+	if !legacy {
+		// Jira Cloud 已停用 /search，新接口为 /search/jql；Server / Data Center 没有新接口
+		err := c.api.Do(ctx, http.MethodGet, "/rest/api/2/search/jql?"+query.Encode(), nil, &resp)
+		var apiErr *atlassian.APIError
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+			if err != nil {
+				return nil, fmt.Errorf("查询已有工单失败: %w", err)
+			}
+			return resp.Issues, nil
+		}
+		c.mu.Lock()
+		c.legacySearch = true
+		c.mu.Unlock()
+	}
+	if err := c.api.Do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &resp); err != nil {
+		return nil, fmt.Errorf("查询已有工单失败: %w", err)
+	}
+	return resp.Issues, nil
+}
+
+// description 返回工单描述（Jira Wiki 标记），reopened 为已关闭的同一问题的工单 Key
+func description(f Finding, reopened string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*严重程度*: %s\n", severityNames[f.Severity])
+	location := "{{" + f.File + "}}"
+	if f.Line > 0 {
+		location += fmt.Sprintf(" 第 %d 行", f.Line)
+	}
+	fmt.Fprintf(&b, "*位置*: %s\n", location)
+	if f.Project != "" {
+		fmt.Fprintf(&b, "*项目*: %s\n", f.Project)
+	}
+	if reopened != "" {
+		fmt.Fprintf(&b, "*再次出现*: 同一问题的工单 %s 已关闭\n", reopened)
+	}
+	fmt.Fprintf(&b, "\nh3. 问题\n%s\n", f.Text)
+	if f.Detail != "" {
+		fmt.Fprintf(&b, "\n%s\n", f.Detail)
+	}
+	if f.Snippet != "" {
+		fmt.Fprintf(&b, "\nh3. 代码\n{noformat}\n%s\n{noformat}\n", strings.TrimRight(f.Snippet, "\n"))
+	}
+	if f.Fix != "" || f.Patch != "" {
+		b.WriteString("\nh3. 修复建议\n")
+		if f.Fix != "" {
+			fmt.Fprintf(&b, "%s\n", f.Fix)
+		}
+		if f.Patch != "" {
+			fmt.Fprintf(&b, "{code:diff}\n%s\n{code}\n", strings.TrimRight(utils.Truncate(f.Patch, maxPatchLength), "\n"))
+		}
+	}
+	b.WriteString("\n----\n由 AI 代码审查自动创建")
+	if f.Report != "" {
+		fmt.Fprintf(&b, "，报告: %s", f.Report)
+	}
+	fmt.Fprintf(&b, " (%s)\n", time.Now().Format(time.DateTime))
+	return b.String()
+}
+
+// summaryText 返回工单标题中的问题描述：合并为一行（Jira 标题不能换行）并截断
+func summaryText(f Finding) string {
+	return utils.Truncate(strings.Join(strings.Fields(f.Text), " "), maxSummaryText)
+}
+
+// fileLabel 返回文件的去重标签：项目与路径的 SHA-256 前 12 位（Jira 标签不能包含空格）
+func fileLabel(project, file string) string {
+	sum := sha256.Sum256([]byte(project + "\x00" + file))
+	return labelPrefix + hex.EncodeToString(sum[:6])
+}
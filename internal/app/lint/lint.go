@@ -0,0 +1,224 @@
+// Package lint 在 LLM 审查前调用本地静态检查工具（gofmt、go vet、eslint、flake8），
+// 为报告提供确定性的问题列表
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 执行限制
+const (
+	// ToolTimeout 是单个工具的最长执行时间，超时的工具结果被丢弃
+	ToolTimeout = 2 * time.Minute
+	// maxArgsPerRun 是单次调用传入的最大文件数，避免超出命令行长度限制
+	maxArgsPerRun = 200
+)
+
+// lineRegex 匹配 "path:line[:col]: message" 格式的输出
+var lineRegex = regexp.MustCompile(`^(.+?):(\d+):(?:\d+:)?\s*(.+)$`)
+
+// Finding 表示静态检查工具报告的一个问题
+type Finding struct {
+	Tool    string // 工具名称
+	Line    int    // 行号（0 表示针对整个文件）
+	Message string
+}
+
+// Report 是一次静态检查的汇总结果，按文件绝对路径索引
+type Report struct {
+	byFile map[string][]Finding
+	tools  []string // 实际执行的工具
+}
+
+// tool 描述一个静态检查工具
+type tool struct {
+	name string
+	bin  string
+	exts []string // 适用的文件扩展名
+
+	// run 执行工具并返回输出，parse 将输出解析为按文件索引的问题
+	run   func(ctx context.Context, root string, files []string) ([]byte, error)
+	parse func(root string, out []byte) map[string][]Finding
+}
+
+// tools 是支持的工具列表，未安装的工具自动跳过
+var tools = []tool{
+	{
+		name: "gofmt", bin: "gofmt", exts: []string{".go"},
+		run: func(ctx context.Context, root string, files []string) ([]byte, error) {
+			return runBatched(ctx, root, "gofmt", []string{"-l"}, files)
+		},
+		parse: parseGofmt,
+	},
+	{
+		name: "go vet", bin: "go", exts: []string{".go"},
+		run:   runGoVet,
+		parse: parseLines("go vet"),
+	},
+	{
+		name: "eslint", bin: "eslint", exts: []string{".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".vue"},
+		run: func(ctx context.Context, root string, files []string) ([]byte, error) {
+			return runBatched(ctx, root, "eslint", []string{"-f", "unix", "--no-error-on-unmatched-pattern"}, files)
+		},
+		parse: parseLines("eslint"),
+	},
+	{
+		name: "flake8", bin: "flake8", exts: []string{".py"},
+		run: func(ctx context.Context, root string, files []string) ([]byte, error) {
+			return runBatched(ctx, root, "flake8", nil, files)
+		},
+		parse: parseLines("flake8"),
+	},
+}
+
+// Run 对 files 执行所有已安装且适用的工具，单个工具失败或超时不影响其他工具
+func Run(ctx context.Context, root string, files []string) *Report {
+	r := &Report{byFile: make(map[string][]Finding)}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+
+	for _, t := range tools {
+		matched := filterByExt(files, t.exts)
+		if len(matched) == 0 {
+			continue
+		}
+		if _, err := exec.LookPath(t.bin); err != nil {
+			continue
+		}
+
+		toolCtx, cancel := context.WithTimeout(ctx, ToolTimeout)
+		out, err := t.run(toolCtx, absRoot, matched)
+		cancel()
+		// 工具发现问题时通常以非零状态退出，只有无法获得输出时才放弃
+		if err != nil && len(out) == 0 {
+			continue
+		}
+
+		r.tools = append(r.tools, t.name)
+		for path, findings := range t.parse(absRoot, out) {
+			r.byFile[path] = append(r.byFile[path], findings...)
+		}
+	}
+
+	return r
+}
+
+// For 返回指定文件的检查结果，Report 为空时返回 nil
+func (r *Report) For(path string) []Finding {
+	if r == nil {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	return r.byFile[abs]
+}
+
+// Tools 返回实际执行的工具名称
+func (r *Report) Tools() []string {
+	if r == nil {
+		return nil
+	}
+	return r.tools
+}
+
+// filterByExt 筛选扩展名匹配的文件，并转换为绝对路径
+func filterByExt(files, exts []string) []string {
+	var matched []string
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file))
+		for _, e := range exts {
+			if ext == e {
+				if abs, err := filepath.Abs(file); err == nil {
+					matched = append(matched, abs)
+				}
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// runBatched 分批调用工具并合并输出（stdout 与 stderr）
+func runBatched(ctx context.Context, root, bin string, args, files []string) ([]byte, error) {
+	var out bytes.Buffer
+	var lastErr error
+	for start := 0; start < len(files); start += maxArgsPerRun {
+		end := min(start+maxArgsPerRun, len(files))
+		cmd := exec.CommandContext(ctx, bin, append(append([]string{}, args...), files[start:end]...)...)
+		cmd.Dir = root
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			lastErr = err
+		}
+	}
+	return out.Bytes(), lastErr
+}
+
+// runGoVet 在模块根目录执行 go vet ./...，不是 Go 模块时跳过
+func runGoVet(ctx context.Context, root string, _ []string) ([]byte, error) {
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, "go", "vet", "./...")
+	cmd.Dir = root
+	return cmd.CombinedOutput()
+}
+
+// parseGofmt 解析 gofmt -l 的输出：每行一个未格式化的文件
+func parseGofmt(root string, out []byte) map[string][]Finding {
+	byFile := make(map[string][]Finding)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" || !strings.HasSuffix(path, ".go") {
+			continue
+		}
+		abs := resolvePath(root, path)
+		byFile[abs] = append(byFile[abs], Finding{Tool: "gofmt", Message: "文件未按 gofmt 格式化"})
+	}
+	return byFile
+}
+
+// parseLines 返回解析 "path:line[:col]: message" 格式输出的函数
+func parseLines(name string) func(root string, out []byte) map[string][]Finding {
+	return func(root string, out []byte) map[string][]Finding {
+		byFile := make(map[string][]Finding)
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			m := lineRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+			if m == nil {
+				continue
+			}
+			line, err := strconv.Atoi(m[2])
+			if err != nil {
+				continue
+			}
+			abs := resolvePath(root, m[1])
+			byFile[abs] = append(byFile[abs], Finding{Tool: name, Line: line, Message: m[3]})
+		}
+		return byFile
+	}
+}
+
+// resolvePath 将工具输出中的路径转换为绝对路径（相对路径基于 root）
+func resolvePath(root, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	return filepath.Clean(path)
+}
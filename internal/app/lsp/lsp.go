@@ -0,0 +1,634 @@
+// Package lsp 实现 Language Server Protocol 服务端的最小子集：通过标准输入输出交换带 Content-Length 头的 JSON-RPC 2.0 消息，
+// 在文件保存（防抖）或用户通过代码操作请求时审查文件，并将问题作为诊断 (publishDiagnostics) 发布到编辑器
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+// CommandReviewFile 是按需审查文件的命令，参数为文件的 URI
+const CommandReviewFile = "reviewer.reviewFile"
+
+// maxMessageSize 是单条消息的最大字节数，防止异常的 Content-Length 耗尽内存
+const maxMessageSize = 64 << 20
+
+// JSON-RPC 错误码
+const (
+	codeParseError           = -32700
+	codeInvalidRequest       = -32600
+	codeMethodNotFound       = -32601
+	codeInvalidParams        = -32602
+	codeServerNotInitialized = -32002
+)
+
+// 诊断的严重程度
+const (
+	SeverityError       = 1
+	SeverityWarning     = 2
+	SeverityInformation = 3
+	SeverityHint        = 4
+)
+
+// window/showMessage 与 window/logMessage 的消息类型
+const (
+	messageError = 1
+	messageLog   = 4
+)
+
+// Position 是文档中的位置，行与列均从 0 开始，列为 UTF-16 码元偏移
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range 是文档中的范围
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic 是发布到编辑器的一条诊断
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code,omitempty"` // 问题引用的团队规则编号
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// LineRange 返回覆盖第 line 行（从 1 开始）整行内容的范围，行号超出 content 的范围时返回第一行
+func LineRange(content string, line int) Range {
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) {
+		line = 1
+	}
+	text := strings.TrimRight(lines[line-1], "\r")
+	return Range{
+		Start: Position{Line: line - 1},
+		End:   Position{Line: line - 1, Character: len(utf16.Encode([]rune(text)))},
+	}
+}
+
+// Reviewer 审查文件，返回文件的诊断；path 为文件的绝对路径，content 为保存时（或磁盘上）的内容
+type Reviewer func(ctx context.Context, path, content string) ([]Diagnostic, error)
+
+// Option 配置服务端
+type Option func(*Server)
+
+// WithDebounce 设置文件保存后等待的静默时间，期间的多次保存合并为一次审查
+func WithDebounce(d time.Duration) Option {
+	return func(s *Server) {
+		s.debounce = d
+	}
+}
+
+// WithoutSaveReview 关闭保存时的自动审查，只在用户请求时审查
+func WithoutSaveReview() Option {
+	return func(s *Server) {
+		s.onSave = false
+	}
+}
+
+// WithSaveFilter 设置保存时自动审查的文件过滤器（按需审查不受影响）
+func WithSaveFilter(filter func(path string) bool) Option {
+	return func(s *Server) {
+		s.saveFilter = filter
+	}
+}
+
+// WithConcurrency 设置同时审查的文件数上限
+func WithConcurrency(n int) Option {
+	return func(s *Server) {
+		s.sem = make(chan struct{}, max(n, 1))
+	}
+}
+
+// document 是编辑器中打开（或请求审查过）的文件
+type document struct {
+	path    string
+	open    bool   // 是否已在编辑器中打开，未打开时审查磁盘上的内容
+	text    string // 编辑器中的最新内容（打开后有效，可以为空）
+	version int
+
+	timer  *time.Timer
+	cancel context.CancelFunc // 取消进行中的审查
+	seq    int                // 审查序号，较早的审查结果不再发布
+
+	reviewed    [sha256.Size]byte // 最近一次审查成功的内容摘要，内容未变时不重复审查
+	diagnostics []Diagnostic
+}
+
+// Server 是 LSP 服务端
+type Server struct {
+	name    string
+	version string
+	review  Reviewer
+
+	debounce   time.Duration
+	onSave     bool
+	saveFilter func(path string) bool
+	sem        chan struct{}
+
+	writeMu sync.Mutex
+	out     io.Writer
+
+	mu           sync.Mutex
+	ctx          context.Context
+	docs         map[string]*document // 按 URI 索引
+	initialized  bool
+	shuttingDown bool
+	wg           sync.WaitGroup
+}
+
+// NewServer 创建服务端，review 执行实际的审查
+func NewServer(name, version string, review Reviewer, opts ...Option) *Server {
+	s := &Server{
+		name:     name,
+		version:  version,
+		review:   review,
+		debounce: 2 * time.Second,
+		onSave:   true,
+		sem:      make(chan struct{}, 1),
+		docs:     make(map[string]*document),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// message 是 JSON-RPC 请求、通知或响应
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError 是 JSON-RPC 错误
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// errExit 表示客户端发送了 exit 通知
+var errExit = errors.New("exit")
+
+// Serve 从 r 读取消息并将响应与通知写入 w，直到客户端发送 exit、r 结束或 ctx 被取消
+// 返回前取消并等待进行中的审查
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = w
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		s.stopAll()
+		s.wg.Wait()
+	}()
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	msgs := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			body, err := readMessage(br)
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+			select {
+			case msgs <- body:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			return err
+		case body := <-msgs:
+			if err := s.handle(body); err != nil {
+				if errors.Is(err, errExit) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
+
+// readMessage 读取一条消息：若干行头部（必须包含 Content-Length）、空行与消息体
+func readMessage(br *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(br).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("读取消息头失败: %w", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 || length > maxMessageSize {
+		return nil, fmt.Errorf("无效的 Content-Length: %q", header.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(br, body); err != nil {
+		return nil, fmt.Errorf("读取消息体失败: %w", err)
+	}
+	return body, nil
+}
+
+// handle 处理一条消息，客户端发送 exit 时返回 errExit
+func (s *Server) handle(body []byte) error {
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		s.reply(json.RawMessage("null"), nil, &rpcError{Code: codeParseError, Message: "无法解析的 JSON"})
+		return nil
+	}
+	if msg.Method == "" {
+		// 带 ID 的是客户端对服务端请求的响应，服务端不发送请求，直接忽略
+		if msg.ID == nil {
+			s.reply(json.RawMessage("null"), nil, &rpcError{Code: codeInvalidRequest, Message: "缺少 method"})
+		}
+		return nil
+	}
+	if msg.Method == "exit" {
+		return errExit
+	}
+
+	s.mu.Lock()
+	initialized, shuttingDown := s.initialized, s.shuttingDown
+	s.mu.Unlock()
+	if msg.ID == nil {
+		if initialized && !shuttingDown {
+			s.notification(msg)
+		}
+		return nil
+	}
+
+	switch {
+	case msg.Method == "initialize":
+		s.reply(msg.ID, s.initialize(msg.Params), nil)
+	case !initialized:
+		s.reply(msg.ID, nil, &rpcError{Code: codeServerNotInitialized, Message: "服务尚未初始化"})
+	case shuttingDown:
+		s.reply(msg.ID, nil, &rpcError{Code: codeInvalidRequest, Message: "服务正在关闭"})
+	case msg.Method == "shutdown":
+		s.mu.Lock()
+		s.shuttingDown = true
+		s.mu.Unlock()
+		s.stopAll()
+		s.reply(msg.ID, nil, nil)
+	case msg.Method == "textDocument/codeAction":
+		s.reply(msg.ID, s.codeActions(msg.Params), nil)
+	case msg.Method == "workspace/executeCommand":
+		result, rpcErr := s.executeCommand(msg.Params)
+		s.reply(msg.ID, result, rpcErr)
+	default:
+		s.reply(msg.ID, nil, &rpcError{Code: codeMethodNotFound, Message: "不支持的方法: " + msg.Method})
+	}
+	return nil
+}
+
+// initialize 返回服务端信息与能力：打开、修改（全量同步）、保存（带内容）与关闭通知，以及按需审查的代码操作与命令
+func (s *Server) initialize(params json.RawMessage) any {
+	var req struct {
+		ClientInfo struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"clientInfo"`
+	}
+	_ = json.Unmarshal(params, &req)
+	slog.Info("LSP 客户端已连接", "client", req.ClientInfo.Name, "version", req.ClientInfo.Version)
+
+	s.mu.Lock()
+	s.initialized = true
+	s.mu.Unlock()
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync": map[string]any{
+				"openClose": true,
+				"change":    1, // Full
+				"save":      map[string]any{"includeText": true},
+			},
+			"codeActionProvider":     true,
+			"executeCommandProvider": map[string]any{"commands": []string{CommandReviewFile}},
+		},
+		"serverInfo": map[string]any{"name": s.name, "version": s.version},
+	}
+}
+
+// textDocumentParams 是文档通知的参数
+type textDocumentParams struct {
+	TextDocument struct {
+		URI     string `json:"uri"`
+		Text    string `json:"text"`
+		Version int    `json:"version"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Text *string `json:"text"` // didSave 的内容（includeText 为 true 时）
+}
+
+// notification 处理客户端通知
+func (s *Server) notification(msg message) {
+	var p textDocumentParams
+	if !strings.HasPrefix(msg.Method, "textDocument/") || json.Unmarshal(msg.Params, &p) != nil || p.TextDocument.URI == "" {
+		return
+	}
+	uri := p.TextDocument.URI
+
+	switch msg.Method {
+	case "textDocument/didOpen":
+		path, err := uriToPath(uri)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		doc := s.document(uri, path)
+		doc.open, doc.text, doc.version = true, p.TextDocument.Text, p.TextDocument.Version
+		s.mu.Unlock()
+	case "textDocument/didChange":
+		s.mu.Lock()
+		if doc := s.docs[uri]; doc != nil && len(p.ContentChanges) > 0 {
+			doc.text, doc.version = p.ContentChanges[len(p.ContentChanges)-1].Text, p.TextDocument.Version
+		}
+		s.mu.Unlock()
+	case "textDocument/didSave":
+		// 只审查已打开的文档，未打开的文件按需审查时才会创建文档
+		s.mu.Lock()
+		doc := s.docs[uri]
+		open := doc != nil && doc.open
+		s.mu.Unlock()
+		if !open || !s.onSave || (s.saveFilter != nil && !s.saveFilter(doc.path)) {
+			return
+		}
+		s.mu.Lock()
+		if p.Text != nil {
+			doc.text = *p.Text
+		}
+		s.schedule(uri, doc, s.debounce)
+		s.mu.Unlock()
+	case "textDocument/didClose":
+		s.mu.Lock()
+		doc := s.docs[uri]
+		if doc != nil {
+			s.stop(doc)
+			delete(s.docs, uri)
+		}
+		s.mu.Unlock()
+		if doc != nil {
+			s.publish(uri, nil, nil)
+		}
+	}
+}
+
+// document 返回 URI 对应的文档，不存在时创建（调用方需持有 mu）
+func (s *Server) document(uri, path string) *document {
+	doc := s.docs[uri]
+	if doc == nil {
+		doc = &document{path: path}
+		s.docs[uri] = doc
+	}
+	return doc
+}
+
+// codeActions 为文件提供“AI 审查当前文件”的代码操作，触发 CommandReviewFile 命令
+func (s *Server) codeActions(params json.RawMessage) any {
+	var p struct {
+		TextDocument struct {
+			URI string `json:"uri"`
+		} `json:"textDocument"`
+	}
+	if json.Unmarshal(params, &p) != nil || !strings.HasPrefix(p.TextDocument.URI, "file:") {
+		return []any{}
+	}
+	return []map[string]any{{
+		"title": "AI 审查当前文件",
+		"kind":  "source",
+		"command": map[string]any{
+			"title":     "AI 审查当前文件",
+			"command":   CommandReviewFile,
+			"arguments": []string{p.TextDocument.URI},
+		},
+	}}
+}
+
+// executeCommand 执行 CommandReviewFile：立即审查文件（忽略保存过滤器与未变化内容的缓存），审查结果以诊断发布
+func (s *Server) executeCommand(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Command   string            `json:"command"`
+		Arguments []json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "参数无效: " + err.Error()}
+	}
+	if p.Command != CommandReviewFile {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "未知的命令: " + p.Command}
+	}
+	var uri string
+	if len(p.Arguments) == 0 || json.Unmarshal(p.Arguments[0], &uri) != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "缺少文件 URI 参数"}
+	}
+	path, err := uriToPath(uri)
+	if err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	s.mu.Lock()
+	doc := s.document(uri, path)
+	doc.reviewed = [sha256.Size]byte{}
+	s.schedule(uri, doc, 0)
+	s.mu.Unlock()
+	return nil, nil
+}
+
+// schedule 在 delay 后审查文档，取消尚未开始的审查与进行中的审查（调用方需持有 mu）
+func (s *Server) schedule(uri string, doc *document, delay time.Duration) {
+	s.stop(doc)
+	doc.seq++
+	seq := doc.seq
+	doc.timer = time.AfterFunc(delay, func() { s.run(uri, seq) })
+}
+
+// stop 取消文档尚未开始与进行中的审查（调用方需持有 mu）
+func (s *Server) stop(doc *document) {
+	if doc.timer != nil {
+		doc.timer.Stop()
+		doc.timer = nil
+	}
+	if doc.cancel != nil {
+		doc.cancel()
+		doc.cancel = nil
+	}
+	doc.seq++
+}
+
+// stopAll 取消所有文档的审查
+func (s *Server) stopAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range s.docs {
+		s.stop(doc)
+	}
+}
+
+// run 执行第 seq 次审查：内容与最近一次审查相同时直接重新发布已有的诊断
+func (s *Server) run(uri string, seq int) {
+	s.mu.Lock()
+	doc := s.docs[uri]
+	if doc == nil || doc.seq != seq || s.shuttingDown || s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	doc.timer, doc.cancel = nil, cancel
+	path, open, text, version := doc.path, doc.open, doc.text, doc.version
+	s.wg.Add(1)
+	s.mu.Unlock()
+	defer s.wg.Done()
+	defer cancel()
+
+	if !open {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			s.showMessage(messageError, fmt.Sprintf("读取 %s 失败: %v", filepath.Base(path), err))
+			return
+		}
+		text = string(data)
+	}
+	sum := sha256.Sum256([]byte(text))
+	s.mu.Lock()
+	cached := doc.reviewed == sum
+	diagnostics := doc.diagnostics
+	s.mu.Unlock()
+	if cached {
+		s.publish(uri, &version, diagnostics)
+		return
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() { <-s.sem }()
+
+	s.logMessage(fmt.Sprintf("正在审查 %s", path))
+	started := time.Now()
+	diagnostics, err := s.review(ctx, path, text)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		slog.Warn("审查失败", "file", path, "err", err)
+		s.showMessage(messageError, fmt.Sprintf("AI 审查 %s 失败: %v", filepath.Base(path), err))
+		return
+	}
+
+	s.mu.Lock()
+	if doc.seq != seq {
+		s.mu.Unlock()
+		return
+	}
+	doc.reviewed, doc.diagnostics, doc.cancel = sum, diagnostics, nil
+	s.mu.Unlock()
+	s.publish(uri, &version, diagnostics)
+	s.logMessage(fmt.Sprintf("审查完成 %s: %d 个问题，耗时 %s", path, len(diagnostics), time.Since(started).Round(100*time.Millisecond)))
+}
+
+// publish 发布文档的诊断，diagnostics 为空时清除已有的诊断
+func (s *Server) publish(uri string, version *int, diagnostics []Diagnostic) {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	params := map[string]any{"uri": uri, "diagnostics": diagnostics}
+	if version != nil && *version > 0 {
+		params["version"] = *version
+	}
+	s.notify("textDocument/publishDiagnostics", params)
+}
+
+// showMessage 在编辑器中弹出消息
+func (s *Server) showMessage(typ int, text string) {
+	s.notify("window/showMessage", map[string]any{"type": typ, "message": text})
+}
+
+// logMessage 写入编辑器的语言服务输出日志
+func (s *Server) logMessage(text string) {
+	s.notify("window/logMessage", map[string]any{"type": messageLog, "message": text})
+}
+
+// notify 发送通知
+func (s *Server) notify(method string, params any) {
+	data, _ := json.Marshal(params)
+	s.write(message{JSONRPC: "2.0", Method: method, Params: data})
+}
+
+// reply 发送响应，result 为 nil 时为 JSON null
+func (s *Server) reply(id json.RawMessage, result any, rpcErr *rpcError) {
+	if rpcErr != nil {
+		s.write(message{JSONRPC: "2.0", ID: id, Error: rpcErr})
+		return
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	s.write(message{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// write 写入一条消息（Content-Length 头与 JSON 消息体）
+func (s *Server) write(msg message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("编码 LSP 消息失败", "err", err)
+		return
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		slog.Warn("写入 LSP 消息失败", "err", err)
+	}
+}
+
+// uriToPath 将 file:// URI 转换为本地路径
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", fmt.Errorf("只支持本地文件: %s", uri)
+	}
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/src/main.go 的路径为 /C:/src/main.go
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path), nil
+}
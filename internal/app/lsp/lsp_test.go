@@ -0,0 +1,323 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// response 是测试客户端收到的响应或通知
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// reviewCall 记录一次审查的路径与内容
+type reviewCall struct {
+	path    string
+	content string
+}
+
+// testClient 通过管道与服务端交换消息
+type testClient struct {
+	t      *testing.T
+	server *Server
+	in     *io.PipeWriter
+	msgs   chan response
+	done   chan error
+	nextID int
+
+	mu      sync.Mutex
+	reviews []reviewCall
+}
+
+// startServer 启动服务端，审查结果为内容的第一行对应的一条诊断
+func startServer(t *testing.T, opts ...Option) *testClient {
+	t.Helper()
+	c := &testClient{t: t, msgs: make(chan response, 64), done: make(chan error, 1)}
+	review := func(_ context.Context, path, content string) ([]Diagnostic, error) {
+		c.mu.Lock()
+		c.reviews = append(c.reviews, reviewCall{path, content})
+		c.mu.Unlock()
+		return []Diagnostic{{Range: LineRange(content, 1), Severity: SeverityWarning, Source: "test", Message: "reviewed"}}, nil
+	}
+	c.server = NewServer("reviewer", "test", review, append([]Option{WithDebounce(0)}, opts...)...)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c.in = inW
+	go func() {
+		c.done <- c.server.Serve(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	go func() {
+		br := bufio.NewReader(outR)
+		for {
+			body, err := readMessage(br)
+			if err != nil {
+				close(c.msgs)
+				return
+			}
+			var resp response
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Errorf("无法解析服务端消息 %s: %v", body, err)
+				continue
+			}
+			c.msgs <- resp
+		}
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+		select {
+		case <-c.done:
+		case <-time.After(5 * time.Second):
+			t.Error("服务端未退出")
+		}
+	})
+	return c
+}
+
+// send 发送一条消息
+func (c *testClient) send(method string, id any, params any) {
+	c.t.Helper()
+	msg := map[string]any{"jsonrpc": "2.0", "method": method}
+	if id != nil {
+		msg["id"] = id
+	}
+	if params != nil {
+		msg["params"] = params
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("编码消息失败: %v", err)
+	}
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		c.t.Fatalf("发送消息失败: %v", err)
+	}
+}
+
+// call 发送请求并等待对应的响应
+func (c *testClient) call(method string, params any) response {
+	c.t.Helper()
+	c.nextID++
+	id := c.nextID
+	c.send(method, id, params)
+	return c.wait(func(r response) bool { return string(r.ID) == fmt.Sprint(id) })
+}
+
+// wait 等待满足条件的消息，跳过其余消息
+func (c *testClient) wait(match func(response) bool) response {
+	c.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case r, ok := <-c.msgs:
+			if !ok {
+				c.t.Fatal("服务端已关闭输出")
+			}
+			if match(r) {
+				return r
+			}
+		case <-timeout:
+			c.t.Fatal("等待消息超时")
+		}
+	}
+}
+
+// diagnostics 等待 uri 的下一条 publishDiagnostics 通知
+func (c *testClient) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+	var p struct {
+		URI         string       `json:"uri"`
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}
+	c.wait(func(r response) bool {
+		return r.Method == "textDocument/publishDiagnostics" && json.Unmarshal(r.Params, &p) == nil && p.URI == uri
+	})
+	return p.Diagnostics
+}
+
+// reviewed 返回已执行的审查
+func (c *testClient) reviewed() []reviewCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]reviewCall(nil), c.reviews...)
+}
+
+// initialize 完成初始化握手
+func (c *testClient) initialize() {
+	c.t.Helper()
+	if r := c.call("initialize", map[string]any{"clientInfo": map[string]string{"name": "test"}}); r.Error != nil {
+		c.t.Fatalf("initialize: %+v", r.Error)
+	}
+	c.send("initialized", nil, map[string]any{})
+}
+
+// tempFile 在临时目录中写入文件，返回路径与 URI
+func tempFile(t *testing.T, content string) (string, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	return path, (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func textDocument(uri, text string) map[string]any {
+	return map[string]any{"textDocument": map[string]any{"uri": uri, "text": text, "version": 1, "languageId": "go"}}
+}
+
+func TestInitialize(t *testing.T) {
+	c := startServer(t)
+
+	if r := c.call("textDocument/codeAction", map[string]any{}); r.Error == nil || r.Error.Code != codeServerNotInitialized {
+		t.Errorf("初始化前的请求: error=%+v, want %d", r.Error, codeServerNotInitialized)
+	}
+
+	r := c.call("initialize", map[string]any{"clientInfo": map[string]string{"name": "test"}})
+	var result struct {
+		Capabilities struct {
+			TextDocumentSync struct {
+				OpenClose bool `json:"openClose"`
+				Change    int  `json:"change"`
+				Save      struct {
+					IncludeText bool `json:"includeText"`
+				} `json:"save"`
+			} `json:"textDocumentSync"`
+			ExecuteCommandProvider struct {
+				Commands []string `json:"commands"`
+			} `json:"executeCommandProvider"`
+		} `json:"capabilities"`
+		ServerInfo struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(r.Result, &result); err != nil {
+		t.Fatalf("解析 initialize 结果失败: %v (%s)", err, r.Result)
+	}
+	docSync := result.Capabilities.TextDocumentSync
+	if !docSync.OpenClose || docSync.Change != 1 || !docSync.Save.IncludeText {
+		t.Errorf("textDocumentSync = %+v", docSync)
+	}
+	if cmds := result.Capabilities.ExecuteCommandProvider.Commands; len(cmds) != 1 || cmds[0] != CommandReviewFile {
+		t.Errorf("commands = %v, want [%s]", cmds, CommandReviewFile)
+	}
+	if result.ServerInfo.Name != "reviewer" {
+		t.Errorf("serverInfo.name = %q", result.ServerInfo.Name)
+	}
+
+	if r := c.call("unknown/method", nil); r.Error == nil || r.Error.Code != codeMethodNotFound {
+		t.Errorf("未知方法: error=%+v, want %d", r.Error, codeMethodNotFound)
+	}
+}
+
+func TestDidSavePublishesDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		open     string  // didOpen 时的内容
+		saveText *string // didSave 携带的内容
+		want     string  // 审查的内容
+	}{
+		{"使用打开时的内容", "package main // buffer\n", nil, "package main // buffer\n"},
+		{"使用保存时的内容", "package main\n", ptr("package main // saved\n"), "package main // saved\n"},
+		{"打开的空文档不读取磁盘", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := startServer(t)
+			c.initialize()
+			path, uri := tempFile(t, "package main // disk\n")
+
+			c.send("textDocument/didOpen", nil, textDocument(uri, tt.open))
+			params := map[string]any{"textDocument": map[string]any{"uri": uri}}
+			if tt.saveText != nil {
+				params["text"] = *tt.saveText
+			}
+			c.send("textDocument/didSave", nil, params)
+
+			if diags := c.diagnostics(uri); len(diags) != 1 || diags[0].Message != "reviewed" {
+				t.Fatalf("diagnostics = %+v", diags)
+			}
+			calls := c.reviewed()
+			if len(calls) != 1 || calls[0].path != path || calls[0].content != tt.want {
+				t.Errorf("reviews = %+v, want [{%s %q}]", calls, path, tt.want)
+			}
+		})
+	}
+}
+
+func TestDidSaveIgnoresUnopenedDocument(t *testing.T) {
+	c := startServer(t)
+	c.initialize()
+	_, uri := tempFile(t, "package main\n")
+
+	c.send("textDocument/didSave", nil, map[string]any{"textDocument": map[string]any{"uri": uri}, "text": "package main\n"})
+	// 通知按顺序处理，收到后续请求的响应时 didSave 已处理完毕
+	c.call("textDocument/codeAction", map[string]any{"textDocument": map[string]any{"uri": uri}})
+
+	c.server.mu.Lock()
+	n := len(c.server.docs)
+	c.server.mu.Unlock()
+	if n != 0 {
+		t.Errorf("didSave 为未打开的文档创建了 %d 个条目", n)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if calls := c.reviewed(); len(calls) != 0 {
+		t.Errorf("审查了未打开的文档: %+v", calls)
+	}
+}
+
+func TestExecuteCommandReadsDisk(t *testing.T) {
+	c := startServer(t)
+	c.initialize()
+	_, uri := tempFile(t, "package main // disk\n")
+
+	if r := c.call("workspace/executeCommand", map[string]any{"command": CommandReviewFile, "arguments": []string{uri}}); r.Error != nil {
+		t.Fatalf("executeCommand: %+v", r.Error)
+	}
+	if diags := c.diagnostics(uri); len(diags) != 1 {
+		t.Fatalf("diagnostics = %+v", diags)
+	}
+	if calls := c.reviewed(); len(calls) != 1 || calls[0].content != "package main // disk\n" {
+		t.Errorf("reviews = %+v, want 磁盘上的内容", calls)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	c := startServer(t)
+	c.initialize()
+
+	r := c.call("shutdown", nil)
+	if r.Error != nil || string(r.Result) != "null" {
+		t.Fatalf("shutdown: result=%s error=%+v", r.Result, r.Error)
+	}
+	if r := c.call("textDocument/codeAction", map[string]any{}); r.Error == nil || r.Error.Code != codeInvalidRequest {
+		t.Errorf("关闭后的请求: error=%+v, want %d", r.Error, codeInvalidRequest)
+	}
+
+	c.send("exit", nil, nil)
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+		c.done <- err // 供 Cleanup 确认已退出
+	case <-time.After(5 * time.Second):
+		t.Fatal("exit 后服务端未退出")
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,337 @@
+// Package mcp 实现 Model Context Protocol 服务端的最小子集：通过标准输入输出交换按行分隔的 JSON-RPC 2.0 消息，
+// 只提供工具（tools）能力，供 AI 助手与 IDE 调用代码审查
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// LatestProtocolVersion 是支持的最新协议版本，客户端请求的版本不受支持时使用
+const LatestProtocolVersion = "2025-06-18"
+
+// protocolVersions 是支持的协议版本
+var protocolVersions = []string{"2024-11-05", "2025-03-26", LatestProtocolVersion}
+
+// JSON-RPC 错误码
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool 是服务端提供的一个工具
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema map[string]any // 参数的 JSON Schema
+	Handler     Handler
+}
+
+// Handler 执行工具调用，返回给模型的文本结果；返回错误时结果标记为 isError，错误信息作为文本返回
+type Handler func(ctx context.Context, call *Call) (string, error)
+
+// Call 是一次工具调用
+type Call struct {
+	Arguments json.RawMessage // 工具参数（JSON 对象）
+
+	progress func(done, total int, message string)
+}
+
+// Bind 将参数解析到 v，未知字段视为错误
+func (c *Call) Bind(v any) error {
+	if len(c.Arguments) == 0 || string(c.Arguments) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(c.Arguments))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("参数无效: %w", err)
+	}
+	return nil
+}
+
+// Progress 向客户端报告进度，客户端没有请求进度通知时忽略
+func (c *Call) Progress(done, total int, message string) {
+	if c.progress != nil {
+		c.progress(done, total, message)
+	}
+}
+
+// Server 是 MCP 服务端
+type Server struct {
+	name         string
+	version      string
+	instructions string
+	tools        []Tool
+
+	writeMu sync.Mutex
+	out     io.Writer
+
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc // 正在执行的工具调用，按请求 ID 取消
+}
+
+// NewServer 创建服务端，instructions 是提供给客户端的使用说明（可为空）
+func NewServer(name, version, instructions string, tools ...Tool) *Server {
+	return &Server{
+		name:         name,
+		version:      version,
+		instructions: instructions,
+		tools:        tools,
+		inflight:     make(map[string]context.CancelFunc),
+	}
+}
+
+// message 是 JSON-RPC 请求、通知或响应
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError 是 JSON-RPC 错误
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve 从 r 读取请求并将响应写入 w，直到 r 结束或 ctx 被取消
+// 工具调用在独立的 goroutine 中执行，返回前取消并等待进行中的调用
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = w
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			return err
+		case line := <-lines:
+			s.handle(ctx, line, &wg)
+		}
+	}
+}
+
+// handle 处理一条消息
+func (s *Server) handle(ctx context.Context, line []byte, wg *sync.WaitGroup) {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		s.reply(json.RawMessage("null"), nil, &rpcError{Code: codeParseError, Message: "无法解析的 JSON"})
+		return
+	}
+	if msg.Method == "" {
+		// 带 ID 的是客户端对服务端请求的响应，服务端不发送请求，直接忽略
+		if msg.ID == nil {
+			s.reply(json.RawMessage("null"), nil, &rpcError{Code: codeInvalidRequest, Message: "缺少 method"})
+		}
+		return
+	}
+
+	if msg.ID == nil {
+		s.notification(msg)
+		return
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.reply(msg.ID, s.initialize(msg.Params), nil)
+	case "ping":
+		s.reply(msg.ID, struct{}{}, nil)
+	case "tools/list":
+		s.reply(msg.ID, s.listTools(), nil)
+	case "tools/call":
+		wg.Go(func() { s.callTool(ctx, msg) })
+	default:
+		s.reply(msg.ID, nil, &rpcError{Code: codeMethodNotFound, Message: "不支持的方法: " + msg.Method})
+	}
+}
+
+// notification 处理客户端通知，目前只处理取消请求
+func (s *Server) notification(msg message) {
+	if msg.Method != "notifications/cancelled" {
+		return
+	}
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if json.Unmarshal(msg.Params, &params) != nil {
+		return
+	}
+	s.mu.Lock()
+	cancel, ok := s.inflight[string(params.RequestID)]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// initialize 返回服务端信息与能力，协议版本与客户端协商
+func (s *Server) initialize(params json.RawMessage) any {
+	var req struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ClientInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"clientInfo"`
+	}
+	_ = json.Unmarshal(params, &req)
+	version := LatestProtocolVersion
+	if slices.Contains(protocolVersions, req.ProtocolVersion) {
+		version = req.ProtocolVersion
+	}
+	slog.Info("MCP 客户端已连接", "client", req.ClientInfo.Name, "version", req.ClientInfo.Version, "protocol", version)
+
+	result := map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{"listChanged": false}},
+		"serverInfo":      map[string]any{"name": s.name, "version": s.version},
+	}
+	if s.instructions != "" {
+		result["instructions"] = s.instructions
+	}
+	return result
+}
+
+// listTools 返回工具列表
+func (s *Server) listTools() any {
+	tools := make([]map[string]any, 0, len(s.tools))
+	for _, t := range s.tools {
+		tools = append(tools, map[string]any{
+			"name":        t.Name,
+			"description": t.Description,
+			"inputSchema": t.InputSchema,
+		})
+	}
+	return map[string]any{"tools": tools}
+}
+
+// callTool 执行工具调用；调用被客户端取消时不发送响应
+func (s *Server) callTool(ctx context.Context, msg message) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+		Meta      struct {
+			ProgressToken json.RawMessage `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		s.reply(msg.ID, nil, &rpcError{Code: codeInvalidParams, Message: "参数无效: " + err.Error()})
+		return
+	}
+	idx := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == params.Name })
+	if idx < 0 {
+		s.reply(msg.ID, nil, &rpcError{Code: codeInvalidParams, Message: "未知的工具: " + params.Name})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	key := string(msg.ID)
+	s.mu.Lock()
+	s.inflight[key] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+	}()
+
+	call := &Call{Arguments: params.Arguments}
+	if token := params.Meta.ProgressToken; len(token) > 0 {
+		call.progress = func(done, total int, message string) {
+			p := map[string]any{"progressToken": token, "progress": done, "total": total}
+			if message != "" {
+				p["message"] = message
+			}
+			s.write(notificationMessage("notifications/progress", p))
+		}
+	}
+
+	slog.Info("调用 MCP 工具", "tool", params.Name)
+	text, err := s.tools[idx].Handler(ctx, call)
+	if ctx.Err() != nil {
+		slog.Info("MCP 工具调用已取消", "tool", params.Name)
+		return
+	}
+	isError := err != nil
+	if isError {
+		text = err.Error()
+		slog.Warn("MCP 工具调用失败", "tool", params.Name, "err", err)
+	}
+	s.reply(msg.ID, map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": isError,
+	}, nil)
+}
+
+// notificationMessage 构造服务端发出的通知
+func notificationMessage(method string, params any) message {
+	data, _ := json.Marshal(params)
+	return message{JSONRPC: "2.0", Method: method, Params: data}
+}
+
+// reply 发送响应
+func (s *Server) reply(id json.RawMessage, result any, rpcErr *rpcError) {
+	msg := message{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		msg.Result = result
+	}
+	s.write(msg)
+}
+
+// write 写入一条消息（一行 JSON）
+func (s *Server) write(msg message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("编码 MCP 消息失败", "err", err)
+		return
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		slog.Warn("写入 MCP 消息失败", "err", err)
+	}
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DingTalk 发送到钉钉群的自定义机器人
+type DingTalk struct {
+	Webhook string
+	Secret  string // 安全设置为“加签”时的密钥（SEC 开头），为空时不签名
+}
+
+// Name 返回渠道名称
+func (d DingTalk) Name() string {
+	return "钉钉"
+}
+
+// Send 发送运行摘要：报告链接为 URL 时使用带“查看报告”按钮的 ActionCard，否则使用 Markdown 消息
+func (d DingTalk) Send(ctx context.Context, run Run) error {
+	webhook, err := d.signedURL(time.Now())
+	if err != nil {
+		return err
+	}
+
+	var payload map[string]any
+	if u := run.reportURL(); u != "" {
+		payload = map[string]any{
+			"msgtype": "actionCard",
+			"actionCard": map[string]any{
+				"title":       run.title(),
+				"text":        "### " + run.title() + "\n\n" + run.markdown(false),
+				"singleTitle": "查看报告",
+				"singleURL":   u,
+			},
+		}
+	} else {
+		payload = map[string]any{
+			"msgtype": "markdown",
+			"markdown": map[string]any{
+				"title": run.title(),
+				"text":  "### " + run.title() + "\n\n" + run.markdown(true),
+			},
+		}
+	}
+
+	body, err := postJSON(ctx, webhook, payload)
+	if err != nil {
+		return err
+	}
+	return checkErrcode(body)
+}
+
+// signedURL 返回带签名的 Webhook 地址：timestamp 为毫秒时间戳，
+// sign 为以 Secret 为密钥对 "timestamp\nSecret" 做 HmacSHA256 后的 Base64
+func (d DingTalk) signedURL(now time.Time) (string, error) {
+	if d.Secret == "" {
+		return d.Webhook, nil
+	}
+	u, err := url.Parse(d.Webhook)
+	if err != nil {
+		return "", fmt.Errorf("钉钉 Webhook 地址无效: %w", err)
+	}
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(d.Secret))
+	mac.Write([]byte(timestamp + "\n" + d.Secret))
+
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
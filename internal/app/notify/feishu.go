@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"time"
+)
+
+// Feishu 发送到飞书（Lark）群的自定义机器人
+type Feishu struct {
+	Webhook string
+	Secret  string // 安全设置为“签名校验”时的密钥，为空时不签名
+}
+
+// Name 返回渠道名称
+func (f Feishu) Name() string {
+	return "飞书"
+}
+
+// Send 以消息卡片发送运行摘要，卡片标题按最高严重程度着色，报告链接为 URL 时附“查看报告”按钮
+func (f Feishu) Send(ctx context.Context, run Run) error {
+	elements := []map[string]any{
+		{"tag": "markdown", "content": run.markdown(run.reportURL() == "")},
+	}
+	if u := run.reportURL(); u != "" {
+		elements = append(elements, map[string]any{
+			"tag": "action",
+			"actions": []map[string]any{{
+				"tag":  "button",
+				"text": map[string]any{"tag": "plain_text", "content": "查看报告"},
+				"type": "primary",
+				"url":  u,
+			}},
+		})
+	}
+
+	payload := map[string]any{
+		"msg_type": "interactive",
+		"card": map[string]any{
+			"config": map[string]any{"wide_screen_mode": true},
+			"header": map[string]any{
+				"title":    map[string]any{"tag": "plain_text", "content": run.title()},
+				"template": feishuTemplate(run),
+			},
+			"elements": elements,
+		},
+	}
+	if f.Secret != "" {
+		timestamp, sign := f.sign(time.Now())
+		payload["timestamp"] = timestamp
+		payload["sign"] = sign
+	}
+
+	body, err := postJSON(ctx, f.Webhook, payload)
+	if err != nil {
+		return err
+	}
+	return checkErrcode(body)
+}
+
+// sign 返回秒级时间戳与签名：以 "timestamp\nSecret" 为密钥对空字符串做 HmacSHA256 后的 Base64
+func (f Feishu) sign(now time.Time) (string, string) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+f.Secret))
+	return timestamp, base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// feishuTemplate 返回卡片标题的颜色：有严重问题为红色，有重要问题为橙色，否则为绿色
+func feishuTemplate(run Run) string {
+	switch {
+	case run.Digest.Critical > 0:
+		return "red"
+	case run.Digest.Major > 0:
+		return "orange"
+	default:
+		return "green"
+	}
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// HookEvent 是通用 Webhook 的事件名称，同时写入 X-Reviewer-Event 请求头
+const HookEvent = "review.completed"
+
+// Hook 将运行摘要以 JSON 发送到任意 HTTP 地址，供 CI、工单系统或自建服务等下游自动化使用
+type Hook struct {
+	URL     string
+	Secret  string            // 签名密钥，设置时请求带有 X-Reviewer-Signature: sha256=<HMAC-SHA256(请求体)>
+	Headers map[string]string // 附加的请求头，如 Authorization
+}
+
+// hookPayload 是通用 Webhook 的请求体
+type hookPayload struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Name      string      `json:"name"`              // 报告名称
+	Project   string      `json:"project,omitempty"` // 所属项目（仓库）
+	Audit     bool        `json:"audit"`
+	Score     float64     `json:"score"` // 综合评分
+	Files     int         `json:"files"`
+	Reviewed  int         `json:"reviewed"`
+	Skipped   int         `json:"skipped"`
+	Issues    hookCounts  `json:"issues"`
+	TopIssues []hookIssue `json:"top_issues"`
+	Summary   string      `json:"summary,omitempty"` // 执行摘要
+	Duration  float64     `json:"duration_seconds"`
+	Tokens    int64       `json:"tokens"`
+	ReportURL string      `json:"report_url,omitempty"`  // 报告的 URL（配置了 notify_report_url 时）
+	Report    string      `json:"report_path,omitempty"` // 报告的本地路径（未配置 notify_report_url 时）
+}
+
+type hookCounts struct {
+	Critical int `json:"critical"`
+	Major    int `json:"major"`
+	Minor    int `json:"minor"`
+}
+
+type hookIssue struct {
+	Severity string `json:"severity"` // critical / major / minor
+	File     string `json:"file"`
+	Text     string `json:"text"`
+}
+
+// Name 返回渠道名称（Webhook 的主机名，地址中可能包含密钥，不输出完整地址）
+func (h Hook) Name() string {
+	if u, err := url.Parse(h.URL); err == nil && u.Host != "" {
+		return "Webhook " + u.Host
+	}
+	return "Webhook"
+}
+
+// Send 发送运行摘要，2xx 以外的状态码视为失败
+func (h Hook) Send(ctx context.Context, run Run) error {
+	d := run.Digest
+	payload := hookPayload{
+		Event:     HookEvent,
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+		Name:      d.Name,
+		Project:   run.Project,
+		Audit:     d.Audit,
+		Score:     d.Score,
+		Files:     d.Files,
+		Reviewed:  d.Reviewed,
+		Skipped:   d.Skipped,
+		Issues:    hookCounts{Critical: d.Critical, Major: d.Major, Minor: d.Minor},
+		TopIssues: make([]hookIssue, 0, len(d.TopIssues)),
+		Summary:   d.Summary,
+		Duration:  run.Duration.Round(time.Second).Seconds(),
+		Tokens:    run.Tokens,
+		ReportURL: run.reportURL(),
+	}
+	if payload.ReportURL == "" {
+		payload.Report = run.ReportLink
+	}
+	for _, issue := range d.TopIssues {
+		payload.TopIssues = append(payload.TopIssues, hookIssue{Severity: issue.Severity.String(), File: issue.File, Text: issue.Text})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"X-Reviewer-Event": HookEvent}
+	for k, v := range h.Headers {
+		headers[k] = v
+	}
+	if h.Secret != "" {
+		headers["X-Reviewer-Signature"] = signature(h.Secret, body)
+	}
+	_, err = post(ctx, h.URL, body, headers)
+	return err
+}
+
+// signature 返回请求体的签名 "sha256=<十六进制 HMAC-SHA256>"，接收方以同一密钥计算后比较即可验证来源
+func signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,167 @@
+// Package notify 将审查完成后的运行摘要（综合评分、问题统计、主要问题与报告链接）
+// 发送到 Slack、钉钉、飞书与企业微信的群机器人，或以 JSON 发送到任意 Webhook
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/reviewer"
+	"go-ai-reviewer/internal/llm"
+	"go-ai-reviewer/pkg/utils"
+)
+
+// requestTimeout 是发送一条消息的时限，通知失败不应拖慢审查的结束
+const requestTimeout = 10 * time.Second
+
+// maxIssueLength 是消息中单个问题的最大字符数，过长的问题截断
+const maxIssueLength = 200
+
+// severityEmoji 是各严重程度在消息中的标记，与报告一致
+var severityEmoji = map[llm.Severity]string{
+	llm.SeverityCritical: "🔴",
+	llm.SeverityMajor:    "🟠",
+	llm.SeverityMinor:    "🟡",
+}
+
+// Run 是一次审查任务的运行摘要
+type Run struct {
+	Digest     reviewer.RunDigest
+	Duration   time.Duration
+	ReportLink string // 报告的 URL 或本地路径，为空时不附链接
+	Project    string // 所属项目（仓库），只写入通用 Webhook 的请求体
+	Tokens     int64  // 消耗的 Token，只写入通用 Webhook 的请求体
+}
+
+// title 返回消息标题，如 "代码审查完成: backend"
+func (r Run) title() string {
+	kind := "代码审查"
+	if r.Digest.Audit {
+		kind = "安全审计"
+	}
+	return fmt.Sprintf("%s完成: %s", kind, r.Digest.Name)
+}
+
+// counts 返回各严重程度的问题数
+func (r Run) counts() string {
+	return fmt.Sprintf("🔴 %d　🟠 %d　🟡 %d", r.Digest.Critical, r.Digest.Major, r.Digest.Minor)
+}
+
+// reportURL 返回报告的 URL，报告链接不是 http(s) 地址时返回空字符串
+func (r Run) reportURL() string {
+	if strings.HasPrefix(r.ReportLink, "https://") || strings.HasPrefix(r.ReportLink, "http://") {
+		return r.ReportLink
+	}
+	return ""
+}
+
+// markdown 返回钉钉、飞书与企业微信通用的 Markdown 正文（不含标题），withLink 为 false 时不包含报告链接
+func (r Run) markdown(withLink bool) string {
+	d := r.Digest
+	var b strings.Builder
+	fmt.Fprintf(&b, "**综合评分**: %.1f / 100\n", d.Score)
+	fmt.Fprintf(&b, "**问题**: %s\n", r.counts())
+	fmt.Fprintf(&b, "**文件**: %d (有效分析 %d，跳过 %d)，耗时 %s\n", d.Files, d.Reviewed, d.Skipped, r.Duration.Round(time.Second))
+	if summary := strings.TrimSpace(d.Summary); summary != "" {
+		fmt.Fprintf(&b, "\n%s\n", summary)
+	}
+	if len(d.TopIssues) > 0 {
+		b.WriteString("\n**主要问题**\n")
+		for _, issue := range d.TopIssues {
+			fmt.Fprintf(&b, "- %s `%s` %s\n", severityEmoji[issue.Severity], issue.File, utils.Truncate(issue.Text, maxIssueLength))
+		}
+	}
+	if withLink && r.ReportLink != "" {
+		if u := r.reportURL(); u != "" {
+			fmt.Fprintf(&b, "\n[📄 查看报告](%s)\n", u)
+		} else {
+			fmt.Fprintf(&b, "\n📄 报告: `%s`\n", r.ReportLink)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Sender 是一个通知渠道
+type Sender interface {
+	// Name 返回渠道名称，用于日志
+	Name() string
+	// Send 发送运行摘要
+	Send(ctx context.Context, run Run) error
+}
+
+// ValidateWebhook 校验 Webhook 地址，应为 http(s) URL，name 为配置项名称
+func ValidateWebhook(name, webhook string) error {
+	u, err := url.Parse(webhook)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%s 应为 https:// 开头的群机器人 Webhook 地址", name)
+	}
+	return nil
+}
+
+// postJSON 将 payload 以 JSON 发送到 webhook，返回响应内容；非 2xx 状态码时返回包含响应内容的错误
+func postJSON(ctx context.Context, webhook string, payload any) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return post(ctx, webhook, body, nil)
+}
+
+// post 将 JSON 请求体与附加的请求头发送到 webhook，返回响应内容
+func post(ctx context.Context, webhook string, body []byte, headers map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// 错误信息中的 URL 包含 Webhook 密钥，只保留原因
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return detail, nil
+}
+
+// checkErrcode 检查钉钉、企业微信（errcode / errmsg）与飞书（code / msg）响应中的错误码
+// 这些平台在签名错误、关键词不匹配等情况下仍返回 200
+func checkErrcode(body []byte) error {
+	var resp struct {
+		Errcode *int   `json:"errcode"`
+		Errmsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil
+	}
+	if resp.Errcode != nil && *resp.Errcode != 0 {
+		return fmt.Errorf("错误码 %d: %s", *resp.Errcode, resp.Errmsg)
+	}
+	if resp.Code != nil && *resp.Code != 0 {
+		return fmt.Errorf("错误码 %d: %s", *resp.Code, resp.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/pkg/utils"
+)
+
+// slackMessage 是 Slack Incoming Webhook 的请求体，Text 是通知与不支持 Block 的客户端中显示的文本
+type slackMessage struct {
+	Channel string       `json:"channel,omitempty"`
+	Text    string       `json:"text"`
+	Blocks  []slackBlock `json:"blocks,omitempty"`
+}
+
+// slackBlock 是 Block Kit 中用到的 header、section 与 context 块
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackText 是 Block Kit 的文本对象
+type slackText struct {
+	Type string `json:"type"` // plain_text 或 mrkdwn
+	Text string `json:"text"`
+}
+
+// mrkdwn 返回 mrkdwn 文本对象
+func mrkdwn(text string) slackText {
+	return slackText{Type: "mrkdwn", Text: text}
+}
+
+// Slack 发送到 Slack Incoming Webhook
+type Slack struct {
+	Webhook string
+	Channel string // 覆盖 Webhook 默认的频道，为空时不覆盖
+}
+
+// Name 返回渠道名称
+func (s Slack) Name() string {
+	return "Slack"
+}
+
+// Send 以 Block Kit 消息发送运行摘要
+func (s Slack) Send(ctx context.Context, run Run) error {
+	_, err := postJSON(ctx, s.Webhook, s.message(run))
+	return err
+}
+
+// message 构建 Block Kit 消息
+func (s Slack) message(run Run) slackMessage {
+	d := run.Digest
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: run.title()}},
+		{Type: "section", Fields: []slackText{
+			mrkdwn(fmt.Sprintf("*综合评分*\n%.1f / 100", d.Score)),
+			mrkdwn("*问题*\n" + run.counts()),
+			mrkdwn(fmt.Sprintf("*文件*\n%d (有效分析 %d，跳过 %d)", d.Files, d.Reviewed, d.Skipped)),
+			mrkdwn("*耗时*\n" + run.Duration.Round(time.Second).String()),
+		}},
+	}
+	if summary := strings.TrimSpace(d.Summary); summary != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: slackEscape(summary)}})
+	}
+	if len(d.TopIssues) > 0 {
+		var b strings.Builder
+		b.WriteString("*主要问题*")
+		for _, issue := range d.TopIssues {
+			fmt.Fprintf(&b, "\n%s `%s` %s", severityEmoji[issue.Severity], slackEscape(issue.File), slackEscape(utils.Truncate(issue.Text, maxIssueLength)))
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: b.String()}})
+	}
+	if run.ReportLink != "" {
+		link := "`" + slackEscape(run.ReportLink) + "`"
+		if u := run.reportURL(); u != "" {
+			link = "<" + u + "|查看报告>"
+		}
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{mrkdwn("📄 报告: " + link)}})
+	}
+
+	return slackMessage{
+		Channel: s.Channel,
+		Text:    fmt.Sprintf("%s，综合评分 %.1f，问题 %s", run.title(), d.Score, run.counts()),
+		Blocks:  blocks,
+	}
+}
+
+// slackEscape 转义 mrkdwn 中的控制字符 &、<、>
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-ai-reviewer/pkg/utils"
+)
+
+// 企业微信模板卡片的长度限制（字符数）
+const (
+	wecomTitleLength    = 26
+	wecomSubTitleLength = 112
+)
+
+// WeCom 发送到企业微信群机器人（Webhook 地址中的 key 即为凭据，不支持签名）
+type WeCom struct {
+	Webhook string
+}
+
+// Name 返回渠道名称
+func (w WeCom) Name() string {
+	return "企业微信"
+}
+
+// Send 发送运行摘要：报告链接为 URL 时使用文本通知模板卡片（点击卡片打开报告），否则使用 Markdown 消息
+func (w WeCom) Send(ctx context.Context, run Run) error {
+	var payload map[string]any
+	if u := run.reportURL(); u != "" {
+		payload = map[string]any{"msgtype": "template_card", "template_card": wecomCard(run, u)}
+	} else {
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"content": "### " + run.title() + "\n" + run.markdown(true)},
+		}
+	}
+
+	body, err := postJSON(ctx, w.Webhook, payload)
+	if err != nil {
+		return err
+	}
+	return checkErrcode(body)
+}
+
+// wecomCard 构建文本通知模板卡片：突出显示综合评分，横向列出各严重程度的问题数，
+// 副标题为执行摘要或最主要的问题
+func wecomCard(run Run, reportURL string) map[string]any {
+	d := run.Digest
+	subtitle := strings.TrimSpace(d.Summary)
+	if subtitle == "" && len(d.TopIssues) > 0 {
+		top := d.TopIssues[0]
+		subtitle = fmt.Sprintf("%s %s: %s", severityEmoji[top.Severity], top.File, top.Text)
+	}
+
+	card := map[string]any{
+		"card_type":  "text_notice",
+		"main_title": map[string]any{"title": utils.Truncate(run.title(), wecomTitleLength)},
+		"emphasis_content": map[string]any{
+			"title": fmt.Sprintf("%.1f", d.Score),
+			"desc":  "综合评分",
+		},
+		"horizontal_content_list": []map[string]any{
+			{"keyname": "🔴 严重", "value": strconv.Itoa(d.Critical)},
+			{"keyname": "🟠 重要", "value": strconv.Itoa(d.Major)},
+			{"keyname": "🟡 一般", "value": strconv.Itoa(d.Minor)},
+			{"keyname": "文件", "value": fmt.Sprintf("%d (跳过 %d)", d.Files, d.Skipped)},
+		},
+		"jump_list":   []map[string]any{{"type": 1, "title": "查看报告", "url": reportURL}},
+		"card_action": map[string]any{"type": 1, "url": reportURL},
+	}
+	if subtitle != "" {
+		card["sub_title_text"] = utils.Truncate(subtitle, wecomSubTitleLength)
+	}
+	return card
+}
@@ -0,0 +1,266 @@
+// Package patch 解析与应用模型生成的 unified diff 修复补丁
+package patch
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Hunk 是补丁中的一个修改块
+type Hunk struct {
+	OldStart int      // 修改块在原文件中的起始行（从 1 开始），模型给出的行号可能有偏差，仅用于定位
+	Lines    []string // 带 ' '（上下文）、'-'（删除）、'+'（新增）前缀的行
+}
+
+// FilePatch 是单个文件的补丁
+type FilePatch struct {
+	Path  string
+	Hunks []Hunk
+}
+
+// hunkHeaderRegex 匹配修改块头部 "@@ -12,5 +12,6 @@"，行数可省略
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+\d+(?:,\d+)? @@`)
+
+// ParseHunks 解析单个文件 diff 中的修改块
+// 忽略 ---/+++ 文件头与头部声明的行数（模型给出的行数常与内容不符），只按各行前缀判断
+func ParseHunks(diff string) []Hunk {
+	var hunks []Hunk
+	var cur *Hunk
+	flush := func() {
+		if cur != nil && cur.changed() {
+			hunks = append(hunks, *cur)
+		}
+		cur = nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			cur = &Hunk{}
+			if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+				cur.OldStart, _ = strconv.Atoi(m[1])
+			}
+		case cur == nil:
+			// 修改块之外的 diff --git、index、---/+++ 等行
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			// 下一个文件的文件头
+			flush()
+		case line == "":
+			// 模型常省略空白上下文行的前缀空格
+			cur.Lines = append(cur.Lines, " ")
+		case line[0] == ' ' || line[0] == '-' || line[0] == '+':
+			cur.Lines = append(cur.Lines, line)
+		case line[0] == '\\':
+			// "\ No newline at end of file"
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	// 去掉末尾由结尾换行产生的空上下文行
+	for i := range hunks {
+		h := &hunks[i]
+		for len(h.Lines) > 0 && h.Lines[len(h.Lines)-1] == " " {
+			h.Lines = h.Lines[:len(h.Lines)-1]
+		}
+	}
+	return hunks
+}
+
+// Parse 解析包含多个文件的补丁（Format 输出的格式），文件路径取自 +++ 行
+func Parse(text string) ([]FilePatch, error) {
+	var patches []FilePatch
+	var path string
+	var body strings.Builder
+	flush := func() {
+		if path != "" {
+			if hunks := ParseHunks(body.String()); len(hunks) > 0 {
+				patches = append(patches, FilePatch{Path: path, Hunks: hunks})
+			}
+		}
+		body.Reset()
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			flush()
+			path = headerPath(lines[i+1])
+			continue
+		}
+		body.WriteString(line + "\n")
+	}
+	flush()
+
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("补丁中没有可识别的文件修改")
+	}
+	return patches, nil
+}
+
+// headerPath 从 "+++ b/path" 文件头中取出路径
+func headerPath(line string) string {
+	p := strings.TrimPrefix(line, "+++ ")
+	p, _, _ = strings.Cut(p, "\t")
+	return strings.TrimPrefix(p, "b/")
+}
+
+// Format 以标准 unified diff 格式输出单个文件的补丁，修改块头部的行数按实际内容重新计算
+func Format(filePath string, hunks []Hunk) string {
+	name := strings.ReplaceAll(filePath, "\\", "/")
+	oldName, newName := "a/"+name, "b/"+name
+	if path.IsAbs(name) {
+		oldName, newName = name, name
+	}
+
+	return fmt.Sprintf("--- %s\n+++ %s\n", oldName, newName) + FormatHunks(hunks)
+}
+
+// FormatHunks 输出不含文件头的修改块
+func FormatHunks(hunks []Hunk) string {
+	var b strings.Builder
+	shift := 0
+	for _, h := range hunks {
+		oldLines, newLines := h.counts()
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.OldStart, oldLines, h.OldStart+shift, newLines)
+		for _, line := range h.Lines {
+			b.WriteString(line + "\n")
+		}
+		shift += newLines - oldLines
+	}
+	return b.String()
+}
+
+// Fragment 返回补丁修改后的代码片段：各修改块的上下文与新增行，修改块之间以空行分隔
+// 用于只有 diff、没有原文件时审查修改后的代码
+func (p FilePatch) Fragment() string {
+	parts := make([]string, 0, len(p.Hunks))
+	for _, h := range p.Hunks {
+		_, newLines := h.split()
+		parts = append(parts, strings.Join(newLines, "\n"))
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// Stats 返回补丁新增与删除的行数
+func (p FilePatch) Stats() (added, removed int) {
+	for _, h := range p.Hunks {
+		for _, line := range h.Lines {
+			switch line[0] {
+			case '+':
+				added++
+			case '-':
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+// Apply 将修改块依次应用到 content 并返回修改后的内容
+// 按上下文与删除行在声明行号附近查找匹配位置（忽略行尾空白），找不到时返回错误；
+// 成功时各修改块的 OldStart 会被修正为实际匹配的位置，便于 Format 输出准确的补丁
+func Apply(content string, hunks []Hunk) (string, error) {
+	lines := strings.Split(content, "\n")
+	crlf := strings.Contains(content, "\r\n")
+
+	cursor, shift := 0, 0 // cursor 之前的内容已被修改，不再参与匹配
+	for i := range hunks {
+		h := &hunks[i]
+		oldLines, _ := h.split()
+		expected := max(h.OldStart-1, 0) + shift
+		pos := locate(lines, oldLines, expected, cursor)
+		if pos < 0 {
+			return "", fmt.Errorf("第 %d 个修改块与文件内容不一致（文件可能已被修改）", i+1)
+		}
+
+		// 上下文行保留文件中的原文（含行尾空白与换行符风格），新增行按文件的换行符风格补齐
+		var newLines []string
+		at := pos
+		for _, line := range h.Lines {
+			switch line[0] {
+			case ' ':
+				newLines = append(newLines, lines[at])
+				at++
+			case '-':
+				at++
+			case '+':
+				if crlf {
+					line += "\r"
+				}
+				newLines = append(newLines, line[1:])
+			}
+		}
+
+		lines = append(lines[:pos], append(newLines, lines[pos+len(oldLines):]...)...)
+		h.OldStart = pos - shift + 1
+		shift += len(newLines) - len(oldLines)
+		cursor = pos + len(newLines)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// locate 返回 old 在 lines 中距 expected 最近的匹配位置（不早于 from），找不到时返回 -1
+func locate(lines, old []string, expected, from int) int {
+	last := len(lines) - len(old)
+	if len(old) == 0 {
+		return min(max(expected, from), len(lines))
+	}
+	for d := 0; expected-d >= from || expected+d <= last; d++ {
+		for _, pos := range []int{expected - d, expected + d} {
+			if pos >= from && pos <= last && matches(lines[pos:pos+len(old)], old) {
+				return pos
+			}
+		}
+	}
+	return -1
+}
+
+// matches 判断两组行在忽略行尾空白后是否相同
+func matches(a, b []string) bool {
+	for i := range b {
+		if strings.TrimRight(a[i], " \t\r") != strings.TrimRight(b[i], " \t\r") {
+			return false
+		}
+	}
+	return true
+}
+
+// split 返回修改块应用前与应用后的内容（不含前缀）
+func (h Hunk) split() (oldLines, newLines []string) {
+	for _, line := range h.Lines {
+		text := line[1:]
+		switch line[0] {
+		case ' ':
+			oldLines = append(oldLines, text)
+			newLines = append(newLines, text)
+		case '-':
+			oldLines = append(oldLines, text)
+		case '+':
+			newLines = append(newLines, text)
+		}
+	}
+	return oldLines, newLines
+}
+
+// counts 返回修改块应用前与应用后的行数
+func (h Hunk) counts() (oldLines, newLines int) {
+	o, n := h.split()
+	return len(o), len(n)
+}
+
+// changed 判断修改块是否包含实际的增删
+func (h Hunk) changed() bool {
+	for _, line := range h.Lines {
+		if line[0] == '-' || line[0] == '+' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,270 @@
+package projectctx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ecosystem 描述一类依赖清单：清单文件名、解析方法以及适用的源码扩展名
+type ecosystem struct {
+	manifests []string
+	parse     func(data []byte) []string // 返回清单中的依赖名称
+	exts      []string
+}
+
+// 依赖清单所属的生态
+var (
+	goEcosystem     = &ecosystem{manifests: []string{"go.mod"}, parse: goModDeps, exts: []string{".go"}}
+	nodeEcosystem   = &ecosystem{manifests: []string{"package.json"}, parse: packageJSONDeps, exts: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts", ".vue", ".svelte"}}
+	pythonEcosystem = &ecosystem{manifests: []string{"requirements.txt", "pyproject.toml"}, parse: pythonDeps, exts: []string{".py"}}
+	jvmEcosystem    = &ecosystem{manifests: []string{"pom.xml", "build.gradle", "build.gradle.kts"}, parse: jvmDeps, exts: []string{".java", ".kt", ".groovy", ".scala"}}
+
+	ecosystems = []*ecosystem{goEcosystem, nodeEcosystem, pythonEcosystem, jvmEcosystem}
+)
+
+// framework 描述一个可从依赖清单识别的框架
+// deps 中以 "*" 结尾的名称按前缀匹配（Go 模块的主版本后缀、Maven 的 starter 等）
+type framework struct {
+	name        string
+	eco         *ecosystem
+	deps        []string
+	conventions string // 注入上下文的框架约定，符合约定的写法不应被报告为问题
+}
+
+// frameworks 是内置的框架识别规则，按在上下文中的显示顺序排列
+var frameworks = []framework{
+	{"Gin", goEcosystem, []string{"github.com/gin-gonic/gin*"}, "handler 签名为 func(*gin.Context)，通过 c.JSON / c.Abort* 写响应，中间件调用 c.Next() 继续处理链；绑定参数使用 ShouldBind* 系列"},
+	{"Echo", goEcosystem, []string{"github.com/labstack/echo*"}, "handler 返回 error，由框架的 HTTPErrorHandler 统一转换为响应；echo.Context 只在请求期间有效"},
+	{"Fiber", goEcosystem, []string{"github.com/gofiber/fiber*"}, "handler 签名为 func(*fiber.Ctx) error；Ctx 返回的字符串在请求结束后会被复用，需要保留时应拷贝"},
+	{"Chi", goEcosystem, []string{"github.com/go-chi/chi*"}, "基于标准库 net/http，路由参数通过 chi.URLParam 读取，中间件为 func(http.Handler) http.Handler"},
+	{"GORM", goEcosystem, []string{"gorm.io/gorm"}, "链式调用的错误保存在返回值的 Error 字段，记录不存在时为 gorm.ErrRecordNotFound；模型嵌入 gorm.Model 是标准写法"},
+	{"gRPC", goEcosystem, []string{"google.golang.org/grpc"}, "服务实现嵌入 Unimplemented*Server 是生成代码要求的写法，错误通过 status.Error 返回状态码"},
+	{"Cobra", goEcosystem, []string{"github.com/spf13/cobra"}, "在 init() 中注册子命令与标志是标准做法，命令定义为包级变量"},
+	{"Bubble Tea", goEcosystem, []string{"github.com/charmbracelet/bubbletea"}, "Elm 架构：Update 接收并返回模型（值接收者是正确的），副作用通过返回 tea.Cmd 执行"},
+
+	{"Next.js", nodeEcosystem, []string{"next"}, "app/ 或 pages/ 下的文件名是路由约定（page、layout、route 等需要默认导出），服务端组件可以是 async 函数，使用浏览器 API 的组件需要 'use client' 指令"},
+	{"Nuxt", nodeEcosystem, []string{"nuxt"}, "ref、computed、useFetch、组件等由框架自动导入，未显式 import 不是错误；pages/ 下的文件名是路由约定"},
+	{"React", nodeEcosystem, []string{"react"}, "组件为返回 JSX 的函数，状态与副作用通过 Hooks 管理"},
+	{"Vue", nodeEcosystem, []string{"vue"}, "<script setup> 中声明的变量与导入自动暴露给模板，defineProps / defineEmits 是编译器宏，无需导入"},
+	{"Svelte", nodeEcosystem, []string{"svelte"}, "组件中对变量赋值即触发更新，$: 是响应式语句，以 $ 开头的变量是 store 的自动订阅"},
+	{"Angular", nodeEcosystem, []string{"@angular/core"}, "依赖通过构造函数或 inject() 注入，装饰器声明组件与服务；订阅 Observable 时需要在销毁时取消"},
+	{"NestJS", nodeEcosystem, []string{"@nestjs/core"}, "控制器与服务通过装饰器声明并由容器注入依赖，构造函数参数属性（private readonly）是标准写法"},
+	{"Express", nodeEcosystem, []string{"express"}, "中间件签名为 (req, res, next)，错误处理中间件必须声明 4 个参数；异步 handler 的异常需要传给 next"},
+
+	{"Django", pythonEcosystem, []string{"django"}, "模型字段、Meta 内部类、settings 中的大写常量与 migrations 目录下的自动生成代码都是框架约定；QuerySet 是惰性求值的"},
+	{"Flask", pythonEcosystem, []string{"flask"}, "路由通过装饰器注册，request、g、current_app 是请求上下文内的代理对象，全局使用是标准做法"},
+	{"FastAPI", pythonEcosystem, []string{"fastapi"}, "Depends()、Query()、Body() 等作为参数默认值是依赖注入的标准写法（不是可变默认参数问题），参数类型注解决定请求校验"},
+	{"pytest", pythonEcosystem, []string{"pytest"}, "fixture 按参数名注入测试函数，conftest.py 中的 fixture 无需导入"},
+
+	{"Spring Boot", jvmEcosystem, []string{"spring-boot*", "org.springframework.boot"}, "Bean 由容器创建并注入，@Autowired / 构造函数注入的字段不会为空；同一个类内部调用 @Transactional 方法不会开启事务"},
+	{"Lombok", jvmEcosystem, []string{"lombok"}, "@Data、@Getter、@Builder、@RequiredArgsConstructor 等注解在编译期生成方法，未手写 getter / 构造函数不是错误"},
+	{"MyBatis", jvmEcosystem, []string{"mybatis*"}, "Mapper 接口由框架生成实现，SQL 在 XML 或注解中；#{} 是参数绑定，${} 是字符串拼接（存在注入风险）"},
+}
+
+// detectedFramework 是在某个目录的依赖清单中识别到的框架，只适用于该目录下的源码
+type detectedFramework struct {
+	dir       string
+	framework *framework
+}
+
+// detectFrameworks 查找根目录及各文件所在目录（直到根目录）中的依赖清单，识别使用的框架
+func detectFrameworks(root string, files []string) []detectedFramework {
+	root = filepath.Clean(root)
+	dirs := []string{root}
+	seen := map[string]bool{root: true}
+	for _, file := range files {
+		for dir := filepath.Dir(filepath.Clean(file)); !seen[dir] && isWithin(root, dir); dir = filepath.Dir(dir) {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	var detected []detectedFramework
+	for _, dir := range dirs {
+		for _, eco := range ecosystems {
+			var deps []string
+			for _, name := range eco.manifests {
+				if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+					deps = append(deps, eco.parse(data)...)
+				}
+			}
+			for i := range frameworks {
+				if fw := &frameworks[i]; fw.eco == eco && fw.matches(deps) {
+					detected = append(detected, detectedFramework{dir: dir, framework: fw})
+				}
+			}
+		}
+	}
+	return detected
+}
+
+// matches 判断依赖列表中是否包含框架的依赖
+func (fw *framework) matches(deps []string) bool {
+	for _, want := range fw.deps {
+		prefix, isPrefix := strings.CutSuffix(want, "*")
+		for _, dep := range deps {
+			if dep == want || (isPrefix && strings.HasPrefix(dep, prefix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isWithin 判断 path 是否为 root 或其子目录
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// frameworksFor 返回适用于指定文件的框架：清单位于文件所在目录或其上级，且与文件属于同一生态
+func (c *Context) frameworksFor(path string) []*framework {
+	ext := strings.ToLower(filepath.Ext(path))
+	var result []*framework
+	for i := range frameworks {
+		fw := &frameworks[i]
+		if !slices.Contains(fw.eco.exts, ext) {
+			continue
+		}
+		for _, d := range c.frameworks {
+			if d.framework == fw && isWithin(d.dir, filepath.Dir(path)) {
+				result = append(result, fw)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// frameworkSection 返回文件适用的框架说明，没有识别到框架时返回空字符串
+func (c *Context) frameworkSection(path string) string {
+	fws := c.frameworksFor(path)
+	if len(fws) == 0 {
+		return ""
+	}
+	names := make([]string, len(fws))
+	var b strings.Builder
+	for i, fw := range fws {
+		names[i] = fw.name
+		fmt.Fprintf(&b, "- **%s**: %s\n", fw.name, fw.conventions)
+	}
+	return fmt.Sprintf("这是一个使用 %s 的项目，符合以下框架约定的写法不是问题：\n%s", strings.Join(names, "、"), b.String())
+}
+
+// Frameworks 返回识别到的所有框架名称，按显示顺序排列
+func (c *Context) Frameworks() []string {
+	if c == nil {
+		return nil
+	}
+	var names []string
+	for i := range frameworks {
+		for _, d := range c.frameworks {
+			if d.framework == &frameworks[i] {
+				names = append(names, frameworks[i].name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// goModDeps 解析 go.mod 中 require 的模块路径
+func goModDeps(data []byte) []string {
+	var deps []string
+	inRequire := false
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inRequire:
+			if fields[0] == ")" {
+				inRequire = false
+			} else {
+				deps = append(deps, fields[0])
+			}
+		case fields[0] == "require" && len(fields) > 1:
+			if fields[1] == "(" {
+				inRequire = true
+			} else {
+				deps = append(deps, fields[1])
+			}
+		}
+	}
+	return deps
+}
+
+// packageJSONDeps 解析 package.json 中的依赖名称（含开发依赖与对等依赖）
+func packageJSONDeps(data []byte) []string {
+	var pkg struct {
+		Dependencies     map[string]string `json:"dependencies"`
+		DevDependencies  map[string]string `json:"devDependencies"`
+		PeerDependencies map[string]string `json:"peerDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	var deps []string
+	for _, m := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies} {
+		for name := range m {
+			deps = append(deps, name)
+		}
+	}
+	return deps
+}
+
+// 匹配 Python 依赖声明：requirements.txt 的行首或 pyproject.toml 中带引号的依赖字符串，以及 Poetry 的 "名称 = 版本" 键
+var (
+	pythonRequirementRegex = regexp.MustCompile(`^\s*["']?([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	pythonQuotedDepRegex   = regexp.MustCompile(`["']([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(?:[=<>~!;]|["'])`)
+)
+
+// pythonDeps 解析 requirements.txt 与 pyproject.toml 中的依赖名称（统一为小写、以 "-" 连接）
+func pythonDeps(data []byte) []string {
+	var deps []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if strings.HasPrefix(strings.TrimSpace(line), "-") {
+			continue // -r、-e 等 pip 选项
+		}
+		if m := pythonRequirementRegex.FindStringSubmatch(line); m != nil {
+			deps = append(deps, m[1])
+		}
+		for _, m := range pythonQuotedDepRegex.FindAllStringSubmatch(line, -1) {
+			deps = append(deps, m[1])
+		}
+	}
+	for i, dep := range deps {
+		deps[i] = strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+	}
+	return deps
+}
+
+// 匹配 Maven 的 artifactId / groupId、Gradle 的 "group:artifact:version" 依赖与插件 id
+var (
+	mavenIDRegex      = regexp.MustCompile(`<(?:artifactId|groupId)>\s*([\w.-]+)\s*</`)
+	gradleDepRegex    = regexp.MustCompile(`["']([\w.-]+):([\w.-]+)(?::[^"']*)?["']`)
+	gradlePluginRegex = regexp.MustCompile(`id\s*\(?\s*["']([\w.-]+)["']`)
+)
+
+// jvmDeps 解析 pom.xml 与 build.gradle 中的依赖（同时返回 groupId 与 artifactId）
+func jvmDeps(data []byte) []string {
+	var deps []string
+	for _, m := range mavenIDRegex.FindAllSubmatch(data, -1) {
+		deps = append(deps, string(m[1]))
+	}
+	for _, m := range gradleDepRegex.FindAllSubmatch(data, -1) {
+		deps = append(deps, string(m[1]), string(m[2]))
+	}
+	for _, m := range gradlePluginRegex.FindAllSubmatch(data, -1) {
+		deps = append(deps, string(m[1]))
+	}
+	return deps
+}
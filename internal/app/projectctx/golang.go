@@ -0,0 +1,215 @@
+package projectctx
+
+import (
+	"bufio"
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxTypeSignatureSize 是类型声明签名的最大长度，超过时省略字段
+const maxTypeSignatureSize = 300
+
+// goSignatures 提取 Go 文件引用的同包兄弟文件符号及模块内其他包符号的签名
+func (c *Context) goSignatures(path string) []string {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	// 1. 当前文件自身声明的符号（不需要上下文）
+	own := make(map[string]struct{})
+	for name := range declSignatures(fset, file) {
+		own[name] = struct{}{}
+	}
+
+	// 2. 模块内的包导入：别名 -> 包目录
+	localPkgs := make(map[string]string)
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || c.modPath == "" || !strings.HasPrefix(importPath, c.modPath+"/") {
+			continue
+		}
+		dir := filepath.Join(c.root, filepath.FromSlash(strings.TrimPrefix(importPath, c.modPath+"/")))
+		alias := filepath.Base(importPath)
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		localPkgs[alias] = dir
+	}
+
+	// 3. 收集引用的标识符
+	siblings := c.goDirIndex(filepath.Dir(path), path)
+	seen := make(map[string]struct{})
+	var sigs []string
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			pkg, ok := node.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			dir, ok := localPkgs[pkg.Name]
+			if !ok {
+				return true
+			}
+			key := pkg.Name + "." + node.Sel.Name
+			if _, dup := seen[key]; dup {
+				return false
+			}
+			seen[key] = struct{}{}
+			if sig, ok := c.goDirIndex(dir, "")[node.Sel.Name]; ok {
+				sigs = append(sigs, "// "+pkg.Name+"\n"+sig)
+			}
+			return false
+		case *ast.Ident:
+			if _, ok := own[node.Name]; ok {
+				return true
+			}
+			if _, dup := seen[node.Name]; dup {
+				return true
+			}
+			if sig, ok := siblings[node.Name]; ok {
+				seen[node.Name] = struct{}{}
+				sigs = append(sigs, sig)
+			}
+		}
+		return true
+	})
+
+	return sigs
+}
+
+// goDirIndex 返回目录下所有 Go 文件（排除 exclude）的顶层符号签名，结果按目录缓存
+func (c *Context) goDirIndex(dir, exclude string) map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cacheKey := dir + "|" + exclude
+	if idx, ok := c.goIndex[cacheKey]; ok {
+		return idx
+	}
+
+	idx := make(map[string]string)
+	entries, _ := os.ReadDir(dir)
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		full := filepath.Join(dir, name)
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || full == exclude {
+			continue
+		}
+		file, err := parser.ParseFile(fset, full, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for sym, sig := range declSignatures(fset, file) {
+			idx[sym] = sig
+		}
+	}
+
+	c.goIndex[cacheKey] = idx
+	return idx
+}
+
+// declSignatures 提取文件中顶层声明的签名（函数不含函数体）
+func declSignatures(fset *token.FileSet, file *ast.File) map[string]string {
+	sigs := make(map[string]string)
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			fn := *d
+			fn.Body = nil
+			fn.Doc = nil
+			name := d.Name.Name
+			// 方法以 "接收者类型.方法名" 为键，避免覆盖同名函数
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = receiverName(d.Recv.List[0].Type) + "." + name
+			}
+			sigs[name] = render(fset, &fn)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					sig := "type " + render(fset, sp)
+					if len(sig) > maxTypeSignatureSize {
+						sig = "type " + sp.Name.Name + " ... (省略)"
+					}
+					sigs[sp.Name.Name] = sig
+				case *ast.ValueSpec:
+					prefix := "var "
+					if d.Tok == token.CONST {
+						prefix = "const "
+					}
+					for _, n := range sp.Names {
+						sig := prefix + n.Name
+						if sp.Type != nil {
+							sig += " " + render(fset, sp.Type)
+						}
+						sigs[n.Name] = sig
+					}
+				}
+			}
+		}
+	}
+
+	// 方法同时挂到类型名上，便于按类型查找
+	for key, sig := range sigs {
+		if typ, _, ok := strings.Cut(key, "."); ok {
+			if typeSig, exists := sigs[typ]; exists {
+				sigs[typ] = typeSig + "\n" + sig
+			}
+		}
+	}
+
+	return sigs
+}
+
+// receiverName 返回方法接收者的类型名
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// render 将 AST 节点渲染为源码文本
+func render(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// readModulePath 读取 go.mod 中的模块路径，不存在时返回空字符串
+func readModulePath(root string) string {
+	f, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if mod, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(mod), `"`)
+		}
+	}
+	return ""
+}
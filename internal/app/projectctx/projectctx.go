@@ -0,0 +1,160 @@
+// Package projectctx 提供项目级上下文构建功能，缓解单文件审查的“跨文件盲区”
+package projectctx
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 上下文大小限制（字节），避免挤占代码本身的 Token 预算
+const (
+	MaxTreeLines      = 60
+	MaxReadmeSize     = 800
+	MaxSignaturesSize = 2000
+)
+
+// readmeNames 是按优先级查找的 README 文件名
+var readmeNames = []string{"README.md", "README", "README.txt", "readme.md", "README.rst"}
+
+// Context 表示一个审查任务的项目上下文
+type Context struct {
+	root       string
+	tree       string
+	readme     string
+	frameworks []detectedFramework // 依赖清单中识别到的框架
+
+	mu      sync.Mutex
+	goIndex map[string]map[string]string // 目录 -> 符号名 -> 签名
+	modPath string                       // go.mod 中的模块路径
+}
+
+// Build 根据扫描得到的文件列表构建项目上下文
+func Build(root string, files []string) *Context {
+	return &Context{
+		root:       root,
+		tree:       buildTree(root, files),
+		readme:     readReadme(root),
+		frameworks: detectFrameworks(root, files),
+		goIndex:    make(map[string]map[string]string),
+		modPath:    readModulePath(root),
+	}
+}
+
+// ForFile 返回指定文件的上下文文本：目录结构、README 摘要、适用的框架约定以及引用到的兄弟文件符号签名
+func (c *Context) ForFile(path string) string {
+	if c == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	if c.tree != "" {
+		fmt.Fprintf(&b, "### 目录结构\n%s\n", c.tree)
+	}
+	if c.readme != "" {
+		fmt.Fprintf(&b, "### README 摘要\n%s\n\n", c.readme)
+	}
+	if fws := c.frameworkSection(path); fws != "" {
+		fmt.Fprintf(&b, "### 项目框架\n%s\n", fws)
+	}
+	if sigs := c.signatures(path); sigs != "" {
+		fmt.Fprintf(&b, "### 当前文件引用的其他文件中的符号\n%s\n", sigs)
+	}
+	return b.String()
+}
+
+// signatures 根据文件语言提取其引用的兄弟文件符号签名
+func (c *Context) signatures(path string) string {
+	var sigs []string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		sigs = c.goSignatures(path)
+	case ".js", ".jsx", ".ts", ".tsx", ".vue", ".mjs":
+		sigs = jsSignatures(path)
+	case ".py":
+		sigs = pySignatures(c.root, path)
+	}
+	return truncateLines(sigs, MaxSignaturesSize)
+}
+
+// buildTree 将文件列表渲染为紧凑的目录树（仅目录及其文件数）
+func buildTree(root string, files []string) string {
+	counts := make(map[string]int)
+	for _, file := range files {
+		rel, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			continue
+		}
+		counts[filepath.ToSlash(rel)]++
+	}
+
+	dirs := make([]string, 0, len(counts))
+	for dir := range counts {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var lines []string
+	for _, dir := range dirs {
+		depth := 0
+		if dir != "." {
+			depth = strings.Count(dir, "/") + 1
+		}
+		lines = append(lines, fmt.Sprintf("%s%s/ (%d 个文件)", strings.Repeat("  ", depth), dir, counts[dir]))
+	}
+
+	if len(lines) > MaxTreeLines {
+		omitted := len(lines) - MaxTreeLines
+		lines = append(lines[:MaxTreeLines], fmt.Sprintf("... (省略 %d 个目录)", omitted))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// readReadme 读取项目 README 的开头部分作为摘要
+func readReadme(root string) string {
+	for _, name := range readmeNames {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		text := strings.TrimSpace(string(data))
+		if len(text) > MaxReadmeSize {
+			text = truncateUTF8(text, MaxReadmeSize) + "..."
+		}
+		return text
+	}
+	return ""
+}
+
+// truncateLines 拼接签名列表，总长度超过 limit 时截断
+func truncateLines(lines []string, limit int) string {
+	var b strings.Builder
+	for _, line := range lines {
+		if b.Len()+len(line)+1 > limit {
+			b.WriteString("...\n")
+			break
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// truncateUTF8 按字节截断字符串，保证不截断多字节字符
+func truncateUTF8(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !isRuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
+
+// isRuneStart 判断字节是否为 UTF-8 字符的起始字节
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,140 @@
+package projectctx
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// JS/TS 与 Python 的导入及声明匹配规则
+var (
+	jsImportRegex = regexp.MustCompile(`(?m)import\s+(?:type\s+)?(?:(\w+)\s*,?\s*)?(?:\{([^}]*)\})?\s*from\s+['"](\.[^'"]+)['"]`)
+	pyImportRegex = regexp.MustCompile(`(?m)^\s*from\s+(\.+[\w.]*|[\w.]+)\s+import\s+\(?([\w\s,]+)\)?`)
+)
+
+// jsExtensions 是解析相对导入时依次尝试的扩展名
+var jsExtensions = []string{"", ".ts", ".tsx", ".js", ".jsx", ".mjs", ".vue", "/index.ts", "/index.tsx", "/index.js"}
+
+// jsSignatures 提取 JS/TS 文件通过相对路径导入的符号在源文件中的声明行
+func jsSignatures(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var sigs []string
+	for _, m := range jsImportRegex.FindAllStringSubmatch(string(data), -1) {
+		names := make(map[string]struct{})
+		if m[1] != "" {
+			names["default"] = struct{}{}
+		}
+		for _, part := range strings.Split(m[2], ",") {
+			name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "type "))
+			// 处理 "a as b" 形式
+			if orig, _, ok := strings.Cut(name, " as "); ok {
+				name = strings.TrimSpace(orig)
+			}
+			if name != "" {
+				names[name] = struct{}{}
+			}
+		}
+
+		target := resolveJSImport(filepath.Dir(path), m[3])
+		if target == "" {
+			continue
+		}
+		sigs = append(sigs, declarationLines(target, names, jsDeclRegex)...)
+	}
+	return sigs
+}
+
+// resolveJSImport 将相对导入解析为实际文件路径
+func resolveJSImport(dir, spec string) string {
+	base := filepath.Join(dir, filepath.FromSlash(spec))
+	for _, ext := range jsExtensions {
+		candidate := base + filepath.FromSlash(ext)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// pySignatures 提取 Python 文件从项目内模块导入的符号在源文件中的声明行
+func pySignatures(root, path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var sigs []string
+	for _, m := range pyImportRegex.FindAllStringSubmatch(string(data), -1) {
+		target := resolvePyModule(root, filepath.Dir(path), m[1])
+		if target == "" {
+			continue
+		}
+		names := make(map[string]struct{})
+		for _, part := range strings.Split(m[2], ",") {
+			name := strings.Fields(part)
+			if len(name) > 0 {
+				names[name[0]] = struct{}{}
+			}
+		}
+		sigs = append(sigs, declarationLines(target, names, pyDeclRegex)...)
+	}
+	return sigs
+}
+
+// resolvePyModule 将模块路径解析为项目内的 .py 文件（支持相对导入）
+func resolvePyModule(root, dir, module string) string {
+	base := root
+	if strings.HasPrefix(module, ".") {
+		trimmed := strings.TrimLeft(module, ".")
+		base = dir
+		for i := 1; i < len(module)-len(trimmed); i++ {
+			base = filepath.Dir(base)
+		}
+		module = trimmed
+	}
+
+	rel := filepath.FromSlash(strings.ReplaceAll(module, ".", "/"))
+	for _, candidate := range []string{filepath.Join(base, rel) + ".py", filepath.Join(base, rel, "__init__.py")} {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// 声明行匹配规则：第一个捕获组为符号名
+var (
+	jsDeclRegex = regexp.MustCompile(`^\s*export\s+(?:default\s+)?(?:async\s+)?(?:function\*?|class|const|let|var|interface|type|enum)\s+(\w+)`)
+	pyDeclRegex = regexp.MustCompile(`^(?:async\s+)?(?:def|class)\s+(\w+)|^(\w+)\s*[:=]`)
+)
+
+// declarationLines 在目标文件中查找指定符号的声明行
+func declarationLines(target string, names map[string]struct{}, declRegex *regexp.Regexp) []string {
+	data, err := os.ReadFile(target)
+	if err != nil {
+		return nil
+	}
+
+	var sigs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		m := declRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[1]
+		if name == "" && len(m) > 2 {
+			name = m[2]
+		}
+		_, wanted := names[name]
+		_, wantDefault := names["default"]
+		if wanted || (wantDefault && strings.Contains(line, "export default")) {
+			sigs = append(sigs, "// "+filepath.Base(target)+"\n"+strings.TrimSpace(strings.TrimSuffix(line, "{")))
+		}
+	}
+	return sigs
+}
@@ -0,0 +1,135 @@
+package reviewer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// WithAudit 以安全审计模式审查：模型为每个漏洞给出 CWE 编号、可利用性与修复方法
+// 报告改用安全审计的布局，并在报告旁生成 SARIF 文件
+func WithAudit() Option {
+	return func(e *Engine) {
+		e.audit = true
+	}
+}
+
+// filterFindings 返回严重程度不低于 minimum 的漏洞
+func filterFindings(findings []llm.Finding, minimum llm.Severity) []llm.Finding {
+	var kept []llm.Finding
+	for _, f := range findings {
+		if f.Level() >= minimum {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// cweSummary 是报告中按 CWE 汇总的一行
+type cweSummary struct {
+	CWE            string
+	Title          string
+	Count          int
+	Files          int
+	Severity       llm.Severity // 最高严重程度
+	Exploitability string       // 最高可利用性
+}
+
+// exploitabilityRank 用于比较可利用性的高低
+var exploitabilityRank = map[string]int{
+	llm.ExploitabilityLow:    1,
+	llm.ExploitabilityMedium: 2,
+	llm.ExploitabilityHigh:   3,
+}
+
+// summarizeFindings 按 CWE 汇总各文件的漏洞，按最高严重程度与数量降序排列
+func summarizeFindings(results []Result, minSeverity llm.Severity) []cweSummary {
+	byCWE := make(map[string]*cweSummary)
+	for _, res := range results {
+		if res.Review == nil || res.Error != nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, f := range filterFindings(res.Review.Findings, minSeverity) {
+			s := byCWE[f.CWE]
+			if s == nil {
+				s = &cweSummary{CWE: f.CWE, Title: llm.CWEName(f.CWE)}
+				if s.Title == "" {
+					s.Title = f.Title
+				}
+				byCWE[f.CWE] = s
+			}
+			s.Count++
+			if !seen[f.CWE] {
+				seen[f.CWE] = true
+				s.Files++
+			}
+			s.Severity = max(s.Severity, f.Level())
+			if exploitabilityRank[f.Exploitability] > exploitabilityRank[s.Exploitability] {
+				s.Exploitability = f.Exploitability
+			}
+		}
+	}
+
+	summary := make([]cweSummary, 0, len(byCWE))
+	for _, s := range byCWE {
+		summary = append(summary, *s)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Severity != summary[j].Severity {
+			return summary[i].Severity > summary[j].Severity
+		}
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		return summary[i].CWE < summary[j].CWE
+	})
+	return summary
+}
+
+// writeAuditSummary 写入按漏洞类别（CWE）汇总的统计表
+func writeAuditSummary(f io.Writer, summary []cweSummary) {
+	fmt.Fprintf(f, "## 🛡️ 漏洞类别\n\n")
+	if len(summary) == 0 {
+		fmt.Fprintf(f, "> 未发现安全漏洞。\n\n---\n\n")
+		return
+	}
+	fmt.Fprintf(f, "| 类别 | 名称 | 漏洞数 | 涉及文件 | 最高严重程度 | 最高可利用性 |\n")
+	fmt.Fprintf(f, "|:---|:---|:---|:---|:---|:---|\n")
+	for _, s := range summary {
+		category := "未归类"
+		if s.CWE != "" {
+			category = fmt.Sprintf("[%s](%s)", s.CWE, llm.CWEURL(s.CWE))
+		}
+		fmt.Fprintf(f, "| %s | %s | %d | %d | %s | %s |\n", category, s.Title, s.Count, s.Files, s.Severity.Label(), llm.ExploitabilityLabel(s.Exploitability))
+	}
+	fmt.Fprintf(f, "\n---\n\n")
+}
+
+// writeFindings 写入单个文件的安全审计发现，每个漏洞列出类别、可利用性、成因与修复方法
+func writeFindings(f io.Writer, findings []llm.Finding) {
+	fmt.Fprintf(f, "### 🛡️ 安全漏洞\n\n")
+	for _, finding := range findings {
+		title := finding.Title
+		if finding.CWE != "" {
+			title = fmt.Sprintf("[%s](%s) %s", finding.CWE, llm.CWEURL(finding.CWE), finding.Title)
+		}
+		if finding.Line > 0 {
+			title += fmt.Sprintf(" (第 %d 行)", finding.Line)
+		}
+		fmt.Fprintf(f, "#### %s %s\n\n", finding.Level().Label(), title)
+		fmt.Fprintf(f, "- **可利用性:** %s\n", llm.ExploitabilityLabel(finding.Exploitability))
+		if finding.Confidence > 0 {
+			fmt.Fprintf(f, "- **置信度:** %.2g\n", finding.Confidence)
+		}
+		if finding.Description != "" {
+			fmt.Fprintf(f, "- **成因:** %s\n", finding.Description)
+		}
+		if finding.Remediation != "" {
+			fmt.Fprintf(f, "- **修复方法:** %s\n", finding.Remediation)
+		}
+		fmt.Fprintln(f)
+	}
+}
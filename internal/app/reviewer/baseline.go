@@ -0,0 +1,245 @@
+package reviewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// DefaultBaselineFile 是默认的基线文件路径（相对于当前目录）
+const DefaultBaselineFile = ".review-baseline.json"
+
+// baselineVersion 是基线文件的格式版本
+const baselineVersion = 1
+
+// baselineSimilarity 是问题文本（去除行号、空白与标点后）判定为同一问题的相似度下限
+// 模型每次运行的措辞会略有不同，因此按字符二元组的 Dice 系数模糊匹配
+const baselineSimilarity = 0.6
+
+// Baseline 记录某一时刻各文件已有的问题，后续运行只报告新增问题（并发安全）
+type Baseline struct {
+	mu    sync.Mutex
+	path  string
+	files map[string][]string // 文件路径 -> 问题列表（含严重程度标注）
+}
+
+// baselineFile 是基线文件的 JSON 结构
+type baselineFile struct {
+	Version int                 `json:"version"`
+	Updated time.Time           `json:"updated"`
+	Files   map[string][]string `json:"files"`
+}
+
+// NewBaseline 创建保存到 path 的空基线
+func NewBaseline(path string) *Baseline {
+	return &Baseline{path: path, files: make(map[string][]string)}
+}
+
+// LoadBaseline 读取基线文件，文件不存在时返回的错误满足 errors.Is(err, os.ErrNotExist)
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f baselineFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("基线文件 %s 格式错误: %w", path, err)
+	}
+	if f.Version > baselineVersion {
+		return nil, fmt.Errorf("基线文件 %s 的版本 %d 高于当前支持的版本 %d，请升级 reviewer", path, f.Version, baselineVersion)
+	}
+
+	b := NewBaseline(path)
+	for file, issues := range f.Files {
+		b.files[baselineKey(file)] = issues
+	}
+	return b, nil
+}
+
+// Path 返回基线文件路径
+func (b *Baseline) Path() string {
+	return b.path
+}
+
+// Counts 返回基线中的文件数与问题数
+func (b *Baseline) Counts() (files, issues int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, list := range b.files {
+		issues += len(list)
+	}
+	return len(b.files), issues
+}
+
+// Update 用本次审查成功的文件结果替换基线中对应文件的问题，其余文件保持不变
+func (b *Baseline) Update(results []Result) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, res := range results {
+		if res.Review == nil || res.Error != nil {
+			continue
+		}
+		key := baselineKey(res.FilePath)
+		if len(res.Review.Issues) == 0 {
+			delete(b.files, key)
+			continue
+		}
+		b.files[key] = append([]string(nil), res.Review.Issues...)
+	}
+}
+
+// Save 将基线写入文件
+func (b *Baseline) Save() error {
+	b.mu.Lock()
+	data, err := json.MarshalIndent(baselineFile{Version: baselineVersion, Updated: time.Now(), Files: b.files}, "", "  ")
+	b.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("序列化基线失败: %w", err)
+	}
+	if err := os.WriteFile(b.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("写入基线文件失败: %w", err)
+	}
+	return nil
+}
+
+// Filter 去掉结果中基线已有的问题，返回只含新增问题的结果，并将对比情况累计到 summary
+// 基线中有、本次审查未再发现的问题计为已解决；审查失败或未审查的文件不参与对比
+func (b *Baseline) Filter(res Result, summary *BaselineSummary) Result {
+	if res.Review == nil || res.Error != nil {
+		return res
+	}
+
+	b.mu.Lock()
+	known := b.files[baselineKey(res.FilePath)]
+	b.mu.Unlock()
+
+	matched := make([]bool, len(known))
+	var fresh []string
+	for _, issue := range res.Review.Issues {
+		if i := matchIssue(issue, known, matched); i >= 0 {
+			matched[i] = true
+			summary.Hidden++
+			continue
+		}
+		fresh = append(fresh, issue)
+	}
+	for i, issue := range known {
+		if !matched[i] {
+			summary.Resolved = append(summary.Resolved, ResolvedIssue{FilePath: res.FilePath, Issue: issue})
+		}
+	}
+	summary.New += len(fresh)
+
+	// 复制审查结果，内容相同的文件可能共享同一个 Review
+	review := *res.Review
+	review.Issues = fresh
+	if len(review.Findings) > 0 {
+		review.Findings = freshFindings(review.Findings, fresh)
+	}
+	res.Review = &review
+	return res
+}
+
+// freshFindings 返回与新问题对应的安全审计漏洞（安全审计的问题由漏洞生成，二者一一对应）
+func freshFindings(findings []llm.Finding, fresh []string) []llm.Finding {
+	keep := make(map[string]int, len(fresh))
+	for _, issue := range fresh {
+		keep[issue]++
+	}
+	var kept []llm.Finding
+	for _, f := range findings {
+		if issue := f.Issue(); keep[issue] > 0 {
+			keep[issue]--
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// BaselineSummary 汇总本次审查与基线的对比结果，写入报告
+type BaselineSummary struct {
+	Path     string          // 基线文件路径
+	New      int             // 基线中没有的新问题数
+	Hidden   int             // 基线中已有、未再报告的问题数
+	Resolved []ResolvedIssue // 基线中有、本次审查未再发现的问题
+}
+
+// ResolvedIssue 是已解决的基线问题
+type ResolvedIssue struct {
+	FilePath string
+	Issue    string
+}
+
+// matchIssue 返回 known 中与 issue 最相似且未被匹配的问题下标，相似度不足时返回 -1
+func matchIssue(issue string, known []string, matched []bool) int {
+	text := normalizeIssue(issue)
+	best, bestScore := -1, baselineSimilarity
+	for i, k := range known {
+		if matched[i] {
+			continue
+		}
+		if score := similarity(text, normalizeIssue(k)); score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// SameIssue 判断两条问题描述是否为同一问题（忽略严重程度标注、行号与措辞的细微差异），与基线对比的规则一致
+func SameIssue(a, b string) bool {
+	return similarity(normalizeIssue(a), normalizeIssue(b)) >= baselineSimilarity
+}
+
+// normalizeIssue 去掉严重程度标注、数字（行号）、空白与标点，只保留用于比较的文字
+func normalizeIssue(issue string) []rune {
+	_, text := llm.SplitIssue(issue)
+	var out []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// similarity 计算两段文字字符二元组的 Dice 系数（0-1）
+func similarity(a, b []rune) float64 {
+	if string(a) == string(b) {
+		return 1
+	}
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+	grams := make(map[[2]rune]int, len(a))
+	for i := 0; i+1 < len(a); i++ {
+		grams[[2]rune{a[i], a[i+1]}]++
+	}
+	shared := 0
+	for i := 0; i+1 < len(b); i++ {
+		g := [2]rune{b[i], b[i+1]}
+		if grams[g] > 0 {
+			grams[g]--
+			shared++
+		}
+	}
+	return float64(2*shared) / float64(len(a)+len(b)-2)
+}
+
+// baselineKey 规范化文件路径，保证 ./src/a.go、src/a.go（以及 Windows 上的 src\a.go）对应同一条记录
+func baselineKey(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
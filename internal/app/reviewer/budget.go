@@ -0,0 +1,63 @@
+package reviewer
+
+import (
+	"time"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// tokenBudget 描述整个运行的 Token 预算
+type tokenBudget struct {
+	usage *llm.Usage
+	limit int64
+}
+
+// WithTokenBudget 开启全局 Token 预算：usage 累计消耗达到 limit 后不再派发新任务，
+// 进行中的审查正常完成，剩余文件以 SkipReasonBudget 写入报告
+// usage 可在多个任务之间共享，实现跨任务的总预算
+func WithTokenBudget(usage *llm.Usage, limit int64) Option {
+	return func(e *Engine) {
+		if usage != nil && limit > 0 {
+			e.budget = &tokenBudget{usage: usage, limit: limit}
+		}
+	}
+}
+
+// exhausted 判断预算是否已耗尽，未设置预算时始终返回 false
+func (b *tokenBudget) exhausted() bool {
+	return b != nil && b.usage.Total() >= b.limit
+}
+
+// WithDeadline 设置整个运行的截止时间：到期后不再派发新任务（配额冷却、重试退避等等待也提前结束），
+// 进行中的审查正常完成，剩余文件以 SkipReasonDeadline 写入报告
+// 批量任务共享同一个截止时间，实现跨任务的总时限
+func WithDeadline(deadline time.Time) Option {
+	return func(e *Engine) {
+		e.deadline = deadline
+	}
+}
+
+// pastDeadline 判断是否已到达运行截止时间，未设置时始终返回 false
+func (e *Engine) pastDeadline() bool {
+	return !e.deadline.IsZero() && !time.Now().Before(e.deadline)
+}
+
+// untilDeadline 将等待时长截断到运行截止时间
+func (e *Engine) untilDeadline(d time.Duration) time.Duration {
+	if e.deadline.IsZero() {
+		return d
+	}
+	return max(min(d, time.Until(e.deadline)), 0)
+}
+
+// stopReason 返回不再派发新任务的原因，可以继续派发时返回 SkipReasonNone
+func (e *Engine) stopReason() SkipReason {
+	switch {
+	case e.budget.exhausted():
+		return SkipReasonBudget
+	case e.pastDeadline():
+		return SkipReasonDeadline
+	default:
+		return SkipReasonNone
+	}
+}
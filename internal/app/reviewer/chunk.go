@@ -0,0 +1,167 @@
+package reviewer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// 分段常量
+const (
+	// ChunkOverlapLines 是相邻分段之间重叠的行数，保证跨段的逻辑不被截断
+	ChunkOverlapLines = 20
+)
+
+// boundaryRegex 匹配常见语言中顶格书写的声明行，作为优先的切分点
+var boundaryRegex = regexp.MustCompile(`^(func|type|var|const|def|class|interface|struct|enum|impl|fn|pub|public|private|protected|internal|static|export|function|async|module|package)\b`)
+
+// Chunk 表示大文件中的一个分段
+type Chunk struct {
+	StartLine int // 起始行号（从 1 开始）
+	EndLine   int // 结束行号（包含）
+	Content   string
+
+	// Context 是发送时置于分段之前的参考上下文（如 Go 的包级上下文），不属于源文件的第 StartLine-EndLine 行，不编行号
+	Context string
+}
+
+// splitContent 根据文件类型选择切分方式：Go 文件按顶层声明切分，其他文件（或解析失败时）按行切分
+func splitContent(filePath, content string, maxSize int) []Chunk {
+	if strings.EqualFold(filepath.Ext(filePath), ".go") {
+		if chunks := splitGoDecls(content, maxSize); len(chunks) > 0 {
+			return chunks
+		}
+	}
+	return splitIntoChunks(content, maxSize, ChunkOverlapLines)
+}
+
+// splitIntoChunks 将内容按行切分为不超过 maxSize 字节的分段
+// 优先在函数/类型声明处切分，相邻分段重叠 overlap 行
+func splitIntoChunks(content string, maxSize, overlap int) []Chunk {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var chunks []Chunk
+	start := 0
+	for start < len(lines) {
+		end, size := start, 0
+		for end < len(lines) && (end == start || size+len(lines[end]) <= maxSize) {
+			size += len(lines[end])
+			end++
+		}
+
+		// 未到文件末尾时，尝试回退到后半段中最近的声明边界
+		if end < len(lines) {
+			for i := end - 1; i > start+(end-start)/2; i-- {
+				if boundaryRegex.MatchString(lines[i]) {
+					end = i
+					break
+				}
+			}
+		}
+
+		chunks = append(chunks, Chunk{
+			StartLine: start + 1,
+			EndLine:   end,
+			Content:   strings.Join(lines[start:end], ""),
+		})
+
+		if end >= len(lines) {
+			break
+		}
+
+		// 下一段回退 overlap 行，但必须向前推进
+		next := end - overlap
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+
+	return chunks
+}
+
+// chunkContent 返回发送给模型的分段内容：上下文在前，minify 时只为分段中的源码加上原始行号
+func chunkContent(filePath string, c Chunk, minify bool) string {
+	if minify {
+		return c.Context + minifyContent(filePath, c.Content, c.StartLine)
+	}
+	return c.Context + c.Content
+}
+
+// chunkLabel 生成分段在提示词中的文件标识
+func chunkLabel(filePath string, c Chunk, index, total int) string {
+	return fmt.Sprintf("%s (分段 %d/%d，第 %d-%d 行，仅为文件片段)", filePath, index+1, total, c.StartLine, c.EndLine)
+}
+
+// mergeChunkReviews 将各分段的审查结果合并为单个文件结果
+// 分数按分段大小加权平均，重要性取最大值，问题标注行号范围后合并去重
+func mergeChunkReviews(chunks []Chunk, reviews []*llm.ReviewResult) *llm.ReviewResult {
+	merged := &llm.ReviewResult{}
+	var weighted, totalWeight float64
+	var summaries, suggestions []string
+	seenPros := make(map[string]struct{})
+	seenIssues := make(map[string]struct{})
+
+	for i, review := range reviews {
+		if review == nil {
+			continue
+		}
+		c := chunks[i]
+		weight := float64(len(c.Content))
+		weighted += float64(review.Score) * weight
+		totalWeight += weight
+
+		if review.Importance > merged.Importance {
+			merged.Importance = review.Importance
+		}
+		if review.Confidence > 0 && (merged.Confidence == 0 || review.Confidence < merged.Confidence) {
+			merged.Confidence = review.Confidence
+		}
+
+		if review.Summary != "" {
+			summaries = append(summaries, fmt.Sprintf("第 %d-%d 行：%s", c.StartLine, c.EndLine, review.Summary))
+		}
+		if review.Suggestion != "" {
+			suggestions = append(suggestions, review.Suggestion)
+		}
+
+		for _, pro := range review.Pros {
+			if _, ok := seenPros[pro]; !ok {
+				seenPros[pro] = struct{}{}
+				merged.Pros = append(merged.Pros, pro)
+			}
+		}
+		for _, issue := range review.Issues {
+			if _, ok := seenIssues[issue]; ok {
+				continue
+			}
+			seenIssues[issue] = struct{}{}
+			// 行号放在严重程度标注之后，保证合并后仍能按严重程度与置信度过滤
+			severity, text := llm.SplitIssue(issue)
+			merged.Issues = append(merged.Issues, llm.FormatIssueWithConfidence(severity, llm.IssueConfidence(issue), fmt.Sprintf("[第 %d-%d 行] %s", c.StartLine, c.EndLine, text)))
+		}
+		merged.Findings = append(merged.Findings, review.Findings...)
+	}
+
+	// 安全审计的问题与漏洞一一对应，由合并后的漏洞重新生成
+	if len(merged.Findings) > 0 {
+		merged.Issues = nil
+		for _, f := range merged.Findings {
+			merged.Issues = append(merged.Issues, f.Issue())
+		}
+	}
+
+	if totalWeight > 0 {
+		merged.Score = int(weighted/totalWeight + 0.5)
+	}
+	merged.Summary = strings.Join(summaries, "；")
+	merged.Suggestion = strings.Join(suggestions, "\n\n")
+
+	return merged
+}
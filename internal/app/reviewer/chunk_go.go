@@ -0,0 +1,141 @@
+package reviewer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// goContextRatio 是包级上下文在单个分段中允许占用的最大比例
+const goContextRatio = 4
+
+// splitGoDecls 使用 go/ast 按顶层声明切分 Go 文件，每个分段附带包级上下文（package、imports、类型声明）
+// 每个分段都是源文件中连续的若干行，声明之间的注释（//go:build、分节注释、//nolint 等）随后一个声明一起发送
+// 解析失败或包级上下文占用过多时返回 nil，调用方应回退到按行切分
+func splitGoDecls(content string, maxSize int) []Chunk {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	header := goPackageContext(content, fset, file, maxSize/goContextRatio)
+	budget := maxSize - len(header)
+	if budget < maxSize/2 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var chunks []Chunk
+	var body strings.Builder
+	startLine, endLine := 0, 0
+
+	flush := func() {
+		if body.Len() == 0 {
+			return
+		}
+		chunks = append(chunks, Chunk{StartLine: startLine, EndLine: endLine, Content: body.String(), Context: header})
+		body.Reset()
+		startLine = 0
+	}
+
+	for _, r := range goDeclLineRanges(fset, file, len(lines)) {
+		text := strings.Join(lines[r[0]-1:r[1]], "")
+
+		// 单个声明超过预算：按行切分该声明，并修正行号
+		if len(text) > budget {
+			flush()
+			for _, sub := range splitIntoChunks(text, budget, ChunkOverlapLines) {
+				chunks = append(chunks, Chunk{
+					StartLine: r[0] + sub.StartLine - 1,
+					EndLine:   r[0] + sub.EndLine - 1,
+					Content:   sub.Content,
+					Context:   header,
+				})
+			}
+			continue
+		}
+
+		if body.Len()+len(text) > budget {
+			flush()
+		}
+		if startLine == 0 {
+			startLine = r[0]
+		}
+		endLine = r[1]
+		body.WriteString(text)
+	}
+	flush()
+
+	return chunks
+}
+
+// goDeclLineRanges 将文件的 total 行划分为首尾相接的行号范围（从 1 开始，包含两端），每个范围以一个顶层声明结尾
+// 第一个范围从第 1 行开始（含 package 与 imports），最后一个范围延伸到文件末尾，保证不遗漏任何一行
+func goDeclLineRanges(fset *token.FileSet, file *ast.File, total int) [][2]int {
+	var ranges [][2]int
+	next := 1
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+		// 与上一个声明写在同一行时已包含在上一个范围中
+		end := fset.Position(decl.End()).Line
+		if end < next {
+			continue
+		}
+		ranges = append(ranges, [2]int{next, end})
+		next = end + 1
+	}
+	switch {
+	case next > total:
+	case len(ranges) == 0:
+		ranges = append(ranges, [2]int{1, total})
+	default:
+		ranges[len(ranges)-1][1] = total
+	}
+	return ranges
+}
+
+// goPackageContext 生成包级上下文：package 声明、imports 以及类型声明，超出 limit 时省略其余的 imports 与类型
+func goPackageContext(content string, fset *token.FileSet, file *ast.File, limit int) string {
+	var b strings.Builder
+	b.WriteString("// ---- 包级上下文（仅供参考，不在本分段审查范围内）----\n")
+	b.WriteString("package " + file.Name.Name + "\n\n")
+
+	if len(file.Imports) > 0 {
+		b.WriteString("import (\n")
+		for i, spec := range file.Imports {
+			line := "\t" + content[fset.Position(spec.Pos()).Offset:fset.Position(spec.End()).Offset] + "\n"
+			if b.Len()+len(line) > limit {
+				b.WriteString("\t// ... (其余 " + strconv.Itoa(len(file.Imports)-i) + " 个导入已省略)\n")
+				break
+			}
+			b.WriteString(line)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		text := content[fset.Position(gen.Pos()).Offset:fset.Position(gen.End()).Offset]
+		if b.Len()+len(text) > limit {
+			b.WriteString("// ... (其余类型声明已省略)\n")
+			break
+		}
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("// ---- 待审查的声明 ----\n\n")
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package reviewer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// largeGoFile 生成包含 n 个函数的 Go 文件，函数之间穿插分节注释与 //nolint 标记
+func largeGoFile(imports, n int) string {
+	var b strings.Builder
+	b.WriteString("//go:build linux\n\n// Package x 用于测试按声明切分\npackage x\n\n")
+	if imports > 0 {
+		b.WriteString("import (\n")
+		for i := range imports {
+			fmt.Fprintf(&b, "\tp%d \"example.com/module/with/a/fairly/long/import/path/pkg%d\"\n", i, i)
+		}
+		b.WriteString(")\n\n")
+	}
+	b.WriteString("// Item 是测试用的类型\ntype Item struct{ ID int }\n\n")
+	for i := range n {
+		if i%5 == 0 {
+			fmt.Fprintf(&b, "// ---- 第 %d 节 ----\n\n", i/5)
+		}
+		fmt.Fprintf(&b, "// F%d 返回参数之和\n//nolint:unused\nfunc F%d(a, b int) int {\n\tsum := a + b\n\treturn sum + %d\n}\n\n", i, i, i)
+	}
+	b.WriteString("// 文件末尾的注释\n")
+	return b.String()
+}
+
+func TestSplitGoDeclsContiguous(t *testing.T) {
+	content := largeGoFile(3, 40)
+	lines := strings.SplitAfter(content, "\n")
+	chunks := splitGoDecls(content, 600)
+	if len(chunks) < 2 {
+		t.Fatalf("得到 %d 个分段，want 多个", len(chunks))
+	}
+
+	next := 1
+	for _, c := range chunks {
+		if c.StartLine != next {
+			t.Errorf("分段从第 %d 行开始，want %d (行号不连续)", c.StartLine, next)
+		}
+		if !strings.HasPrefix(c.Context, "// ---- 包级上下文") {
+			t.Errorf("分段 %d-%d 缺少包级上下文", c.StartLine, c.EndLine)
+		}
+		if want := strings.Join(lines[c.StartLine-1:c.EndLine], ""); c.Content != want {
+			t.Errorf("分段 %d-%d 的内容与源文件中的行不一致:\n%s\nwant:\n%s", c.StartLine, c.EndLine, c.Content, want)
+		}
+		next = c.EndLine + 1
+	}
+	if total := strings.Count(content, "\n"); next != total+1 {
+		t.Errorf("分段到第 %d 行结束，want %d", next-1, total)
+	}
+
+	// 声明之间的注释与编译指令都在某个分段中
+	all := ""
+	for _, c := range chunks {
+		all += c.Content
+	}
+	for _, want := range []string{"//go:build linux", "// ---- 第 3 节 ----", "//nolint:unused", "// 文件末尾的注释"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("分段中缺少 %q", want)
+		}
+	}
+}
+
+func TestSplitGoDeclsLargeImports(t *testing.T) {
+	// 导入块本身就超过了单次请求的上限
+	content := largeGoFile(1000, 300)
+	maxSize := DefaultMaxFileSize
+	chunks := splitGoDecls(content, maxSize)
+	if len(chunks) == 0 {
+		t.Fatal("没有得到分段")
+	}
+
+	// 包级上下文不超过上限的 1/4 左右，分段数与按行切分相当，不会因上下文过大而每行一段
+	lineChunks := len(splitIntoChunks(content, maxSize, ChunkOverlapLines))
+	if len(chunks) > 2*lineChunks {
+		t.Errorf("得到 %d 个分段，按行切分只有 %d 个", len(chunks), lineChunks)
+	}
+	header := chunks[0].Context
+	if len(header) > maxSize/2 {
+		t.Errorf("包级上下文 %d 字节，超过上限的一半", len(header))
+	}
+	if !strings.Contains(header, "个导入已省略") {
+		t.Error("包级上下文没有省略多余的导入")
+	}
+	for _, c := range chunks {
+		if len(c.Context)+len(c.Content) > 2*maxSize {
+			t.Errorf("分段 %d-%d 有 %d 字节", c.StartLine, c.EndLine, len(c.Content))
+		}
+	}
+}
+
+func TestSplitGoDeclsSmallLimit(t *testing.T) {
+	// 上限过小，包级上下文的固定部分就占了一半以上时回退到按行切分
+	content := largeGoFile(3, 10)
+	if chunks := splitGoDecls(content, 150); chunks != nil {
+		t.Errorf("得到 %d 个分段，want nil", len(chunks))
+	}
+	if chunks := splitContent("x.go", content, 150); len(chunks) == 0 {
+		t.Error("splitContent 没有回退到按行切分")
+	}
+}
+
+func TestChunkContentMinifyLineNumbers(t *testing.T) {
+	content := largeGoFile(3, 40)
+	lines := strings.Split(content, "\n")
+	chunks := splitContent("x.go", content, 600)
+	if len(chunks) < 2 {
+		t.Fatalf("得到 %d 个分段，want 多个", len(chunks))
+	}
+
+	for _, c := range chunks {
+		got := chunkContent("x.go", c, true)
+		context, code, ok := strings.Cut(got, "// ---- 待审查的声明 ----\n\n")
+		if !ok {
+			t.Fatalf("分段 %d-%d 缺少包级上下文", c.StartLine, c.EndLine)
+		}
+		if strings.Contains(context, "| ") {
+			t.Errorf("包级上下文被编了行号:\n%s", context)
+		}
+		// 每个编号行都与源文件中同一行的内容一致
+		for _, line := range strings.Split(strings.TrimSuffix(code, "\n"), "\n") {
+			num, text, ok := strings.Cut(line, "| ")
+			if !ok {
+				t.Errorf("缺少行号前缀: %q", line)
+				continue
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(num))
+			if err != nil || n < c.StartLine || n > c.EndLine {
+				t.Errorf("行号 %q 不在分段 %d-%d 中", num, c.StartLine, c.EndLine)
+				continue
+			}
+			if src := strings.TrimRight(lines[n-1], " \t"); src != text {
+				t.Errorf("第 %d 行标注为 %q，源文件中为 %q", n, text, src)
+			}
+		}
+	}
+}
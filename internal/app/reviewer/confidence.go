@@ -0,0 +1,40 @@
+package reviewer
+
+import "go-ai-reviewer/internal/llm"
+
+// WithMinConfidence 丢弃置信度低于 minimum 的问题（报告、SARIF 与基线对比均不再包含），
+// 未标注置信度的问题总是保留；minimum 为 0 时不过滤
+func WithMinConfidence(minimum float64) Option {
+	return func(e *Engine) {
+		e.minConfidence = minimum
+	}
+}
+
+// applyConfidence 按引擎的置信度阈值丢弃低置信度的问题
+func (e *Engine) applyConfidence(res Result) Result {
+	return FilterLowConfidence(res, e.minConfidence)
+}
+
+// FilterLowConfidence 丢弃置信度低于 minimum 的问题与漏洞，并在 LowConfidence 中记录丢弃的数量，
+// 返回的结果不与 res 共享审查结论；minimum 为 0 时原样返回
+func FilterLowConfidence(res Result, minimum float64) Result {
+	if res.Review == nil || minimum <= 0 {
+		return res
+	}
+	review := *res.Review
+	review.Issues = llm.FilterConfidentIssues(review.Issues, minimum)
+	if len(review.Findings) > 0 {
+		var findings []llm.Finding
+		for _, f := range review.Findings {
+			if f.Confidence == 0 || f.Confidence >= minimum {
+				findings = append(findings, f)
+			}
+		}
+		review.Findings = findings
+	}
+	if dropped := len(res.Review.Issues) - len(review.Issues); dropped > 0 {
+		res.Review = &review
+		res.LowConfidence = dropped
+	}
+	return res
+}
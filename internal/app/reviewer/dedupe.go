@@ -0,0 +1,72 @@
+package reviewer
+
+import (
+	"crypto/sha256"
+	"sync"
+)
+
+// dedupeIndex 记录内容完全相同的文件：每组只审查第一个文件（主文件），
+// 其余文件在主文件得到最终结果后复用该结果（并发安全）
+type dedupeIndex struct {
+	mu       sync.Mutex
+	primary  map[[sha256.Size]byte]string // 内容哈希 -> 主文件路径
+	pending  map[string][]string          // 主文件路径 -> 等待复用结果的文件
+	finished map[string]Result            // 已得到最终结果的主文件
+}
+
+// WithDedupe 开启内容去重：内容完全相同的文件（复制的模板、重复生成的文件）只审查一次，
+// 其余文件复用结果并在报告中注明
+func WithDedupe() Option {
+	return func(e *Engine) {
+		e.dedupe = &dedupeIndex{
+			primary:  make(map[[sha256.Size]byte]string),
+			pending:  make(map[string][]string),
+			finished: make(map[string]Result),
+		}
+	}
+}
+
+// claim 登记文件内容，返回内容相同的主文件路径（自身即为主文件时返回空）
+// 主文件已有最终结果时同时返回可直接复用的结果
+func (d *dedupeIndex) claim(path, content string) (string, *Result) {
+	sum := sha256.Sum256([]byte(content))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	primary, ok := d.primary[sum]
+	if !ok {
+		d.primary[sum] = path
+		return "", nil
+	}
+	if res, done := d.finished[primary]; done {
+		alias := aliasResult(res, path)
+		return primary, &alias
+	}
+	d.pending[primary] = append(d.pending[primary], path)
+	return primary, nil
+}
+
+// complete 记录主文件的最终结果，返回等待复用该结果的文件的结果
+func (d *dedupeIndex) complete(res Result) []Result {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.finished[res.FilePath] = res
+	paths := d.pending[res.FilePath]
+	delete(d.pending, res.FilePath)
+
+	aliases := make([]Result, 0, len(paths))
+	for _, path := range paths {
+		aliases = append(aliases, aliasResult(res, path))
+	}
+	return aliases
+}
+
+// aliasResult 基于主文件的结果生成重复文件的结果
+func aliasResult(res Result, path string) Result {
+	alias := res
+	alias.DuplicateOf = res.FilePath
+	alias.FilePath = path
+	return alias
+}
@@ -0,0 +1,160 @@
+package reviewer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/deps"
+	"go-ai-reviewer/internal/llm"
+)
+
+// DependencyReportMeta 描述依赖审查报告的元信息
+type DependencyReportMeta struct {
+	Name           string // 报告名称（为空时使用时间戳）
+	ProjectLicense string // 项目的许可证，未识别时为空
+	Tokens         int64  // 模型审查消耗的 Token 数（0 表示未调用模型）
+	LocalOnly      bool   // 只运行了本地规则
+}
+
+// GenerateDependencyReport 生成依赖审查报告，返回报告路径
+func GenerateDependencyReport(reviews []deps.Review, duration time.Duration, outputDir string, meta DependencyReportMeta) (string, error) {
+	reportPath := filepath.Join(outputDir, sanitizeFileName(meta.Name))
+	if err := os.MkdirAll(outputDir, DirPermission); err != nil {
+		return "", fmt.Errorf("创建报告目录失败: %w", err)
+	}
+	f, err := os.Create(reportPath)
+	if err != nil {
+		return "", fmt.Errorf("创建报告文件失败: %w", err)
+	}
+	defer f.Close()
+
+	writeDependencyHeader(f, strings.TrimSuffix(filepath.Base(reportPath), ".md"), reviews, meta, duration)
+	fmt.Fprintf(f, "## 📦 依赖审查\n\n")
+	for _, r := range reviews {
+		writeDependencyReview(f, r, outputDir)
+	}
+	return reportPath, nil
+}
+
+// writeDependencyHeader 写入依赖审查报告的概览
+func writeDependencyHeader(f io.Writer, displayName string, reviews []deps.Review, meta DependencyReportMeta, duration time.Duration) {
+	var total int
+	counts := make(map[llm.Severity]int)
+	for _, r := range reviews {
+		total += len(r.Manifest.Deps)
+		for _, finding := range r.Findings {
+			counts[finding.Severity]++
+		}
+	}
+
+	fmt.Fprintf(f, "# 依赖审查报告: %s\n\n", displayName)
+	fmt.Fprintf(f, "## 📊 项目概览\n\n")
+	fmt.Fprintf(f, "| 指标 | 值 |\n")
+	fmt.Fprintf(f, "|:---|:---|\n")
+	fmt.Fprintf(f, "| 生成时间 | %s |\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(f, "| 耗时 | %s |\n", duration.Round(time.Millisecond))
+	if meta.Tokens > 0 {
+		fmt.Fprintf(f, "| Token 消耗 | %d |\n", meta.Tokens)
+	}
+	fmt.Fprintf(f, "| 依赖清单 | %d 个 (共 %d 个依赖) |\n", len(reviews), total)
+	license := meta.ProjectLicense
+	if license == "" {
+		license = "未识别 (按闭源项目检查兼容性)"
+	}
+	fmt.Fprintf(f, "| 项目许可证 | %s |\n", license)
+	fmt.Fprintf(f, "| 发现问题 | %s %d / %s %d / %s %d |\n",
+		llm.SeverityCritical.Label(), counts[llm.SeverityCritical],
+		llm.SeverityMajor.Label(), counts[llm.SeverityMajor],
+		llm.SeverityMinor.Label(), counts[llm.SeverityMinor])
+	if meta.LocalOnly {
+		fmt.Fprintf(f, "| 审查方式 | 仅本地规则 (未调用模型) |\n")
+	}
+	fmt.Fprintf(f, "\n---\n\n")
+}
+
+// writeDependencyReview 写入一个依赖清单的审查结果
+func writeDependencyReview(f io.Writer, r deps.Review, outputDir string) {
+	m := r.Manifest
+	fmt.Fprintf(f, "### [%s](%s) (%s，%d 个依赖)\n\n", m.Path, getRelativeLink(m.Path, outputDir), deps.EcosystemLabel(m.Ecosystem), len(m.Deps))
+	if r.Err != nil {
+		fmt.Fprintf(f, "> ❌ 模型审查失败，以下仅包含本地规则的结果: %v\n\n", r.Err)
+	}
+	if r.Summary != "" {
+		fmt.Fprintf(f, "**总结:** %s\n\n", r.Summary)
+	}
+
+	if len(r.Findings) == 0 {
+		fmt.Fprintf(f, "> ✅ 未发现需要处理的依赖。\n\n")
+	} else {
+		fmt.Fprintf(f, "| 严重程度 | 依赖 | 版本 | 类别 | 说明 | 建议 |\n")
+		fmt.Fprintf(f, "|:---|:---|:---|:---|:---|:---|\n")
+		for _, finding := range r.Findings {
+			name, version := "(整个清单)", ""
+			if finding.Dependency != "" {
+				name = "`" + finding.Dependency + "`"
+				if finding.Line > 0 {
+					name = fmt.Sprintf("[`%s`](%s#L%d)", finding.Dependency, getRelativeLink(m.Path, outputDir), finding.Line)
+				}
+			}
+			if d, ok := m.Dependency(finding.Dependency); ok {
+				version = d.Version
+			}
+			category := deps.CategoryLabel(finding.Category)
+			if !finding.Local {
+				category += " (模型)"
+			}
+			fmt.Fprintf(f, "| %s | %s | %s | %s | %s | %s |\n", finding.Severity.Label(), name, escapeTableCell(version), category,
+				escapeTableCell(finding.Message), escapeTableCell(finding.Suggestion))
+		}
+		fmt.Fprintln(f)
+	}
+
+	if licenses := licenseSummary(m.Deps); licenses != "" {
+		fmt.Fprintf(f, "**许可证 (本地识别):** %s\n\n", licenses)
+	}
+	fmt.Fprintf(f, "---\n\n")
+}
+
+// licenseSummary 汇总本地识别到的依赖许可证，如 "MIT × 5、Apache-2.0 × 2、未识别 × 3"
+func licenseSummary(dependencies []deps.Dependency) string {
+	counts := make(map[string]int)
+	unknown := 0
+	for _, d := range dependencies {
+		if d.License == "" {
+			unknown++
+		} else {
+			counts[d.License]++
+		}
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if counts[ids[i]] != counts[ids[j]] {
+			return counts[ids[i]] > counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	parts := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%s × %d", id, counts[id]))
+	}
+	if unknown > 0 {
+		parts = append(parts, fmt.Sprintf("未识别 × %d", unknown))
+	}
+	return strings.Join(parts, "、")
+}
+
+// escapeTableCell 转义 Markdown 表格单元格中的竖线与换行
+func escapeTableCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
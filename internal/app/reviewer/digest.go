@@ -0,0 +1,66 @@
+package reviewer
+
+import "go-ai-reviewer/internal/llm"
+
+// maxDigestIssues 是运行摘要中列出的主要问题数
+const maxDigestIssues = 5
+
+// DigestIssue 是运行摘要中的一个主要问题
+type DigestIssue struct {
+	Severity llm.Severity
+	File     string
+	Text     string
+}
+
+// RunDigest 是一次审查的简要统计，用于发送到 Slack 等外部通知渠道
+type RunDigest struct {
+	Name     string  // 报告名称
+	Audit    bool    // 是否为安全审计
+	Score    float64 // 项目综合评分
+	Files    int     // 文件总数
+	Reviewed int     // 有效分析的文件数
+	Skipped  int     // 跳过的文件数
+
+	// 不低于 MinSeverity 的问题数（安全审计时包含漏洞）
+	Critical int
+	Major    int
+	Minor    int
+
+	// TopIssues 是按严重程度与文件重要性排列的前几个问题
+	TopIssues []DigestIssue
+
+	// Summary 是模型生成的执行摘要，未生成时为空
+	Summary string
+}
+
+// NewRunDigest 汇总审查结果，问题统计与报告一致，只包含不低于 meta.MinSeverity 的问题
+func NewRunDigest(results []Result, meta ReportMeta) RunDigest {
+	stats, _ := calculateStats(results, meta.MinSeverity)
+	d := RunDigest{
+		Name:     meta.Name,
+		Audit:    meta.Audit,
+		Score:    stats.FinalScore,
+		Files:    stats.TotalFiles,
+		Reviewed: stats.ValidFiles,
+		Skipped:  stats.SkippedFiles,
+	}
+	if meta.Summary != nil {
+		d.Summary = meta.Summary.Summary
+	}
+
+	issues := collectSummaryIssues(results, meta.MinSeverity)
+	for _, issue := range issues {
+		switch issue.severity {
+		case llm.SeverityCritical:
+			d.Critical++
+		case llm.SeverityMajor:
+			d.Major++
+		default:
+			d.Minor++
+		}
+	}
+	for _, issue := range issues[:min(len(issues), maxDigestIssues)] {
+		d.TopIssues = append(d.TopIssues, DigestIssue{Severity: issue.severity, File: issue.file, Text: issue.text})
+	}
+	return d
+}
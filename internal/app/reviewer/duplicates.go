@@ -0,0 +1,40 @@
+package reviewer
+
+import (
+	"fmt"
+	"io"
+
+	"go-ai-reviewer/internal/app/duplicates"
+)
+
+// maxListedDuplicates 是报告中列出的重复代码上限（按重复行数降序）
+const maxListedDuplicates = 30
+
+// duplicatedLines 统计重复代码的总行数（不含空行与注释行）
+func duplicatedLines(clones []duplicates.Clone) int {
+	total := 0
+	for _, c := range clones {
+		total += c.Lines
+	}
+	return total
+}
+
+// writeDuplicates 写入本地检测到的重复代码
+func writeDuplicates(f io.Writer, clones []duplicates.Clone, outputDir string) {
+	fmt.Fprintf(f, "## 🧬 重复代码 (%d 处)\n\n", len(clones))
+	fmt.Fprintf(f, "> 以下片段由本地相似度检测发现（忽略缩进、空白与注释），建议提取为共用的函数或模块。\n\n")
+	fmt.Fprintf(f, "| 位置 | 重复位置 | 行数 |\n")
+	fmt.Fprintf(f, "|:---|:---|:---|\n")
+	for _, c := range clones[:min(len(clones), maxListedDuplicates)] {
+		fmt.Fprintf(f, "| %s | %s | %d |\n", blockLink(c.A, outputDir), blockLink(c.B, outputDir), c.Lines)
+	}
+	if len(clones) > maxListedDuplicates {
+		fmt.Fprintf(f, "\n> 另有 %d 处较短的重复未列出。\n", len(clones)-maxListedDuplicates)
+	}
+	fmt.Fprintf(f, "\n---\n\n")
+}
+
+// blockLink 返回重复片段的链接，如 "[a.go](../a.go#L10) 第 10-25 行"
+func blockLink(b duplicates.Block, outputDir string) string {
+	return fmt.Sprintf("[%s](%s#L%d) 第 %d-%d 行", b.File, getRelativeLink(b.File, outputDir), b.StartLine, b.StartLine, b.EndLine)
+}
@@ -3,18 +3,28 @@ package reviewer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"sync"
+	"time"
 
+	"go-ai-reviewer/internal/app/lint"
+	"go-ai-reviewer/internal/app/projectctx"
+	"go-ai-reviewer/internal/app/secrets"
+	"go-ai-reviewer/internal/app/textenc"
 	"go-ai-reviewer/internal/llm"
 )
 
 // 常量定义
 const (
-	// MaxFileSize 是允许审查的最大文件大小（32KB）
-	MaxFileSize = 32 * 1024
+	// DefaultMaxFileSize 是单次请求默认允许发送的最大文件大小（32KB），超过则分段审查
+	DefaultMaxFileSize = 32 * 1024
+	// MaxChunkedFileSize 是允许分段审查的最大文件大小（512KB），超过则跳过；
+	// 单次上限配置得更大时以单次上限为准
+	MaxChunkedFileSize = 512 * 1024
 	// DefaultConcurrency 是默认的并发数
 	DefaultConcurrency = 5
 	// DefaultLevel 是默认的审查级别
@@ -23,21 +33,50 @@ const (
 	MinLevel = 1
 	// MaxLevel 是最大审查级别
 	MaxLevel = 6
+	// DefaultFileTimeout 是单个文件的默认审查时限
+	DefaultFileTimeout = 10 * time.Minute
+	// DefaultReverifyScore 是触发复核的默认分数阈值（低于该分数即复核）
+	DefaultReverifyScore = 40
+	// DefaultReverifyConfidence 是触发复核的默认置信度阈值（低于该置信度即复核）
+	DefaultReverifyConfidence = 0.5
 )
 
 // Job 表示一个待审查的文件任务
 type Job struct {
 	FilePath string
 	Content  string
+	Chunks   []Chunk // 大文件的分段，为空表示整体审查
+	Attempt  int     // 已重试的次数
+
+	Secrets []secrets.Finding // 发送前检测到的疑似敏感信息
+
+	// Redactions 是发送前遮盖的内容（此时 Content 为遮盖后的内容），Original 为遮盖前的原文
+	Redactions []secrets.Redaction
+	Original   string
+}
+
+// original 返回文件的原始内容，用于校验修复补丁
+func (j Job) original() string {
+	if j.Original != "" {
+		return j.Original
+	}
+	return j.Content
 }
 
 // SkipReason 表示文件被跳过的原因
 type SkipReason string
 
 const (
-	SkipReasonNone     SkipReason = ""
-	SkipReasonTooLarge SkipReason = "file_too_large"
-	SkipReasonReadErr  SkipReason = "read_error"
+	SkipReasonNone      SkipReason = ""
+	SkipReasonTooLarge  SkipReason = "file_too_large"
+	SkipReasonReadErr   SkipReason = "read_error"
+	SkipReasonGenerated SkipReason = "generated"
+	SkipReasonVendored  SkipReason = "vendored"
+	SkipReasonMinified  SkipReason = "minified"
+	SkipReasonBudget    SkipReason = "token_budget"
+	SkipReasonTimeout   SkipReason = "timeout"
+	SkipReasonDeadline  SkipReason = "run_deadline"
+	SkipReasonSecrets   SkipReason = "secrets"
 )
 
 // Result 表示审查结果
@@ -47,17 +86,155 @@ type Result struct {
 	Review     *llm.ReviewResult
 	Error      error
 	SkipReason SkipReason // 跳过原因
+	Reverified bool       // 是否经过低置信度复核
+	Chunks     int        // 分段审查的段数（0 表示整体审查）
+	Retries    int        // 重试次数
+
+	// Triage 非空表示该文件只经过初筛、未进入深度审查（此时 Review 为空）
+	Triage *llm.TriageResult
+
+	// Lint 是本地静态检查工具报告的问题，与 AI 审查结果一并写入报告
+	Lint []lint.Finding
+
+	// DuplicateOf 非空表示文件内容与该文件完全相同，直接复用了其审查结果
+	DuplicateOf string
+
+	// Secrets 是发送前在文件中检测到的疑似敏感信息（内容已遮盖）
+	Secrets []secrets.Finding
+
+	// Redactions 是发送前遮盖的内容（类型与行号）
+	Redactions []secrets.Redaction
+
+	// ImportanceRule 是调整了重要性的路径规则，为空表示使用模型给出的重要性
+	ImportanceRule *ImportanceRule
+
+	// LowConfidence 是因置信度低于阈值而丢弃的问题数
+	LowConfidence int
+
+	// SuppressedRules 是因引用了被抑制的规则（suppress_rules 或文件中的 reviewer:ignore 注释）而丢弃的问题数
+	SuppressedRules int
 }
 
 // Engine 是代码审查引擎，协调并发审查流程
 type Engine struct {
-	client      *llm.Client
-	concurrency int
-	level       int
+	client        *llm.Client
+	concurrency   int
+	level         int
+	reverify      reverifyPolicy
+	maxFileSize   int64             // 单次请求允许发送的最大文件大小，超过则分段审查
+	fileTimeout   time.Duration     // 单个文件（含分段与复核）的审查时限，0 表示不限制
+	minify        bool              // 发送前去除注释与空行，并以原始行号标注每一行
+	fix           bool              // 要求模型同时给出修复补丁（分段审查或压缩时不请求）
+	audit         bool              // 安全审计：要求模型给出 CWE 编号、可利用性与修复方法
+	secrets       secretPolicy      // 发送前的敏感信息检测
+	redactor      *secrets.Redactor // 发送前遮盖敏感内容，为空时不遮盖
+	importance    []ImportanceRule  // 按路径调整重要性的规则，按模式长度降序排列
+	minConfidence float64           // 丢弃置信度低于该值的问题，0 表示不过滤
+	rules         []llm.Rule        // 团队规则，每个文件只注入路径匹配的规则
+	suppressRules []string          // 抑制的规则编号：不注入，引用它们的问题被丢弃
+
+	// 自适应并发：maxConcurrency > 0 时启用，limiter 在 NewEngine 中创建
+	// 也可以通过 WithLimiter 使用外部限流器（共享并发上限、运行期间暂停或手动调整）
+	maxConcurrency int
+	limiter        *Limiter
+
+	prioritize bool                // 是否按启发式重要性排序任务队列
+	projectCtx *projectctx.Context // 项目上下文，为空时不注入
+	lint       *lint.Report        // 本地静态检查结果，为空时不合并
+	triage     *triageStage        // 两阶段模式的初筛配置，为空时不初筛
+	retry      retryPolicy         // 失败文件的重试策略
+	budget     *tokenBudget        // 全局 Token 预算，为空时不限制
+	deadline   time.Time           // 整个运行的截止时间，为零值时不限制
+	dedupe     *dedupeIndex        // 内容去重索引，为空时不去重
+	quota      *quotaPause         // 配额耗尽时的暂停策略，为空时按普通失败处理
+	onEvent    func(Event)         // 事件回调（暂停、恢复、开始审查文件等）
+	metrics    metrics             // 运行指标
+}
+
+// reverifyPolicy 描述低分/低置信度结果的复核策略
+type reverifyPolicy struct {
+	enabled         bool
+	scoreBelow      int
+	confidenceBelow float64
+}
+
+// Option 定义 Engine 的配置选项
+type Option func(*Engine)
+
+// WithReverify 开启低分/低置信度复核：分数低于 scoreBelow 或置信度低于 confidenceBelow 时再问一次模型
+func WithReverify(scoreBelow int, confidenceBelow float64) Option {
+	return func(e *Engine) {
+		e.reverify = reverifyPolicy{
+			enabled:         true,
+			scoreBelow:      scoreBelow,
+			confidenceBelow: confidenceBelow,
+		}
+	}
+}
+
+// WithAdaptiveConcurrency 开启自适应并发：以 concurrency 为初始值，
+// 限流/超时增多时自动收缩，API 恢复健康后逐步增长，上限为 maxConcurrency
+func WithAdaptiveConcurrency(maxConcurrency int) Option {
+	return func(e *Engine) {
+		e.maxConcurrency = maxConcurrency
+	}
+}
+
+// WithPrioritize 开启重要性调度：审查前按启发式重要性排序文件，核心文件优先审查
+func WithPrioritize() Option {
+	return func(e *Engine) {
+		e.prioritize = true
+	}
+}
+
+// WithProjectContext 为每个文件注入项目上下文（目录结构、README 摘要、引用符号签名）
+func WithProjectContext(pc *projectctx.Context) Option {
+	return func(e *Engine) {
+		e.projectCtx = pc
+	}
+}
+
+// WithMaxFileSize 设置单次请求允许发送的最大文件大小（字节），超过则分段审查
+// 上下文窗口较大的模型可以调大该值，减少分段
+func WithMaxFileSize(size int64) Option {
+	return func(e *Engine) {
+		if size > 0 {
+			e.maxFileSize = size
+		}
+	}
+}
+
+// WithFileTimeout 设置单个文件的审查时限：超时的请求被取消，文件在报告中标记为超时，流水线继续处理其他文件
+func WithFileTimeout(d time.Duration) Option {
+	return func(e *Engine) {
+		e.fileTimeout = d
+	}
+}
+
+// WithMinify 开启 Prompt 压缩：发送前去除注释、许可证头与空行以节省 Token，
+// 保留的代码行以原始行号为前缀，保证报告中的行号与源文件一致
+func WithMinify() Option {
+	return func(e *Engine) {
+		e.minify = true
+	}
+}
+
+// WithLint 将本地静态检查结果合并到对应文件的审查结果中
+func WithLint(r *lint.Report) Option {
+	return func(e *Engine) {
+		e.lint = r
+	}
+}
+
+// WithLimiter 使用外部共享的限流器（例如并行执行的批量任务共享全局并发上限）
+func WithLimiter(l *Limiter) Option {
+	return func(e *Engine) {
+		e.limiter = l
+	}
 }
 
 // NewEngine 创建一个新的审查引擎
-func NewEngine(client *llm.Client, concurrency, level int) (*Engine, error) {
+func NewEngine(client *llm.Client, concurrency, level int, opts ...Option) (*Engine, error) {
 	if client == nil {
 		return nil, fmt.Errorf("LLM 客户端不能为空")
 	}
@@ -70,11 +247,42 @@ func NewEngine(client *llm.Client, concurrency, level int) (*Engine, error) {
 		level = DefaultLevel
 	}
 
-	return &Engine{
+	e := &Engine{
 		client:      client,
 		concurrency: concurrency,
 		level:       level,
-	}, nil
+		maxFileSize: DefaultMaxFileSize,
+	}
+
+	// 应用选项
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.quota != nil {
+		e.quota.notify = e.notify
+		e.quota.deadline = e.deadline
+	}
+
+	switch {
+	case e.limiter != nil:
+		e.maxConcurrency = e.limiter.MaxLimit()
+	case e.maxConcurrency > 0:
+		e.maxConcurrency = max(e.maxConcurrency, e.concurrency)
+		e.limiter = NewLimiter(e.concurrency, e.maxConcurrency)
+	}
+
+	return e, nil
+}
+
+// MaxFileSize 返回单次请求允许发送的最大文件大小
+func (e *Engine) MaxFileSize() int64 {
+	return e.maxFileSize
+}
+
+// maxReadSize 返回允许读取（分段审查）的最大文件大小
+func (e *Engine) maxReadSize() int64 {
+	return max(MaxChunkedFileSize, e.maxFileSize)
 }
 
 // GetLevel 返回当前审查严格级别
@@ -82,31 +290,98 @@ func (e *Engine) GetLevel() int {
 	return e.level
 }
 
+// CurrentConcurrency 返回当前实际生效的并发数
+func (e *Engine) CurrentConcurrency() int {
+	if e.limiter != nil {
+		return e.limiter.Limit()
+	}
+	return e.concurrency
+}
+
+// workerCount 返回需要启动的 Worker 数量
+// 自适应模式下按上限启动，由 limiter 控制同时执行的数量
+func (e *Engine) workerCount() int {
+	if e.limiter != nil {
+		return e.maxConcurrency
+	}
+	return e.concurrency
+}
+
 // Start 启动审查流程，返回结果 channel
 func (e *Engine) Start(ctx context.Context, files []string) <-chan Result {
-	jobs := make(chan Job, e.concurrency)
-	results := make(chan Result, e.concurrency*2)
+	results := make(chan Result, e.workerCount()*2)
+
+	go func() {
+		// 关闭器：所有阶段完成后关闭 results channel
+		defer close(results)
+
+		if e.prioritize {
+			files = prioritizeFiles(files)
+		}
+
+		// 两阶段模式：先初筛，只有优先级最高的部分进入深度审查
+		if e.triage != nil {
+			files = e.runTriage(ctx, files, results)
+		}
+
+		e.run(ctx, files, results)
+	}()
+
+	return results
+}
+
+// run 启动生产者与 Worker Pool，阻塞直到所有文件处理完毕
+// 因暂时性错误失败的文件会在主队列结束后按重试策略重新审查
+func (e *Engine) run(ctx context.Context, files []string, results chan<- Result) {
+	queue := &retryQueue{}
+	if e.retry.rounds <= 0 {
+		queue = nil
+	}
+
+	jobs := make(chan Job, e.workerCount())
 
 	// 生产者：读取文件并推送到 jobs channel
 	go e.producer(ctx, files, jobs, results)
+	e.consume(ctx, jobs, results, queue)
+
+	// 重试队列：每轮开始前等待（逐轮翻倍），最后一轮的失败结果直接发送
+	backoff := e.retry.backoff
+	for round := 1; queue != nil && round <= e.retry.rounds; round++ {
+		pending := queue.Drain()
+		if len(pending) == 0 || !sleepContext(ctx, e.untilDeadline(backoff)) {
+			return
+		}
+		backoff *= 2
+
+		retryJobs := make(chan Job, len(pending))
+		e.metrics.queued.Add(int64(len(pending)))
+		for _, job := range pending {
+			job.Attempt = round
+			retryJobs <- job
+		}
+		close(retryJobs)
+
+		if round == e.retry.rounds {
+			queue = nil
+		}
+		e.consume(ctx, retryJobs, results, queue)
+	}
+}
+
+// consume 启动 Worker Pool 消费 jobs，阻塞直到 jobs 关闭且所有 Worker 退出
+func (e *Engine) consume(ctx context.Context, jobs <-chan Job, results chan<- Result, queue *retryQueue) {
+	workers := e.workerCount()
 
-	// 消费者：Worker Pool
 	var wg sync.WaitGroup
-	wg.Add(e.concurrency)
-	for i := 0; i < e.concurrency; i++ {
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
 		go func() {
 			defer wg.Done()
-			e.worker(ctx, jobs, results)
+			e.worker(ctx, jobs, results, queue)
 		}()
 	}
 
-	// 关闭器：等待所有 worker 完成后关闭 results channel
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	return results
+	wg.Wait()
 }
 
 // producer 读取文件内容并发送到 jobs channel
@@ -137,10 +412,47 @@ func (e *Engine) producer(ctx context.Context, files []string, jobs chan<- Job,
 			continue
 		}
 
+		// 发送前检测疑似密钥，按策略跳过包含密钥的文件
+		found := e.secrets.scan(content)
+		if e.secrets.blocks(found) {
+			select {
+			case results <- Result{FilePath: file, FileSize: fileSize, SkipReason: SkipReasonSecrets, Secrets: found}:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		// 内容与已登记文件完全相同：不再审查，复用主文件的结果
+		if e.dedupe != nil {
+			if primary, res := e.dedupe.claim(file, content); primary != "" {
+				if res != nil {
+					res.Lint = e.lint.For(file)
+					select {
+					case results <- e.applyConfidence(e.applyImportance(*res)):
+					case <-ctx.Done():
+						return
+					}
+				}
+				continue
+			}
+		}
+
+		// 超过单次请求上限的大文件切分为多个分段
+		job := Job{FilePath: file, Content: content, Secrets: found}
+		if redacted, redactions := e.redactor.Redact(content); len(redactions) > 0 {
+			job.Content, job.Original, job.Redactions = redacted, content, redactions
+		}
+		if fileSize > e.maxFileSize {
+			job.Chunks = splitContent(file, job.Content, int(e.maxFileSize))
+		}
+
 		// 发送任务
+		e.metrics.queued.Add(1)
 		select {
-		case jobs <- Job{FilePath: file, Content: content}:
+		case jobs <- job:
 		case <-ctx.Done():
+			e.metrics.queued.Add(-1)
 			return
 		}
 	}
@@ -161,13 +473,14 @@ func (e *Engine) readFile(path string) (string, int64, SkipReason, error) {
 		return "", 0, SkipReasonReadErr, fmt.Errorf("无法获取文件信息: %w", err)
 	}
 
+	limit := e.maxReadSize()
 	fileSize := info.Size()
-	if fileSize > MaxFileSize {
-		return "", fileSize, SkipReasonTooLarge, fmt.Errorf("文件过大 (%d KB > %d KB)，已跳过", fileSize/1024, MaxFileSize/1024)
+	if fileSize > limit {
+		return "", fileSize, SkipReasonTooLarge, fmt.Errorf("文件过大 (%d KB > %d KB)，已跳过", fileSize/1024, limit/1024)
 	}
 
 	// 使用 LimitReader 防止读取超过限制
-	limitReader := io.LimitReader(f, MaxFileSize+1)
+	limitReader := io.LimitReader(f, limit+1)
 	content, err := io.ReadAll(limitReader)
 	if err != nil {
 		return "", fileSize, SkipReasonReadErr, fmt.Errorf("读取文件失败: %w", err)
@@ -175,16 +488,20 @@ func (e *Engine) readFile(path string) (string, int64, SkipReason, error) {
 
 	// 二次校验：防止 TOCTOU（文件在 Stat 和 Read 之间变大）
 	actualSize := int64(len(content))
-	if actualSize > MaxFileSize {
-		return "", actualSize, SkipReasonTooLarge, fmt.Errorf("文件过大 (%d KB > %d KB)，已跳过", actualSize/1024, MaxFileSize/1024)
+	if actualSize > limit {
+		return "", actualSize, SkipReasonTooLarge, fmt.Errorf("文件过大 (%d KB > %d KB)，已跳过", actualSize/1024, limit/1024)
 	}
 
-	return string(content), actualSize, SkipReasonNone, nil
+	// UTF-16 等编码统一转换为 UTF-8 后再发送
+	return string(textenc.ToUTF8(content)), actualSize, SkipReasonNone, nil
 }
 
 // worker 从 jobs channel 消费任务并执行审查
-func (e *Engine) worker(ctx context.Context, jobs <-chan Job, results chan<- Result) {
+// queue 非空时，因暂时性错误失败的任务进入重试队列而不是直接发送失败结果
+func (e *Engine) worker(ctx context.Context, jobs <-chan Job, results chan<- Result, queue *retryQueue) {
 	for job := range jobs {
+		e.metrics.queued.Add(-1)
+
 		// 检查 context 取消
 		select {
 		case <-ctx.Done():
@@ -192,18 +509,227 @@ func (e *Engine) worker(ctx context.Context, jobs <-chan Job, results chan<- Res
 		default:
 		}
 
-		// 执行审查
-		review, err := e.client.ReviewCode(ctx, job.FilePath, job.Content, e.level)
+		// 预算耗尽或运行超时：不再派发新请求，直接标记为未审查
+		if reason := e.stopReason(); reason != SkipReasonNone {
+			if !e.emit(ctx, results, skippedJob(job, reason)) {
+				return
+			}
+			continue
+		}
+
+		// 配额耗尽时暂停派发，冷却结束后重新审查同一个文件
+		var res Result
+		var timedOut bool
+		for {
+			if !e.quota.wait(ctx) {
+				return
+			}
+			// 冷却期间到达运行截止时间
+			if e.pastDeadline() {
+				res = skippedJob(job, SkipReasonDeadline)
+				break
+			}
+
+			var ok bool
+			if res, timedOut, ok = e.attempt(ctx, job); !ok {
+				return
+			}
+			if e.quota == nil || ctx.Err() != nil || !llm.IsQuotaExhausted(res.Error) || !e.quota.pause(res.Error) {
+				break
+			}
+		}
+		if res.Error == nil && res.SkipReason == SkipReasonNone {
+			e.quota.succeeded()
+		}
+
+		if !timedOut && queue != nil && ctx.Err() == nil && llm.IsTransient(res.Error) {
+			slog.Debug("暂时性错误，加入重试队列", "file", job.FilePath, "attempt", job.Attempt, "err", res.Error)
+			e.metrics.retries.Add(1)
+			e.notify(Event{Kind: EventRetrying, Path: job.FilePath, Attempt: job.Attempt, Err: res.Error})
+			queue.Add(job)
+			continue
+		}
 
 		// 发送结果（检查 context 取消）
+		if !e.emit(ctx, results, res) {
+			return
+		}
+	}
+}
+
+// skippedJob 返回因 reason 未审查的任务结果
+func skippedJob(job Job, reason SkipReason) Result {
+	return Result{FilePath: job.FilePath, FileSize: int64(len(job.Content)), SkipReason: reason, Secrets: job.Secrets}
+}
+
+// emit 发送最终结果，开启去重时一并发送复用该结果的重复文件
+// ctx 取消时返回 false
+func (e *Engine) emit(ctx context.Context, results chan<- Result, res Result) bool {
+	out := []Result{res}
+	if e.dedupe != nil {
+		out = append(out, e.dedupe.complete(res)...)
+	}
+
+	for i, r := range out {
+		// 静态检查结果与路径相关，重复文件使用自身的检查结果
+		if i > 0 {
+			r.Lint = e.lint.For(r.FilePath)
+		}
+		r = e.applyConfidence(e.applyImportance(r))
 		select {
 		case <-ctx.Done():
-			return
-		case results <- Result{
-			FilePath: job.FilePath,
-			Review:   review,
-			Error:    err,
-		}:
+			return false
+		case results <- r:
 		}
+		e.metrics.done.Add(1)
+		if r.Error != nil {
+			e.metrics.failed.Add(1)
+			logResultError(r)
+		}
+	}
+	return true
+}
+
+// logResultError 记录最终失败的文件：因大小、超时等原因跳过的记为信息，其余审查失败记为警告
+func logResultError(r Result) {
+	if r.SkipReason != SkipReasonNone {
+		slog.Info("文件已跳过", "file", r.FilePath, "err", r.Error)
+		return
+	}
+	slog.Warn("文件审查失败", "file", r.FilePath, "retries", r.Retries, "err", r.Error)
+}
+
+// attempt 获取并发名额后审查一次任务，返回结果、是否超时；ctx 取消导致无法获取名额时 ok 为 false
+func (e *Engine) attempt(ctx context.Context, job Job) (res Result, timedOut, ok bool) {
+	// 先获取并发名额（自适应或手动暂停时可能需要等待）
+	if e.limiter != nil {
+		if err := e.acquire(ctx); err != nil {
+			if ctx.Err() != nil {
+				return Result{}, false, false
+			}
+			// 等待期间到达运行截止时间
+			return skippedJob(job, SkipReasonDeadline), false, true
+		}
+	}
+
+	e.notify(Event{Kind: EventFileStarted, Path: job.FilePath, Attempt: job.Attempt})
+	e.metrics.inFlight.Add(1)
+	start := time.Now()
+	res, timedOut = e.reviewWithTimeout(ctx, job)
+	e.metrics.observe(time.Since(start))
+	slog.Debug("审查请求结束", "file", job.FilePath, "duration", time.Since(start).Round(time.Millisecond), "err", res.Error)
+	e.metrics.inFlight.Add(-1)
+
+	if e.limiter != nil {
+		e.limiter.Release(!timedOut && llm.IsThrottled(res.Error))
+	}
+	return res, timedOut, true
+}
+
+// acquire 获取并发名额，设置了运行截止时间时最多等待到截止时间
+func (e *Engine) acquire(ctx context.Context) error {
+	if e.deadline.IsZero() {
+		return e.limiter.Acquire(ctx)
+	}
+	waitCtx, cancel := context.WithDeadline(ctx, e.deadline)
+	defer cancel()
+	return e.limiter.Acquire(waitCtx)
+}
+
+// reviewWithTimeout 在单文件时限内审查任务，超时的文件标记为 SkipReasonTimeout，不进入重试队列
+func (e *Engine) reviewWithTimeout(ctx context.Context, job Job) (Result, bool) {
+	if e.fileTimeout <= 0 {
+		return e.reviewJob(ctx, job), false
+	}
+
+	fileCtx, cancel := context.WithTimeout(ctx, e.fileTimeout)
+	defer cancel()
+
+	res := e.reviewJob(fileCtx, job)
+	if res.Error == nil || ctx.Err() != nil || !errors.Is(fileCtx.Err(), context.DeadlineExceeded) {
+		return res, false
+	}
+
+	res.Review = nil
+	res.FileSize = int64(len(job.Content))
+	res.SkipReason = SkipReasonTimeout
+	res.Error = fmt.Errorf("审查超时 (超过 %s)，已跳过", e.fileTimeout)
+	return res, true
+}
+
+// reviewJob 审查单个任务，大文件逐段审查后合并
+func (e *Engine) reviewJob(ctx context.Context, job Job) Result {
+	res := Result{FilePath: job.FilePath, Retries: job.Attempt, Lint: e.lint.For(job.FilePath), Secrets: job.Secrets, Redactions: job.Redactions}
+	projectContext, _ := e.redactor.Redact(e.projectCtx.ForFile(job.FilePath))
+	req := llm.ReviewRequest{
+		FilePath: job.FilePath,
+		Content:  job.Content,
+		Level:    e.level,
+		Context:  projectContext,
+		Audit:    e.audit,
+	}
+	suppressed := RuleSuppressions(job.original(), e.suppressRules)
+	req.Rules = MatchRules(e.rules, job.FilePath, suppressed)
+
+	if len(job.Chunks) == 0 {
+		if e.minify {
+			req.Content = minifyContent(job.FilePath, job.Content, 1)
+			req.LineNumbered = true
+		}
+		req.Fix = e.fix && !req.LineNumbered
+		res.Review, res.Reverified, res.Error = e.reviewContent(ctx, req)
+		if res.Review != nil && res.Review.Patch != "" {
+			res.Review.Patch = normalizePatch(job.FilePath, job.original(), res.Review.Patch)
+		}
+		res.SuppressedRules = SuppressRuleIssues(res.Review, suppressed)
+		return res
+	}
+
+	reviews := make([]*llm.ReviewResult, len(job.Chunks))
+	for i, chunk := range job.Chunks {
+		chunkReq := req
+		chunkReq.FilePath = chunkLabel(job.FilePath, chunk, i, len(job.Chunks))
+		chunkReq.Content = chunkContent(job.FilePath, chunk, e.minify)
+		chunkReq.LineNumbered = e.minify
+		review, reverified, err := e.reviewContent(ctx, chunkReq)
+		if err != nil {
+			res.Error = fmt.Errorf("分段 %d/%d 审查失败: %w", i+1, len(job.Chunks), err)
+			return res
+		}
+		reviews[i] = review
+		res.Reverified = res.Reverified || reverified
+	}
+
+	res.Review = mergeChunkReviews(job.Chunks, reviews)
+	res.Chunks = len(job.Chunks)
+	res.SuppressedRules = SuppressRuleIssues(res.Review, suppressed)
+	return res
+}
+
+// reviewContent 审查一段代码，低分或低置信度时复核一次
+// 返回：审查结果、是否经过复核、错误
+func (e *Engine) reviewContent(ctx context.Context, req llm.ReviewRequest) (*llm.ReviewResult, bool, error) {
+	review, err := e.client.ReviewCode(ctx, req)
+	if err != nil || !e.needsReverify(review) {
+		return review, false, err
+	}
+
+	// 复核失败时保留初步结论
+	verified, err := e.client.VerifyReview(ctx, req, review)
+	if err != nil {
+		return review, false, nil
+	}
+	return verified, true, nil
+}
+
+// needsReverify 判断审查结果是否需要复核
+// 模型未给出置信度（为 0）时只按分数判断
+func (e *Engine) needsReverify(review *llm.ReviewResult) bool {
+	if !e.reverify.enabled || review == nil {
+		return false
+	}
+	if review.Score < e.reverify.scoreBelow {
+		return true
 	}
+	return review.Confidence > 0 && review.Confidence < e.reverify.confidenceBelow
 }
@@ -0,0 +1,122 @@
+package reviewer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// evalDir 是 reviewer eval 使用的样例集，安全审计的端到端测试复用其中带漏洞的文件
+const evalDir = "../../../testdata/eval"
+
+// runMockEngine 使用 Mock Provider 审查 dir 下的文件，按文件名返回结果
+func runMockEngine(t *testing.T, dir string, names []string, opts ...Option) (map[string]Result, []Result) {
+	t.Helper()
+	client, err := llm.NewMockClient(llm.MockOptions{})
+	if err != nil {
+		t.Fatalf("NewMockClient: %v", err)
+	}
+	engine, err := NewEngine(client, 2, DefaultLevel, opts...)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	files := make([]string, len(names))
+	for i, name := range names {
+		files[i] = filepath.Join(dir, name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	byName := make(map[string]Result)
+	var results []Result
+	for res := range engine.Start(ctx, files) {
+		if res.Error != nil {
+			t.Fatalf("审查 %s 失败: %v", res.FilePath, res.Error)
+		}
+		if res.Review == nil {
+			t.Fatalf("%s 没有审查结果", res.FilePath)
+		}
+		byName[filepath.Base(res.FilePath)] = res
+		results = append(results, res)
+	}
+	if len(results) != len(files) {
+		t.Fatalf("得到 %d 个结果，want %d", len(results), len(files))
+	}
+	return byName, results
+}
+
+// readReport 生成 Markdown 报告并返回其内容
+func readReport(t *testing.T, results []Result, meta ReportMeta) string {
+	t.Helper()
+	path, err := GenerateMarkdownReport(results, time.Second, t.TempDir(), meta)
+	if err != nil {
+		t.Fatalf("GenerateMarkdownReport: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取报告失败: %v", err)
+	}
+	return string(data)
+}
+
+func TestEngineMockReview(t *testing.T) {
+	byName, results := runMockEngine(t, "testdata", []string{"clean.go", "queue.go"})
+
+	clean := byName["clean.go"].Review
+	if clean.Score != 95 || len(clean.Issues) != 0 {
+		t.Errorf("clean.go: score=%d issues=%v, want 95 分且没有问题", clean.Score, clean.Issues)
+	}
+	// queue.go 中有一处 TODO 与一处 panic
+	queue := byName["queue.go"].Review
+	if queue.Score != 80 || len(queue.Issues) != 2 {
+		t.Errorf("queue.go: score=%d issues=%v, want 80 分且有 2 个问题", queue.Score, queue.Issues)
+	}
+
+	report := readReport(t, results, ReportMeta{Name: "mock-review", Level: DefaultLevel})
+	for _, want := range []string{"clean.go", "queue.go", "TODO/FIXME", "panic/os.Exit/eval"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("报告中缺少 %q", want)
+		}
+	}
+}
+
+func TestEngineMockAudit(t *testing.T) {
+	byName, results := runMockEngine(t, evalDir, []string{"sqli.go", "tls.go", "clean.go"}, WithAudit())
+
+	tests := []struct {
+		file string
+		cwe  string
+		line int
+	}{
+		{"sqli.go", "CWE-89", 11},
+		{"tls.go", "CWE-295", 14},
+	}
+	for _, tt := range tests {
+		findings := byName[tt.file].Review.Findings
+		i := slices.IndexFunc(findings, func(f llm.Finding) bool { return f.CWE == tt.cwe })
+		if i < 0 {
+			t.Errorf("%s: 缺少 %s，findings=%+v", tt.file, tt.cwe, findings)
+			continue
+		}
+		if findings[i].Line != tt.line {
+			t.Errorf("%s: %s 位于第 %d 行，want %d", tt.file, tt.cwe, findings[i].Line, tt.line)
+		}
+	}
+	if findings := byName["clean.go"].Review.Findings; len(findings) != 0 {
+		t.Errorf("clean.go: findings=%+v, want 没有漏洞", findings)
+	}
+
+	report := readReport(t, results, ReportMeta{Name: "mock-audit", Level: DefaultLevel, Audit: true})
+	for _, want := range []string{"CWE-89", "CWE-295", "sqli.go"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("报告中缺少 %q", want)
+		}
+	}
+}
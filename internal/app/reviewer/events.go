@@ -0,0 +1,43 @@
+package reviewer
+
+import "time"
+
+// EventKind 表示引擎事件的类型
+type EventKind int
+
+const (
+	// EventPaused 表示 API 配额耗尽，引擎暂停派发新请求
+	EventPaused EventKind = iota + 1
+	// EventResumed 表示冷却结束，引擎恢复派发
+	EventResumed
+	// EventFileStarted 表示已获取并发名额、开始发送文件的审查请求（重试时会再次发送）
+	EventFileStarted
+	// EventRetrying 表示文件因暂时性错误失败，已加入重试队列
+	EventRetrying
+)
+
+// Event 表示引擎运行中的状态变化，通过 WithEventHandler 通知调用方（例如 TUI）
+type Event struct {
+	Kind    EventKind
+	Until   time.Time // EventPaused：预计恢复的时间
+	Err     error     // 触发事件的错误
+	Path    string    // EventFileStarted / EventRetrying：相关的文件
+	Attempt int       // EventFileStarted / EventRetrying：已重试的次数
+}
+
+// WithEventHandler 注册事件回调，回调可能在多个 Worker goroutine 中被调用，需要自行保证并发安全
+func WithEventHandler(fn func(Event)) Option {
+	return func(e *Engine) {
+		e.onEvent = fn
+	}
+}
+
+// notify 记录并发送事件，未注册回调时只记录指标
+func (e *Engine) notify(ev Event) {
+	if ev.Kind == EventPaused {
+		e.metrics.pauses.Add(1)
+	}
+	if e.onEvent != nil {
+		e.onEvent(ev)
+	}
+}
@@ -0,0 +1,64 @@
+package reviewer
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-ai-reviewer/internal/app/patch"
+)
+
+// WithFix 要求模型在审查结果中同时给出修复问题的补丁（unified diff）
+// 补丁只对整体审查的文件请求：分段审查或 Prompt 压缩时模型看不到完整的原文，无法给出可应用的补丁
+func WithFix() Option {
+	return func(e *Engine) {
+		e.fix = true
+	}
+}
+
+// normalizePatch 校验模型给出的补丁能否应用到审查时的文件内容，并修正修改块的行号与行数
+// 无法应用的补丁被丢弃（返回空字符串），保证写入报告旁的补丁都可以直接应用
+func normalizePatch(filePath, content, diff string) string {
+	hunks := patch.ParseHunks(diff)
+	if len(hunks) == 0 {
+		return ""
+	}
+	if _, err := patch.Apply(content, hunks); err != nil {
+		slog.Debug("丢弃无法应用的修复补丁", "file", filePath, "err", err)
+		return ""
+	}
+	return patch.FormatHunks(hunks)
+}
+
+// PatchPath 返回报告对应的修复补丁路径（报告旁的同名 .patch 文件）
+func PatchPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".patch"
+}
+
+// writePatchFile 将所有文件的修复补丁合并写入报告旁的 .patch 文件，返回包含补丁的文件数
+// 没有补丁时删除同名的旧补丁，避免 reviewer fix 应用上一次运行的结果
+func writePatchFile(results []Result, reportPath string) (int, error) {
+	var b strings.Builder
+	count := 0
+	for _, res := range results {
+		if res.Review == nil || res.Review.Patch == "" {
+			continue
+		}
+		b.WriteString(patch.Format(res.FilePath, patch.ParseHunks(res.Review.Patch)))
+		count++
+	}
+
+	path := PatchPath(reportPath)
+	if count == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("删除旧的修复补丁失败: %w", err)
+		}
+		return 0, nil
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return 0, fmt.Errorf("写入修复补丁失败: %w", err)
+	}
+	return count, nil
+}
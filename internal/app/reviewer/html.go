@@ -0,0 +1,166 @@
+package reviewer
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// 行内语法：行内代码、加粗、链接（在 HTML 转义之后匹配）
+var (
+	inlineCodeRegex = regexp.MustCompile("`([^`]+)`")
+	boldRegex       = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	linkRegex       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	headingRegex    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	tableSepRegex   = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// htmlReportStyle 是 HTML 报告的内联样式，保证单文件即可离线查看
+const htmlReportStyle = `body{max-width:960px;margin:2em auto;padding:0 1em;font-family:-apple-system,"Segoe UI","PingFang SC","Microsoft YaHei",sans-serif;line-height:1.6;color:#24292f}
+h1,h2,h3{border-bottom:1px solid #d0d7de;padding-bottom:.3em}
+h3{border-bottom:none}
+table{border-collapse:collapse;margin:1em 0}
+th,td{border:1px solid #d0d7de;padding:6px 13px;text-align:left}
+tr:nth-child(2n){background:#f6f8fa}
+blockquote{margin:1em 0;padding:0 1em;color:#57606a;border-left:.25em solid #d0d7de}
+code{background:#eff1f3;border-radius:6px;padding:.2em .4em;font-size:85%}
+pre{background:#f6f8fa;border-radius:6px;padding:1em;overflow:auto}
+pre code{background:none;padding:0}
+hr{border:0;border-top:1px solid #d0d7de;margin:2em 0}
+a{color:#0969da;text-decoration:none}
+a:hover{text-decoration:underline}`
+
+// RenderHTMLReport 将 Markdown 报告渲染为独立的 HTML 页面
+// 只支持报告中用到的语法：标题、段落、列表、引用、表格、代码块、分隔线与行内格式
+func RenderHTMLReport(markdown, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"zh-CN\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n",
+		html.EscapeString(title), htmlReportStyle)
+	b.WriteString(RenderHTMLBody(markdown))
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// RenderHTMLBody 将 Markdown 报告渲染为页面主体片段（不含 html/head/body），
+// 空元素使用自闭合写法，结果同时是合法的 XHTML，可直接作为 Confluence 的存储格式
+func RenderHTMLBody(markdown string) string {
+	var b strings.Builder
+	renderMarkdownBlocks(&b, strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n"))
+	return b.String()
+}
+
+// renderMarkdownBlocks 逐行识别块级元素并输出 HTML
+func renderMarkdownBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```"):
+			i++
+			b.WriteString("<pre><code>")
+			for ; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				b.WriteString(html.EscapeString(lines[i]) + "\n")
+			}
+			b.WriteString("</code></pre>\n")
+			i++ // 跳过结束标记
+
+		case trimmed == "---" || trimmed == "***":
+			b.WriteString("<hr />\n")
+			i++
+
+		case headingRegex.MatchString(trimmed):
+			m := headingRegex.FindStringSubmatch(trimmed)
+			fmt.Fprintf(b, "<h%d>%s</h%d>\n", len(m[1]), renderInline(m[2]), len(m[1]))
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			b.WriteString("<blockquote>\n")
+			renderMarkdownBlocks(b, quote)
+			b.WriteString("</blockquote>\n")
+
+		case isListItem(trimmed):
+			b.WriteString("<ul>\n")
+			for ; i < len(lines) && isListItem(strings.TrimSpace(lines[i])); i++ {
+				fmt.Fprintf(b, "<li>%s</li>\n", renderInline(strings.TrimSpace(lines[i])[2:]))
+			}
+			b.WriteString("</ul>\n")
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableSepRegex.MatchString(strings.TrimSpace(lines[i+1])):
+			b.WriteString("<table>\n<thead>\n")
+			writeTableRow(b, trimmed, "th")
+			b.WriteString("</thead>\n<tbody>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				writeTableRow(b, strings.TrimSpace(lines[i]), "td")
+			}
+			b.WriteString("</tbody>\n</table>\n")
+
+		default:
+			// 首行总是属于段落（如没有分隔行的 | 开头的行），保证循环前进
+			para := []string{renderInline(trimmed)}
+			for i++; i < len(lines) && isParagraphLine(lines, i); i++ {
+				para = append(para, renderInline(strings.TrimSpace(lines[i])))
+			}
+			fmt.Fprintf(b, "<p>%s</p>\n", strings.Join(para, "<br />\n"))
+		}
+	}
+}
+
+// isListItem 判断是否为无序列表项
+func isListItem(line string) bool {
+	return strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")
+}
+
+// isParagraphLine 判断第 i 行是否属于普通段落（不是空行或其他块级元素的开头）
+func isParagraphLine(lines []string, i int) bool {
+	trimmed := strings.TrimSpace(lines[i])
+	return trimmed != "" && trimmed != "---" && trimmed != "***" &&
+		!strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, ">") &&
+		!strings.HasPrefix(trimmed, "|") && !isListItem(trimmed) && !headingRegex.MatchString(trimmed)
+}
+
+// writeTableRow 输出表格的一行，tag 为 th 或 td
+func writeTableRow(b *strings.Builder, line, tag string) {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	b.WriteString("<tr>")
+	for _, cell := range strings.Split(line, "|") {
+		fmt.Fprintf(b, "<%s>%s</%s>", tag, renderInline(strings.TrimSpace(cell)), tag)
+	}
+	b.WriteString("</tr>\n")
+}
+
+// renderInline 渲染行内格式；行内代码中的内容不再解析其他语法
+func renderInline(text string) string {
+	var b strings.Builder
+	text = html.EscapeString(text)
+	for {
+		loc := inlineCodeRegex.FindStringSubmatchIndex(text)
+		if loc == nil {
+			b.WriteString(renderEmphasis(text))
+			return b.String()
+		}
+		b.WriteString(renderEmphasis(text[:loc[0]]))
+		b.WriteString("<code>" + text[loc[2]:loc[3]] + "</code>")
+		text = text[loc[1]:]
+	}
+}
+
+// renderEmphasis 渲染加粗与链接（输入已转义）；问题描述来自模型输出，不渲染 javascript: 链接
+func renderEmphasis(text string) string {
+	text = boldRegex.ReplaceAllString(text, "<strong>$1</strong>")
+	return linkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		m := linkRegex.FindStringSubmatch(link)
+		if strings.HasPrefix(strings.ToLower(m[2]), "javascript:") {
+			return m[1]
+		}
+		return `<a href="` + m[2] + `">` + m[1] + `</a>`
+	})
+}
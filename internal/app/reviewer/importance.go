@@ -0,0 +1,127 @@
+package reviewer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ImportanceRule 按路径覆盖或限制模型给出的重要性
+// Min 与 Max 相等时为固定值，否则将重要性限制在 [Min, Max] 范围内
+type ImportanceRule struct {
+	// Pattern 是相对于执行目录的路径模式（不区分大小写），支持 *、? 与匹配任意层目录的 **；
+	// 不含 / 时匹配任意目录下的文件名，以 / 结尾时匹配该目录下的所有文件
+	Pattern string
+	Min     float64
+	Max     float64
+}
+
+// Validate 校验规则的模式与取值范围
+func (r ImportanceRule) Validate() error {
+	if strings.TrimSpace(r.Pattern) == "" {
+		return fmt.Errorf("路径模式不能为空")
+	}
+	for _, seg := range strings.Split(r.Pattern, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("无效的路径模式 %q: %w", r.Pattern, err)
+		}
+	}
+	if r.Min < 0 || r.Max > 1 || r.Min > r.Max {
+		return fmt.Errorf("%s 的重要性应在 0-1 之间且 min 不大于 max", r.Pattern)
+	}
+	return nil
+}
+
+// apply 返回按规则调整后的重要性
+func (r ImportanceRule) apply(importance float64) float64 {
+	return min(max(importance, r.Min), r.Max)
+}
+
+// label 返回规则在报告中的说明（Markdown）
+func (r ImportanceRule) label() string {
+	if r.Min == r.Max {
+		return "`" + r.Pattern + "`"
+	}
+	return fmt.Sprintf("`%s` 限制为 %.1f-%.1f", r.Pattern, r.Min, r.Max)
+}
+
+// WithImportanceRules 按路径规则覆盖或限制模型给出的重要性（影响加权综合评分与报告排序）
+// 多条规则匹配同一文件时使用最具体（通配符以外的字符最多）的一条
+func WithImportanceRules(rules []ImportanceRule) Option {
+	return func(e *Engine) {
+		e.importance = append([]ImportanceRule(nil), rules...)
+		sort.SliceStable(e.importance, func(i, j int) bool {
+			a, b := e.importance[i].Pattern, e.importance[j].Pattern
+			if la, lb := literalLength(a), literalLength(b); la != lb {
+				return la > lb
+			}
+			return a < b
+		})
+	}
+}
+
+// literalLength 返回模式中通配符以外的字符数，用于衡量规则的具体程度
+func literalLength(pattern string) int {
+	return len(pattern) - strings.Count(pattern, "*") - strings.Count(pattern, "?")
+}
+
+// applyImportance 按路径规则调整结果的重要性，返回的结果不与 res 共享审查结论
+// （重复文件复用主文件的审查结论，各自按路径调整）
+func (e *Engine) applyImportance(res Result) Result {
+	if res.Review == nil || len(e.importance) == 0 {
+		return res
+	}
+	key := baselineKey(res.FilePath)
+	for i, rule := range e.importance {
+		if !matchPathPattern(rule.Pattern, key) {
+			continue
+		}
+		review := *res.Review
+		review.Importance = rule.apply(review.Importance)
+		res.Review = &review
+		res.ImportanceRule = &e.importance[i]
+		break
+	}
+	return res
+}
+
+// matchPathPattern 判断以 / 分隔的相对路径是否匹配模式（不区分大小写）
+func matchPathPattern(pattern, p string) bool {
+	pattern = strings.ToLower(strings.TrimPrefix(pattern, "./"))
+	p = strings.ToLower(p)
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+// matchSegments 逐段匹配路径，** 匹配零个或多个目录
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return true
+			}
+			for i := range parts {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
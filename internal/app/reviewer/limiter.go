@@ -0,0 +1,156 @@
+package reviewer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 自适应并发常量
+const (
+	// MinConcurrency 是自适应并发的下限
+	MinConcurrency = 1
+	// limiterDecreaseCooldown 是两次降低并发之间的最小间隔，避免同一波失败把并发压到底
+	limiterDecreaseCooldown = 2 * time.Second
+)
+
+// Limiter 基于 AIMD（加性增、乘性减）动态调整并发上限
+// 出现限流/超时时并发减半，连续成功达到当前上限次数后并发加一
+// 多个引擎共享同一个 Limiter 时，并发上限对所有任务全局生效
+// 运行期间可以暂停派发或手动调整并发（如进度界面的 p、+、- 按键）
+type Limiter struct {
+	mu           sync.Mutex
+	limit        int
+	minLimit     int
+	maxLimit     int
+	ceiling      int  // 自适应增长不超过的上限，手动调整并发后等于调整后的值
+	adaptive     bool // 是否根据限流情况自动调整并发
+	paused       bool // 暂停派发：已在执行的请求继续完成，新请求等待恢复
+	inFlight     int
+	successes    int
+	lastDecrease time.Time
+	changed      chan struct{} // 状态变化时关闭并替换，用于唤醒等待者
+}
+
+// NewLimiter 创建自适应限流器，初始并发为 initial，上限为 maxLimit
+func NewLimiter(initial, maxLimit int) *Limiter {
+	if maxLimit < MinConcurrency {
+		maxLimit = MinConcurrency
+	}
+	if initial > maxLimit {
+		initial = maxLimit
+	}
+	if initial < MinConcurrency {
+		initial = MinConcurrency
+	}
+
+	return &Limiter{
+		limit:    initial,
+		minLimit: MinConcurrency,
+		maxLimit: maxLimit,
+		ceiling:  maxLimit,
+		adaptive: true,
+		changed:  make(chan struct{}),
+	}
+}
+
+// NewFixedLimiter 创建不自动调整的限流器，并发固定为 initial，只能手动调整，最大为 maxLimit
+func NewFixedLimiter(initial, maxLimit int) *Limiter {
+	l := NewLimiter(initial, maxLimit)
+	l.adaptive = false
+	return l
+}
+
+// Acquire 获取一个并发名额，名额不足时阻塞直到释放或 ctx 取消
+func (l *Limiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if !l.paused && l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		ch := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+	}
+}
+
+// Release 释放名额，并根据本次请求是否被限流调整并发上限
+func (l *Limiter) Release(throttled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+
+	switch {
+	case !l.adaptive:
+		// 固定并发：只能通过 SetLimit 手动调整
+	case throttled:
+		l.successes = 0
+		if time.Since(l.lastDecrease) >= limiterDecreaseCooldown {
+			l.limit = max(l.minLimit, l.limit/2)
+			l.lastDecrease = time.Now()
+		}
+	default:
+		l.successes++
+		if l.successes >= l.limit && l.limit < l.ceiling {
+			l.limit++
+			l.successes = 0
+		}
+	}
+
+	l.broadcast()
+}
+
+// MaxLimit 返回并发上限的最大值
+func (l *Limiter) MaxLimit() int {
+	return l.maxLimit
+}
+
+// Limit 返回当前并发上限
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetLimit 手动设置并发上限（限制在 MinConcurrency 到 MaxLimit 之间），返回实际生效的值
+// 自适应模式下此后的自动增长不会超过该值，限流时仍会自动收缩
+func (l *Limiter) SetLimit(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = min(max(n, l.minLimit), l.maxLimit)
+	l.ceiling = l.limit
+	l.successes = 0
+	l.broadcast()
+	return l.limit
+}
+
+// SetPaused 暂停或恢复派发新请求，暂停期间 Acquire 阻塞，已在执行的请求不受影响
+func (l *Limiter) SetPaused(paused bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.paused = paused
+	l.broadcast()
+}
+
+// Paused 返回是否已暂停派发
+func (l *Limiter) Paused() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.paused
+}
+
+// broadcast 唤醒所有等待者（调用方需持有锁）
+func (l *Limiter) broadcast() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
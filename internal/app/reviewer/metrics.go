@@ -0,0 +1,79 @@
+package reviewer
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metrics 记录引擎运行指标，用于排查大仓库审查的吞吐问题（并发安全）
+type metrics struct {
+	queued   atomic.Int64 // 已读取、等待 Worker 处理的任务数
+	inFlight atomic.Int64 // 正在请求 LLM 的任务数
+	done     atomic.Int64 // 已得到最终结果的文件数
+	failed   atomic.Int64 // 最终失败的文件数
+	retries  atomic.Int64 // 进入重试队列的次数
+	pauses   atomic.Int64 // 因配额耗尽暂停的次数
+
+	mu        sync.Mutex
+	latencies []time.Duration // 每次审查（含分段与复核）的耗时
+}
+
+// MetricsSnapshot 是某一时刻的引擎指标快照
+type MetricsSnapshot struct {
+	QueueDepth  int64 `json:"queue_depth"`
+	InFlight    int64 `json:"in_flight"`
+	Concurrency int   `json:"concurrency"`
+	Completed   int64 `json:"completed"`
+	Failed      int64 `json:"failed"`
+	Retries     int64 `json:"retries"`
+	Pauses      int64 `json:"pauses"`
+
+	// 单文件审查耗时分布
+	LatencyP50 time.Duration `json:"latency_p50"`
+	LatencyP90 time.Duration `json:"latency_p90"`
+	LatencyP99 time.Duration `json:"latency_p99"`
+	LatencyMax time.Duration `json:"latency_max"`
+}
+
+// observe 记录一次审查的耗时
+func (m *metrics) observe(d time.Duration) {
+	m.mu.Lock()
+	m.latencies = append(m.latencies, d)
+	m.mu.Unlock()
+}
+
+// Metrics 返回引擎当前的运行指标
+func (e *Engine) Metrics() MetricsSnapshot {
+	m := &e.metrics
+	s := MetricsSnapshot{
+		QueueDepth:  m.queued.Load(),
+		InFlight:    m.inFlight.Load(),
+		Concurrency: e.CurrentConcurrency(),
+		Completed:   m.done.Load(),
+		Failed:      m.failed.Load(),
+		Retries:     m.retries.Load(),
+		Pauses:      m.pauses.Load(),
+	}
+
+	m.mu.Lock()
+	sorted := append([]time.Duration(nil), m.latencies...)
+	m.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return s
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	s.LatencyP50 = percentile(sorted, 0.50)
+	s.LatencyP90 = percentile(sorted, 0.90)
+	s.LatencyP99 = percentile(sorted, 0.99)
+	s.LatencyMax = sorted[len(sorted)-1]
+	return s
+}
+
+// percentile 返回已排序耗时的 p 分位数（最近秩法）
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[min(max(idx, 0), len(sorted)-1)]
+}
@@ -0,0 +1,135 @@
+package reviewer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// commentStyle 描述一种语言的注释语法
+type commentStyle int
+
+const (
+	commentNone commentStyle = iota // 不识别注释，仅去除空行
+	commentC                        // // 行注释与 /* */ 块注释
+	commentHash                     // # 行注释
+)
+
+// commentStyles 按扩展名映射注释语法
+var commentStyles = map[string]commentStyle{
+	".go": commentC, ".js": commentC, ".jsx": commentC, ".ts": commentC, ".tsx": commentC,
+	".mjs": commentC, ".cjs": commentC, ".java": commentC, ".kt": commentC, ".scala": commentC,
+	".c": commentC, ".h": commentC, ".cc": commentC, ".cpp": commentC, ".hpp": commentC,
+	".cs": commentC, ".rs": commentC, ".swift": commentC, ".dart": commentC, ".php": commentC,
+	".py": commentHash, ".rb": commentHash, ".sh": commentHash, ".bash": commentHash,
+	".pl": commentHash, ".r": commentHash, ".yaml": commentHash, ".yml": commentHash, ".toml": commentHash,
+}
+
+// keepCommentRegex 匹配需要保留的注释：编译指令、shebang 以及 TODO/FIXME 等标记
+var keepCommentRegex = regexp.MustCompile(`^(//go:|// \+build|#!|# -\*-)|\b(TODO|FIXME|HACK|XXX|BUG)\b`)
+
+// minifyContent 去除注释（含许可证头）与空行以节省 Token
+// 保留的每一行都以原始行号为前缀（startLine 为 content 第一行在文件中的行号），
+// 保证模型报告的行号仍与源文件对应
+func minifyContent(filePath, content string, startLine int) string {
+	style := commentStyles[strings.ToLower(filepath.Ext(filePath))]
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	width := len(fmt.Sprint(startLine + len(lines) - 1))
+
+	var b strings.Builder
+	var state cState
+	for i, line := range lines {
+		var code string
+		switch style {
+		case commentC:
+			code = state.strip(line)
+		case commentHash:
+			code = stripHashComment(line)
+		default:
+			code = line
+		}
+
+		code = strings.TrimRight(code, " \t\r")
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%*d| %s\n", width, startLine+i, code)
+	}
+	return b.String()
+}
+
+// cState 记录 C 风格注释扫描中跨行的状态
+type cState struct {
+	inBlock bool // 处于 /* */ 块注释中
+	inRaw   bool // 处于可跨行的反引号字符串中（Go 原始字符串、JS 模板字符串）
+}
+
+// strip 去除一行中的 // 与 /* */ 注释，跳过字符串字面量中的注释符号
+func (s *cState) strip(line string) string {
+	if !s.inBlock && !s.inRaw && keepCommentRegex.MatchString(strings.TrimSpace(line)) {
+		return line
+	}
+
+	var b strings.Builder
+	var quote byte
+	if s.inRaw {
+		quote = '`'
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case s.inBlock:
+			if c == '*' && i+1 < len(line) && line[i+1] == '/' {
+				s.inBlock = false
+				i++
+			}
+		case quote != 0:
+			b.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(line) {
+				b.WriteByte(line[i+1])
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			s.inRaw = false
+			return b.String()
+		case c == '/' && i+1 < len(line) && line[i+1] == '*':
+			s.inBlock = true
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	s.inRaw = quote == '`'
+	return b.String()
+}
+
+// stripHashComment 去除一行中的 # 注释，跳过字符串字面量中的 #
+func stripHashComment(line string) string {
+	if keepCommentRegex.MatchString(strings.TrimSpace(line)) {
+		return line
+	}
+
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
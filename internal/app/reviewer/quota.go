@@ -0,0 +1,98 @@
+package reviewer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 配额暂停默认值
+const (
+	// DefaultQuotaCooldown 是配额耗尽后暂停派发的默认时长
+	DefaultQuotaCooldown = 5 * time.Minute
+	// DefaultQuotaMaxPauses 是连续暂停的默认上限，超过后按普通失败处理
+	DefaultQuotaMaxPauses = 3
+)
+
+// quotaPause 在 API 配额耗尽时暂停所有 Worker 的派发，冷却结束后自动恢复（并发安全）
+type quotaPause struct {
+	cooldown  time.Duration
+	maxPauses int
+	notify    func(Event)
+	deadline  time.Time // 运行截止时间，冷却等待不会超过该时间
+
+	mu      sync.Mutex
+	until   time.Time // 暂停截止时间
+	pauses  int       // 连续暂停次数，审查成功后清零
+	resumed bool      // 本次暂停的恢复事件是否已发送
+}
+
+// WithQuotaPause 开启配额暂停：遇到配额耗尽错误时暂停派发 cooldown 后重新审查该文件，
+// 连续暂停超过 maxPauses 次后不再等待，文件按普通失败处理
+func WithQuotaPause(cooldown time.Duration, maxPauses int) Option {
+	return func(e *Engine) {
+		if cooldown <= 0 {
+			cooldown = DefaultQuotaCooldown
+		}
+		if maxPauses <= 0 {
+			maxPauses = DefaultQuotaMaxPauses
+		}
+		e.quota = &quotaPause{cooldown: cooldown, maxPauses: maxPauses, resumed: true}
+	}
+}
+
+// pause 因配额耗尽暂停派发，返回调用方是否应在冷却后重试该文件
+// 其他 Worker 已触发暂停时只等待，不重复计数
+func (q *quotaPause) pause(err error) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if time.Now().Before(q.until) {
+		return true
+	}
+	if q.pauses >= q.maxPauses {
+		return false
+	}
+
+	q.pauses++
+	q.until = time.Now().Add(q.cooldown)
+	q.resumed = false
+	q.notify(Event{Kind: EventPaused, Until: q.until, Err: err})
+	return true
+}
+
+// wait 阻塞直到暂停结束，ctx 取消时返回 false；q 为空时立即返回
+func (q *quotaPause) wait(ctx context.Context) bool {
+	if q == nil {
+		return true
+	}
+
+	q.mu.Lock()
+	until := q.until
+	q.mu.Unlock()
+	if !q.deadline.IsZero() && q.deadline.Before(until) {
+		until = q.deadline
+	}
+
+	if !sleepContext(ctx, time.Until(until)) {
+		return false
+	}
+
+	q.mu.Lock()
+	if !q.resumed && !time.Now().Before(q.until) {
+		q.resumed = true
+		q.notify(Event{Kind: EventResumed})
+	}
+	q.mu.Unlock()
+	return true
+}
+
+// succeeded 在审查成功后清零连续暂停次数
+func (q *quotaPause) succeeded() {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.pauses = 0
+	q.mu.Unlock()
+}
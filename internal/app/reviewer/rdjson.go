@@ -0,0 +1,131 @@
+package reviewer
+
+import (
+	"encoding/json"
+	"io"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// rdjsonSourceName 是 reviewdog 评论中显示的工具名称
+const rdjsonSourceName = "go-ai-reviewer"
+
+// rdjsonSeverities 是严重程度对应的 reviewdog 严重程度
+var rdjsonSeverities = map[llm.Severity]string{
+	llm.SeverityCritical: "ERROR",
+	llm.SeverityMajor:    "WARNING",
+	llm.SeverityMinor:    "INFO",
+}
+
+// RDDiagnostic 及以下类型是 Reviewdog Diagnostic Format (rdjson / rdjsonl) 中用到的部分结构
+type RDDiagnostic struct {
+	Message  string     `json:"message"`
+	Location rdLocation `json:"location"`
+	Severity string     `json:"severity"`
+	Source   rdSource   `json:"source"`
+	Code     *rdCode    `json:"code,omitempty"`
+}
+
+type rdLocation struct {
+	Path  string   `json:"path"`
+	Range *rdRange `json:"range,omitempty"`
+}
+
+type rdRange struct {
+	Start rdPosition `json:"start"`
+}
+
+type rdPosition struct {
+	Line int `json:"line"`
+}
+
+type rdSource struct {
+	Name string `json:"name"`
+}
+
+type rdCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// NewRDDiagnostics 将报告中显示的问题转换为 reviewdog 诊断（遵循 meta.MinSeverity）：
+// 路径相对于当前目录，未引用行号或行号超出文件范围的问题不带行号范围（reviewdog 按文件级问题处理）
+// 安全审计的漏洞以 CWE 编号为诊断代码并链接到说明页面，普通问题以引用的团队规则编号为诊断代码
+func NewRDDiagnostics(results []Result, meta ReportMeta) []RDDiagnostic {
+	var diagnostics []RDDiagnostic
+	for _, res := range results {
+		if res.Review == nil || res.Error != nil {
+			continue
+		}
+		path := baselineKey(res.FilePath)
+		lines := countFileLines(res.FilePath)
+
+		if len(res.Review.Findings) > 0 {
+			for _, f := range filterFindings(res.Review.Findings, meta.MinSeverity) {
+				d := newRDDiagnostic(path, f.Line, lines, f.Level(), f.Title)
+				if f.Description != "" {
+					d.Message += "\n\n" + f.Description
+				}
+				if f.Remediation != "" {
+					d.Message += "\n\n修复方法: " + f.Remediation
+				}
+				if f.CWE != "" {
+					d.Code = &rdCode{Value: f.CWE, URL: llm.CWEURL(f.CWE)}
+				}
+				diagnostics = append(diagnostics, d)
+			}
+			continue
+		}
+
+		for _, issue := range llm.FilterIssues(res.Review.Issues, meta.MinSeverity) {
+			s, text := llm.SplitIssue(issue)
+			if s == llm.SeverityUnknown {
+				s = llm.SeverityMajor
+			}
+			d := newRDDiagnostic(path, llm.IssueLine(text), lines, s, text)
+			if rule := llm.IssueRule(issue); rule != "" {
+				d.Code = &rdCode{Value: rule}
+			}
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics
+}
+
+// newRDDiagnostic 创建诊断，行号为 0 或超出文件的 lines 行时不带行号范围
+func newRDDiagnostic(path string, line, lines int, s llm.Severity, message string) RDDiagnostic {
+	d := RDDiagnostic{
+		Message:  message,
+		Location: rdLocation{Path: path},
+		Severity: rdjsonSeverities[s],
+		Source:   rdSource{Name: rdjsonSourceName},
+	}
+	if line > 0 && line <= lines {
+		d.Location.Range = &rdRange{Start: rdPosition{Line: line}}
+	}
+	return d
+}
+
+// WriteRDJSON 以 rdjson 格式（一个 DiagnosticResult 对象）写入诊断，供 reviewdog -f=rdjson 读取
+func WriteRDJSON(w io.Writer, diagnostics []RDDiagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []RDDiagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Source      rdSource       `json:"source"`
+		Diagnostics []RDDiagnostic `json:"diagnostics"`
+	}{rdSource{Name: rdjsonSourceName}, diagnostics})
+}
+
+// WriteRDJSONL 以 rdjsonl 格式（每行一个 Diagnostic）写入诊断，供 reviewdog -f=rdjsonl 读取
+func WriteRDJSONL(w io.Writer, diagnostics []RDDiagnostic) error {
+	enc := json.NewEncoder(w)
+	for _, d := range diagnostics {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,11 +3,16 @@ package reviewer
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	"go-ai-reviewer/internal/app/duplicates"
+	"go-ai-reviewer/internal/app/lint"
+	"go-ai-reviewer/internal/llm"
 )
 
 // 评分阈值常量
@@ -17,6 +22,29 @@ const (
 	DirPermission      = 0755
 )
 
+// skipReasonTexts 是报告中需要单独列出的跳过原因及其说明
+var skipReasonTexts = map[SkipReason]string{
+	SkipReasonTooLarge:  "文件过大",
+	SkipReasonGenerated: "生成代码",
+	SkipReasonVendored:  "第三方代码",
+	SkipReasonMinified:  "压缩/打包产物",
+	SkipReasonBudget:    "Token 预算耗尽",
+	SkipReasonTimeout:   "审查超时",
+	SkipReasonDeadline:  "运行超时",
+	SkipReasonSecrets:   "包含疑似密钥",
+}
+
+// Text 返回跳过原因的说明，无需单独列出的原因返回空字符串
+func (r SkipReason) Text() string {
+	return skipReasonTexts[r]
+}
+
+// isListedSkip 判断结果是否属于需要在跳过列表中展示的文件
+func isListedSkip(res Result) bool {
+	_, ok := skipReasonTexts[res.SkipReason]
+	return ok
+}
+
 // 级别名称映射
 var levelNames = map[int]string{
 	1: "宽松模式",
@@ -27,10 +55,57 @@ var levelNames = map[int]string{
 	6: "极致模式",
 }
 
+// ReportMeta 描述报告的元信息
+type ReportMeta struct {
+	Name       string // 报告名称（为空时使用时间戳）
+	Level      int    // 审查严格级别
+	Partial    bool   // 是否为中断后生成的部分报告
+	Unreviewed int    // 未审查的文件数（部分报告时有效）
+	Tokens     int64  // 本次审查累计消耗的 Token 数（0 表示未统计）
+
+	// MinSeverity 只输出不低于该严重程度的问题，SeverityUnknown 表示不过滤
+	MinSeverity llm.Severity
+
+	// MinConfidence 是审查时丢弃低置信度问题的阈值（见 WithMinConfidence），0 表示未过滤
+	MinConfidence float64
+
+	// Focus 是本次审查的重点（见 llm.ParseFocus），为空表示全面审查
+	Focus []string
+
+	// Frameworks 是从依赖清单识别到的项目框架，已作为上下文告知模型
+	Frameworks []string
+
+	// Conventions 是注入系统提示的团队规范文件（如 "CONVENTIONS.md (已截断)"），为空表示未使用
+	Conventions string
+
+	// Rules 是本次审查使用的团队规则（见 WithRules），报告中按规则统计违反次数
+	Rules []llm.Rule
+
+	Metrics *MetricsSnapshot // 引擎运行指标，为空时不输出
+
+	// Baseline 是与基线的对比结果，为空表示未使用基线
+	Baseline *BaselineSummary
+
+	// Audit 表示安全审计报告：按漏洞类别汇总，并在报告旁生成 SARIF 文件
+	Audit bool
+
+	// Sonar 表示在报告旁生成 SonarQube 通用问题导入格式的 .sonar.json 文件
+	Sonar bool
+
+	// Duplicates 是本地检测到的重复代码（见 duplicates.Detect），作为项目级问题写入报告
+	Duplicates []duplicates.Clone
+
+	// TestGaps 是没有对应测试文件的重要文件（见 FindTestGaps），作为项目级问题写入报告
+	TestGaps []TestGap
+
+	// Summary 是模型根据汇总数据生成的执行摘要（见 Engine.ExecutiveSummary），写在报告最前面
+	Summary *llm.ExecutiveSummary
+}
+
 // GenerateMarkdownReport 生成 Markdown 格式的审查报告
-func GenerateMarkdownReport(results []Result, duration time.Duration, outputDir, customName string, level int) (string, error) {
+func GenerateMarkdownReport(results []Result, duration time.Duration, outputDir string, meta ReportMeta) (string, error) {
 	// 1. 验证并清理文件名（防止路径遍历）
-	reportFileName := sanitizeFileName(customName)
+	reportFileName := sanitizeFileName(meta.Name)
 
 	// 2. 构建报告路径
 	reportPath := filepath.Join(outputDir, reportFileName)
@@ -47,20 +122,68 @@ func GenerateMarkdownReport(results []Result, duration time.Duration, outputDir,
 	}
 	defer f.Close()
 
-	// 5. 计算统计数据
-	stats, skippedFiles := calculateStats(results)
+	// 5. 计算统计数据，并将修复补丁、SARIF 与 SonarQube 导入文件写入报告旁的同名文件
+	stats, skippedFiles := calculateStats(results, meta.MinSeverity)
+	if stats.Patches, err = writePatchFile(results, reportPath); err != nil {
+		return "", err
+	}
+	if stats.Findings, err = writeSARIFFile(results, reportPath, meta); err != nil {
+		return "", err
+	}
+	if stats.SonarIssues, err = writeSonarFile(results, reportPath, meta); err != nil {
+		return "", err
+	}
+	ruleCounts := countRuleViolations(results, meta.Rules, meta.MinSeverity)
+	for _, c := range ruleCounts {
+		stats.RuleViolations += c.issues
+	}
 
 	// 6. 写入报告内容
 	displayName := strings.TrimSuffix(reportFileName, ".md")
-	writeReportHeader(f, displayName, stats, level, duration, len(results))
+	writeReportHeader(f, displayName, stats, meta, duration, len(results))
 
 	// 7. 写入跳过的文件列表（如果有）
 	if len(skippedFiles) > 0 {
 		writeSkippedFiles(f, skippedFiles, outputDir)
 	}
 
-	// 8. 写入详细审查结果
-	writeReportDetails(f, results, outputDir)
+	// 8. 写入只经过初筛的文件（两阶段模式）
+	if stats.TriagedFiles > 0 {
+		writeTriageResults(f, results, outputDir)
+	}
+
+	// 9. 写入发送前检测到的疑似敏感信息
+	if stats.SecretFiles > 0 {
+		writeSecretFindings(f, results, outputDir, stats.Secrets)
+	}
+
+	// 10. 写入重复代码
+	if len(meta.Duplicates) > 0 {
+		writeDuplicates(f, meta.Duplicates, outputDir)
+	}
+
+	// 11. 写入缺少测试的重要文件
+	if len(meta.TestGaps) > 0 {
+		writeTestGaps(f, meta.TestGaps, outputDir)
+	}
+
+	// 12. 写入按团队规则的违反统计
+	if len(meta.Rules) > 0 {
+		writeRuleSummary(f, ruleCounts, stats.SuppressedRules)
+	}
+
+	// 13. 写入已解决的基线问题
+	if meta.Baseline != nil && len(meta.Baseline.Resolved) > 0 {
+		writeResolvedIssues(f, meta.Baseline.Resolved, outputDir)
+	}
+
+	// 14. 写入按漏洞类别的汇总（安全审计）
+	if meta.Audit {
+		writeAuditSummary(f, summarizeFindings(results, meta.MinSeverity))
+	}
+
+	// 15. 写入详细审查结果
+	writeReportDetails(f, results, outputDir, meta.MinSeverity)
 
 	return reportPath, nil
 }
@@ -103,7 +226,19 @@ type reportStats struct {
 	TotalFiles      int
 	ValidFiles      int
 	SkippedFiles    int // 跳过的文件数
+	TriagedFiles    int // 只经过初筛的文件数
 	TotalImportance float64
+	HiddenIssues    int // 低于 MinSeverity 而未输出的问题数
+	LowConfidence   int // 置信度低于 MinConfidence 而丢弃的问题数
+	SuppressedRules int // 引用了被抑制的规则而丢弃的问题数
+	RuleViolations  int // 引用了团队规则的问题数
+	Patches         int // 生成了修复补丁的文件数
+	Findings        int // 写入 SARIF 的安全漏洞数（安全审计）
+	SonarIssues     int // 写入 SonarQube 导入文件的问题数
+	SecretFiles     int // 检测到疑似敏感信息的文件数
+	Secrets         int // 疑似敏感信息的总处数
+	RedactedFiles   int // 发送前遮盖过内容的文件数
+	Redactions      int // 发送前遮盖的总处数
 }
 
 // skippedFileInfo 跳过文件的信息
@@ -114,7 +249,7 @@ type skippedFileInfo struct {
 }
 
 // calculateStats 计算报告统计数据
-func calculateStats(results []Result) (reportStats, []skippedFileInfo) {
+func calculateStats(results []Result, minSeverity llm.Severity) (reportStats, []skippedFileInfo) {
 	var stats reportStats
 	var totalScore float64
 	var skippedFiles []skippedFileInfo
@@ -122,24 +257,35 @@ func calculateStats(results []Result) (reportStats, []skippedFileInfo) {
 	for _, res := range results {
 		stats.TotalFiles++
 
-		// 检查是否是跳过的大文件
-		if res.SkipReason == SkipReasonTooLarge {
+		// 检查是否是跳过的文件（过大、生成代码、第三方代码）
+		if isListedSkip(res) {
 			stats.SkippedFiles++
 			skippedFiles = append(skippedFiles, skippedFileInfo{
 				FilePath: res.FilePath,
 				FileSize: res.FileSize,
-				Reason:   "文件过大",
+				Reason:   skipReasonTexts[res.SkipReason],
 			})
 			continue
 		}
 
+		if res.Triage != nil {
+			stats.TriagedFiles++
+			continue
+		}
+
 		if res.Error == nil && res.Review != nil {
 			totalScore += float64(res.Review.Score) * res.Review.Importance
 			stats.TotalImportance += res.Review.Importance
 			stats.ValidFiles++
+			stats.HiddenIssues += len(res.Review.Issues) - len(llm.FilterIssues(res.Review.Issues, minSeverity))
+			stats.LowConfidence += res.LowConfidence
+			stats.SuppressedRules += res.SuppressedRules
 		}
 	}
 
+	stats.SecretFiles, stats.Secrets = countSecrets(results)
+	stats.RedactedFiles, stats.Redactions = countRedactions(results)
+
 	if stats.TotalImportance > 0 {
 		stats.FinalScore = totalScore / stats.TotalImportance
 	}
@@ -148,8 +294,22 @@ func calculateStats(results []Result) (reportStats, []skippedFileInfo) {
 }
 
 // writeReportHeader 写入报告头部
-func writeReportHeader(f *os.File, displayName string, stats reportStats, level int, duration time.Duration, totalFiles int) {
-	fmt.Fprintf(f, "# 代码审查报告: %s\n\n", displayName)
+func writeReportHeader(f *os.File, displayName string, stats reportStats, meta ReportMeta, duration time.Duration, totalFiles int) {
+	level := meta.Level
+	if meta.Audit {
+		fmt.Fprintf(f, "# 安全审计报告: %s\n\n", displayName)
+	} else {
+		fmt.Fprintf(f, "# 代码审查报告: %s\n\n", displayName)
+	}
+
+	if meta.Partial {
+		fmt.Fprintf(f, "> ⚠️ **部分报告**：审查在完成前被中断，以下仅包含已完成的 %d 个文件，另有 %d 个文件未审查。\n\n", totalFiles, meta.Unreviewed)
+	}
+
+	if meta.Summary != nil {
+		writeExecutiveSummary(f, meta.Summary)
+	}
+
 	fmt.Fprintf(f, "## 📊 项目概览\n\n")
 	fmt.Fprintf(f, "### 🏆 项目综合评分: **%.1f / 100**\n\n", stats.FinalScore)
 	fmt.Fprintf(f, "| 指标 | 值 |\n")
@@ -157,14 +317,73 @@ func writeReportHeader(f *os.File, displayName string, stats reportStats, level
 	fmt.Fprintf(f, "| 审查级别 | %d/6 (%s) |\n", level, getLevelName(level))
 	fmt.Fprintf(f, "| 生成时间 | %s |\n", time.Now().Format("2006-01-02 15:04:05"))
 	fmt.Fprintf(f, "| 耗时 | %s |\n", duration.Round(time.Millisecond))
-	fmt.Fprintf(f, "| 文件总数 | %d (有效分析: %d, 跳过: %d) |\n\n", totalFiles, stats.ValidFiles, stats.SkippedFiles)
+	if meta.Tokens > 0 {
+		fmt.Fprintf(f, "| Token 消耗 | %d |\n", meta.Tokens)
+	}
+	fmt.Fprintf(f, "| 文件总数 | %d (有效分析: %d, 跳过: %d) |\n", totalFiles, stats.ValidFiles, stats.SkippedFiles)
+	if m := meta.Metrics; m != nil && m.LatencyMax > 0 {
+		fmt.Fprintf(f, "| 单文件耗时 | P50 %s / P90 %s / 最大 %s |\n",
+			m.LatencyP50.Round(time.Millisecond), m.LatencyP90.Round(time.Millisecond), m.LatencyMax.Round(time.Millisecond))
+		if m.Retries > 0 || m.Pauses > 0 {
+			fmt.Fprintf(f, "| 重试 / 配额暂停 | %d 次 / %d 次 |\n", m.Retries, m.Pauses)
+		}
+	}
+	if len(meta.Focus) > 0 {
+		fmt.Fprintf(f, "| 审查重点 | %s (其他方面只报告严重问题) |\n", llm.FocusLabel(meta.Focus))
+	}
+	if len(meta.Frameworks) > 0 {
+		fmt.Fprintf(f, "| 项目框架 | %s |\n", strings.Join(meta.Frameworks, "、"))
+	}
+	if meta.Conventions != "" {
+		fmt.Fprintf(f, "| 团队规范 | %s |\n", meta.Conventions)
+	}
+	if len(meta.Rules) > 0 {
+		fmt.Fprintf(f, "| 团队规则 | %d 条，违反 %d 处 (见下方统计) |\n", len(meta.Rules), stats.RuleViolations)
+	}
+	if meta.MinSeverity > llm.SeverityMinor {
+		fmt.Fprintf(f, "| 问题过滤 | 仅显示 %s 及以上的问题 (已隐藏 %d 个) |\n", meta.MinSeverity.Label(), stats.HiddenIssues)
+	}
+	if meta.MinConfidence > 0 {
+		fmt.Fprintf(f, "| 置信度过滤 | 已丢弃 %d 个置信度低于 %.2g 的问题 |\n", stats.LowConfidence, meta.MinConfidence)
+	}
+	if stats.SecretFiles > 0 {
+		fmt.Fprintf(f, "| 疑似敏感信息 | %d 处 (%d 个文件，见下方列表) |\n", stats.Secrets, stats.SecretFiles)
+	}
+	if len(meta.Duplicates) > 0 {
+		fmt.Fprintf(f, "| 重复代码 | %d 处 (共 %d 行，见下方列表) |\n", len(meta.Duplicates), duplicatedLines(meta.Duplicates))
+	}
+	if len(meta.TestGaps) > 0 {
+		fmt.Fprintf(f, "| 缺少测试 | %d 个重要文件没有对应的测试 (见下方列表) |\n", len(meta.TestGaps))
+	}
+	if stats.RedactedFiles > 0 {
+		fmt.Fprintf(f, "| 发送前遮盖 | %d 处 (%d 个文件，密钥、邮箱与自定义规则匹配的内容未发送给模型) |\n", stats.Redactions, stats.RedactedFiles)
+	}
+	if b := meta.Baseline; b != nil {
+		fmt.Fprintf(f, "| 基线 | 仅显示新问题 %d 个 (已隐藏基线中已有的 %d 个，已解决 %d 个，基线文件 `%s`) |\n", b.New, b.Hidden, len(b.Resolved), b.Path)
+	}
+	if meta.Audit {
+		sarifName := displayName + ".sarif"
+		fmt.Fprintf(f, "| 安全漏洞 | %d 个 ([%s](%s)，可上传到代码扫描平台) |\n", stats.Findings, sarifName, sarifName)
+	}
+	if meta.Sonar {
+		sonarName := displayName + ".sonar.json"
+		fmt.Fprintf(f, "| SonarQube | %d 个问题 ([%s](%s)，通过 sonar.externalIssuesReportPaths 导入) |\n", stats.SonarIssues, sonarName, sonarName)
+	}
+	if stats.Patches > 0 {
+		patchName := displayName + ".patch"
+		fmt.Fprintf(f, "| 修复补丁 | %d 个文件 ([%s](%s))，可使用 `reviewer fix --apply` 逐个确认后应用 |\n", stats.Patches, patchName, patchName)
+	}
+	if stats.TriagedFiles > 0 {
+		fmt.Fprintf(f, "| 两阶段模式 | 深度审查 %d 个文件，仅初筛 %d 个文件 |\n", totalFiles-stats.TriagedFiles-stats.SkippedFiles, stats.TriagedFiles)
+	}
+	fmt.Fprintln(f)
 	fmt.Fprintf(f, "---\n\n")
 }
 
 // writeSkippedFiles 写入跳过的文件列表
 func writeSkippedFiles(f *os.File, skippedFiles []skippedFileInfo, outputDir string) {
 	fmt.Fprintf(f, "## ⏭️ 跳过的文件 (%d 个)\n\n", len(skippedFiles))
-	fmt.Fprintf(f, "> 以下文件因超过大小限制 (32KB) 而被跳过，建议手动审查。\n\n")
+	fmt.Fprintf(f, "> 以下文件因超过分段审查上限、属于生成代码或第三方代码、审查超时、Token 预算耗尽或包含疑似密钥而未经审查，如有需要请手动审查。\n\n")
 	fmt.Fprintf(f, "| 文件路径 | 文件大小 | 原因 |\n")
 	fmt.Fprintf(f, "|:---|:---|:---|\n")
 
@@ -177,24 +396,56 @@ func writeSkippedFiles(f *os.File, skippedFiles []skippedFileInfo, outputDir str
 	fmt.Fprintf(f, "\n---\n\n")
 }
 
+// writeTriageResults 写入只经过初筛、未进入深度审查的文件
+func writeTriageResults(f *os.File, results []Result, outputDir string) {
+	fmt.Fprintf(f, "## 🔍 仅初筛的文件\n\n")
+	fmt.Fprintf(f, "> 以下文件经快速初筛后优先级较低，未进入深度审查，不计入综合评分。\n\n")
+	fmt.Fprintf(f, "| 文件路径 | 重要性 | 风险 | 初筛结论 |\n")
+	fmt.Fprintf(f, "|:---|:---|:---|:---|\n")
+
+	for _, res := range results {
+		if res.Triage == nil {
+			continue
+		}
+		relLink := getRelativeLink(res.FilePath, outputDir)
+		fmt.Fprintf(f, "| [%s](%s) | %.1f | %.1f | %s |\n", res.FilePath, relLink, res.Triage.Importance, res.Triage.Risk, res.Triage.Reason)
+	}
+
+	fmt.Fprintf(f, "\n---\n\n")
+}
+
+// writeResolvedIssues 写入基线中有、本次审查未再发现的问题
+func writeResolvedIssues(f *os.File, resolved []ResolvedIssue, outputDir string) {
+	fmt.Fprintf(f, "## ✅ 已解决的基线问题 (%d 个)\n\n", len(resolved))
+	fmt.Fprintf(f, "> 以下问题记录在基线中，本次审查未再发现。确认修复后可运行 `reviewer baseline` 更新基线。\n\n")
+	for _, r := range resolved {
+		fmt.Fprintf(f, "- [%s](%s): %s\n", r.FilePath, getRelativeLink(r.FilePath, outputDir), formatIssue(r.Issue))
+	}
+	fmt.Fprintf(f, "\n---\n\n")
+}
+
 // writeReportDetails 写入详细审查结果
-func writeReportDetails(f *os.File, results []Result, outputDir string) {
+func writeReportDetails(f *os.File, results []Result, outputDir string, minSeverity llm.Severity) {
 	// 按重要性排序
 	sortResultsByImportance(results)
 
 	for _, res := range results {
-		// 跳过大文件（已在跳过列表中显示）
-		if res.SkipReason == SkipReasonTooLarge {
+		// 跳过的文件和仅初筛的文件（已在对应列表中显示）
+		if isListedSkip(res) || res.Triage != nil {
 			continue
 		}
 
 		if res.Error != nil {
 			fmt.Fprintf(f, "## ⚠️ %s\n\n", res.FilePath)
-			fmt.Fprintf(f, "**分析失败:** %v\n\n---\n\n", res.Error)
+			if res.Retries > 0 {
+				fmt.Fprintf(f, "**分析失败 (已重试 %d 轮):** %v\n\n---\n\n", res.Retries, res.Error)
+			} else {
+				fmt.Fprintf(f, "**分析失败:** %v\n\n---\n\n", res.Error)
+			}
 			continue
 		}
 
-		writeFileResult(f, res, outputDir)
+		writeFileResult(f, res, outputDir, minSeverity)
 	}
 }
 
@@ -212,15 +463,44 @@ func sortResultsByImportance(results []Result) {
 	})
 }
 
-// writeFileResult 写入单个文件的审查结果
-func writeFileResult(f *os.File, res Result, outputDir string) {
+// WriteResultMarkdown 以报告中的格式输出单个文件的审查结果（文件链接相对于当前目录），低于 minSeverity 的问题不输出
+func WriteResultMarkdown(w io.Writer, res Result, minSeverity llm.Severity) {
+	writeFileResult(w, res, ".", minSeverity)
+}
+
+// writeFileResult 写入单个文件的审查结果，低于 minSeverity 的问题不输出
+func writeFileResult(f io.Writer, res Result, outputDir string, minSeverity llm.Severity) {
 	review := res.Review
-	emoji := getScoreEmoji(review.Score)
+	emoji := ScoreEmoji(review.Score)
 	relLink := getRelativeLink(res.FilePath, outputDir)
 
-	fmt.Fprintf(f, "## %s [%s](%s) (得分: %d | 重要性: %.1f)\n\n", emoji, res.FilePath, relLink, review.Score, review.Importance)
+	importance := fmt.Sprintf("%.1f", review.Importance)
+	if res.ImportanceRule != nil {
+		importance += fmt.Sprintf(" (路径规则 %s)", res.ImportanceRule.label())
+	}
+	fmt.Fprintf(f, "## %s [%s](%s) (得分: %d | 重要性: %s)\n\n", emoji, res.FilePath, relLink, review.Score, importance)
 	fmt.Fprintf(f, "**总结:** %s\n\n", review.Summary)
 
+	if res.DuplicateOf != "" {
+		fmt.Fprintf(f, "> 🪞 文件内容与 [%s](%s) 完全相同，已复用其审查结果。\n\n", res.DuplicateOf, getRelativeLink(res.DuplicateOf, outputDir))
+	}
+
+	if res.Chunks > 0 {
+		fmt.Fprintf(f, "> 📦 文件超过单次审查上限，已分 %d 段审查后合并结果。\n\n", res.Chunks)
+	}
+
+	if res.Reverified {
+		fmt.Fprintf(f, "> 🔁 初步结论评分过低或置信度不足，已自动复核并剔除无法确认的问题。\n\n")
+	}
+
+	if len(res.Redactions) > 0 {
+		fmt.Fprintf(f, "> 🙈 发送前已遮盖 %d 处内容: %s\n\n", len(res.Redactions), redactionNote(res.Redactions))
+	}
+
+	if review.Patch != "" {
+		fmt.Fprintf(f, "> 🩹 已生成修复补丁，可使用 `reviewer fix --apply` 确认后应用。\n\n")
+	}
+
 	if len(review.Pros) > 0 {
 		fmt.Fprintf(f, "### ✅ 亮点\n")
 		for _, pro := range review.Pros {
@@ -229,14 +509,22 @@ func writeFileResult(f *os.File, res Result, outputDir string) {
 		fmt.Fprintln(f)
 	}
 
-	if len(review.Issues) > 0 {
+	if len(review.Findings) > 0 {
+		if findings := filterFindings(review.Findings, minSeverity); len(findings) > 0 {
+			writeFindings(f, findings)
+		}
+	} else if issues := llm.FilterIssues(review.Issues, minSeverity); len(issues) > 0 {
 		fmt.Fprintf(f, "### 🐛 发现问题\n")
-		for _, issue := range review.Issues {
-			fmt.Fprintf(f, "- %s\n", issue)
+		for _, issue := range issues {
+			fmt.Fprintf(f, "- %s\n", formatIssue(issue))
 		}
 		fmt.Fprintln(f)
 	}
 
+	if len(res.Lint) > 0 {
+		writeLintFindings(f, res.Lint)
+	}
+
 	if review.Suggestion != "" {
 		fmt.Fprintf(f, "### 💡 优化建议\n")
 		fmt.Fprintf(f, "%s\n\n", review.Suggestion)
@@ -245,8 +533,33 @@ func writeFileResult(f *os.File, res Result, outputDir string) {
 	fmt.Fprintf(f, "---\n\n")
 }
 
-// getScoreEmoji 根据分数返回对应的 emoji
-func getScoreEmoji(score int) string {
+// formatIssue 将问题开头的严重程度标注替换为带图标的显示文本，标注了置信度时附在末尾
+func formatIssue(issue string) string {
+	severity, text := llm.SplitIssue(issue)
+	if severity == llm.SeverityUnknown {
+		return issue
+	}
+	if confidence := llm.IssueConfidence(issue); confidence > 0 {
+		return fmt.Sprintf("**%s** %s _(置信度 %.2g)_", severity.Label(), text, confidence)
+	}
+	return fmt.Sprintf("**%s** %s", severity.Label(), text)
+}
+
+// writeLintFindings 写入本地静态检查工具报告的问题
+func writeLintFindings(f io.Writer, findings []lint.Finding) {
+	fmt.Fprintf(f, "### 🔧 静态检查\n")
+	for _, finding := range findings {
+		if finding.Line > 0 {
+			fmt.Fprintf(f, "- `%s` 第 %d 行: %s\n", finding.Tool, finding.Line, finding.Message)
+		} else {
+			fmt.Fprintf(f, "- `%s`: %s\n", finding.Tool, finding.Message)
+		}
+	}
+	fmt.Fprintln(f)
+}
+
+// ScoreEmoji 根据分数返回对应的 emoji（报告与 TUI 共用）
+func ScoreEmoji(score int) string {
 	switch {
 	case score >= ScoreThresholdGood:
 		return "🟢"
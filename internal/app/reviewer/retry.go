@@ -0,0 +1,66 @@
+package reviewer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 重试队列默认值
+const (
+	// DefaultRetryRounds 是主队列结束后重试失败文件的默认轮数
+	DefaultRetryRounds = 2
+	// DefaultRetryBackoff 是第一轮重试前的默认等待时间，之后每轮翻倍
+	DefaultRetryBackoff = 10 * time.Second
+)
+
+// retryPolicy 描述失败文件的重试策略
+type retryPolicy struct {
+	rounds  int
+	backoff time.Duration
+}
+
+// WithRetryQueue 开启重试队列：因暂时性错误失败的文件在主队列结束后重新审查，
+// 最多 rounds 轮，每轮开始前等待 backoff（逐轮翻倍）
+func WithRetryQueue(rounds int, backoff time.Duration) Option {
+	return func(e *Engine) {
+		if backoff <= 0 {
+			backoff = DefaultRetryBackoff
+		}
+		e.retry = retryPolicy{rounds: rounds, backoff: backoff}
+	}
+}
+
+// retryQueue 收集因暂时性错误失败的任务（并发安全）
+type retryQueue struct {
+	mu   sync.Mutex
+	jobs []Job
+}
+
+// Add 将任务加入重试队列
+func (q *retryQueue) Add(job Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, job)
+}
+
+// Drain 取出并清空队列中的全部任务
+func (q *retryQueue) Drain() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := q.jobs
+	q.jobs = nil
+	return jobs
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
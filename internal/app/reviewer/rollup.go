@@ -0,0 +1,166 @@
+package reviewer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// maxRollupIssues 是组织级汇总报告中列出的主要问题数
+const maxRollupIssues = 10
+
+// RollupEntry 是组织级汇总报告中的一个仓库
+type RollupEntry struct {
+	Name       string     // 仓库的报告名称
+	Source     string     // 本地路径或 Git 地址
+	Digest     *RunDigest // 审查统计，未生成报告时为空
+	ReportPath string     // 仓库的审查报告
+	Partial    bool       // 审查被中断，报告只包含已完成的文件
+	Error      string     // 获取或审查失败的原因
+}
+
+// GenerateRollupReport 生成多个仓库的组织级汇总报告，返回报告路径
+// 综合评分按各仓库有效分析的文件数加权，仓库按 entries 的顺序列出
+func GenerateRollupReport(entries []RollupEntry, duration time.Duration, outputDir, name string) (string, error) {
+	reportPath := filepath.Join(outputDir, sanitizeFileName(name))
+	if err := os.MkdirAll(outputDir, DirPermission); err != nil {
+		return "", fmt.Errorf("创建报告目录失败: %w", err)
+	}
+	f, err := os.Create(reportPath)
+	if err != nil {
+		return "", fmt.Errorf("创建报告文件失败: %w", err)
+	}
+	defer f.Close()
+
+	writeRollupHeader(f, strings.TrimSuffix(filepath.Base(reportPath), ".md"), entries, duration)
+	writeRollupRepos(f, entries, outputDir)
+	writeRollupIssues(f, entries)
+	writeRollupFailures(f, entries)
+	return reportPath, nil
+}
+
+// writeRollupHeader 写入汇总报告的概览：加权综合评分、仓库数、文件数与问题统计
+func writeRollupHeader(f io.Writer, displayName string, entries []RollupEntry, duration time.Duration) {
+	var reviewed, partial, files, valid, skipped, critical, major, minor int
+	var weighted float64
+	for _, e := range entries {
+		if e.Partial {
+			partial++
+		}
+		d := e.Digest
+		if d == nil {
+			continue
+		}
+		reviewed++
+		files += d.Files
+		valid += d.Reviewed
+		skipped += d.Skipped
+		critical += d.Critical
+		major += d.Major
+		minor += d.Minor
+		weighted += d.Score * float64(d.Reviewed)
+	}
+
+	fmt.Fprintf(f, "# 组织级审查汇总: %s\n\n", displayName)
+	if partial > 0 {
+		fmt.Fprintf(f, "> ⚠️ **部分报告**：%d 个仓库的审查在完成前被中断，其统计只包含已完成的文件。\n\n", partial)
+	}
+
+	fmt.Fprintf(f, "## 📊 总览\n\n")
+	if valid > 0 {
+		fmt.Fprintf(f, "### 🏆 综合评分: **%.1f / 100** (按各仓库有效分析的文件数加权)\n\n", weighted/float64(valid))
+	}
+	fmt.Fprintf(f, "| 指标 | 值 |\n")
+	fmt.Fprintf(f, "|:---|:---|\n")
+	fmt.Fprintf(f, "| 生成时间 | %s |\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(f, "| 耗时 | %s |\n", duration.Round(time.Millisecond))
+	fmt.Fprintf(f, "| 仓库数 | %d (已审查: %d, 未完成: %d) |\n", len(entries), reviewed, len(entries)-reviewed)
+	fmt.Fprintf(f, "| 文件总数 | %d (有效分析: %d, 跳过: %d) |\n", files, valid, skipped)
+	fmt.Fprintf(f, "| 发现问题 | %s %d / %s %d / %s %d |\n",
+		llm.SeverityCritical.Label(), critical,
+		llm.SeverityMajor.Label(), major,
+		llm.SeverityMinor.Label(), minor)
+	fmt.Fprintf(f, "\n---\n\n")
+}
+
+// writeRollupRepos 写入各仓库的评分、问题数与报告链接
+func writeRollupRepos(f io.Writer, entries []RollupEntry, outputDir string) {
+	fmt.Fprintf(f, "## 📦 各仓库\n\n")
+	fmt.Fprintf(f, "| 仓库 | 来源 | 评分 | 文件 | %s | %s | %s | 报告 |\n",
+		llm.SeverityCritical.Label(), llm.SeverityMajor.Label(), llm.SeverityMinor.Label())
+	fmt.Fprintf(f, "|:---|:---|---:|---:|---:|---:|---:|:---|\n")
+	for _, e := range entries {
+		name, source := escapeTableCell(e.Name), "`"+escapeTableCell(e.Source)+"`"
+		d := e.Digest
+		if d == nil {
+			status := "⏭️ 未生成报告"
+			if e.Error != "" {
+				status = "❌ 失败"
+			}
+			fmt.Fprintf(f, "| %s | %s | - | - | - | - | - | %s |\n", name, source, status)
+			continue
+		}
+		report := fmt.Sprintf("[%s](%s)", filepath.Base(e.ReportPath), getRelativeLink(e.ReportPath, outputDir))
+		if e.Partial {
+			report += " (部分)"
+		}
+		fmt.Fprintf(f, "| %s | %s | %s %.1f | %d | %d | %d | %d | %s |\n",
+			name, source, ScoreEmoji(int(d.Score)), d.Score, d.Files, d.Critical, d.Major, d.Minor, report)
+	}
+	fmt.Fprintln(f)
+}
+
+// writeRollupIssues 写入所有仓库中最严重的问题（每个仓库取其运行摘要中的主要问题）
+func writeRollupIssues(f io.Writer, entries []RollupEntry) {
+	type repoIssue struct {
+		repo string
+		DigestIssue
+	}
+	var issues []repoIssue
+	for _, e := range entries {
+		if e.Digest == nil {
+			continue
+		}
+		for _, issue := range e.Digest.TopIssues {
+			issues = append(issues, repoIssue{repo: e.Name, DigestIssue: issue})
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Severity > issues[j].Severity })
+
+	fmt.Fprintf(f, "## 🔥 主要问题\n\n")
+	for _, issue := range issues[:min(len(issues), maxRollupIssues)] {
+		label := issue.Severity.Label()
+		if label == "" {
+			label = llm.SeverityMinor.Label()
+		}
+		fmt.Fprintf(f, "- %s **%s** `%s`: %s\n", label, issue.repo, issue.File, issue.Text)
+	}
+	fmt.Fprintln(f)
+}
+
+// writeRollupFailures 写入获取或审查失败的仓库及原因
+func writeRollupFailures(f io.Writer, entries []RollupEntry) {
+	var failed []RollupEntry
+	for _, e := range entries {
+		if e.Digest == nil && e.Error != "" {
+			failed = append(failed, e)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Fprintf(f, "## ❌ 失败的仓库\n\n")
+	for _, e := range failed {
+		fmt.Fprintf(f, "- **%s** (`%s`): %s\n", e.Name, e.Source, e.Error)
+	}
+	fmt.Fprintln(f)
+}
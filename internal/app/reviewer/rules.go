@@ -0,0 +1,133 @@
+package reviewer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// ignoreDirective 匹配文件中按规则抑制问题的注释，如 // reviewer:ignore SEC-001, SEC-002
+var ignoreDirective = regexp.MustCompile(`reviewer:ignore\s+([A-Za-z][A-Za-z0-9_.-]*(?:\s*,\s*[A-Za-z][A-Za-z0-9_.-]*)*)`)
+
+// WithRules 设置团队规则：每个文件只注入路径匹配的规则；suppressed 中的规则（以及文件中
+// reviewer:ignore 注释列出的规则）不注入，引用它们的问题被丢弃
+func WithRules(rules []llm.Rule, suppressed []string) Option {
+	return func(e *Engine) {
+		e.rules = rules
+		e.suppressRules = suppressed
+	}
+}
+
+// RuleSuppressions 返回对 content 抑制的规则编号（大写）：suppressed 中的规则，
+// 以及 content 中 reviewer:ignore 注释列出的规则
+func RuleSuppressions(content string, suppressed []string) map[string]bool {
+	set := make(map[string]bool, len(suppressed))
+	for _, id := range suppressed {
+		set[strings.ToUpper(strings.TrimSpace(id))] = true
+	}
+	for _, m := range ignoreDirective.FindAllStringSubmatch(content, -1) {
+		for _, id := range strings.Split(m[1], ",") {
+			set[strings.ToUpper(strings.TrimSpace(id))] = true
+		}
+	}
+	return set
+}
+
+// MatchRules 返回适用于 path 且未被抑制的规则，Paths 为空的规则适用于所有文件
+func MatchRules(rules []llm.Rule, path string, suppressed map[string]bool) []llm.Rule {
+	key := baselineKey(path)
+	var matched []llm.Rule
+	for _, rule := range rules {
+		if suppressed[strings.ToUpper(rule.ID)] {
+			continue
+		}
+		if len(rule.Paths) == 0 {
+			matched = append(matched, rule)
+			continue
+		}
+		for _, pattern := range rule.Paths {
+			if matchPathPattern(pattern, key) {
+				matched = append(matched, rule)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// SuppressRuleIssues 丢弃引用了被抑制规则的问题，返回丢弃的数量
+func SuppressRuleIssues(review *llm.ReviewResult, suppressed map[string]bool) int {
+	if review == nil || len(suppressed) == 0 {
+		return 0
+	}
+	kept := review.Issues[:0:0]
+	for _, issue := range review.Issues {
+		if id := llm.IssueRule(issue); id != "" && suppressed[strings.ToUpper(id)] {
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	dropped := len(review.Issues) - len(kept)
+	review.Issues = kept
+	return dropped
+}
+
+// ruleCount 是一条规则在报告中的违反统计
+type ruleCount struct {
+	rule   llm.Rule
+	issues int
+	files  int
+}
+
+// countRuleViolations 按规则统计不低于 minSeverity 的问题数与涉及的文件数，顺序与 rules 一致
+func countRuleViolations(results []Result, rules []llm.Rule, minSeverity llm.Severity) []ruleCount {
+	counts := make([]ruleCount, len(rules))
+	index := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		counts[i].rule = rule
+		index[strings.ToUpper(rule.ID)] = i
+	}
+	for _, res := range results {
+		if res.Review == nil || res.Error != nil {
+			continue
+		}
+		seen := make(map[int]bool)
+		for _, issue := range llm.FilterIssues(res.Review.Issues, minSeverity) {
+			i, ok := index[strings.ToUpper(llm.IssueRule(issue))]
+			if !ok {
+				continue
+			}
+			counts[i].issues++
+			if !seen[i] {
+				seen[i] = true
+				counts[i].files++
+			}
+		}
+	}
+	return counts
+}
+
+// writeRuleSummary 写入按团队规则的违反统计
+func writeRuleSummary(f io.Writer, counts []ruleCount, suppressed int) {
+	total := 0
+	for _, c := range counts {
+		total += c.issues
+	}
+	fmt.Fprintf(f, "## 📏 团队规则 (违反 %d 处)\n\n", total)
+	if suppressed > 0 {
+		fmt.Fprintf(f, "> 另有 %d 个问题引用的规则已被抑制 (suppress_rules 或文件中的 `reviewer:ignore` 注释)，未列入报告。\n\n", suppressed)
+	}
+	fmt.Fprintf(f, "| 规则 | 说明 | 违反 | 涉及文件 |\n")
+	fmt.Fprintf(f, "|:---|:---|:---|:---|\n")
+	for _, c := range counts {
+		desc := strings.TrimSpace(c.rule.Description)
+		if desc == "" {
+			desc, _, _ = strings.Cut(strings.TrimSpace(c.rule.Instructions), "\n")
+		}
+		fmt.Fprintf(f, "| %s | %s | %d | %d |\n", c.rule.ID, escapeTableCell(desc), c.issues, c.files)
+	}
+	fmt.Fprintf(f, "\n---\n\n")
+}
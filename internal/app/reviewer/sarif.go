@@ -0,0 +1,225 @@
+package reviewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// SARIF 输出使用的格式版本与工具信息
+const (
+	sarifVersion  = "2.1.0"
+	sarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName = "go-ai-reviewer"
+)
+
+// unclassifiedRule 是无法归类到 CWE 的漏洞使用的规则编号
+const unclassifiedRule = "security/unclassified"
+
+// SARIFPath 返回报告对应的 SARIF 文件路径（报告旁的同名 .sarif 文件）
+func SARIFPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".sarif"
+}
+
+// sarifLog 及以下类型是 SARIF 2.1.0 中用到的部分结构
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name,omitempty"`
+	ShortDescription sarifMessage   `json:"shortDescription"`
+	HelpURI          string         `json:"helpUri,omitempty"`
+	Properties       sarifRuleProps `json:"properties"`
+}
+
+type sarifRuleProps struct {
+	Tags []string `json:"tags"`
+	// SecuritySeverity 是 GitHub 代码扫描用于划分严重程度的分值 (0-10)
+	SecuritySeverity string `json:"security-severity"`
+}
+
+type sarifResult struct {
+	RuleID     string           `json:"ruleId"`
+	RuleIndex  int              `json:"ruleIndex"`
+	Level      string           `json:"level"`
+	Message    sarifMessage     `json:"message"`
+	Locations  []sarifLocation  `json:"locations"`
+	Properties sarifResultProps `json:"properties"`
+}
+
+type sarifResultProps struct {
+	Exploitability string  `json:"exploitability,omitempty"`
+	Confidence     float64 `json:"confidence,omitempty"`
+	Remediation    string  `json:"remediation,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevels 是严重程度对应的 SARIF 级别
+var sarifLevels = map[llm.Severity]string{
+	llm.SeverityCritical: "error",
+	llm.SeverityMajor:    "warning",
+	llm.SeverityMinor:    "note",
+}
+
+// securitySeverities 是严重程度对应的 security-severity 分值
+var securitySeverities = map[llm.Severity]string{
+	llm.SeverityCritical: "9.0",
+	llm.SeverityMajor:    "7.0",
+	llm.SeverityMinor:    "4.0",
+}
+
+// buildSARIF 将安全审计发现转换为 SARIF：每个 CWE 对应一条规则，每个漏洞对应一条结果
+func buildSARIF(results []Result, minSeverity llm.Severity) sarifLog {
+	rules := make(map[string]*sarifRule)
+	ruleSeverity := make(map[string]llm.Severity)
+	type pending struct {
+		res     Result
+		finding llm.Finding
+	}
+	var all []pending
+
+	for _, res := range results {
+		if res.Review == nil || res.Error != nil {
+			continue
+		}
+		for _, f := range filterFindings(res.Review.Findings, minSeverity) {
+			id := f.CWE
+			if id == "" {
+				id = unclassifiedRule
+			}
+			if rules[id] == nil {
+				rules[id] = newSARIFRule(id, f)
+			}
+			ruleSeverity[id] = max(ruleSeverity[id], f.Level())
+			all = append(all, pending{res, f})
+		}
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	index := make(map[string]int, len(ids))
+	driver := sarifDriver{Name: sarifToolName, Rules: make([]sarifRule, 0, len(ids))}
+	for i, id := range ids {
+		rule := *rules[id]
+		rule.Properties.SecuritySeverity = securitySeverities[ruleSeverity[id]]
+		driver.Rules = append(driver.Rules, rule)
+		index[id] = i
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: make([]sarifResult, 0, len(all))}
+	for _, p := range all {
+		id := p.finding.CWE
+		if id == "" {
+			id = unclassifiedRule
+		}
+		message := p.finding.Title
+		if p.finding.Description != "" {
+			message += ": " + p.finding.Description
+		}
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: baselineKey(p.res.FilePath)}}
+		if p.finding.Line > 0 {
+			location.Region = &sarifRegion{StartLine: p.finding.Line}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    id,
+			RuleIndex: index[id],
+			Level:     sarifLevels[p.finding.Level()],
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+			Properties: sarifResultProps{
+				Exploitability: p.finding.Exploitability,
+				Confidence:     p.finding.Confidence,
+				Remediation:    p.finding.Remediation,
+			},
+		})
+	}
+
+	return sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
+
+// newSARIFRule 创建漏洞类别对应的规则，名称优先使用内置的 CWE 名称
+func newSARIFRule(id string, f llm.Finding) *sarifRule {
+	rule := &sarifRule{
+		ID:         id,
+		Properties: sarifRuleProps{Tags: []string{"security"}},
+	}
+	name := llm.CWEName(f.CWE)
+	if name == "" {
+		name = f.Title
+	}
+	rule.ShortDescription = sarifMessage{Text: name}
+	if f.CWE != "" {
+		rule.Name = f.CWE
+		rule.HelpURI = llm.CWEURL(f.CWE)
+		rule.Properties.Tags = append(rule.Properties.Tags, "external/cwe/"+strings.ToLower(f.CWE))
+	}
+	return rule
+}
+
+// writeSARIFFile 将安全审计发现写入报告旁的 .sarif 文件，返回写入的漏洞数
+// 非安全审计的报告删除同名的旧文件，避免与报告内容不一致
+func writeSARIFFile(results []Result, reportPath string, meta ReportMeta) (int, error) {
+	path := SARIFPath(reportPath)
+	if !meta.Audit {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("删除旧的 SARIF 文件失败: %w", err)
+		}
+		return 0, nil
+	}
+
+	log := buildSARIF(results, meta.MinSeverity)
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("序列化 SARIF 失败: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return 0, fmt.Errorf("写入 SARIF 文件失败: %w", err)
+	}
+	return len(log.Runs[0].Results), nil
+}
@@ -0,0 +1,107 @@
+package reviewer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go-ai-reviewer/internal/app/secrets"
+)
+
+// secretPolicy 描述发送前的敏感信息检测策略
+type secretPolicy struct {
+	enabled bool
+	block   bool // 包含疑似密钥的文件不发送给模型
+}
+
+// WithSecretScan 在文件内容发送给模型之前检测疑似密钥、令牌与私钥，检测结果写入报告
+// block 为 true 时包含疑似密钥的文件不发送（初筛与深度审查均跳过），在报告中标记为跳过
+func WithSecretScan(block bool) Option {
+	return func(e *Engine) {
+		e.secrets = secretPolicy{enabled: true, block: block}
+	}
+}
+
+// scan 检测文件内容中的疑似敏感信息，未开启检测时返回空
+func (p secretPolicy) scan(content string) []secrets.Finding {
+	if !p.enabled {
+		return nil
+	}
+	return secrets.Scan(content)
+}
+
+// blocks 判断包含 found 的文件是否不允许发送给模型
+func (p secretPolicy) blocks(found []secrets.Finding) bool {
+	return p.block && len(found) > 0
+}
+
+// WithRedaction 在内容发送给模型之前用 r 遮盖疑似密钥、邮箱与自定义规则匹配的内容（含项目上下文），
+// 遮盖记录写入报告；遮盖保留行号，修复补丁仍以原文校验
+func WithRedaction(r *secrets.Redactor) Option {
+	return func(e *Engine) {
+		e.redactor = r
+	}
+}
+
+// maxListedRedactions 是报告中每个文件列出的遮盖记录上限
+const maxListedRedactions = 10
+
+// redactionNote 返回文件遮盖记录的说明，如 "第 3 行 (GitHub Token)、第 8 行 (邮箱)"
+func redactionNote(redactions []secrets.Redaction) string {
+	parts := make([]string, 0, min(len(redactions), maxListedRedactions))
+	for _, r := range redactions[:min(len(redactions), maxListedRedactions)] {
+		parts = append(parts, fmt.Sprintf("第 %d 行 (%s)", r.Line, r.Kind))
+	}
+	note := strings.Join(parts, "、")
+	if len(redactions) > maxListedRedactions {
+		note += fmt.Sprintf(" 等 %d 处", len(redactions))
+	}
+	return note
+}
+
+// countRedactions 统计发送前遮盖过内容的文件数与总处数
+func countRedactions(results []Result) (files, total int) {
+	for _, res := range results {
+		if len(res.Redactions) > 0 {
+			files++
+			total += len(res.Redactions)
+		}
+	}
+	return files, total
+}
+
+// countSecrets 统计包含疑似敏感信息的文件数与总处数
+func countSecrets(results []Result) (files, total int) {
+	for _, res := range results {
+		if len(res.Secrets) > 0 {
+			files++
+			total += len(res.Secrets)
+		}
+	}
+	return files, total
+}
+
+// writeSecretFindings 写入检测到的疑似敏感信息（内容已遮盖）
+func writeSecretFindings(f io.Writer, results []Result, outputDir string, total int) {
+	fmt.Fprintf(f, "## 🔐 疑似敏感信息 (%d 处)\n\n", total)
+	fmt.Fprintf(f, "> 以下内容由本地规则检测，可能存在误报。已发送给模型的文件请确认后轮换泄露的凭据，并改为从环境变量或密钥管理服务读取。\n\n")
+	fmt.Fprintf(f, "| 文件路径 | 行号 | 类型 | 内容 (已遮盖) | 是否发送 |\n")
+	fmt.Fprintf(f, "|:---|:---|:---|:---|:---|\n")
+	for _, res := range results {
+		if len(res.Secrets) == 0 {
+			continue
+		}
+		sent := "已发送"
+		switch {
+		case res.SkipReason == SkipReasonSecrets:
+			sent = "未发送"
+		case len(res.Redactions) > 0:
+			sent = "已遮盖"
+		}
+		relLink := getRelativeLink(res.FilePath, outputDir)
+		for _, s := range res.Secrets {
+			fmt.Fprintf(f, "| [%s](%s) | %d | %s | `%s` | %s |\n", res.FilePath, relLink, s.Line, s.Rule, s.Preview, sent)
+		}
+	}
+	fmt.Fprintf(f, "\n---\n\n")
+}
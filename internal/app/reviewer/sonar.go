@@ -0,0 +1,211 @@
+package reviewer
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+	"go-ai-reviewer/pkg/utils"
+)
+
+// sonarEngineID 是导入到 SonarQube 的外部问题所属的引擎
+const sonarEngineID = "go-ai-reviewer"
+
+// maxSonarMessage 是问题描述的最大字符数（SonarQube 的上限为 4000）
+const maxSonarMessage = 1000
+
+// SonarPath 返回报告对应的 SonarQube 导入文件路径（报告旁的同名 .sonar.json 文件）
+func SonarPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".sonar.json"
+}
+
+// sonarReport 及以下类型是 SonarQube 通用问题导入格式 (Generic Issue Import，SonarQube 10.3 起的格式)
+type sonarReport struct {
+	Rules  []sonarRule  `json:"rules"`
+	Issues []sonarIssue `json:"issues"`
+}
+
+type sonarRule struct {
+	ID                 string        `json:"id"`
+	Name               string        `json:"name"`
+	Description        string        `json:"description,omitempty"`
+	EngineID           string        `json:"engineId"`
+	CleanCodeAttribute string        `json:"cleanCodeAttribute"`
+	Impacts            []sonarImpact `json:"impacts"`
+}
+
+type sonarImpact struct {
+	SoftwareQuality string `json:"softwareQuality"` // SECURITY / RELIABILITY / MAINTAINABILITY
+	Severity        string `json:"severity"`        // HIGH / MEDIUM / LOW
+}
+
+type sonarIssue struct {
+	RuleID          string        `json:"ruleId"`
+	PrimaryLocation sonarLocation `json:"primaryLocation"`
+}
+
+type sonarLocation struct {
+	Message   string          `json:"message"`
+	FilePath  string          `json:"filePath"`
+	TextRange *sonarTextRange `json:"textRange,omitempty"`
+}
+
+type sonarTextRange struct {
+	StartLine int `json:"startLine"`
+}
+
+// sonarSeverities 是严重程度对应的 SonarQube 影响程度
+var sonarSeverities = map[llm.Severity]string{
+	llm.SeverityCritical: "HIGH",
+	llm.SeverityMajor:    "MEDIUM",
+	llm.SeverityMinor:    "LOW",
+}
+
+// sonarSeverityRules 是未引用团队规则的审查问题按严重程度归入的规则
+var sonarSeverityRules = map[llm.Severity]sonarRule{
+	llm.SeverityCritical: {ID: "ai-review/critical", Name: "AI 审查: 严重问题", Description: "运行时崩溃、安全漏洞、数据损坏等严重问题"},
+	llm.SeverityMajor:    {ID: "ai-review/major", Name: "AI 审查: 重要问题", Description: "逻辑错误、资源泄漏、错误处理缺失等重要问题"},
+	llm.SeverityMinor:    {ID: "ai-review/minor", Name: "AI 审查: 一般问题", Description: "代码风格、命名、可读性等一般问题"},
+}
+
+// sonarBuilder 汇总规则与问题，同一规则取出现过的最高严重程度
+type sonarBuilder struct {
+	rules    map[string]*sonarRule
+	severity map[string]llm.Severity
+	quality  map[string]string
+	issues   []sonarIssue
+}
+
+// addRule 登记规则与本次出现的严重程度
+func (b *sonarBuilder) addRule(rule sonarRule, quality string, s llm.Severity) {
+	if b.rules[rule.ID] == nil {
+		rule.EngineID = sonarEngineID
+		b.rules[rule.ID] = &rule
+		b.quality[rule.ID] = quality
+	}
+	b.severity[rule.ID] = max(b.severity[rule.ID], s)
+}
+
+// buildSonar 将报告中显示的问题转换为 SonarQube 通用问题导入格式：
+// 安全审计的漏洞按 CWE 归入规则，引用团队规则的问题归入该规则，其余问题按严重程度归入规则
+func buildSonar(results []Result, meta ReportMeta) sonarReport {
+	teamRules := make(map[string]llm.Rule, len(meta.Rules))
+	for _, r := range meta.Rules {
+		teamRules[strings.ToUpper(r.ID)] = r
+	}
+	b := &sonarBuilder{
+		rules:    make(map[string]*sonarRule),
+		severity: make(map[string]llm.Severity),
+		quality:  make(map[string]string),
+	}
+
+	for _, res := range results {
+		if res.Review == nil || res.Error != nil {
+			continue
+		}
+		path := baselineKey(res.FilePath)
+		lines := countFileLines(res.FilePath)
+
+		if len(res.Review.Findings) > 0 {
+			for _, f := range filterFindings(res.Review.Findings, meta.MinSeverity) {
+				// 规则在 SonarQube 中被多个问题共用，无法归类的漏洞不使用某个漏洞的标题作为规则名
+				rule := sonarRule{ID: unclassifiedRule, Name: "未归类的安全漏洞", CleanCodeAttribute: "TRUSTWORTHY"}
+				if f.CWE != "" {
+					rule.ID, rule.Name, rule.Description = f.CWE, cmp.Or(llm.CWEName(f.CWE), f.Title), "参见 "+llm.CWEURL(f.CWE)
+				}
+				id := rule.ID
+				b.addRule(rule, "SECURITY", f.Level())
+				message := f.Title
+				if f.Description != "" {
+					message += ": " + f.Description
+				}
+				b.issues = append(b.issues, newSonarIssue(id, message, path, f.Line, lines))
+			}
+			continue
+		}
+
+		for _, issue := range llm.FilterIssues(res.Review.Issues, meta.MinSeverity) {
+			s, text := llm.SplitIssue(issue)
+			if s == llm.SeverityUnknown {
+				s = llm.SeverityMajor
+			}
+			quality, attribute := "RELIABILITY", "LOGICAL"
+			if s == llm.SeverityMinor {
+				quality, attribute = "MAINTAINABILITY", "CONVENTIONAL"
+			}
+			rule := sonarSeverityRules[s]
+			if r, ok := teamRules[strings.ToUpper(llm.IssueRule(issue))]; ok {
+				rule = sonarRule{ID: r.ID, Name: r.ID, Description: r.Description}
+			}
+			rule.CleanCodeAttribute = attribute
+			b.addRule(rule, quality, s)
+			b.issues = append(b.issues, newSonarIssue(rule.ID, text, path, llm.IssueLine(text), lines))
+		}
+	}
+
+	ids := make([]string, 0, len(b.rules))
+	for id := range b.rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	report := sonarReport{Rules: make([]sonarRule, 0, len(ids)), Issues: b.issues}
+	for _, id := range ids {
+		rule := *b.rules[id]
+		rule.Impacts = []sonarImpact{{SoftwareQuality: b.quality[id], Severity: sonarSeverities[b.severity[id]]}}
+		report.Rules = append(report.Rules, rule)
+	}
+	if report.Issues == nil {
+		report.Issues = []sonarIssue{}
+	}
+	return report
+}
+
+// newSonarIssue 创建问题，行号超出文件范围（模型给错或文件已修改）时作为文件级问题，避免 SonarQube 拒绝导入
+func newSonarIssue(ruleID, message, path string, line, lines int) sonarIssue {
+	loc := sonarLocation{Message: utils.Truncate(message, maxSonarMessage), FilePath: path}
+	if line > 0 && line <= lines {
+		loc.TextRange = &sonarTextRange{StartLine: line}
+	}
+	return sonarIssue{RuleID: ruleID, PrimaryLocation: loc}
+}
+
+// countFileLines 返回文件的行数，读取失败时返回 0
+func countFileLines(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return 0
+	}
+	n := bytes.Count(data, []byte{'\n'})
+	if data[len(data)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// writeSonarFile 将问题写入报告旁的 .sonar.json 文件，返回写入的问题数
+// 未开启导出时删除同名的旧文件，避免与报告内容不一致
+func writeSonarFile(results []Result, reportPath string, meta ReportMeta) (int, error) {
+	path := SonarPath(reportPath)
+	if !meta.Sonar {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("删除旧的 SonarQube 导入文件失败: %w", err)
+		}
+		return 0, nil
+	}
+
+	report := buildSonar(results, meta)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("序列化 SonarQube 导入文件失败: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return 0, fmt.Errorf("写入 SonarQube 导入文件失败: %w", err)
+	}
+	return len(report.Issues), nil
+}
@@ -0,0 +1,154 @@
+package reviewer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// 执行摘要的汇总数据
+const (
+	maxSummaryFiles  = 10 // 列出的得分最低的文件数
+	maxSummaryIssues = 30 // 列出的主要问题数（按严重程度与文件重要性排序）
+)
+
+// summaryIssue 是汇总数据中的一个问题
+type summaryIssue struct {
+	severity   llm.Severity
+	importance float64
+	file       string
+	text       string
+}
+
+// ExecutiveSummary 将审查结果汇总后发送给模型一次，生成执行摘要与按优先级排列的行动项
+// 没有成功审查的文件时返回 nil；请求经过引擎的限流器，汇总数据按发送前遮盖的设置处理
+func (e *Engine) ExecutiveSummary(ctx context.Context, results []Result, meta ReportMeta) (*llm.ExecutiveSummary, error) {
+	if !slices.ContainsFunc(results, func(res Result) bool { return res.Error == nil && res.Review != nil }) {
+		return nil, nil
+	}
+	digest, _ := e.redactor.Redact(summaryDigest(results, meta))
+	if e.limiter != nil {
+		if err := e.limiter.Acquire(ctx); err != nil {
+			return nil, err
+		}
+		defer e.limiter.Release(false)
+	}
+	return e.client.SummarizeReview(ctx, digest)
+}
+
+// summaryDigest 构建发送给模型的汇总数据：综合评分、问题统计、得分最低的文件与主要问题
+func summaryDigest(results []Result, meta ReportMeta) string {
+	stats, _ := calculateStats(results, meta.MinSeverity)
+
+	var reviewed []Result
+	for _, res := range results {
+		if res.Error == nil && res.Review != nil {
+			reviewed = append(reviewed, res)
+		}
+	}
+	issues := collectSummaryIssues(results, meta.MinSeverity)
+	counts := make(map[llm.Severity]int)
+	for _, issue := range issues {
+		counts[issue.severity]++
+	}
+
+	var b strings.Builder
+	if meta.Audit {
+		b.WriteString("审查类型: 安全审计\n")
+	}
+	fmt.Fprintf(&b, "审查级别: %d/6 (%s)\n", meta.Level, getLevelName(meta.Level))
+	if len(meta.Focus) > 0 {
+		fmt.Fprintf(&b, "审查重点: %s\n", llm.FocusLabel(meta.Focus))
+	}
+	if len(meta.Frameworks) > 0 {
+		fmt.Fprintf(&b, "项目框架: %s\n", strings.Join(meta.Frameworks, ", "))
+	}
+	fmt.Fprintf(&b, "综合评分: %.1f / 100 (按文件重要性加权)\n", stats.FinalScore)
+	fmt.Fprintf(&b, "文件: 共 %d 个，有效分析 %d 个，跳过 %d 个\n", stats.TotalFiles, stats.ValidFiles, stats.SkippedFiles)
+	if meta.Partial {
+		fmt.Fprintf(&b, "注意: 审查被中断，另有 %d 个文件未审查\n", meta.Unreviewed)
+	}
+	fmt.Fprintf(&b, "问题: 严重 %d / 重要 %d / 一般 %d\n",
+		counts[llm.SeverityCritical], counts[llm.SeverityMajor], counts[llm.SeverityMinor])
+	if stats.Secrets > 0 {
+		fmt.Fprintf(&b, "疑似敏感信息: %d 处 (%d 个文件)\n", stats.Secrets, stats.SecretFiles)
+	}
+	if len(meta.Duplicates) > 0 {
+		fmt.Fprintf(&b, "重复代码: %d 处 (共 %d 行)\n", len(meta.Duplicates), duplicatedLines(meta.Duplicates))
+	}
+	if len(meta.TestGaps) > 0 {
+		files := make([]string, 0, len(meta.TestGaps))
+		for _, gap := range meta.TestGaps[:min(len(meta.TestGaps), maxSummaryFiles)] {
+			files = append(files, gap.File)
+		}
+		fmt.Fprintf(&b, "缺少测试的重要文件: %d 个 (%s)\n", len(meta.TestGaps), strings.Join(files, ", "))
+	}
+
+	sort.SliceStable(reviewed, func(i, j int) bool {
+		return reviewed[i].Review.Score < reviewed[j].Review.Score
+	})
+	b.WriteString("\n得分最低的文件:\n")
+	for _, res := range reviewed[:min(len(reviewed), maxSummaryFiles)] {
+		fmt.Fprintf(&b, "- %s (得分 %d，重要性 %.1f): %s\n", res.FilePath, res.Review.Score, res.Review.Importance, res.Review.Summary)
+	}
+
+	if len(issues) > 0 {
+		fmt.Fprintf(&b, "\n主要问题 (共 %d 个，按严重程度列出前 %d 个):\n", len(issues), min(len(issues), maxSummaryIssues))
+		for _, issue := range issues[:min(len(issues), maxSummaryIssues)] {
+			fmt.Fprintf(&b, "- [%s] %s: %s\n", issue.severity, issue.file, issue.text)
+		}
+	}
+	return b.String()
+}
+
+// collectSummaryIssues 收集不低于 minSeverity 的问题与漏洞，按严重程度与文件重要性降序排列
+func collectSummaryIssues(results []Result, minSeverity llm.Severity) []summaryIssue {
+	var issues []summaryIssue
+	for _, res := range results {
+		if res.Error != nil || res.Review == nil {
+			continue
+		}
+		for _, issue := range llm.FilterIssues(res.Review.Issues, minSeverity) {
+			s, text := llm.SplitIssue(issue)
+			if s == llm.SeverityUnknown {
+				s = llm.SeverityMajor
+			}
+			issues = append(issues, summaryIssue{s, res.Review.Importance, res.FilePath, text})
+		}
+		for _, f := range res.Review.Findings {
+			if f.Level() >= minSeverity {
+				issues = append(issues, summaryIssue{f.Level(), res.Review.Importance, res.FilePath, f.CWE + " " + f.Title + ": " + f.Description})
+			}
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].severity != issues[j].severity {
+			return issues[i].severity > issues[j].severity
+		}
+		return issues[i].importance > issues[j].importance
+	})
+	return issues
+}
+
+// writeExecutiveSummary 写入执行摘要与按优先级排列的行动项
+func writeExecutiveSummary(f io.Writer, s *llm.ExecutiveSummary) {
+	fmt.Fprintf(f, "## 📝 执行摘要\n\n")
+	fmt.Fprintf(f, "%s\n\n", strings.TrimSpace(s.Summary))
+	if len(s.Actions) > 0 {
+		fmt.Fprintf(f, "### 🎯 优先行动\n\n")
+		for i, action := range s.Actions {
+			fmt.Fprintf(f, "%d. **%s**", i+1, action.Title)
+			if action.Reason != "" {
+				fmt.Fprintf(f, " — %s", action.Reason)
+			}
+			fmt.Fprintln(f)
+		}
+		fmt.Fprintln(f)
+	}
+	fmt.Fprintf(f, "---\n\n")
+}
@@ -0,0 +1,20 @@
+package stats
+
+import "errors"
+
+// ErrEmpty 表示输入为空
+var ErrEmpty = errors.New("stats: empty input")
+
+// Max 返回切片中的最大值，切片为空时返回 ErrEmpty
+func Max(values []int) (int, error) {
+	if len(values) == 0 {
+		return 0, ErrEmpty
+	}
+	m := values[0]
+	for _, v := range values[1:] {
+		if v > m {
+			m = v
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,16 @@
+package queue
+
+// Queue 是一个固定容量的先进先出队列
+type Queue struct {
+	items []string
+	limit int
+}
+
+// Push 将元素加入队尾
+func (q *Queue) Push(item string) {
+	// TODO: 队列已满时返回错误而不是直接退出
+	if len(q.items) >= q.limit {
+		panic("queue: full")
+	}
+	q.items = append(q.items, item)
+}
@@ -0,0 +1,135 @@
+package reviewer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// 缺少测试检测
+const (
+	// DefaultTestGapImportance 是报告缺少测试的文件的默认最低重要性
+	DefaultTestGapImportance = 0.7
+	// MaxTestSuggestions 是询问模型测试重点的文件数上限（按重要性降序）
+	MaxTestSuggestions = 10
+	// maxListedTestGaps 是报告中列出的缺少测试的文件上限
+	maxListedTestGaps = 30
+)
+
+// TestGap 是没有对应测试文件的重要文件
+type TestGap struct {
+	File       string
+	Importance float64
+	Score      int
+	Targets    []llm.TestTarget // 模型建议优先测试的函数，未询问模型时为空
+	Err        error            // 询问模型失败的原因
+}
+
+// FindTestGaps 从审查结果中挑出没有对应测试（见 testgaps.Untested）且重要性不低于 minImportance 的文件，按重要性降序排列
+func FindTestGaps(results []Result, untested []string, minImportance float64) []TestGap {
+	isUntested := make(map[string]struct{}, len(untested))
+	for _, file := range untested {
+		isUntested[file] = struct{}{}
+	}
+
+	var gaps []TestGap
+	for _, res := range results {
+		if res.Review == nil || res.Review.Importance < minImportance {
+			continue
+		}
+		if _, ok := isUntested[res.FilePath]; ok {
+			gaps = append(gaps, TestGap{File: res.FilePath, Importance: res.Review.Importance, Score: res.Review.Score})
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		if gaps[i].Importance != gaps[j].Importance {
+			return gaps[i].Importance > gaps[j].Importance
+		}
+		return gaps[i].File < gaps[j].File
+	})
+	return gaps
+}
+
+// SuggestTests 询问模型前 MaxTestSuggestions 个文件中最需要补充测试的函数，结果写入 gaps
+// 请求经过引擎的限流器，遵循敏感信息检测与发送前遮盖的设置
+func (e *Engine) SuggestTests(ctx context.Context, gaps []TestGap) {
+	var wg sync.WaitGroup
+	for i := range gaps[:min(len(gaps), MaxTestSuggestions)] {
+		wg.Add(1)
+		go func(gap *TestGap) {
+			defer wg.Done()
+			gap.Targets, gap.Err = e.suggestTests(ctx, gap.File)
+		}(&gaps[i])
+	}
+	wg.Wait()
+}
+
+// suggestTests 询问模型单个文件中最需要补充测试的函数
+func (e *Engine) suggestTests(ctx context.Context, file string) ([]llm.TestTarget, error) {
+	content, _, _, err := e.readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if e.secrets.blocks(e.secrets.scan(content)) {
+		return nil, fmt.Errorf("文件包含疑似密钥，未发送")
+	}
+	content, _ = e.redactor.Redact(content)
+
+	if e.limiter != nil {
+		if err := e.limiter.Acquire(ctx); err != nil {
+			return nil, err
+		}
+		defer e.limiter.Release(false)
+	}
+	result, err := e.client.SuggestTests(ctx, llm.ReviewRequest{FilePath: file, Content: content})
+	if err != nil {
+		return nil, err
+	}
+	return result.Functions, nil
+}
+
+// writeTestGaps 写入没有对应测试文件的重要文件
+func writeTestGaps(f io.Writer, gaps []TestGap, outputDir string) {
+	suggested := false
+	for _, gap := range gaps {
+		suggested = suggested || len(gap.Targets) > 0 || gap.Err != nil
+	}
+
+	fmt.Fprintf(f, "## 🧪 缺少测试 (%d 个重要文件)\n\n", len(gaps))
+	fmt.Fprintf(f, "> 以下文件按各语言的测试约定 (如 `foo_test.go`、`test_foo.py`、`foo.test.ts`、`FooTest.java`) 没有找到对应的测试文件。\n\n")
+	if suggested {
+		fmt.Fprintf(f, "| 文件 | 重要性 | 得分 | 建议优先测试 |\n")
+		fmt.Fprintf(f, "|:---|:---|:---|:---|\n")
+	} else {
+		fmt.Fprintf(f, "| 文件 | 重要性 | 得分 |\n")
+		fmt.Fprintf(f, "|:---|:---|:---|\n")
+	}
+	for _, gap := range gaps[:min(len(gaps), maxListedTestGaps)] {
+		fmt.Fprintf(f, "| [%s](%s) | %.1f | %d |", gap.File, getRelativeLink(gap.File, outputDir), gap.Importance, gap.Score)
+		if suggested {
+			fmt.Fprintf(f, " %s |", testTargetsCell(gap))
+		}
+		fmt.Fprintln(f)
+	}
+	if len(gaps) > maxListedTestGaps {
+		fmt.Fprintf(f, "\n> 另有 %d 个重要性较低的文件未列出。\n", len(gaps)-maxListedTestGaps)
+	}
+	fmt.Fprintf(f, "\n---\n\n")
+}
+
+// testTargetsCell 返回建议测试的函数列表单元格，如 "`Parse`: 覆盖空输入<br>`Load`: ..."
+func testTargetsCell(gap TestGap) string {
+	if gap.Err != nil {
+		return "(询问失败: " + escapeTableCell(gap.Err.Error()) + ")"
+	}
+	parts := make([]string, 0, len(gap.Targets))
+	for _, t := range gap.Targets {
+		parts = append(parts, fmt.Sprintf("`%s`: %s", escapeTableCell(t.Name), escapeTableCell(t.Reason)))
+	}
+	return strings.Join(parts, "<br>")
+}
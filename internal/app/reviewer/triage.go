@@ -0,0 +1,135 @@
+package reviewer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"go-ai-reviewer/internal/llm"
+)
+
+// DefaultTriageRatio 是两阶段模式下进入深度审查的默认文件比例
+const DefaultTriageRatio = 0.3
+
+// triageStage 描述两阶段模式的初筛配置
+type triageStage struct {
+	client *llm.Client
+	ratio  float64
+}
+
+// WithTriage 开启两阶段模式：先用廉价模型 triageClient 初筛所有文件，
+// 再对优先级（重要性 × 风险）最高的 ratio 比例文件以最高严格级别深度审查
+func WithTriage(triageClient *llm.Client, ratio float64) Option {
+	return func(e *Engine) {
+		if triageClient == nil {
+			return
+		}
+		if ratio <= 0 || ratio > 1 {
+			ratio = DefaultTriageRatio
+		}
+		e.triage = &triageStage{client: triageClient, ratio: ratio}
+		e.level = MaxLevel
+	}
+}
+
+// errSecretsBlocked 表示文件包含疑似密钥而未发送给初筛模型
+var errSecretsBlocked = errors.New("文件包含疑似密钥，未发送")
+
+// triaged 表示单个文件的初筛结果
+type triaged struct {
+	path   string
+	result *llm.TriageResult
+	err    error
+}
+
+// runTriage 并发初筛所有文件，将未入选深度审查的文件作为初筛结果发送，
+// 返回按优先级排序的深度审查文件列表（初筛失败的文件直接进入深度审查）
+func (e *Engine) runTriage(ctx context.Context, files []string, results chan<- Result) []string {
+	items := make([]triaged, len(files))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	workers := e.workerCount()
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				items[idx] = e.triageFile(ctx, files[idx])
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		// 到达运行截止时间：剩余文件不再初筛，交给深度审查阶段标记为未审查
+		if e.pastDeadline() {
+			for j := i; j < len(files); j++ {
+				items[j].path = files[j]
+			}
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	// 初筛成功的文件按优先级降序排序
+	var deep, ranked []triaged
+	for _, item := range items {
+		if item.err != nil || item.result == nil {
+			deep = append(deep, item)
+			continue
+		}
+		ranked = append(ranked, item)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].result.Priority() > ranked[j].result.Priority()
+	})
+
+	keep := int(math.Ceil(float64(len(ranked)) * e.triage.ratio))
+	deepFiles := make([]string, 0, keep+len(deep))
+	for _, item := range ranked[:keep] {
+		deepFiles = append(deepFiles, item.path)
+	}
+	for _, item := range deep {
+		deepFiles = append(deepFiles, item.path)
+	}
+
+	// 未入选的文件只保留初筛结论
+	for _, item := range ranked[keep:] {
+		select {
+		case <-ctx.Done():
+			return nil
+		case results <- Result{FilePath: item.path, Triage: item.result}:
+		}
+	}
+
+	return deepFiles
+}
+
+// triageFile 读取并初筛单个文件
+func (e *Engine) triageFile(ctx context.Context, path string) triaged {
+	content, _, _, err := e.readFile(path)
+	if err != nil {
+		return triaged{path: path, err: err}
+	}
+	// 不允许发送的文件不初筛，交给深度审查阶段标记为跳过
+	if e.secrets.blocks(e.secrets.scan(content)) {
+		return triaged{path: path, err: errSecretsBlocked}
+	}
+	content, _ = e.redactor.Redact(content)
+
+	result, err := e.triage.client.TriageCode(ctx, llm.ReviewRequest{FilePath: path, Content: content})
+	return triaged{path: path, result: result, err: err}
+}
@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFileList 从 r 读取文件列表（每行一个路径），忽略空行与 # 开头的注释行，重复路径只保留一次
+func ReadFileList(r io.Reader) ([]string, error) {
+	var paths []string
+	seen := make(map[string]struct{})
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		path := filepath.Clean(line)
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+		paths = append(paths, path)
+	}
+	return paths, sc.Err()
+}
+
+// FilterFiles 过滤显式指定的文件列表，不做目录扫描与规则过滤：
+// 仅保留存在的普通文本文件，已删除的文件（如 git diff 中的删除项）、目录与二进制文件会被丢弃
+func FilterFiles(paths []string) []string {
+	files := make([]string, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		header, err := readHeader(path)
+		if err != nil || isBinary(header) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files
+}
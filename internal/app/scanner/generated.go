@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 跳过原因
+const (
+	SkipGenerated = "generated" // 自动生成的代码
+	SkipVendored  = "vendored"  // 第三方/内嵌依赖代码
+	SkipMinified  = "minified"  // 压缩代码或打包产物
+)
+
+// SkippedFile 表示扫描时被过滤、但需要在报告中说明的文件
+type SkippedFile struct {
+	Path   string
+	Reason string
+}
+
+// generatedSuffixes 是约定俗成的生成文件后缀
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_pb2.py", "_pb2_grpc.py", ".pb.h", ".pb.cc",
+	"_generated.go", ".generated.ts", ".g.dart", ".designer.cs",
+}
+
+// generatedPrefixes 是约定俗成的生成文件前缀
+var generatedPrefixes = []string{"zz_generated", "bindata"}
+
+// lockFiles 是依赖锁文件，由包管理器生成
+var lockFiles = map[string]struct{}{
+	"package-lock.json": {},
+	"yarn.lock":         {},
+	"pnpm-lock.yaml":    {},
+	"go.sum":            {},
+	"Cargo.lock":        {},
+	"poetry.lock":       {},
+	"composer.lock":     {},
+	"Gemfile.lock":      {},
+}
+
+// vendoredDirs 是第三方代码目录（vendor 等已在默认排除列表中）
+var vendoredDirs = map[string]struct{}{
+	"third_party":      {},
+	"third-party":      {},
+	"bower_components": {},
+	"Pods":             {},
+}
+
+// generatedMarkerRegex 匹配文件头部的生成代码标记
+// Go 约定: https://go.dev/s/generatedcode
+var generatedMarkerRegex = regexp.MustCompile(`(?mi)^\s*(//|#|/\*|\*|<!--)?\s*(Code generated .* DO NOT EDIT|@generated|Autogenerated|Auto-generated|This file (was|is) (automatically|auto)[- ]generated)`)
+
+// isGeneratedName 根据文件名判断是否为生成文件
+func isGeneratedName(name string) bool {
+	lower := strings.ToLower(name)
+	if _, ok := lockFiles[name]; ok {
+		return true
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	for _, prefix := range generatedPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGeneratedMarker 判断文件头部是否包含生成代码标记
+func hasGeneratedMarker(header []byte) bool {
+	return generatedMarkerRegex.Match(header)
+}
+
+// inVendoredDir 判断相对路径是否位于第三方代码目录中
+func inVendoredDir(relPath string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/") {
+		if _, ok := vendoredDirs[part]; ok {
+			return true
+		}
+	}
+	return false
+}
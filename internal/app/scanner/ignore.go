@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// ignoreMatcher 是一组以 base 目录为根的忽略规则
+type ignoreMatcher struct {
+	base string
+	gi   *ignore.GitIgnore
+}
+
+// matches 判断 path 是否被忽略，path 不在 base 之下时不匹配
+func (m ignoreMatcher) matches(path string) bool {
+	rel, err := filepath.Rel(m.base, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	return m.gi.MatchesPath(filepath.ToSlash(rel))
+}
+
+// ReviewIgnoreFile 是扫描根目录下只影响代码审查的忽略文件，语法与 .gitignore 相同
+const ReviewIgnoreFile = ".reviewignore"
+
+// loadIgnoreMatchers 按 git 的规则加载忽略文件：
+// 扫描根目录的 .gitignore 与 .reviewignore、仓库的 .git/info/exclude 以及用户的全局忽略文件（core.excludesFile）
+// 任一文件不存在或解析失败时静默跳过
+func loadIgnoreMatchers(root string) []ignoreMatcher {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+
+	var matchers []ignoreMatcher
+	add := func(base, file string) {
+		if file == "" {
+			return
+		}
+		if _, err := os.Stat(file); err != nil {
+			return
+		}
+		if gi, err := ignore.CompileIgnoreFile(file); err == nil {
+			matchers = append(matchers, ignoreMatcher{base: base, gi: gi})
+		}
+	}
+
+	add(absRoot, filepath.Join(absRoot, ".gitignore"))
+	add(absRoot, filepath.Join(absRoot, ReviewIgnoreFile))
+
+	// 仓库级与全局规则以仓库根目录为基准，不在仓库中时以扫描根目录为基准
+	repoRoot := findRepoRoot(absRoot)
+	base := absRoot
+	if repoRoot != "" {
+		base = repoRoot
+		add(repoRoot, filepath.Join(repoRoot, ".git", "info", "exclude"))
+	}
+	add(base, globalExcludesFile(absRoot))
+
+	return matchers
+}
+
+// findRepoRoot 从 dir 向上查找包含 .git 目录的仓库根目录，找不到时返回空字符串
+func findRepoRoot(dir string) string {
+	for {
+		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// globalExcludesFile 返回用户的全局忽略文件路径：
+// 优先使用 git config core.excludesFile（在 dir 中执行，包含仓库级配置），未配置时使用 git 的默认位置 $XDG_CONFIG_HOME/git/ignore
+func globalExcludesFile(dir string) string {
+	if _, err := exec.LookPath("git"); err == nil {
+		cmd := exec.Command("git", "config", "--get", "core.excludesFile")
+		cmd.Dir = dir
+		if out, err := cmd.Output(); err == nil {
+			if path := strings.TrimSpace(string(out)); path != "" {
+				return expandHome(path)
+			}
+		}
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "git", "ignore")
+}
+
+// expandHome 展开路径开头的 ~/
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"go-ai-reviewer/internal/app/textenc"
+)
+
+// 压缩代码检测阈值
+const (
+	minifiedSampleSize      = 8 * 1024 // 检测时读取的字节数
+	minifiedMinSample       = 512      // 内容过短时不做判断
+	minifiedLongLine        = 1000     // 超过该长度视为超长行
+	minifiedAvgLine         = 300      // 平均行长超过该值视为压缩代码
+	minifiedWhitespaceRatio = 0.03     // 空白字符占比低于该值视为压缩代码
+)
+
+// minifiedSuffixes 是约定俗成的压缩代码与打包产物后缀
+var minifiedSuffixes = []string{
+	".min.js", ".min.mjs", ".min.css", ".bundle.js", ".chunk.js", ".chunk.css",
+	".js.map", ".css.map",
+}
+
+// bundleExts 是需要检测打包标识的文件扩展名（其他语言的源码可能合法地提到这些标识）
+var bundleExts = map[string]struct{}{".js": {}, ".mjs": {}, ".cjs": {}}
+
+// bundleMarkers 是打包工具产物中的典型标识
+var bundleMarkers = [][]byte{
+	[]byte("__webpack_require__"),
+	[]byte("webpackBootstrap"),
+	[]byte("webpackChunk"),
+	[]byte("parcelRequire"),
+	[]byte("__vite__"),
+}
+
+// isMinifiedFile 判断文件是否为压缩代码或打包产物，header 为已读取的文件头部
+func isMinifiedFile(path, baseName string, header []byte) bool {
+	lower := strings.ToLower(baseName)
+	for _, suffix := range minifiedSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+
+	sample := header
+	if len(header) == headerSize {
+		if s, err := readSample(path, minifiedSampleSize); err == nil {
+			sample = textenc.ToUTF8(s)
+		}
+	}
+	if _, ok := bundleExts[filepath.Ext(lower)]; ok && isBundle(sample) {
+		return true
+	}
+	return isMinified(sample)
+}
+
+// isBundle 判断内容是否包含打包工具的运行时标识
+func isBundle(sample []byte) bool {
+	for _, marker := range bundleMarkers {
+		if bytes.Contains(sample, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMinified 根据行长与空白字符占比判断内容是否为压缩代码
+func isMinified(sample []byte) bool {
+	if len(sample) < minifiedMinSample {
+		return false
+	}
+
+	lines := bytes.Split(sample, []byte("\n"))
+	longest, whitespace := 0, 0
+	for _, line := range lines {
+		longest = max(longest, len(line))
+	}
+	for _, c := range sample {
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			whitespace++
+		}
+	}
+
+	avgLine := len(sample) / len(lines)
+	if longest >= minifiedLongLine && avgLine >= minifiedAvgLine {
+		return true
+	}
+	return float64(whitespace)/float64(len(sample)) < minifiedWhitespaceRatio
+}
@@ -2,14 +2,14 @@
 package scanner
 
 import (
-	"bytes"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	ignore "github.com/sabhiram/go-gitignore"
+	"go-ai-reviewer/internal/app/textenc"
 )
 
 // 默认排除的目录名（精确匹配目录名，非路径）
@@ -29,9 +29,18 @@ var defaultExcludeDirs = map[string]struct{}{
 // Scanner 负责文件扫描和过滤
 type Scanner struct {
 	rootPath    string
-	gitIgnore   *ignore.GitIgnore
+	absRoot     string
+	ignores     []ignoreMatcher     // .gitignore、.reviewignore、.git/info/exclude 与全局忽略规则
 	includeExts map[string]struct{} // 使用 map 提高查找效率
 	excludeDirs map[string]struct{} // 排除的目录名（非路径）
+
+	skipGenerated  bool          // 是否跳过生成代码与第三方代码
+	followSymlinks bool          // 是否跟随符号链接
+	since          time.Time     // 只保留该时间之后修改的文件，零值表示不过滤
+	maxDepth       int           // 最大扫描深度（根目录下的文件深度为 1），0 表示不限制
+	includeHidden  bool          // 是否扫描以 . 开头的隐藏文件与目录
+	skipTests      bool          // 是否跳过测试代码
+	skipped        []SkippedFile // 需要在报告中说明的跳过文件
 }
 
 // Option 定义 Scanner 的配置选项
@@ -46,6 +55,48 @@ func WithExcludeDirs(dirs []string) Option {
 	}
 }
 
+// WithSkipGenerated 设置是否跳过生成代码与第三方代码（默认跳过）
+func WithSkipGenerated(enabled bool) Option {
+	return func(s *Scanner) {
+		s.skipGenerated = enabled
+	}
+}
+
+// WithFollowSymlinks 设置是否跟随符号链接（默认跳过），跟随时记录已访问目录以防止循环
+func WithFollowSymlinks(enabled bool) Option {
+	return func(s *Scanner) {
+		s.followSymlinks = enabled
+	}
+}
+
+// WithModifiedSince 只保留 since 之后修改的文件，零值表示不过滤
+func WithModifiedSince(since time.Time) Option {
+	return func(s *Scanner) {
+		s.since = since
+	}
+}
+
+// WithMaxDepth 限制扫描深度，depth 为 1 时只扫描根目录下的文件，0 表示不限制
+func WithMaxDepth(depth int) Option {
+	return func(s *Scanner) {
+		s.maxDepth = max(depth, 0)
+	}
+}
+
+// WithIncludeHidden 设置是否扫描隐藏文件与目录（默认跳过），.git 等默认排除目录仍然排除
+func WithIncludeHidden(enabled bool) Option {
+	return func(s *Scanner) {
+		s.includeHidden = enabled
+	}
+}
+
+// WithSkipTests 设置是否跳过测试代码（*_test.go、*.spec.ts、__tests__/ 等）
+func WithSkipTests(enabled bool) Option {
+	return func(s *Scanner) {
+		s.skipTests = enabled
+	}
+}
+
 // NewScanner 创建一个新的 Scanner 实例
 func NewScanner(root string, includeExts []string, opts ...Option) (*Scanner, error) {
 	// 验证根目录是否存在
@@ -74,9 +125,10 @@ func NewScanner(root string, includeExts []string, opts ...Option) (*Scanner, er
 	}
 
 	s := &Scanner{
-		rootPath:    root,
-		includeExts: extMap,
-		excludeDirs: excludeDirs,
+		rootPath:      root,
+		includeExts:   extMap,
+		excludeDirs:   excludeDirs,
+		skipGenerated: true,
 	}
 
 	// 应用选项
@@ -84,27 +136,60 @@ func NewScanner(root string, includeExts []string, opts ...Option) (*Scanner, er
 		opt(s)
 	}
 
-	// 尝试加载 .gitignore（可选，失败不影响扫描）
-	gitIgnorePath := filepath.Join(root, ".gitignore")
-	if _, err := os.Stat(gitIgnorePath); err == nil {
-		if gi, err := ignore.CompileIgnoreFile(gitIgnorePath); err == nil {
-			s.gitIgnore = gi
-		}
-		// 如果 .gitignore 解析失败，静默忽略，不影响扫描
+	// 加载忽略规则（可选，失败不影响扫描）
+	if s.absRoot, err = filepath.Abs(root); err != nil {
+		s.absRoot = root
 	}
+	s.ignores = loadIgnoreMatchers(root)
 
 	return s, nil
 }
 
+// Skipped 返回最近一次扫描中被过滤、需要在报告中说明的文件
+func (s *Scanner) Skipped() []SkippedFile {
+	return s.skipped
+}
+
 // Scan 执行扫描并返回文件列表
 func (s *Scanner) Scan() ([]string, error) {
-	var files []string
+	s.skipped = nil
+
+	w := &walkState{}
+	err := s.walk(w, s.rootPath, s.rootPath)
+	return w.files, err
+}
+
+// walkState 记录一次扫描的结果与已访问的目录
+type walkState struct {
+	files   []string
+	visited []os.FileInfo // 跟随符号链接时已访问的目录，用于检测循环
+}
 
-	err := filepath.WalkDir(s.rootPath, func(path string, d fs.DirEntry, err error) error {
+// seen 判断目录是否已访问过（比较设备号与 inode）
+func (w *walkState) seen(info os.FileInfo) bool {
+	for _, v := range w.visited {
+		if os.SameFile(v, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk 遍历 dir，logicalDir 是 dir 在扫描根目录下的逻辑路径
+// 二者仅在跟随符号链接进入目标目录时不同，报告中的路径始终使用逻辑路径
+func (s *Scanner) walk(w *walkState, dir, logicalDir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			// 跳过无法访问的文件/目录，继续扫描
 			return nil
 		}
+		if dir != logicalDir {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return nil
+			}
+			path = filepath.Join(logicalDir, rel)
+		}
 
 		// 1. 获取相对路径
 		relPath, err := filepath.Rel(s.rootPath, path)
@@ -112,76 +197,207 @@ func (s *Scanner) Scan() ([]string, error) {
 			return nil // 跳过无法获取相对路径的文件
 		}
 
-		// 2. 跳过根目录自身
-		if relPath == "." {
+		// 2. 跳过根目录自身（包括符号链接的目标目录，链接本身已检查过）
+		if path == logicalDir {
+			s.markVisited(w, d)
 			return nil
 		}
 
-		// 3. 检查是否是符号链接（跳过以避免循环）
-		if d.Type()&fs.ModeSymlink != 0 {
+		// 3. 超出最大深度的条目不再处理
+		depth := pathDepth(relPath)
+		if s.maxDepth > 0 && depth > s.maxDepth {
 			if d.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// 4. 检查目录名是否在排除列表中
-		baseName := d.Name()
-		if _, excluded := s.excludeDirs[baseName]; excluded {
+		// 4. 检查是否是符号链接（未开启跟随时跳过以避免循环）
+		isSymlink := d.Type()&fs.ModeSymlink != 0
+		if isSymlink && !s.followSymlinks {
 			if d.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// 5. 检查 .gitignore 规则
-		if s.gitIgnore != nil && s.gitIgnore.MatchesPath(relPath) {
+		// 5. 检查目录名是否在排除列表中，以及是否为隐藏文件
+		baseName := d.Name()
+		_, excluded := s.excludeDirs[baseName]
+		if excluded || (!s.includeHidden && strings.HasPrefix(baseName, ".")) {
 			if d.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// 6. 跳过目录，只处理文件
-		if d.IsDir() {
+		// 6. 检查 .gitignore、.reviewignore、.git/info/exclude 与全局忽略规则
+		if s.ignored(filepath.Join(s.absRoot, relPath)) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		// 7. 检查文件扩展名（如果设置了白名单）
-		if len(s.includeExts) > 0 {
-			ext := strings.ToLower(filepath.Ext(path))
-			if _, ok := s.includeExts[ext]; !ok {
-				return nil
-			}
+		// 7. 跟随符号链接
+		if isSymlink {
+			return s.followSymlink(w, path, relPath, baseName)
 		}
 
-		// 8. 检查是否为二进制文件
-		if isBinary, _ := isBinaryFile(path); isBinary {
+		// 8. 目录只记录访问状态（已达最大深度时不再进入），只处理文件
+		if d.IsDir() {
+			if !s.markVisited(w, d) || (s.maxDepth > 0 && depth >= s.maxDepth) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		files = append(files, path)
+		s.visitFile(w, path, relPath, baseName)
 		return nil
 	})
+}
+
+// markVisited 在跟随符号链接时记录已访问的目录，目录已访问过时返回 false
+func (s *Scanner) markVisited(w *walkState, d fs.DirEntry) bool {
+	if !s.followSymlinks {
+		return true
+	}
+	info, err := d.Info()
+	if err != nil {
+		return true
+	}
+	if w.seen(info) {
+		return false
+	}
+	w.visited = append(w.visited, info)
+	return true
+}
+
+// followSymlink 解析符号链接：指向目录时遍历目标目录（已访问过的目录跳过，防止循环），指向文件时按普通文件处理
+func (s *Scanner) followSymlink(w *walkState, path, relPath, baseName string) error {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil // 悬空链接
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case info.IsDir():
+		if w.seen(info) || (s.maxDepth > 0 && pathDepth(relPath) >= s.maxDepth) {
+			return nil
+		}
+		return s.walk(w, target, path)
+	case info.Mode().IsRegular():
+		s.visitFile(w, path, relPath, baseName)
+	}
+	return nil
+}
+
+// pathDepth 返回相对路径的深度（根目录下的条目为 1）
+func pathDepth(relPath string) int {
+	return strings.Count(filepath.ToSlash(relPath), "/") + 1
+}
+
+// visitFile 检查单个文件，通过过滤的文件加入扫描结果
+func (s *Scanner) visitFile(w *walkState, path, relPath, baseName string) {
+	// 1. 检查文件扩展名（如果设置了白名单）
+	if len(s.includeExts) > 0 {
+		ext := strings.ToLower(filepath.Ext(path))
+		if _, ok := s.includeExts[ext]; !ok {
+			return
+		}
+	}
+
+	// 2. 检查是否为测试代码
+	if s.skipTests && isTestFile(relPath, baseName) {
+		return
+	}
 
-	return files, err
+	// 3. 检查修改时间
+	if !s.since.IsZero() {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Before(s.since) {
+			return
+		}
+	}
+
+	// 4. 读取文件头部，检查是否为二进制文件
+	header, err := readHeader(path)
+	if err != nil || isBinary(header) {
+		return
+	}
+	header = textenc.ToUTF8(header)
+
+	// 5. 检查是否为生成代码、第三方代码或压缩/打包产物
+	if s.skipGenerated {
+		if reason := generatedReason(path, relPath, baseName, header); reason != "" {
+			s.skipped = append(s.skipped, SkippedFile{Path: path, Reason: reason})
+			return
+		}
+	}
+
+	w.files = append(w.files, path)
+}
+
+// ignored 判断绝对路径是否被任一忽略规则匹配
+func (s *Scanner) ignored(absPath string) bool {
+	for _, m := range s.ignores {
+		if m.matches(absPath) {
+			return true
+		}
+	}
+	return false
 }
 
-// isBinaryFile 检测文件是否为二进制文件
-// 通过检查前 512 字节是否包含 NULL 字符来判断
-func isBinaryFile(path string) (bool, error) {
+// headerSize 是检测二进制与生成代码标记时读取的文件头部大小
+const headerSize = 1024
+
+// binarySniffSize 是二进制检测使用的字节数
+const binarySniffSize = 512
+
+// readHeader 读取文件头部
+func readHeader(path string) ([]byte, error) {
+	return readSample(path, headerSize)
+}
+
+// readSample 读取文件开头最多 size 字节
+func readSample(path string, size int) ([]byte, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer f.Close()
 
-	buffer := make([]byte, 512)
-	n, err := f.Read(buffer)
-	if err != nil && err != io.EOF {
-		return false, err
+	buffer := make([]byte, size)
+	n, err := io.ReadFull(f, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return buffer[:n], nil
+}
+
+// isBinary 检测文件头部是否为二进制内容
+// 检查前 512 字节的 BOM、UTF-16 特征、NULL 字符与控制字符比例
+func isBinary(header []byte) bool {
+	if len(header) > binarySniffSize {
+		header = header[:binarySniffSize]
 	}
+	return textenc.IsBinary(header)
+}
 
-	// 包含 NULL 字符则认为是二进制文件
-	return bytes.IndexByte(buffer[:n], 0) != -1, nil
+// generatedReason 判断文件是否为生成代码、第三方代码或压缩/打包产物，返回跳过原因（空字符串表示不跳过）
+func generatedReason(path, relPath, baseName string, header []byte) string {
+	if inVendoredDir(relPath) {
+		return SkipVendored
+	}
+	if isMinifiedFile(path, baseName, header) {
+		return SkipMinified
+	}
+	if isGeneratedName(baseName) || hasGeneratedMarker(header) {
+		return SkipGenerated
+	}
+	return ""
 }
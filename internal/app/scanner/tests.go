@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// testDirs 是约定俗成的测试目录名
+var testDirs = map[string]struct{}{
+	"__tests__": {},
+	"__mocks__": {},
+	"test":      {},
+	"tests":     {},
+	"spec":      {},
+	"testdata":  {},
+}
+
+// testSuffixes 是各语言测试文件的后缀（按小写比较）
+var testSuffixes = []string{
+	"_test.go",
+	".test.js", ".test.jsx", ".test.ts", ".test.tsx", ".test.mjs",
+	".spec.js", ".spec.jsx", ".spec.ts", ".spec.tsx", ".spec.mjs",
+	"_test.py", "_spec.rb", "_test.rb", "_test.exs",
+}
+
+// testClassSuffixes 是 Java/Kotlin/C#/PHP/Swift 等语言测试类的命名后缀（区分大小写）
+var testClassSuffixes = []string{"Test", "Tests", "Spec"}
+
+// IsTestFile 判断相对于扫描根目录的路径是否为测试代码（规则与 WithSkipTests 相同）
+func IsTestFile(relPath string) bool {
+	return isTestFile(relPath, filepath.Base(relPath))
+}
+
+// isTestFile 根据文件名与所在目录判断是否为测试代码
+func isTestFile(relPath, baseName string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/") {
+		if _, ok := testDirs[part]; ok {
+			return true
+		}
+	}
+
+	lower := strings.ToLower(baseName)
+	for _, suffix := range testSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	if strings.HasPrefix(lower, "test_") && strings.HasSuffix(lower, ".py") || lower == "conftest.py" {
+		return true
+	}
+
+	stem := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	for _, suffix := range testClassSuffixes {
+		if len(stem) > len(suffix) && strings.HasSuffix(stem, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 遮盖类型与替换文本
+const (
+	KindEmail  = "邮箱"
+	KindCustom = "自定义规则"
+
+	redactedSecret = "[REDACTED_SECRET]"
+	redactedEmail  = "[REDACTED_EMAIL]"
+	redactedCustom = "[REDACTED]"
+)
+
+// emailRegex 匹配邮箱地址
+var emailRegex = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`)
+
+// Redaction 记录一处被遮盖的内容（不含原文）
+type Redaction struct {
+	Kind string // 检测规则名称（如 "GitHub Token"）、邮箱或自定义规则
+	Line int    // 所在行号（从 1 开始）
+}
+
+// Redactor 在内容发送给模型之前遮盖疑似密钥、邮箱与自定义规则匹配的内容
+type Redactor struct {
+	custom []*regexp.Regexp
+}
+
+// NewRedactor 创建遮盖器，patterns 是额外需要遮盖的正则表达式（如内部域名、工号）
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("无效的遮盖规则 %q: %w", p, err)
+		}
+		r.custom = append(r.custom, re)
+	}
+	return r, nil
+}
+
+// span 是一段待遮盖的内容
+type span struct {
+	start, end  int
+	kind        string
+	replacement string
+}
+
+// Redact 返回遮盖后的内容与遮盖记录，r 为空时原样返回
+// 替换文本保留原内容中的换行，保证遮盖前后的行号一致（模型引用的行号与修复补丁仍然有效）
+func (r *Redactor) Redact(content string) (string, []Redaction) {
+	if r == nil {
+		return content, nil
+	}
+
+	var spans []span
+	for _, f := range Scan(content) {
+		spans = append(spans, span{f.Start, f.End, f.Rule, redactedSecret})
+	}
+	for _, m := range emailRegex.FindAllStringIndex(content, -1) {
+		spans = append(spans, span{m[0], m[1], KindEmail, redactedEmail})
+	}
+	for _, re := range r.custom {
+		for _, m := range re.FindAllStringIndex(content, -1) {
+			if m[1] > m[0] {
+				spans = append(spans, span{m[0], m[1], KindCustom, redactedCustom})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return content, nil
+	}
+
+	// 按位置排序，重叠时保留先出现（位置相同时保留密钥规则）的一段
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	var redactions []Redaction
+	pos, line := 0, 1
+	for _, s := range spans {
+		if s.start < pos {
+			continue
+		}
+		b.WriteString(content[pos:s.start])
+		line += strings.Count(content[pos:s.start], "\n")
+		redactions = append(redactions, Redaction{Kind: s.kind, Line: line})
+
+		original := content[s.start:s.end]
+		b.WriteString(s.replacement)
+		b.WriteString(strings.Repeat("\n", strings.Count(original, "\n")))
+		line += strings.Count(original, "\n")
+		pos = s.end
+	}
+	b.WriteString(content[pos:])
+	return b.String(), redactions
+}
@@ -0,0 +1,145 @@
+// Package secrets 在源码发送给模型之前检测疑似密钥、令牌与私钥
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding 是检测到的一处疑似敏感信息
+type Finding struct {
+	Rule    string // 规则名称，如 "AWS Access Key"
+	Line    int    // 所在行号（从 1 开始）
+	Preview string // 遮盖后的内容，只保留首尾少量字符，可安全写入报告
+
+	// Start、End 是敏感内容在原文中的字节偏移，用于遮盖
+	Start, End int
+}
+
+// rule 描述一条检测规则
+// pattern 中名为 "secret" 的分组是敏感内容本身（没有该分组时为整个匹配），
+// minEntropy > 0 时只有字符熵不低于该值的内容才视为密钥，用于排除占位符与普通单词；
+// preview 生成报告中显示的内容，为空时使用 Mask
+type rule struct {
+	name       string
+	pattern    *regexp.Regexp
+	minEntropy float64
+	preview    func(string) string
+}
+
+// rules 是内置的检测规则，先匹配的规则优先（同一位置只报告一次）
+var rules = []rule{
+	// 完整的私钥块优先；只有开头（截断或格式不完整）时也视为私钥
+	{name: "私钥", pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----[\s\S]*?-----END (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`), preview: firstLine},
+	{name: "私钥", pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`), preview: firstLine},
+	{name: "AWS Access Key", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "GitHub Token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{name: "GitLab Token", pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{name: "Slack Token", pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{name: "Google API Key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{name: "Stripe Key", pattern: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{20,}\b`)},
+	{name: "OpenAI/Anthropic API Key", pattern: regexp.MustCompile(`\bsk-(?:proj-|ant-(?:api\d+-)?)?[A-Za-z0-9_-]{20,}\b`), minEntropy: 3.5},
+	{name: "JWT", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{name: "连接串中的密码", pattern: regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@"']+:(?P<secret>[^/\s@"']{4,})@`), minEntropy: 2.5},
+	{
+		name:       "硬编码凭据",
+		pattern:    regexp.MustCompile(`(?i)\b[\w.-]*(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|credential)[\w.-]*["']?\s*(?::=|[:=]|=>)\s*["'](?P<secret>[^"'\s]{8,})["']`),
+		minEntropy: 3.0,
+	},
+}
+
+// placeholders 是常见的占位写法，匹配到的内容不视为密钥
+var placeholders = []string{"${", "{{", "<", "xxxx", "****", "changeme", "change_me", "example", "placeholder", "your_", "your-", "dummy", "redacted"}
+
+// Scan 检测 content 中的疑似敏感信息，按出现位置排序
+func Scan(content string) []Finding {
+	var findings []Finding
+	var taken [][2]int // 已被前面的规则匹配的区间
+	for _, r := range rules {
+		group := r.pattern.SubexpIndex("secret")
+		for _, m := range r.pattern.FindAllStringSubmatchIndex(content, -1) {
+			start, end := m[0], m[1]
+			if group > 0 && m[2*group] >= 0 {
+				start, end = m[2*group], m[2*group+1]
+			}
+			value := content[start:end]
+			if r.minEntropy > 0 && (isPlaceholder(value) || entropy(value) < r.minEntropy) {
+				continue
+			}
+			if overlaps(taken, start, end) {
+				continue
+			}
+			taken = append(taken, [2]int{start, end})
+			preview := Mask
+			if r.preview != nil {
+				preview = r.preview
+			}
+			findings = append(findings, Finding{
+				Rule:    r.name,
+				Line:    strings.Count(content[:start], "\n") + 1,
+				Preview: preview(value),
+				Start:   start,
+				End:     end,
+			})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Start < findings[j].Start })
+	return findings
+}
+
+// Mask 遮盖敏感内容，只保留开头 4 个与结尾 2 个字符（过短时全部遮盖）
+func Mask(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 10 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + strings.Repeat("*", min(len(runes)-6, 12)) + string(runes[len(runes)-2:])
+}
+
+// firstLine 返回内容的第一行（私钥的 BEGIN 行本身不含敏感信息）
+func firstLine(value string) string {
+	line, _, _ := strings.Cut(value, "\n")
+	return strings.TrimRight(line, "\r")
+}
+
+// isPlaceholder 判断内容是否为占位符或示例值
+func isPlaceholder(value string) bool {
+	lower := strings.ToLower(value)
+	for _, p := range placeholders {
+		if strings.Contains(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// entropy 计算字符串的香农熵（比特/字符），随机生成的密钥通常在 3.5 以上
+func entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// overlaps 判断 [start, end) 是否与已有区间重叠
+func overlaps(ranges [][2]int, start, end int) bool {
+	for _, r := range ranges {
+		if start < r[1] && r[0] < end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+// Package share 将审查报告上传到 GitHub Gist（默认为不公开的 secret gist）或粘贴服务，返回可分享的链接
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/pkg/utils"
+)
+
+// 上传目标
+const (
+	ProviderGist  = "gist"  // GitHub Gist，需要具有 gist 权限的 Token
+	ProviderPaste = "paste" // 粘贴服务：以请求体上传原文，响应中返回链接
+)
+
+// DefaultGitHubAPI 是 GitHub API 地址，GitHub Enterprise 为 https://github.example.com/api/v3
+const DefaultGitHubAPI = "https://api.github.com"
+
+// requestTimeout 是上传请求的时限（报告可能较大）
+const requestTimeout = 60 * time.Second
+
+// Config 是上传目标配置
+type Config struct {
+	Provider string // gist 或 paste
+	URL      string // gist 为 GitHub API 地址（为空时为 DefaultGitHubAPI），paste 为上传地址
+	Token    string // gist 为 GitHub Token；paste 不为空时以 Bearer 认证发送
+}
+
+// File 是要分享的报告
+type File struct {
+	Name        string // 文件名，Gist 据此识别 Markdown 并渲染
+	Content     string
+	Description string // Gist 的描述，粘贴服务忽略
+	Public      bool   // 公开的 Gist 会出现在用户主页与搜索中，默认只有持有链接的人可以查看
+}
+
+// Upload 上传文件并返回浏览地址
+func Upload(ctx context.Context, cfg Config, file File) (string, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	switch cfg.Provider {
+	case ProviderGist:
+		return uploadGist(ctx, client, cfg, file)
+	case ProviderPaste:
+		// 部分粘贴服务以重定向返回链接，不跟随重定向，从 Location 中读取
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+		return uploadPaste(ctx, client, cfg, file)
+	default:
+		return "", fmt.Errorf("不支持的上传目标 %q (可选: %s / %s)", cfg.Provider, ProviderGist, ProviderPaste)
+	}
+}
+
+// uploadGist 创建 Gist，返回其页面地址
+func uploadGist(ctx context.Context, client *http.Client, cfg Config, file File) (string, error) {
+	body, err := json.Marshal(map[string]any{
+		"description": file.Description,
+		"public":      file.Public,
+		"files":       map[string]any{file.Name: map[string]string{"content": file.Content}},
+	})
+	if err != nil {
+		return "", err
+	}
+	api := strings.TrimRight(cfg.URL, "/")
+	if api == "" {
+		api = DefaultGitHubAPI
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, api+"/gists", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", "Bearer "+cfg.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("创建 Gist 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("创建 Gist 失败: %d %s: %s", resp.StatusCode, http.StatusText(resp.StatusCode), utils.ErrorDetail(data))
+	}
+
+	var gist struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(data, &gist); err != nil || gist.HTMLURL == "" {
+		return "", errors.New("创建 Gist 失败: 响应中没有 html_url")
+	}
+	return gist.HTMLURL, nil
+}
+
+// uploadPaste 以请求体上传原文，兼容常见的粘贴服务（如 paste.rs、0x0.st 风格的接口与自建服务）：
+// 链接取自 JSON 响应的 url / link / html_url 字段、重定向的 Location，或纯文本响应的第一行
+func uploadPaste(ctx context.Context, client *http.Client, cfg Config, file File) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, strings.NewReader(file.Content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/markdown; charset=utf-8")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("上传失败: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode/100 == 3 {
+		if loc, err := resp.Location(); err == nil {
+			return loc.String(), nil
+		}
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("上传失败: %d %s: %s", resp.StatusCode, http.StatusText(resp.StatusCode), utils.ErrorDetail(data))
+	}
+
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) == nil {
+		for _, key := range []string{"url", "link", "html_url"} {
+			if link, ok := fields[key].(string); ok && isLink(link) {
+				return link, nil
+			}
+		}
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	if first = strings.TrimSpace(first); isLink(first) {
+		return first, nil
+	}
+	return "", fmt.Errorf("上传成功但无法从响应中识别链接: %s", utils.ErrorDetail(data))
+}
+
+// isLink 判断是否为 http(s) 链接
+func isLink(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
@@ -0,0 +1,438 @@
+// Package site 将审查历史渲染为静态 HTML 看板：评分趋势、问题数变化、各模块得分与最近一次审查的问题，
+// 生成的页面是单个自包含的 index.html（内联样式与 SVG 图表，不依赖脚本与外部资源），可直接发布到 GitHub Pages 或内部 Web 服务器
+package site
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go-ai-reviewer/internal/app/history"
+	"go-ai-reviewer/internal/llm"
+	"go-ai-reviewer/pkg/utils"
+)
+
+// maxModules 是模块图表中显示的模块数上限（得分最低的优先）
+const maxModules = 20
+
+// maxFindingText 是问题列表中问题描述的最大字符数
+const maxFindingText = 300
+
+// 图表尺寸
+const (
+	chartWidth  = 640
+	chartHeight = 160
+	chartLeft   = 36 // 纵轴刻度的宽度
+	chartBottom = 22 // 横轴日期的高度
+	moduleRow   = 24
+	moduleLabel = 200
+	moduleBar   = 300
+)
+
+// 严重程度的颜色与显示文本
+var (
+	severityColors = map[llm.Severity]string{
+		llm.SeverityCritical: "#cf222e",
+		llm.SeverityMajor:    "#e16f24",
+		llm.SeverityMinor:    "#d4a72c",
+	}
+	severityLabels = map[llm.Severity]string{
+		llm.SeverityCritical: "🔴 严重",
+		llm.SeverityMajor:    "🟠 重要",
+		llm.SeverityMinor:    "🟡 一般",
+	}
+)
+
+// pageStyle 是看板的内联样式，与 HTML 报告的配色一致
+const pageStyle = `body{max-width:1080px;margin:2em auto;padding:0 1em;font-family:-apple-system,"Segoe UI","PingFang SC","Microsoft YaHei",sans-serif;line-height:1.6;color:#24292f}
+h1,h2{border-bottom:1px solid #d0d7de;padding-bottom:.3em}
+h3{margin-bottom:.3em}
+table{border-collapse:collapse;margin:1em 0;width:100%}
+th,td{border:1px solid #d0d7de;padding:6px 10px;text-align:left;vertical-align:top}
+tr:nth-child(2n){background:#f6f8fa}
+td.num,th.num{text-align:right;white-space:nowrap}
+code{background:#eff1f3;border-radius:6px;padding:.2em .4em;font-size:85%}
+a{color:#0969da;text-decoration:none}
+a:hover{text-decoration:underline}
+.muted{color:#57606a}
+.up{color:#1a7f37}
+.down{color:#cf222e}
+.charts{display:flex;flex-wrap:wrap;gap:1em 2em}
+svg text{font-size:11px;fill:#57606a}`
+
+// Series 是同一项目中同一报告名的运行，对应看板中的一节
+type Series struct {
+	Project string
+	Name    string
+	Runs    []history.Run // 按时间从旧到新，不含文件，用于趋势图表
+	Latest  *history.Run  // 最近一次运行（含文件的审查结论），为空时不显示模块与问题
+}
+
+// Options 是看板的渲染选项
+type Options struct {
+	Title       string
+	Generated   time.Time
+	MinSeverity llm.Severity // 问题列表与模块统计只包含不低于该严重程度的问题
+	MaxFindings int          // 每节最多列出的问题数，不大于 0 时不限制
+}
+
+// GroupRuns 将运行按项目与报告名分组，每组按时间从旧到新排列，组之间按最近一次运行从新到旧排列
+// 被中断的部分运行只包含已完成的文件，评分不具可比性，不计入趋势
+func GroupRuns(runs []history.Run) []Series {
+	index := make(map[[2]string]int)
+	var series []Series
+	for _, r := range runs {
+		if r.Partial {
+			continue
+		}
+		key := [2]string{r.Project, r.Name}
+		i, ok := index[key]
+		if !ok {
+			i = len(series)
+			index[key] = i
+			series = append(series, Series{Project: r.Project, Name: r.Name})
+		}
+		series[i].Runs = append(series[i].Runs, r)
+	}
+	for i := range series {
+		slices.SortStableFunc(series[i].Runs, func(a, b history.Run) int { return a.Created.Compare(b.Created) })
+	}
+	slices.SortStableFunc(series, func(a, b Series) int {
+		return b.Runs[len(b.Runs)-1].Created.Compare(a.Runs[len(a.Runs)-1].Created)
+	})
+	return series
+}
+
+// Render 渲染看板页面
+func Render(series []Series, opts Options) string {
+	var b strings.Builder
+	title := html.EscapeString(opts.Title)
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"zh-CN\">\n<head>\n<meta charset=\"utf-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n", title, pageStyle)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p class=\"muted\">生成于 %s，共 %d 个报告</p>\n", title, opts.Generated.Format("2006-01-02 15:04"), len(series))
+
+	if len(series) == 0 {
+		b.WriteString("<p>还没有审查记录，运行 <code>reviewer run</code> 后会自动保存。</p>\n")
+	} else {
+		writeOverview(&b, series)
+		for i, s := range series {
+			writeSeries(&b, i, s, opts)
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// seriesTitle 返回一节的标题：项目 / 报告名
+func seriesTitle(s Series) string {
+	if s.Project == "" {
+		return s.Name
+	}
+	return s.Project + " / " + s.Name
+}
+
+// writeOverview 输出所有报告的概览表：最近得分、与上次相比的变化与问题数
+func writeOverview(b *strings.Builder, series []Series) {
+	b.WriteString("<h2>概览</h2>\n<table>\n<thead><tr><th>报告</th><th class=\"num\">得分</th><th class=\"num\">变化</th>")
+	for _, s := range []llm.Severity{llm.SeverityCritical, llm.SeverityMajor, llm.SeverityMinor} {
+		fmt.Fprintf(b, "<th class=\"num\">%s</th>", severityLabels[s])
+	}
+	b.WriteString("<th class=\"num\">运行次数</th><th>最近审查</th></tr></thead>\n<tbody>\n")
+	for i, s := range series {
+		last := s.Runs[len(s.Runs)-1]
+		fmt.Fprintf(b, "<tr><td><a href=\"#s%d\">%s</a></td><td class=\"num\">%.1f</td><td class=\"num\">%s</td><td class=\"num\">%d</td><td class=\"num\">%d</td><td class=\"num\">%d</td><td class=\"num\">%d</td><td>%s</td></tr>\n",
+			i, html.EscapeString(seriesTitle(s)), last.Score, scoreDelta(s.Runs), last.Critical, last.Major, last.Minor, len(s.Runs), last.Created.Format("2006-01-02 15:04"))
+	}
+	b.WriteString("</tbody>\n</table>\n")
+}
+
+// scoreDelta 返回最近一次与上一次运行的得分变化，只有一次运行时返回 -
+func scoreDelta(runs []history.Run) string {
+	if len(runs) < 2 {
+		return "-"
+	}
+	d := runs[len(runs)-1].Score - runs[len(runs)-2].Score
+	switch {
+	case d > 0:
+		return fmt.Sprintf("<span class=\"up\">▲ %.1f</span>", d)
+	case d < 0:
+		return fmt.Sprintf("<span class=\"down\">▼ %.1f</span>", -d)
+	}
+	return "0"
+}
+
+// writeSeries 输出一个报告的趋势图表、模块得分与最近一次审查的问题
+func writeSeries(b *strings.Builder, i int, s Series, opts Options) {
+	fmt.Fprintf(b, "<h2 id=\"s%d\">%s</h2>\n<div class=\"charts\">\n", i, html.EscapeString(seriesTitle(s)))
+	b.WriteString("<div><h3>综合评分</h3>\n")
+	writeScoreChart(b, s.Runs)
+	b.WriteString("</div>\n<div><h3>问题数</h3>\n")
+	writeIssueChart(b, s.Runs)
+	b.WriteString("</div>\n</div>\n")
+
+	if s.Latest == nil {
+		return
+	}
+	if modules := moduleStats(s.Latest, opts.MinSeverity); len(modules) > 0 {
+		b.WriteString("<h3>各模块得分 <span class=\"muted\">(最近一次审查)</span></h3>\n")
+		writeModuleChart(b, modules)
+	}
+
+	findings := latestFindings(s.Latest, opts.MinSeverity)
+	fmt.Fprintf(b, "<h3>最近一次审查的问题 <span class=\"muted\">(%s，%d 个)</span></h3>\n", s.Latest.Created.Format("2006-01-02 15:04"), len(findings))
+	if len(findings) == 0 {
+		b.WriteString("<p>✅ 没有发现问题</p>\n")
+		return
+	}
+	shown := findings
+	if opts.MaxFindings > 0 && len(shown) > opts.MaxFindings {
+		shown = shown[:opts.MaxFindings]
+	}
+	b.WriteString("<table>\n<thead><tr><th>严重程度</th><th>位置</th><th>问题</th></tr></thead>\n<tbody>\n")
+	for _, f := range shown {
+		location := f.file
+		if f.line > 0 {
+			location += fmt.Sprintf(":%d", f.line)
+		}
+		fmt.Fprintf(b, "<tr><td style=\"white-space:nowrap\">%s</td><td><code>%s</code></td><td>%s</td></tr>\n",
+			severityLabels[f.severity], html.EscapeString(location), html.EscapeString(utils.Truncate(f.text, maxFindingText)))
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	if len(shown) < len(findings) {
+		fmt.Fprintf(b, "<p class=\"muted\">另有 %d 个问题未列出，详见报告 <code>%s</code></p>\n", len(findings)-len(shown), html.EscapeString(s.Latest.Report))
+	}
+}
+
+// writeScoreChart 输出评分折线图，纵轴为 0-100
+func writeScoreChart(b *strings.Builder, runs []history.Run) {
+	plotW, plotH := chartWidth-chartLeft-8, chartHeight-chartBottom-8
+	fmt.Fprintf(b, "<svg width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" role=\"img\">\n", chartWidth, chartHeight, chartWidth, chartHeight)
+	for _, v := range []int{0, 25, 50, 75, 100} {
+		y := 8 + plotH - plotH*v/100
+		fmt.Fprintf(b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#d0d7de\"/><text x=\"%d\" y=\"%d\" text-anchor=\"end\">%d</text>\n",
+			chartLeft, y, chartWidth-8, y, chartLeft-4, y+4, v)
+	}
+	var points []string
+	for i, r := range runs {
+		x, y := chartX(i, len(runs), plotW), 8+float64(plotH)*(1-min(max(r.Score, 0), 100)/100)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		fmt.Fprintf(b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"3\" fill=\"#0969da\"><title>%s 得分 %.1f</title></circle>\n",
+			x, y, r.Created.Format("2006-01-02 15:04"), r.Score)
+	}
+	if len(points) > 1 {
+		fmt.Fprintf(b, "<polyline points=\"%s\" fill=\"none\" stroke=\"#0969da\" stroke-width=\"2\"/>\n", strings.Join(points, " "))
+	}
+	writeDateAxis(b, runs, plotW)
+	b.WriteString("</svg>\n")
+}
+
+// writeIssueChart 输出各次运行按严重程度堆叠的问题数柱状图
+func writeIssueChart(b *strings.Builder, runs []history.Run) {
+	plotW, plotH := chartWidth-chartLeft-8, chartHeight-chartBottom-8
+	peak := 1
+	for _, r := range runs {
+		peak = max(peak, r.Critical+r.Major+r.Minor)
+	}
+	fmt.Fprintf(b, "<svg width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" role=\"img\">\n", chartWidth, chartHeight, chartWidth, chartHeight)
+	for _, v := range []int{0, peak} {
+		y := 8 + plotH - plotH*v/peak
+		fmt.Fprintf(b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#d0d7de\"/><text x=\"%d\" y=\"%d\" text-anchor=\"end\">%d</text>\n",
+			chartLeft, y, chartWidth-8, y, chartLeft-4, y+4, v)
+	}
+	barW := min(24, max(2, float64(plotW)/float64(len(runs))*0.6))
+	for i, r := range runs {
+		x := chartX(i, len(runs), plotW) - barW/2
+		y := float64(8 + plotH)
+		title := fmt.Sprintf("%s 严重 %d / 重要 %d / 一般 %d", r.Created.Format("2006-01-02 15:04"), r.Critical, r.Major, r.Minor)
+		for _, part := range []struct {
+			severity llm.Severity
+			n        int
+		}{{llm.SeverityMinor, r.Minor}, {llm.SeverityMajor, r.Major}, {llm.SeverityCritical, r.Critical}} {
+			if part.n == 0 {
+				continue
+			}
+			h := float64(plotH*part.n) / float64(peak)
+			y -= h
+			fmt.Fprintf(b, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%s\"><title>%s</title></rect>\n",
+				x, y, barW, h, severityColors[part.severity], title)
+		}
+	}
+	writeDateAxis(b, runs, plotW)
+	b.WriteString("</svg>\n")
+}
+
+// chartX 返回第 i 次运行在横轴上的位置，只有一次运行时居中
+func chartX(i, n, plotW int) float64 {
+	if n == 1 {
+		return float64(chartLeft) + float64(plotW)/2
+	}
+	return float64(chartLeft) + 8 + float64(plotW-16)*float64(i)/float64(n-1)
+}
+
+// writeDateAxis 在横轴下方标注第一次与最近一次运行的日期
+func writeDateAxis(b *strings.Builder, runs []history.Run, plotW int) {
+	y := chartHeight - 6
+	first, last := runs[0], runs[len(runs)-1]
+	if len(runs) == 1 {
+		fmt.Fprintf(b, "<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", chartX(0, 1, plotW), y, first.Created.Format("01-02"))
+		return
+	}
+	fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\">%s</text><text x=\"%d\" y=\"%d\" text-anchor=\"end\">%s</text>\n",
+		chartLeft, y, first.Created.Format("2006-01-02"), chartWidth-8, y, last.Created.Format("2006-01-02"))
+}
+
+// module 是最近一次审查中一个模块（顶层目录）的统计
+type module struct {
+	name   string
+	files  int
+	score  float64 // 各文件得分的平均值
+	counts map[llm.Severity]int
+}
+
+// moduleStats 按模块汇总最近一次审查：去掉所有文件共同的目录前缀后，以第一级目录为模块，得分最低的排在前面
+func moduleStats(run *history.Run, minSeverity llm.Severity) []module {
+	var paths []string
+	for _, f := range run.Files {
+		if f.Review != nil {
+			paths = append(paths, filepath.ToSlash(filepath.Clean(f.Path)))
+		}
+	}
+	prefix := commonDir(paths)
+
+	index := make(map[string]*module)
+	var modules []*module
+	for _, f := range run.Files {
+		if f.Review == nil {
+			continue
+		}
+		rel := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(f.Path)), prefix)
+		name, _, nested := strings.Cut(rel, "/")
+		if !nested {
+			name = "."
+		}
+		m := index[name]
+		if m == nil {
+			m = &module{name: name, counts: make(map[llm.Severity]int)}
+			index[name] = m
+			modules = append(modules, m)
+		}
+		m.files++
+		m.score += float64(f.Review.Score)
+		for _, finding := range fileFindings(f, minSeverity) {
+			m.counts[finding.severity]++
+		}
+	}
+
+	result := make([]module, 0, len(modules))
+	for _, m := range modules {
+		m.score /= float64(m.files)
+		result = append(result, *m)
+	}
+	slices.SortStableFunc(result, func(a, b module) int {
+		return cmp.Or(cmp.Compare(a.score, b.score), strings.Compare(a.name, b.name))
+	})
+	return result[:min(len(result), maxModules)]
+}
+
+// commonDir 返回所有路径共同的目录前缀（以 / 结尾），没有时返回空字符串
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	prefix := path.Dir(paths[0]) + "/"
+	for _, p := range paths[1:] {
+		for prefix != "" && !strings.HasPrefix(p, prefix) {
+			prefix = strings.TrimSuffix(prefix, "/")
+			if i := strings.LastIndex(prefix, "/"); i >= 0 {
+				prefix = prefix[:i+1]
+			} else {
+				prefix = ""
+			}
+		}
+	}
+	if prefix == "./" {
+		return ""
+	}
+	return prefix
+}
+
+// writeModuleChart 输出各模块得分的横向柱状图，标注文件数与问题数
+func writeModuleChart(b *strings.Builder, modules []module) {
+	height := moduleRow*len(modules) + 4
+	width := moduleLabel + moduleBar + 220
+	fmt.Fprintf(b, "<svg width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" role=\"img\">\n", width, height, width, height)
+	for i, m := range modules {
+		y := i*moduleRow + 2
+		color := "#1a7f37"
+		switch {
+		case m.score < 60:
+			color = "#cf222e"
+		case m.score < 80:
+			color = "#d4a72c"
+		}
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%s</text>\n", moduleLabel-8, y+15, html.EscapeString(utils.Truncate(m.name, 28)))
+		fmt.Fprintf(b, "<rect x=\"%d\" y=\"%d\" width=\"%.1f\" height=\"%d\" fill=\"%s\"><title>%s 平均得分 %.1f</title></rect>\n",
+			moduleLabel, y+3, float64(moduleBar)*min(max(m.score, 0), 100)/100, moduleRow-8, color, html.EscapeString(m.name), m.score)
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\">%.1f · %d 个文件 · 严重 %d / 重要 %d / 一般 %d</text>\n",
+			moduleLabel+moduleBar+8, y+15, m.score, m.files, m.counts[llm.SeverityCritical], m.counts[llm.SeverityMajor], m.counts[llm.SeverityMinor])
+	}
+	b.WriteString("</svg>\n")
+}
+
+// finding 是问题列表中的一个问题
+type finding struct {
+	severity   llm.Severity
+	importance float64
+	file       string
+	line       int
+	text       string
+}
+
+// latestFindings 返回最近一次审查中不低于 minSeverity 的问题，按严重程度与文件重要性从高到低排列
+func latestFindings(run *history.Run, minSeverity llm.Severity) []finding {
+	var findings []finding
+	for _, f := range run.Files {
+		findings = append(findings, fileFindings(f, minSeverity)...)
+	}
+	slices.SortStableFunc(findings, func(a, b finding) int {
+		return cmp.Or(cmp.Compare(b.severity, a.severity), cmp.Compare(b.importance, a.importance))
+	})
+	return findings
+}
+
+// fileFindings 返回文件中不低于 minSeverity 的问题：安全审计的漏洞（其问题列表只是漏洞的摘要），或普通审查的问题
+func fileFindings(f history.File, minSeverity llm.Severity) []finding {
+	if f.Review == nil {
+		return nil
+	}
+	var findings []finding
+	file := filepath.ToSlash(f.Path)
+	if len(f.Review.Findings) > 0 {
+		for _, v := range f.Review.Findings {
+			if v.Level() < minSeverity {
+				continue
+			}
+			text := v.Title
+			if v.CWE != "" {
+				text = v.CWE + " " + text
+			}
+			if v.Description != "" {
+				text += ": " + v.Description
+			}
+			findings = append(findings, finding{v.Level(), f.Review.Importance, file, v.Line, text})
+		}
+		return findings
+	}
+	for _, issue := range llm.FilterIssues(f.Review.Issues, minSeverity) {
+		s, text := llm.SplitIssue(issue)
+		if s == llm.SeverityUnknown {
+			s = llm.SeverityMajor
+		}
+		findings = append(findings, finding{s, f.Review.Importance, file, llm.IssueLine(text), text})
+	}
+	return findings
+}
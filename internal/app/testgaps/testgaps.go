@@ -0,0 +1,150 @@
+// Package testgaps 按各语言的测试约定将源文件与测试文件对应起来，
+// 找出没有对应测试的源文件，作为项目级的问题写入报告
+package testgaps
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// families 将源文件扩展名映射到语言族：同一语言族内的测试文件可以对应彼此的源文件（如 Button.test.ts 对应 Button.tsx）
+var families = map[string]string{
+	".go": "go",
+	".py": "python",
+	".js": "js", ".jsx": "js", ".ts": "js", ".tsx": "js", ".mjs": "js", ".cjs": "js", ".vue": "js", ".svelte": "js",
+	".java": "jvm", ".kt": "jvm", ".scala": "jvm",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".cs":    "csharp",
+	".php":   "php",
+	".swift": "swift",
+	".c":     "c", ".cc": "c", ".cpp": "c", ".cxx": "c",
+}
+
+// SourceExts 返回参与检测的源文件扩展名
+func SourceExts() []string {
+	exts := make([]string, 0, len(families))
+	for ext := range families {
+		exts = append(exts, ext)
+	}
+	slices.Sort(exts)
+	return exts
+}
+
+// 测试文件名中的测试标记：后缀按顺序尝试，命中一个即停止
+var (
+	testMarkers      = []string{".test", ".spec", "_test", "_tests", "_spec", "-test", "-spec"}
+	testClassMarkers = []string{"Tests", "Test", "Spec"} // Java、Kotlin、C# 等的测试类命名（区分大小写）
+)
+
+// indexStems 是入口文件的文件名：测试通常以所在目录命名（如 Button/index.tsx 对应 Button.test.tsx）
+var indexStems = map[string]struct{}{"index": {}, "__init__": {}, "mod": {}, "lib": {}, "main": {}}
+
+// skipNames 是通常不需要单独测试的文件（包文档、构建与测试配置）
+var skipNames = map[string]struct{}{
+	"doc.go":      {},
+	"__init__.py": {},
+	"setup.py":    {},
+	"conftest.py": {},
+	"manage.py":   {},
+	"build.rs":    {},
+}
+
+// Untested 返回 files 中没有对应测试文件的源文件（保持原顺序），tests 是项目中的全部测试文件
+// Go 的测试须与源文件位于同一目录（同一个包），其他语言按去掉测试标记后的文件名在任意目录中匹配
+// （如 test_foo.py、tests/foo_test.py 对应 foo.py，FooTest.java 对应 Foo.java）；
+// Rust 文件包含 #[cfg(test)] 模块时视为已有测试
+func Untested(files, tests []string) []string {
+	isTest := make(map[string]struct{}, len(tests))
+	dirs := make(map[string][]string) // "语言族:文件名" -> 测试文件所在目录
+	for _, t := range tests {
+		t = filepath.Clean(t)
+		isTest[t] = struct{}{}
+		if family := familyOf(t); family != "" {
+			key := family + ":" + testStem(filepath.Base(t))
+			dirs[key] = append(dirs[key], filepath.Dir(t))
+		}
+	}
+
+	var untested []string
+	for _, file := range files {
+		family := familyOf(file)
+		if _, ok := isTest[filepath.Clean(file)]; ok || family == "" || skipSource(file) {
+			continue
+		}
+		if hasTest(dirs, family, filepath.Clean(file)) || family == "rust" && hasInlineTests(file) {
+			continue
+		}
+		untested = append(untested, file)
+	}
+	return untested
+}
+
+// familyOf 返回文件所属的语言族，不参与检测的文件返回空字符串
+func familyOf(path string) string {
+	return families[strings.ToLower(filepath.Ext(path))]
+}
+
+// skipSource 判断源文件是否不需要单独测试：包文档、类型声明与构建配置
+func skipSource(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	if _, ok := skipNames[base]; ok {
+		return true
+	}
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	return strings.HasSuffix(stem, ".d") || strings.HasSuffix(stem, ".config")
+}
+
+// hasTest 判断源文件是否有对应的测试文件
+func hasTest(dirs map[string][]string, family, file string) bool {
+	for _, stem := range sourceStems(file) {
+		found := dirs[family+":"+stem]
+		if family != "go" && len(found) > 0 || slices.Contains(found, filepath.Dir(file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceStems 返回源文件可能对应的测试文件名（去掉扩展名、统一小写）：入口文件同时匹配所在目录名
+func sourceStems(path string) []string {
+	base := filepath.Base(path)
+	stem := strings.ToLower(strings.TrimSuffix(base, filepath.Ext(base)))
+	if _, ok := indexStems[stem]; ok {
+		if dir := filepath.Base(filepath.Dir(path)); dir != "." && dir != string(filepath.Separator) {
+			return []string{stem, strings.ToLower(dir)}
+		}
+	}
+	return []string{stem}
+}
+
+// testStem 去掉测试文件名中的扩展名与测试标记，返回统一小写的文件名
+// 如 foo_test.go、foo.spec.ts、test_foo.py、FooTest.java 均返回 "foo"；
+// 位于测试目录但没有测试标记的文件（如 tests/foo.py）原样返回
+func testStem(base string) string {
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	lower := strings.ToLower(stem)
+	for _, marker := range testMarkers {
+		if len(lower) > len(marker) && strings.HasSuffix(lower, marker) {
+			return strings.TrimSuffix(lower, marker)
+		}
+	}
+	if len(lower) > len("test_") && strings.HasPrefix(lower, "test_") {
+		return strings.TrimPrefix(lower, "test_")
+	}
+	for _, marker := range testClassMarkers {
+		if len(stem) > len(marker) && strings.HasSuffix(stem, marker) {
+			return strings.ToLower(strings.TrimSuffix(stem, marker))
+		}
+	}
+	return lower
+}
+
+// hasInlineTests 判断 Rust 源文件是否包含内联的测试模块
+func hasInlineTests(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && bytes.Contains(data, []byte("#[cfg(test)]"))
+}
@@ -0,0 +1,134 @@
+// Package textenc 提供源码文件的编码识别与 UTF-8 转换
+package textenc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding 表示识别出的文件编码
+type Encoding int
+
+const (
+	UTF8    Encoding = iota // UTF-8（含 ASCII，可能带 BOM）
+	UTF16LE                 // UTF-16 小端
+	UTF16BE                 // UTF-16 大端
+	Binary                  // 二进制内容
+)
+
+// 字节序标记
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+	bomUTF32LE = []byte{0xFF, 0xFE, 0x00, 0x00}
+)
+
+// 启发式阈值
+const (
+	utf16ZeroRatio   = 0.4  // 无 BOM 的 UTF-16：高位字节为 0 的比例下限（ASCII 为主的源码）
+	utf16OtherRatio  = 0.05 // 无 BOM 的 UTF-16：低位字节为 0 的比例上限
+	controlByteRatio = 0.05 // 控制字符比例超过该值视为二进制
+)
+
+// Detect 根据文件头部识别编码：优先检查 BOM，其次识别无 BOM 的 UTF-16，
+// 最后以 NULL 字符与控制字符比例判断是否为二进制
+func Detect(sample []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		return UTF8
+	case bytes.HasPrefix(sample, bomUTF32LE):
+		return Binary // UTF-32 在源码中极少见，按二进制处理
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		return UTF16LE
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		return UTF16BE
+	}
+
+	if enc, ok := detectUTF16(sample); ok {
+		return enc
+	}
+	if bytes.IndexByte(sample, 0) != -1 {
+		return Binary
+	}
+
+	control := 0
+	for _, c := range sample {
+		if isControl(c) {
+			control++
+		}
+	}
+	if len(sample) > 0 && float64(control)/float64(len(sample)) > controlByteRatio {
+		return Binary
+	}
+	return UTF8
+}
+
+// IsBinary 判断文件头部是否为二进制内容
+func IsBinary(sample []byte) bool {
+	return Detect(sample) == Binary
+}
+
+// ToUTF8 将内容转换为不带 BOM 的 UTF-8，UTF-8 内容原样返回（仅去除 BOM）
+func ToUTF8(data []byte) []byte {
+	switch Detect(data) {
+	case UTF16LE:
+		return decodeUTF16(bytes.TrimPrefix(data, bomUTF16LE), binary.LittleEndian)
+	case UTF16BE:
+		return decodeUTF16(bytes.TrimPrefix(data, bomUTF16BE), binary.BigEndian)
+	default:
+		return bytes.TrimPrefix(data, bomUTF8)
+	}
+}
+
+// detectUTF16 识别无 BOM 的 UTF-16：ASCII 为主的源码中每个字符的高位字节为 0
+func detectUTF16(sample []byte) (Encoding, bool) {
+	pairs := len(sample) / 2
+	if pairs < 2 {
+		return 0, false
+	}
+
+	evenZero, oddZero := 0, 0
+	for i := 0; i < pairs*2; i += 2 {
+		if sample[i] == 0 {
+			evenZero++
+		}
+		if sample[i+1] == 0 {
+			oddZero++
+		}
+	}
+
+	even, odd := float64(evenZero)/float64(pairs), float64(oddZero)/float64(pairs)
+	switch {
+	case odd >= utf16ZeroRatio && even <= utf16OtherRatio:
+		return UTF16LE, true
+	case even >= utf16ZeroRatio && odd <= utf16OtherRatio:
+		return UTF16BE, true
+	}
+	return 0, false
+}
+
+// decodeUTF16 将 UTF-16 字节解码为 UTF-8，末尾不完整的字节被丢弃
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[i*2:])
+	}
+
+	out := make([]byte, 0, len(units))
+	for _, r := range utf16.Decode(units) {
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
+
+// isControl 判断字节是否为文本中不应出现的控制字符（常见空白与 ANSI 转义除外）
+func isControl(c byte) bool {
+	switch c {
+	case '\t', '\n', '\r', '\f', '\v', '\b', 0x1B:
+		return false
+	}
+	return c < 0x20 || c == 0x7F
+}
@@ -0,0 +1,57 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// AskMaxContent 是追问时随背景发送的最大文件长度（字节）
+const AskMaxContent = 48 * 1024
+
+// 追问提示词：基于上次的审查结论与文件内容回答开发者的问题
+const askPrompt = `你是一位资深代码审查专家，正在回答开发者对一次代码审查结果的追问。
+第一条消息给出了背景：被审查的文件内容（带行号）与上次审查的结论（得分、问题与建议），之后是开发者的问题。
+要求：
+1. 回答简洁、具体，引用相关的行号与代码；需要说明修复方法时给出修改后的代码片段。
+2. 如果上次审查的结论有误或言过其实，直接指出，不要为其辩护。
+3. 只根据背景中的代码作答，无法从中判断时说明还需要哪些信息。
+请使用中文回答，可以使用 Markdown。`
+
+// Conversation 是围绕一次审查结果的多轮追问（非并发安全）
+type Conversation struct {
+	client     *Client
+	background string
+	messages   []openai.ChatCompletionMessage
+}
+
+// NewConversation 以背景（文件内容与上次的审查结论）开始一次追问对话
+func (c *Client) NewConversation(background string) *Conversation {
+	return &Conversation{
+		client:     c,
+		background: background,
+		messages:   []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleSystem, Content: askPrompt}},
+	}
+}
+
+// Ask 发送问题并返回回答；问答记录保留在对话中，后续问题可以引用之前的回答
+// 请求失败时不记录本轮问题，可以直接重试
+func (cv *Conversation) Ask(ctx context.Context, question string) (string, error) {
+	content := question
+	if len(cv.messages) == 1 {
+		content = cv.background + "\n\n问题: " + question
+	}
+	messages := append(cv.messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: content})
+
+	answer, err := cv.client.send(ctx, messages)
+	if err != nil {
+		return "", err
+	}
+	cv.messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: answer})
+	return answer, nil
+}
+
+// Turns 返回已完成的问答轮数
+func (cv *Conversation) Turns() int {
+	return (len(cv.messages) - 1) / 2
+}
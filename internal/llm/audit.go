@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 安全审计要求：reviewer audit 时追加到系统提示末尾
+const auditFormat = `## 安全审计
+
+本次为安全审计：只关注可被利用的安全漏洞，不报告代码风格、性能等一般问题。
+请在 JSON 中额外给出 "findings" 数组，每个漏洞一项（issues 中对应的条目可以省略，会根据 findings 自动生成）：
+{
+  "cwe": "<CWE 编号，如 CWE-89；无法归类时为空字符串>",
+  "title": "<漏洞名称，如 SQL 注入>",
+  "severity": "<critical|major|minor>",
+  "line": <漏洞所在行号，无法确定时为 0>,
+  "exploitability": "<high|medium|low，结合输入来源与利用条件评估的可利用性>",
+  "confidence": <0.0-1.0 的浮点数，表示你对该漏洞确实存在的把握>,
+  "description": "<漏洞成因与可能的攻击方式>",
+  "remediation": "<具体的修复方法>"
+}
+没有发现漏洞时 findings 为空数组；评分按漏洞的严重程度与可利用性扣分。`
+
+// 可利用性
+const (
+	ExploitabilityHigh   = "high"
+	ExploitabilityMedium = "medium"
+	ExploitabilityLow    = "low"
+)
+
+// Finding 是安全审计发现的单个漏洞
+type Finding struct {
+	CWE            string  `json:"cwe"`                  // 漏洞类别，规范化为 "CWE-<编号>"，无法归类时为空
+	Title          string  `json:"title"`                // 漏洞名称
+	Severity       string  `json:"severity"`             // critical / major / minor
+	Line           int     `json:"line,omitempty"`       // 漏洞所在行号，0 表示未知
+	Exploitability string  `json:"exploitability"`       // high / medium / low
+	Confidence     float64 `json:"confidence,omitempty"` // 漏洞确实存在的置信度 (0.0-1.0)，0 表示未给出
+	Description    string  `json:"description"`          // 成因与攻击方式
+	Remediation    string  `json:"remediation"`          // 修复方法
+}
+
+// Level 返回漏洞的严重程度，无法识别时视为重要问题
+func (f Finding) Level() Severity {
+	if s, err := ParseSeverity(f.Severity); err == nil && s != SeverityUnknown {
+		return s
+	}
+	return SeverityMajor
+}
+
+// Issue 返回与普通审查问题格式一致的文本，用于计数、严重程度过滤与基线对比
+func (f Finding) Issue() string {
+	text := f.Title
+	if f.CWE != "" {
+		text = fmt.Sprintf("[%s] %s", f.CWE, f.Title)
+	}
+	if f.Line > 0 {
+		text = fmt.Sprintf("第 %d 行: %s", f.Line, text)
+	}
+	return FormatIssueWithConfidence(f.Level(), f.Confidence, text)
+}
+
+// exploitabilityLabels 是可利用性的显示名称
+var exploitabilityLabels = map[string]string{
+	ExploitabilityHigh:   "高",
+	ExploitabilityMedium: "中",
+	ExploitabilityLow:    "低",
+}
+
+// ExploitabilityLabel 返回可利用性的显示名称，未知时返回 "未知"
+func ExploitabilityLabel(exploitability string) string {
+	if label, ok := exploitabilityLabels[exploitability]; ok {
+		return label
+	}
+	return "未知"
+}
+
+// cweRegex 匹配 "CWE-89"、"cwe 89"、"89" 等写法中的编号
+var cweRegex = regexp.MustCompile(`(?i)^(?:cwe)?[\s:_-]*(\d+)$`)
+
+// NormalizeCWE 将 CWE 编号规范化为 "CWE-<编号>"，无法识别时返回空字符串
+func NormalizeCWE(id string) string {
+	m := cweRegex.FindStringSubmatch(strings.TrimSpace(id))
+	if m == nil {
+		return ""
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("CWE-%d", n)
+}
+
+// cweNames 是常见 CWE 的中文名称（参考 CWE Top 25），用于报告与 SARIF 规则说明
+var cweNames = map[string]string{
+	"CWE-20":   "输入校验不当",
+	"CWE-22":   "路径遍历",
+	"CWE-77":   "命令注入",
+	"CWE-78":   "操作系统命令注入",
+	"CWE-79":   "跨站脚本 (XSS)",
+	"CWE-89":   "SQL 注入",
+	"CWE-94":   "代码注入",
+	"CWE-119":  "内存缓冲区越界",
+	"CWE-125":  "越界读取",
+	"CWE-190":  "整数溢出",
+	"CWE-200":  "敏感信息泄露",
+	"CWE-287":  "认证不当",
+	"CWE-295":  "证书校验不当",
+	"CWE-306":  "关键功能缺少认证",
+	"CWE-327":  "使用有缺陷的加密算法",
+	"CWE-328":  "使用弱哈希算法",
+	"CWE-330":  "随机数不够随机",
+	"CWE-352":  "跨站请求伪造 (CSRF)",
+	"CWE-362":  "竞态条件",
+	"CWE-400":  "资源消耗不受控制",
+	"CWE-416":  "释放后使用",
+	"CWE-434":  "危险类型文件上传",
+	"CWE-476":  "空指针解引用",
+	"CWE-502":  "不可信数据反序列化",
+	"CWE-601":  "开放重定向",
+	"CWE-611":  "XML 外部实体 (XXE)",
+	"CWE-787":  "越界写入",
+	"CWE-798":  "硬编码凭据",
+	"CWE-862":  "缺少授权",
+	"CWE-863":  "授权不当",
+	"CWE-918":  "服务端请求伪造 (SSRF)",
+	"CWE-1321": "原型污染",
+}
+
+// CWEName 返回常见 CWE 的中文名称，未收录时返回空字符串
+func CWEName(cwe string) string {
+	return cweNames[cwe]
+}
+
+// CWEURL 返回 CWE 在 MITRE 网站上的说明页面
+func CWEURL(cwe string) string {
+	return "https://cwe.mitre.org/data/definitions/" + strings.TrimPrefix(cwe, "CWE-") + ".html"
+}
+
+// normalizeFindings 规范化安全审计结果：统一 CWE 编号、严重程度与可利用性的写法，
+// 并根据 findings 重新生成 issues，保证计数、过滤与基线对比与普通审查一致
+func normalizeFindings(result *ReviewResult) {
+	if result == nil || len(result.Findings) == 0 {
+		return
+	}
+	result.Issues = nil
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		f.CWE = NormalizeCWE(f.CWE)
+		f.Severity = f.Level().String()
+		f.Exploitability = strings.ToLower(strings.TrimSpace(f.Exploitability))
+		if _, ok := exploitabilityLabels[f.Exploitability]; !ok {
+			f.Exploitability = ""
+		}
+		f.Confidence = normalizeConfidence(f.Confidence)
+		if f.Title == "" {
+			f.Title = CWEName(f.CWE)
+		}
+		result.Issues = append(result.Issues, f.Issue())
+	}
+}
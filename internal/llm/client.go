@@ -5,8 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"regexp"
 	"strings"
+	"unicode/utf8"
+
+	"go-ai-reviewer/pkg/utils"
 
 	"github.com/sashabaranov/go-openai"
 )
@@ -20,17 +25,32 @@ const (
 	DefaultLevel       = 2
 )
 
-// 系统提示模板
+// 系统提示模板（text/template 语法，可用变量见 PromptData）
 const systemPromptTemplate = `你是一位高级代码审计专家。请分析给定的代码，寻找逻辑错误、安全漏洞和代码风格问题。
-你的输出必须是一个严格的 JSON 对象，不要包含任何 Markdown 格式（不要使用代码块）。
 请使用中文回答。
 
-**审查严格级别: %d/6**
-%s
+**审查严格级别: {{.Level}}/6**
+{{.LevelDescription}}
+{{- with .Focus}}
+
+{{.}}
+{{- end}}
+{{- with .LanguageGuide}}
+
+{{.}}
+{{- end}}
+{{- with .Conventions}}
+
+{{.}}
+{{- end}}
+{{- with .Rules}}
+
+{{.}}
+{{- end}}
 
 ## 重要提示（避免误报）
 
-1. **跨文件依赖**：你只能看到当前单个文件。如果代码调用了未在当前文件定义的函数/类/模块，它很可能定义在项目的其他文件中。**不要将"函数未定义"、"模块未导入"等报告为错误**，除非语法明显错误。
+1. **跨文件依赖**：你只能看到当前单个文件。如果代码调用了未在当前文件定义的函数/类/模块，它很可能定义在项目的其他文件中。**不要将"函数未定义"、"模块未导入"等报告为错误**，除非语法明显错误。如果提供了"项目上下文"（目录结构、README 摘要、引用符号的签名），请据此判断跨文件引用的用法是否正确。
 
 2. **语言特性**：
    - Go: 同 package 内文件可互相访问；init() 中 panic 是标准做法
@@ -46,24 +66,59 @@ const systemPromptTemplate = `你是一位高级代码审计专家。请分析
 
 4. **只报告确定的问题**：如果某个问题依赖于你看不到的上下文（其他文件、配置、运行时），请不要报告。只报告在当前文件内**可以 100% 确定存在**的问题。
 
-5. **区分严重程度**：
-   - 语法错误、运行时崩溃、安全漏洞 = 严重问题（必须报告）
-   - 代码风格、命名规范 = 一般建议（可以报告）
+5. **区分严重程度**：每条问题必须以严重程度标注开头
+   - [critical]：语法错误、运行时崩溃、安全漏洞、数据损坏（必须报告）
+   - [major]：逻辑错误、资源泄漏、错误处理缺失等会影响正确性的问题
+   - [minor]：代码风格、命名规范、可读性等一般建议（可以报告）
    - 基于假设的"可能问题" = **不要报告**
 
-## 评估要求
+6. **注明行号**：问题对应具体代码时，在严重程度标注后注明行号，如 "[major] 第 42 行: 关闭文件前未检查错误"
+{{- with .Examples}}
+
+{{.}}
+{{- end}}
+
+{{.OutputFormat}}`
 
+// 输出格式要求：自定义模板未引用 {{.OutputFormat}} 时自动追加，保证结果可以解析
+const outputFormat = `## 评估要求
+
+你的输出必须是一个严格的 JSON 对象，不要包含任何 Markdown 格式（不要使用代码块）。
 评估该文件在项目中的重要性（0.0 - 1.0）：核心业务逻辑/入口=0.9~1.0，辅助工具=0.5，配置文件/简单模型=0.3。
+同时给出你对本次审查结论的置信度（0.0 - 1.0）：结论依赖你看不到的上下文时应降低置信度。
 
 格式：
 {
   "score": <0-100 的整数>,
   "importance": <0.0-1.0 的浮点数，表示文件重要性>,
+  "confidence": <0.0-1.0 的浮点数，表示审查结论的置信度>,
   "summary": "<一句话总结>",
   "pros": ["<优点 1>", "<优点 2>"],
-  "issues": ["<确定存在的问题 1>", "<确定存在的问题 2>"],
+  "issues": ["[<critical|major|minor>|<置信度>] <确定存在的问题 1>", "[<critical|major|minor>|<置信度>] <确定存在的问题 2>"],
   "suggestion": "<简短的优化建议>"
-}`
+}
+issues 中每条问题的标注同时给出严重程度与该问题的置信度（0.0 - 1.0，表示你对该问题确实存在的把握），如 "[major|0.9] 第 42 行: 关闭文件前未检查错误"。`
+
+// 修复补丁要求：请求修复补丁时追加到系统提示末尾
+const fixFormat = `## 修复补丁
+
+请在 JSON 中额外给出 "patch" 字段：修复上述 issues 的 unified diff（字符串），要求：
+- 只包含 "@@ -起始行,行数 +起始行,行数 @@" 开头的修改块，不需要 ---/+++ 文件头
+- 每个修改块保留 2-3 行未修改的上下文，上下文与删除行必须与原文件逐字一致（包括缩进）
+- 只修复可以确定的问题，不要重排格式或做无关改动；没有适合自动修复的问题时 patch 为空字符串`
+
+// 复核提示模板：要求模型逐条核实上一轮发现的问题
+const verifyPromptTemplate = `你是一位严谨的代码审计复核专家。另一位审查者已经对下面的代码给出了初步审查结论，但该结论的评分很低或置信度不足，可能包含误报。
+请逐条核实初步结论中的每一个问题：
+1. 只保留在当前代码中**可以直接找到证据**的问题；
+2. 删除依赖于看不到的上下文（其他文件、配置、运行时）或基于假设的问题；
+3. 根据核实后的问题重新评分，并重新给出置信度。
+
+你的输出必须是一个严格的 JSON 对象，格式与初步结论完全相同，不要包含任何 Markdown 格式。
+请使用中文回答。
+
+**审查严格级别: %d/6**
+%s`
 
 // 级别描述映射
 var levelDescriptions = map[int]string{
@@ -77,22 +132,41 @@ var levelDescriptions = map[int]string{
 
 // ReviewResult 表示 LLM 返回的结构化审查结果
 type ReviewResult struct {
-	Score      int      `json:"score"`      // 评分 (0-100)
-	Importance float64  `json:"importance"` // 重要性 (0.0-1.0)
-	Summary    string   `json:"summary"`    // 一句话总结
-	Pros       []string `json:"pros"`       // 优点列表
-	Issues     []string `json:"issues"`     // 问题列表
-	Suggestion string   `json:"suggestion"` // 优化建议
+	Score      int      `json:"score"`           // 评分 (0-100)
+	Importance float64  `json:"importance"`      // 重要性 (0.0-1.0)
+	Confidence float64  `json:"confidence"`      // 置信度 (0.0-1.0)
+	Summary    string   `json:"summary"`         // 一句话总结
+	Pros       []string `json:"pros"`            // 优点列表
+	Issues     []string `json:"issues"`          // 问题列表
+	Suggestion string   `json:"suggestion"`      // 优化建议
+	Patch      string   `json:"patch,omitempty"` // 修复问题的 unified diff（仅在请求修复补丁时返回）
+
+	// Findings 是安全审计发现的漏洞（仅在安全审计时返回），Issues 根据它生成
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// chatCompleter 抽象对话补全接口，便于替换为 Mock 实现
+type chatCompleter interface {
+	CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
 }
 
 // Client 封装 OpenAI API 客户端
 type Client struct {
-	api   *openai.Client
-	model string
+	api    chatCompleter
+	model  string
+	usage  *Usage   // Token 消耗统计
+	prompt *Prompt  // 系统提示模板，为空时使用内置模板
+	focus  []string // 审查重点，为空时全面审查
+
+	noLanguage  bool   // 不按文件语言注入语言要点
+	conventions string // 团队规范（见 WithConventions），为空时不注入
+	examples    string // 少样本示例（见 WithExamples），为空时不注入
+
+	httpClient *http.Client // 访问接口的 HTTP 客户端（见 WithTLSConfig），为空时使用默认客户端
 }
 
 // NewClient 创建一个新的 LLM 客户端
-func NewClient(apiKey, model, baseURL string) (*Client, error) {
+func NewClient(apiKey, model, baseURL string, opts ...ClientOption) (*Client, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API Key 不能为空")
 	}
@@ -107,50 +181,220 @@ func NewClient(apiKey, model, baseURL string) (*Client, error) {
 		config.BaseURL = baseURL
 	}
 
-	return &Client{
-		api:   openai.NewClientWithConfig(config),
-		model: model,
-	}, nil
+	// 选项可能替换 HTTP 客户端，先应用选项再创建接口客户端
+	c := newClient(nil, model, opts)
+	if c.httpClient != nil {
+		config.HTTPClient = c.httpClient
+	}
+	c.api = openai.NewClientWithConfig(config)
+	return c, nil
+}
+
+// newClient 组装 Client 并应用选项
+func newClient(api chatCompleter, model string, opts []ClientOption) *Client {
+	c := &Client{api: api, model: model, usage: &Usage{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Usage 返回客户端累计的 Token 消耗
+func (c *Client) Usage() *Usage {
+	return c.usage
+}
+
+// ReviewRequest 表示一次代码审查请求
+type ReviewRequest struct {
+	FilePath string
+	Content  string
+	Level    int
+	Context  string // 项目上下文（目录结构、README 摘要、引用符号签名），可为空
+
+	// LineNumbered 表示 Content 已压缩（去除注释与空行），每行以原始行号为前缀
+	LineNumbered bool
+
+	// Fix 要求模型同时给出修复问题的补丁（Content 须为完整的原始文件）
+	Fix bool
+
+	// Audit 表示安全审计：要求模型为每个漏洞给出 CWE 编号、可利用性与修复方法
+	Audit bool
+
+	// Rules 是适用于当前文件的团队规则，违反规则的问题以 [<规则编号>] 标注
+	Rules []Rule
 }
 
 // ReviewCode 发送代码给 LLM 并返回分析结果
-func (c *Client) ReviewCode(ctx context.Context, filePath, content string, level int) (*ReviewResult, error) {
+func (c *Client) ReviewCode(ctx context.Context, req ReviewRequest) (*ReviewResult, error) {
 	// 验证并规范化 level
-	level = normalizeLevel(level)
+	level := normalizeLevel(req.Level)
 
 	// 构建提示词
-	levelDesc := getLevelDescription(level)
-	systemPrompt := fmt.Sprintf(systemPromptTemplate, level, levelDesc)
-	userPrompt := fmt.Sprintf("File: %s\n\nCode:\n%s", filePath, content)
+	data := PromptData{Level: level, FilePath: req.FilePath, Focus: focusInstructions(c.focus),
+		Conventions: c.conventions, Rules: rulesInstructions(req.Rules), Examples: c.examples}
+	if !c.noLanguage {
+		data.LanguageGuide, data.Language = c.prompt.languageInstructions(req.FilePath, req.Content)
+	}
+	systemPrompt, err := c.prompt.system(data)
+	if err != nil {
+		return nil, err
+	}
+	if req.Audit {
+		systemPrompt += "\n\n" + auditFormat
+	}
+	if req.Fix {
+		systemPrompt += "\n\n" + fixFormat
+	}
+
+	return c.completeRequest(ctx, req, systemPrompt, buildUserPrompt(req))
+}
+
+// VerifyReview 对低分或低置信度的审查结论进行一次复核，返回核实后的结果
+func (c *Client) VerifyReview(ctx context.Context, req ReviewRequest, prev *ReviewResult) (*ReviewResult, error) {
+	if prev == nil {
+		return nil, fmt.Errorf("待复核的审查结果不能为空")
+	}
+
+	level := normalizeLevel(req.Level)
+
+	prevJSON, err := json.Marshal(prev)
+	if err != nil {
+		return nil, fmt.Errorf("序列化初步结论失败: %w", err)
+	}
+
+	systemPrompt := fmt.Sprintf(verifyPromptTemplate, level, c.prompt.levelDescription(level))
+	if focus := focusInstructions(c.focus); focus != "" {
+		systemPrompt += "\n\n" + focus
+	}
+	if !c.noLanguage {
+		if guide, _ := c.prompt.languageInstructions(req.FilePath, req.Content); guide != "" {
+			systemPrompt += "\n\n" + guide
+		}
+	}
+	if c.conventions != "" {
+		systemPrompt += "\n\n" + c.conventions
+	}
+	if rules := rulesInstructions(req.Rules); rules != "" {
+		systemPrompt += "\n\n" + rules
+	}
+	if c.examples != "" {
+		systemPrompt += "\n\n" + c.examples
+	}
+	if req.Audit {
+		systemPrompt += "\n\n" + auditFormat
+	}
+	if req.Fix {
+		// 剔除误报后补丁也需要相应调整
+		systemPrompt += "\n\n" + fixFormat
+	}
+	userPrompt := fmt.Sprintf("%s\n\n初步结论:\n%s", buildUserPrompt(req), prevJSON)
+
+	return c.completeRequest(ctx, req, systemPrompt, userPrompt)
+}
+
+// completeRequest 发送审查请求，安全审计时规范化返回的漏洞列表
+func (c *Client) completeRequest(ctx context.Context, req ReviewRequest, systemPrompt, userPrompt string) (*ReviewResult, error) {
+	result, err := c.complete(ctx, systemPrompt, userPrompt)
+	if err == nil && req.Audit {
+		normalizeFindings(result)
+	}
+	return result, err
+}
+
+// buildUserPrompt 构建用户消息：可选的项目上下文 + 文件路径 + 代码
+func buildUserPrompt(req ReviewRequest) string {
+	prompt := fmt.Sprintf("File: %s\n\nCode:\n%s", req.FilePath, req.Content)
+	if req.LineNumbered {
+		prompt = fmt.Sprintf("File: %s\n\nCode（已去除注释与空行，每行 \"N| \" 前缀为源文件行号，引用行号时请使用该行号）:\n%s", req.FilePath, req.Content)
+	}
+	if req.Context == "" {
+		return prompt
+	}
+	return fmt.Sprintf("## 项目上下文（仅供参考，不需要审查）\n%s\n## 待审查文件\n%s", req.Context, prompt)
+}
+
+// complete 发送一次对话请求并解析为 ReviewResult
+func (c *Client) complete(ctx context.Context, systemPrompt, userPrompt string) (*ReviewResult, error) {
+	content, err := c.chat(ctx, systemPrompt, userPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	// 解析响应；原始响应只记录在调试日志中，不放入错误信息
+	result, err := parseResponse(content, false)
+	if err == nil {
+		return result, nil
+	}
+	slog.Debug("LLM 响应解析失败，请模型修复", "model", c.model, "err", err, "response", utils.Truncate(content, maxLoggedResponse))
+
+	// 本地无法修复或不符合 Schema 时，将原始输出发回给模型修复一次
+	repaired, repairErr := c.repair(ctx, content, err)
+	if repairErr != nil {
+		slog.Debug("修复 LLM 响应失败", "model", c.model, "err", repairErr)
+		return nil, fmt.Errorf("%w (请模型修复后仍无法解析)", err)
+	}
+	slog.Debug("模型已修复格式错误的响应", "model", c.model)
+	return repaired, nil
+}
+
+// maxLoggedResponse 是调试日志中记录的原始响应最大长度
+const maxLoggedResponse = 2000
+
+// chat 发送一次对话请求，返回模型的原始文本输出
+func (c *Client) chat(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	return c.send(ctx, []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
+	})
+}
 
-	// 调用 API
+// send 发送完整的消息列表（多轮对话），返回模型的回复
+func (c *Client) send(ctx context.Context, messages []openai.ChatCompletionMessage) (string, error) {
 	resp, err := c.api.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: c.model,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
-			{Role: openai.ChatMessageRoleUser, Content: userPrompt},
-		},
+		Model:       c.model,
+		Messages:    messages,
 		Temperature: DefaultTemperature,
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("API 调用失败: %w", err)
+		slog.Debug("API 调用失败", "model", c.model, "err", err)
+		return "", fmt.Errorf("API 调用失败: %w", err)
 	}
 
+	c.usage.add(resp.Usage)
+
 	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("API 返回空响应")
+		return "", fmt.Errorf("API 返回空响应")
 	}
 
-	// 解析响应
-	return parseResponse(resp.Choices[0].Message.Content)
+	return resp.Choices[0].Message.Content, nil
 }
 
-// parseResponse 解析 LLM 响应为 ReviewResult
-func parseResponse(content string) (*ReviewResult, error) {
-	// 使用正则表达式清理 Markdown 代码块
-	// 匹配 ```json ... ``` 或 ``` ... ```
-	// 使用非贪婪匹配 (.*?) 避免匹配到最后一个 ```
-	codeBlockRegex := regexp.MustCompile("(?s)^\\s*```(?:json)?\\s*(.*?)```\\s*$")
+// codeBlockRegex 匹配 ```json ... ``` 或 ``` ... ``` 包裹的内容
+// 使用非贪婪匹配 (.*?) 避免匹配到最后一个 ```
+var codeBlockRegex = regexp.MustCompile("(?s)^\\s*```(?:json)?\\s*(.*?)```\\s*$")
+
+// parseResponse 解析 LLM 响应为 ReviewResult，并按审查结果的 Schema 校验（见 schema.go）
+// clamp 为 false 时任何违规都返回错误，以便请模型修正；为 true 时将越界的数值修正到范围内
+func parseResponse(content string, clamp bool) (*ReviewResult, error) {
+	var raw json.RawMessage
+	if err := decodeJSON(content, &raw, "score"); err != nil {
+		return nil, err
+	}
+	valid, err := validateReview(raw, clamp)
+	if err != nil {
+		return nil, err
+	}
+	var result ReviewResult
+	if err := json.Unmarshal(valid, &result); err != nil {
+		return nil, fmt.Errorf("JSON 解析失败: %w", err)
+	}
+	return &result, nil
+}
+
+// decodeJSON 清理 Markdown 代码块后将 LLM 响应解析到 v
+// 无法直接解析时尝试在本地修复（见 recoverJSON），修复结果必须包含 required 中的字段
+func decodeJSON(content string, v any, required ...string) error {
 	if matches := codeBlockRegex.FindStringSubmatch(content); len(matches) > 1 {
 		content = matches[1]
 	}
@@ -159,16 +403,18 @@ func parseResponse(content string) (*ReviewResult, error) {
 
 	// 如果内容为空，返回错误
 	if content == "" {
-		return nil, fmt.Errorf("响应内容为空")
+		return fmt.Errorf("响应内容为空")
 	}
 
-	var result ReviewResult
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
+	if err := json.Unmarshal([]byte(content), v); err != nil {
+		if decodeRecovered(content, v, required) == nil {
+			return nil
+		}
 		// 不在错误信息中包含原始响应，避免泄露敏感信息
-		return nil, fmt.Errorf("JSON 解析失败: %w", err)
+		return fmt.Errorf("JSON 解析失败: %w", err)
 	}
 
-	return &result, nil
+	return nil
 }
 
 // normalizeLevel 将 level 规范化到有效范围内
@@ -191,8 +437,16 @@ func getLevelDescription(level int) string {
 }
 
 // EstimateTokenCount 估算文本的 Token 数量
-// 注意：这是粗略估算（约 4 字符 = 1 Token），仅用于成本预估
+// 注意：这是粗略估算（ASCII 约 4 字符 = 1 Token，中文等非 ASCII 字符约 1 字符 = 1 Token），仅用于成本预估
 // 精确计算请使用 tiktoken-go 等专业库
 func EstimateTokenCount(text string) int {
-	return len(text) / 4
+	var ascii, other int
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return ascii/4 + other
 }
@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ConventionFiles 是团队规范文件的文件名，按优先级排列
+var ConventionFiles = []string{"CONVENTIONS.md", ".review-guidelines.md"}
+
+// MaxConventionsSize 是注入系统提示的规范内容最大长度（字节），超出部分截断，避免每个请求都携带过长的文档
+const MaxConventionsSize = 8 * 1024
+
+// TruncateConventions 将规范内容截断到 MaxConventionsSize（在行尾截断），返回截断后的内容与是否截断
+func TruncateConventions(content string) (string, bool) {
+	content = strings.TrimSpace(content)
+	if len(content) <= MaxConventionsSize {
+		return content, false
+	}
+	cut := content[:MaxConventionsSize]
+	for !utf8.ValidString(cut) {
+		cut = cut[:len(cut)-1]
+	}
+	if i := strings.LastIndexByte(cut, '\n'); i > MaxConventionsSize/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "\n\n...（规范过长，以下内容已截断）", true
+}
+
+// conventionsInstructions 构建系统提示中的团队规范部分
+func conventionsInstructions(name, content string) string {
+	return fmt.Sprintf("## 团队规范\n\n以下是团队在 %s 中记录的编码规范，审查时以此为准：\n"+
+		"- 违反规范的写法应当报告，并注明违反的条目；一般为 [minor]，影响正确性或安全时相应提高严重程度。\n"+
+		"- 规范明确允许或要求的写法不要报告为问题，即使它与通用的最佳实践不同。\n\n%s", name, content)
+}
+
+// WithConventions 在系统提示中注入团队规范文件的内容（name 为文件名，内容超过 MaxConventionsSize 时截断）
+func WithConventions(name, content string) ClientOption {
+	return func(c *Client) {
+		if content, _ = TruncateConventions(content); content != "" {
+			c.conventions = conventionsInstructions(name, content)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// DependencyMaxContent 是依赖审查时发送的最大清单长度（字节）
+const DependencyMaxContent = 32 * 1024
+
+// 依赖审查提示词：评估依赖清单中过时、有风险与许可证不兼容的依赖
+const dependencyPrompt = `你是一位依赖治理与开源合规专家。请审查给定的依赖清单，找出需要处理的依赖：
+1. outdated：版本明显过旧、已停止维护、已弃用或已被其他包取代；
+2. risky：已知存在安全漏洞的版本、发生过投毒事件、来源不可信或维护状况堪忧；
+3. license：许可证与项目许可证不兼容（用户消息中给出了项目许可证与已识别的依赖许可证，未识别的依赖请根据你的知识判断）；
+4. pinning：版本未锁定、没有上限或使用未发布的提交等写法问题。
+
+只报告你有把握的问题，不确定最新版本号时不要编造版本号；本地规则已经发现的问题不需要重复报告。
+开发依赖 (dev) 不随产品分发，不需要检查许可证。
+你的输出必须是一个严格的 JSON 对象，不要包含任何 Markdown 格式。
+请使用中文回答。
+
+格式：
+{
+  "summary": "<一两句话总结依赖的整体状况>",
+  "findings": [
+    {
+      "dependency": "<依赖名称，与清单中的写法一致>",
+      "category": "<outdated|risky|license|pinning>",
+      "severity": "<critical|major|minor>",
+      "message": "<问题说明>",
+      "suggestion": "<具体的处理建议，如升级到的版本或替代的包>"
+    }
+  ]
+}
+没有需要处理的依赖时 findings 为空数组。`
+
+// DependencyRequest 表示一次依赖审查请求
+type DependencyRequest struct {
+	FilePath string
+	Content  string // 清单原文
+	Context  string // 项目许可证、已识别的依赖许可证与本地规则发现的问题
+}
+
+// DependencyFinding 是模型给出的依赖问题
+type DependencyFinding struct {
+	Dependency string `json:"dependency"`
+	Category   string `json:"category"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// Level 返回问题的严重程度，无法识别时视为重要问题
+func (f DependencyFinding) Level() Severity {
+	if s, err := ParseSeverity(f.Severity); err == nil && s != SeverityUnknown {
+		return s
+	}
+	return SeverityMajor
+}
+
+// DependencyResult 表示依赖审查结果
+type DependencyResult struct {
+	Summary  string              `json:"summary"`
+	Findings []DependencyFinding `json:"findings"`
+}
+
+// ReviewDependencies 审查一个依赖清单中过时、有风险与许可证不兼容的依赖
+func (c *Client) ReviewDependencies(ctx context.Context, req DependencyRequest) (*DependencyResult, error) {
+	content := req.Content
+	if len(content) > DependencyMaxContent {
+		content = content[:DependencyMaxContent] + "\n... (已截断)"
+	}
+	userPrompt := fmt.Sprintf("File: %s\n\n%s\n\nManifest:\n%s", req.FilePath, req.Context, content)
+
+	raw, err := c.chat(ctx, dependencyPrompt, userPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var result DependencyResult
+	if err := decodeJSON(raw, &result, "findings"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// statusCode 提取 API 错误中的 HTTP 状态码，无法识别时返回 0
+func statusCode(err error) int {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode
+	}
+	return 0
+}
+
+// IsRateLimited 判断错误是否为限流错误（HTTP 429）
+func IsRateLimited(err error) bool {
+	return err != nil && statusCode(err) == http.StatusTooManyRequests
+}
+
+// IsTimeout 判断错误是否为超时错误（请求超时或网关超时）
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	code := statusCode(err)
+	return code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout
+}
+
+// IsQuotaExhausted 判断错误是否表明 API 配额或余额耗尽（HTTP 402、insufficient_quota 等），
+// 这类错误短时间内重试不会成功，调用方应暂停派发并等待冷却
+func IsQuotaExhausted(err error) bool {
+	if err == nil {
+		return false
+	}
+	if statusCode(err) == http.StatusPaymentRequired {
+		return true
+	}
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if code, ok := apiErr.Code.(string); ok && code == "insufficient_quota" {
+		return true
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return apiErr.Type == "insufficient_quota" || strings.Contains(msg, "quota") || strings.Contains(msg, "insufficient balance")
+}
+
+// IsThrottled 判断错误是否表明服务端过载（限流或超时），调用方应降低并发
+func IsThrottled(err error) bool {
+	return IsRateLimited(err) || IsTimeout(err)
+}
+
+// IsTransient 判断错误是否为暂时性错误（限流、超时、5xx、网络中断），稍后重试可能成功
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if IsThrottled(err) {
+		return true
+	}
+	if code := statusCode(err); code >= http.StatusInternalServerError {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsUnauthorized 判断错误是否为认证失败（HTTP 401/403），通常是 API Key 无效或无权限
+func IsUnauthorized(err error) bool {
+	code := statusCode(err)
+	return err != nil && (code == http.StatusUnauthorized || code == http.StatusForbidden)
+}
+
+// IsNotFound 判断错误是否为 HTTP 404，通常是 base_url 路径错误或模型不存在
+func IsNotFound(err error) bool {
+	return err != nil && statusCode(err) == http.StatusNotFound
+}
+
+// IsModelNotFound 判断错误是否表明请求的模型不存在
+func IsModelNotFound(err error) bool {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if code, ok := apiErr.Code.(string); ok && code == "model_not_found" {
+		return true
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "model") && (strings.Contains(msg, "not exist") || strings.Contains(msg, "not found"))
+}
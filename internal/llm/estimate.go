@@ -0,0 +1,33 @@
+package llm
+
+// 输出 Token 的粗略估算：审查结果 JSON 的基础长度，级别越高报告的问题越多
+const (
+	estimatedCompletionBase     = 250
+	estimatedCompletionPerLevel = 50
+)
+
+// EstimateReviewTokens 粗略估算单次审查请求的 Token 消耗
+// contentSize 为代码字节数；输入包含系统提示词，输出按级别估算
+func EstimateReviewTokens(level int, contentSize int64) (prompt, completion int64) {
+	level = normalizeLevel(level)
+	system, _ := defaultPrompt.system(PromptData{Level: level})
+	prompt = int64(EstimateTokenCount(system)) + contentSize/4
+	completion = int64(estimatedCompletionBase + estimatedCompletionPerLevel*level)
+	return prompt, completion
+}
+
+// Pricing 是模型的 Token 单价（每百万 Token），用于费用估算
+type Pricing struct {
+	Input  float64
+	Output float64
+}
+
+// Enabled 返回是否配置了单价
+func (p Pricing) Enabled() bool {
+	return p.Input > 0 || p.Output > 0
+}
+
+// Cost 按单价计算费用
+func (p Pricing) Cost(prompt, completion int64) float64 {
+	return (float64(prompt)*p.Input + float64(completion)*p.Output) / 1e6
+}
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 示例的类型
+const (
+	ExampleFinding       = "finding"        // 应当报告的问题
+	ExampleFalsePositive = "false_positive" // 容易误报、不应报告的问题
+)
+
+// MaxExamples 是注入系统提示的示例数量上限，示例过多会挤占代码的上下文并增加每个请求的 Token
+const MaxExamples = 8
+
+// MaxExampleCodeSize 是单个示例代码片段的最大长度（字节），示例应当是能说明判断标准的最小片段
+const MaxExampleCodeSize = 2 * 1024
+
+// Example 是少样本示例：一段代码及团队对其的审查结论，帮助模型（尤其是较小的模型）保持一致的判断标准
+type Example struct {
+	Kind   string // ExampleFinding 或 ExampleFalsePositive
+	File   string // 示例代码的文件名（如 handler.go），可为空
+	Code   string // 代码片段
+	Issue  string // 应当报告的问题，或不应报告的误报（可带 [critical|major|minor] 标注与行号）
+	Reason string // 判断理由，可为空
+}
+
+// ValidateExamples 校验示例：数量不超过 MaxExamples，每个示例的类型有效、代码与问题不为空、代码不超过 MaxExampleCodeSize
+func ValidateExamples(examples []Example) error {
+	if len(examples) > MaxExamples {
+		return fmt.Errorf("示例过多 (%d 个)，最多 %d 个", len(examples), MaxExamples)
+	}
+	for i, ex := range examples {
+		switch {
+		case ex.Kind != ExampleFinding && ex.Kind != ExampleFalsePositive:
+			return fmt.Errorf("第 %d 个示例的 kind 为 %q，应为 %s 或 %s", i+1, ex.Kind, ExampleFinding, ExampleFalsePositive)
+		case strings.TrimSpace(ex.Code) == "":
+			return fmt.Errorf("第 %d 个示例缺少 code", i+1)
+		case strings.TrimSpace(ex.Issue) == "":
+			return fmt.Errorf("第 %d 个示例缺少 issue", i+1)
+		case len(ex.Code) > MaxExampleCodeSize:
+			return fmt.Errorf("第 %d 个示例的 code 过长 (%d 字节)，最多 %d 字节", i+1, len(ex.Code), MaxExampleCodeSize)
+		}
+	}
+	return nil
+}
+
+// examplesInstructions 构建系统提示中的示例部分，没有示例时返回空字符串
+func examplesInstructions(examples []Example) string {
+	if len(examples) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## 审查示例\n\n以下是团队标注的审查示例，请按相同的标准判断当前文件：" +
+		"与“应当报告”相似的问题需要报告，与“误报”相似的情况不要报告。示例代码不是待审查的代码，不要报告其中的问题。")
+	for i, ex := range examples {
+		label := "应当报告"
+		if ex.Kind == ExampleFalsePositive {
+			label = "误报，不要报告"
+		}
+		fmt.Fprintf(&b, "\n\n### 示例 %d：%s\n", i+1, label)
+		if ex.File != "" {
+			fmt.Fprintf(&b, "文件: %s\n", ex.File)
+		}
+		fmt.Fprintf(&b, "```\n%s\n```\n", strings.Trim(ex.Code, "\n"))
+		if ex.Kind == ExampleFalsePositive {
+			fmt.Fprintf(&b, "不要报告: %s", strings.TrimSpace(ex.Issue))
+		} else {
+			fmt.Fprintf(&b, "应报告: %s", strings.TrimSpace(ex.Issue))
+		}
+		if reason := strings.TrimSpace(ex.Reason); reason != "" {
+			fmt.Fprintf(&b, "\n理由: %s", reason)
+		}
+	}
+	return b.String()
+}
+
+// WithExamples 在系统提示中注入少样本示例（应在注入前用 ValidateExamples 校验）
+func WithExamples(examples []Example) ClientOption {
+	return func(c *Client) {
+		c.examples = examplesInstructions(examples)
+	}
+}
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// 审查重点（--focus），按在提示词与报告中的显示顺序排列
+const (
+	FocusSecurity    = "security"
+	FocusPerformance = "performance"
+	FocusCorrectness = "correctness"
+	FocusStyle       = "style"
+)
+
+// focusArea 描述一个审查重点
+type focusArea struct {
+	name         string
+	label        string // 报告中的显示名称
+	instructions string // 注入系统提示的检查要点
+}
+
+// focusAreas 是支持的审查重点
+var focusAreas = []focusArea{
+	{FocusSecurity, "安全", "注入 (SQL/命令/路径遍历)、认证与授权缺陷、敏感信息泄露与硬编码凭据、不安全的反序列化与加密用法、缺失的输入校验"},
+	{FocusPerformance, "性能", "不必要的内存分配与拷贝、循环中的重复计算或 I/O、N+1 查询、锁竞争与阻塞调用、过高的算法复杂度"},
+	{FocusCorrectness, "正确性", "逻辑错误、边界条件、错误处理缺失、并发竞态与资源泄漏"},
+	{FocusStyle, "风格", "命名、注释、函数长度与复杂度、重复代码、与项目约定的一致性"},
+}
+
+// FocusAreas 返回支持的审查重点名称
+func FocusAreas() []string {
+	names := make([]string, len(focusAreas))
+	for i, a := range focusAreas {
+		names[i] = a.name
+	}
+	return names
+}
+
+// ParseFocus 校验审查重点（不区分大小写，支持逗号分隔），按固定顺序去重后返回，空列表表示全面审查
+func ParseFocus(values []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(FocusAreas(), name) {
+				return nil, fmt.Errorf("无效的审查重点 %q，可选值: %s", name, strings.Join(FocusAreas(), ", "))
+			}
+			seen[name] = true
+		}
+	}
+
+	var focus []string
+	for _, a := range focusAreas {
+		if seen[a.name] {
+			focus = append(focus, a.name)
+		}
+	}
+	return focus, nil
+}
+
+// FocusLabel 返回审查重点在报告中的显示文本，如 "安全、性能"
+func FocusLabel(focus []string) string {
+	labels := make([]string, 0, len(focus))
+	for _, a := range focusAreas {
+		if slices.Contains(focus, a.name) {
+			labels = append(labels, a.label)
+		}
+	}
+	return strings.Join(labels, "、")
+}
+
+// focusInstructions 返回注入系统提示的审查重点说明，未设置重点时返回空字符串
+// 重点以外的方面只报告严重问题，评分也以重点方面为主要依据
+func focusInstructions(focus []string) string {
+	if len(focus) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## 审查重点\n\n本次审查只聚焦以下方面，请优先且深入地检查：\n")
+	for _, a := range focusAreas {
+		if slices.Contains(focus, a.name) {
+			fmt.Fprintf(&b, "- **%s**：%s\n", a.label, a.instructions)
+		}
+	}
+	b.WriteString("\n重点以外的方面只报告 [critical] 级别的问题。评分时以重点方面的问题为主要依据（约占 80% 权重），其他方面对分数的影响很小。")
+	return b.String()
+}
+
+// WithFocus 设置审查重点，系统提示中加入对应的检查要点与评分权重说明
+func WithFocus(focus []string) ClientOption {
+	return func(c *Client) {
+		c.focus = focus
+	}
+}
@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// 支持语言要点的语言，按在系统提示中的显示顺序排列（框架排在语言之后）
+const (
+	LanguageGo         = "go"
+	LanguagePython     = "python"
+	LanguageJavaScript = "javascript"
+	LanguageTypeScript = "typescript"
+	LanguageJava       = "java"
+	LanguageRust       = "rust"
+	LanguageC          = "c"
+	LanguageCPP        = "cpp"
+	LanguageShell      = "shell"
+	LanguageReact      = "react"
+	LanguageVue        = "vue"
+)
+
+// languageGuide 描述一种语言（或框架）的审查要点
+type languageGuide struct {
+	name  string
+	label string   // 系统提示中的显示名称
+	exts  []string // 按扩展名识别，React 另外按导入语句识别
+	guide string   // 注入系统提示的检查要点
+}
+
+// languageGuides 是内置的语言要点
+var languageGuides = []languageGuide{
+	{LanguageGo, "Go", []string{".go"}, `- error 必须检查，向上返回时用 fmt.Errorf("...: %w", err) 包装并补充上下文，比较错误使用 errors.Is / errors.As
+- 不要既记录日志又返回同一个错误；defer 关闭可写文件时需要处理 Close 的错误
+- 启动 goroutine 时确认其退出条件，阻塞操作应接受 context.Context（作为第一个参数）以支持取消
+- map 并发读写必须加锁；切片 append 后可能与原切片共享底层数组
+- 接口应小而在使用方定义；导出标识符需要以名称开头的文档注释`},
+	{LanguagePython, "Python", []string{".py"}, `- 公共函数与方法应有完整的类型注解（PEP 484），优先使用 list[str]、X | None 等现代写法，注解需与实际返回值一致
+- 不要使用可变对象（list、dict）作为参数默认值
+- 禁止裸 except 与静默吞掉异常，捕获具体的异常类型
+- 文件、锁、连接等资源使用 with 管理
+- 比较 None 使用 is / is not，避免 from x import *`},
+	{LanguageJavaScript, "JavaScript", []string{".js", ".jsx", ".mjs", ".cjs"}, `- 使用 === / !==，使用 const / let 而不是 var
+- async 函数中的 Promise 必须 await 或显式处理 rejection，避免未捕获的异步错误
+- 注意回调中 this 的绑定，以及由用户输入构造对象键导致的原型污染`},
+	{LanguageTypeScript, "TypeScript", []string{".ts", ".tsx", ".mts", ".cts"}, `- 避免 any 与非空断言 (!)，外部输入优先使用 unknown 并通过类型守卫收窄
+- as 类型断言不能替代运行时校验；联合类型的分支判断应穷尽（default 分支赋值给 never）
+- 代码应在 strict 模式下通过类型检查，公共 API 显式声明返回类型`},
+	{LanguageJava, "Java", []string{".java"}, `- 流、连接等资源使用 try-with-resources 关闭
+- 重写 equals 时必须同时重写 hashCode；不要捕获 Exception / Throwable 后吞掉
+- 可能为空的返回值优先使用 Optional，共享可变状态需要同步或使用并发集合`},
+	{LanguageRust, "Rust", []string{".rs"}, `- 库代码避免 unwrap / expect，使用 Result 与 ? 传播错误
+- unsafe 块必须以 SAFETY 注释说明成立的前提
+- 避免为绕过借用检查而进行的不必要 clone；注意 Mutex 持锁跨越 .await`},
+	{LanguageC, "C", []string{".c", ".h"}, `- 检查 malloc 等分配函数与系统调用的返回值，每条错误路径都要释放已分配的资源
+- 字符串与缓冲区操作必须检查边界，禁止 gets、sprintf、strcpy 等不检查长度的函数
+- 注意整数溢出、有符号与无符号混用，以及释放后使用与重复释放`},
+	{LanguageCPP, "C++", []string{".cpp", ".cc", ".cxx", ".hpp", ".hh"}, `- 资源遵循 RAII，使用 std::unique_ptr / std::shared_ptr 而不是裸 new / delete
+- 注意迭代器失效、悬垂引用与未定义行为；单参数构造函数应为 explicit
+- 管理资源的类型遵循三/五法则，移动后的对象不应再被使用`},
+	{LanguageShell, "Shell", []string{".sh", ".bash", ".zsh"}, `- 变量展开必须加双引号（"$var"），避免分词与通配符展开
+- 脚本开头使用 set -euo pipefail，或显式检查关键命令的退出码
+- 使用 $(...) 而不是反引号；临时文件用 mktemp 创建并通过 trap 清理`},
+	{LanguageReact, "React", []string{".jsx", ".tsx"}, `- Hooks 只能在组件或自定义 Hook 的顶层调用，不能在条件、循环或嵌套函数中调用
+- useEffect / useMemo / useCallback 的依赖数组必须包含所有用到的响应式值；订阅、定时器等副作用需要在清理函数中释放
+- 不要直接修改 state 或 props，更新基于旧值时使用函数式更新
+- 列表元素的 key 应稳定且唯一，不要使用数组索引；渲染过程中不要产生副作用`},
+	{LanguageVue, "Vue", []string{".vue"}, `- 不要直接修改 props，通过 emit 通知父组件
+- computed 中不要产生副作用；对 reactive 对象解构会失去响应性，需要使用 toRefs
+- v-for 必须提供稳定的 key，且不要与 v-if 用在同一元素上；组件卸载时清理事件监听与定时器`},
+}
+
+// reactImportRegex 匹配导入 React 的语句，用于识别 .js / .ts 中的 React 组件
+var reactImportRegex = regexp.MustCompile(`(?m)(?:from\s+|require\(\s*)['"]react(?:-dom)?['"]`)
+
+// Languages 返回支持语言要点的语言名称
+func Languages() []string {
+	names := make([]string, len(languageGuides))
+	for i, g := range languageGuides {
+		names[i] = g.name
+	}
+	return names
+}
+
+// DetectLanguages 根据扩展名与导入语句识别文件的语言（及框架），按显示顺序返回
+func DetectLanguages(filePath, content string) []string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	var langs []string
+	for _, g := range languageGuides {
+		if slices.Contains(g.exts, ext) {
+			langs = append(langs, g.name)
+		}
+	}
+	if !slices.Contains(langs, LanguageReact) && (slices.Contains(langs, LanguageJavaScript) || slices.Contains(langs, LanguageTypeScript)) && reactImportRegex.MatchString(content) {
+		langs = append(langs, LanguageReact)
+	}
+	return langs
+}
+
+// defaultLanguageGuides 返回内置的语言要点，用于导出提示文件
+func defaultLanguageGuides() map[string]string {
+	guides := make(map[string]string, len(languageGuides))
+	for _, g := range languageGuides {
+		guides[g.name] = g.guide
+	}
+	return guides
+}
+
+// validateLanguages 校验提示文件中 languages 的语言名称
+func validateLanguages(guides map[string]string) error {
+	for name := range guides {
+		if !slices.Contains(Languages(), name) {
+			return fmt.Errorf("无效的语言 %q，可选值: %s", name, strings.Join(Languages(), ", "))
+		}
+	}
+	return nil
+}
+
+// languageInstructions 返回注入系统提示的语言要点与语言显示名称，未识别的语言返回空字符串
+// 提示文件的 languages 中覆盖的要点优先，覆盖为空字符串时不注入该语言的要点
+func (p *Prompt) languageInstructions(filePath, content string) (guide, label string) {
+	var b strings.Builder
+	var labels []string
+	for _, name := range DetectLanguages(filePath, content) {
+		g := languageGuides[slices.Index(Languages(), name)]
+		labels = append(labels, g.label)
+
+		text := g.guide
+		if p != nil {
+			if custom, ok := p.languages[name]; ok {
+				text = custom
+			}
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			fmt.Fprintf(&b, "\n\n### %s\n%s", g.label, text)
+		}
+	}
+	label = strings.Join(labels, "、")
+	if b.Len() == 0 {
+		return "", label
+	}
+	return "## 语言要点\n\n除通用要求外，请按以下约定检查本文件（违反约定且影响正确性时按 [major] 报告，其余按 [minor]）：" + b.String(), label
+}
+
+// WithLanguagePrompts 设置是否按文件语言注入语言要点（默认注入）
+func WithLanguagePrompts(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.noLanguage = !enabled
+	}
+}
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrModelListUnsupported 表示接口未提供模型列表（部分兼容接口没有实现 /models）
+var ErrModelListUnsupported = errors.New("接口不支持查询模型列表")
+
+// modelLister 是可选的模型列表接口，OpenAI 兼容接口通常通过 /models 提供
+type modelLister interface {
+	ListModels(ctx context.Context) (openai.ModelsList, error)
+}
+
+// Model 返回客户端使用的模型名称
+func (c *Client) Model() string {
+	return c.model
+}
+
+// Ping 发送一次只生成 1 个 Token 的对话请求，用于检查连通性、认证与模型是否可用
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.api.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:     c.model,
+		Messages:  []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "ping"}},
+		MaxTokens: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("API 调用失败: %w", err)
+	}
+	c.usage.add(resp.Usage)
+	return nil
+}
+
+// ListModels 返回接口提供的模型 ID（已排序），接口不支持时返回 ErrModelListUnsupported
+func (c *Client) ListModels(ctx context.Context) ([]string, error) {
+	lister, ok := c.api.(modelLister)
+	if !ok {
+		return nil, ErrModelListUnsupported
+	}
+	list, err := lister.ListModels(ctx)
+	if err != nil {
+		if code := statusCode(err); code == http.StatusNotFound || code == http.StatusMethodNotAllowed {
+			return nil, ErrModelListUnsupported
+		}
+		return nil, fmt.Errorf("查询模型列表失败: %w", err)
+	}
+
+	ids := make([]string, 0, len(list.Models))
+	for _, m := range list.Models {
+		ids = append(ids, m.ID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
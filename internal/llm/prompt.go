@@ -0,0 +1,181 @@
+package llm
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// PromptData 是系统提示模板中可用的变量
+type PromptData struct {
+	Level            int    // 审查严格级别 (1-6)
+	LevelDescription string // 当前级别的描述（可在提示文件的 levels 中覆盖）
+	FilePath         string // 待审查文件的路径，可用于按语言调整要求
+	Focus            string // 审查重点的检查要点（--focus），未设置时为空，模板未引用时自动追加
+	Language         string // 识别出的文件语言（及框架），如 "TypeScript、React"，未识别时为空
+	LanguageGuide    string // 按文件语言注入的检查要点，未识别或已关闭时为空，模板未引用时自动追加
+	Conventions      string // 团队规范文件（CONVENTIONS.md 等）的内容与使用说明，未找到时为空，模板未引用时自动追加
+	Rules            string // 适用于当前文件的团队规则（rules 配置），没有适用的规则时为空，模板未引用时自动追加
+	Examples         string // 配置的少样本示例（应当报告的问题与误报），未配置时为空，模板未引用时自动追加
+	OutputFormat     string // JSON 输出格式要求，模板未引用时自动追加到末尾
+}
+
+// Prompt 是审查使用的系统提示模板与级别描述
+type Prompt struct {
+	tmpl      *template.Template
+	levels    map[int]string    // 覆盖内置描述的级别，未覆盖的级别使用内置描述
+	languages map[string]string // 覆盖内置要点的语言，未覆盖的语言使用内置要点
+	format    bool              // 模板是否引用了 {{.OutputFormat}}
+	focus     bool              // 模板是否引用了 {{.Focus}}
+	language  bool              // 模板是否引用了 {{.LanguageGuide}}
+	rules     bool              // 模板是否引用了 {{.Conventions}}
+	ruleSet   bool              // 模板是否引用了 {{.Rules}}
+	examples  bool              // 模板是否引用了 {{.Examples}}
+}
+
+// promptFuncs 是模板中可用的函数，便于按文件类型调整要求
+var promptFuncs = template.FuncMap{
+	"ext":       filepath.Ext,
+	"lower":     strings.ToLower,
+	"contains":  strings.Contains,
+	"hasPrefix": strings.HasPrefix,
+	"hasSuffix": strings.HasSuffix,
+}
+
+// promptFile 是提示文件的 YAML 结构
+type promptFile struct {
+	System    string            `yaml:"system"`
+	Levels    map[int]string    `yaml:"levels"`
+	Languages map[string]string `yaml:"languages,omitempty"`
+}
+
+// defaultPrompt 是内置的系统提示
+var defaultPrompt = mustParsePrompt(systemPromptTemplate, nil)
+
+// LoadPromptFile 读取自定义提示文件（YAML：system 为 text/template 模板，levels 按级别覆盖描述，
+// languages 按语言覆盖语言要点，均可省略）
+// 加载时用示例数据渲染一次，模板语法或变量名错误会在审查开始前报告
+func LoadPromptFile(path string) (*Prompt, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取提示文件失败: %w", err)
+	}
+
+	var f promptFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("提示文件 %s 格式错误: %w", path, err)
+	}
+	for level := range f.Levels {
+		if level < MinLevel || level > MaxLevel {
+			return nil, fmt.Errorf("提示文件 %s 的 levels 包含无效级别 %d (应为 %d-%d)", path, level, MinLevel, MaxLevel)
+		}
+	}
+	if err := validateLanguages(f.Languages); err != nil {
+		return nil, fmt.Errorf("提示文件 %s 的 languages: %w", path, err)
+	}
+	if strings.TrimSpace(f.System) == "" {
+		f.System = systemPromptTemplate
+	}
+
+	p, err := parsePrompt(f.System, f.Levels)
+	if err != nil {
+		return nil, fmt.Errorf("提示文件 %s 的 system 模板错误: %w", path, err)
+	}
+	p.languages = f.Languages
+	guide, label := p.languageInstructions("main.go", "")
+	sample := PromptData{Level: DefaultLevel, FilePath: "main.go", Focus: focusInstructions(FocusAreas()), Language: label, LanguageGuide: guide,
+		Conventions: conventionsInstructions(ConventionFiles[0], "- 示例规范"),
+		Rules:       rulesInstructions([]Rule{{ID: "R001", Description: "示例规则"}}),
+		Examples:    examplesInstructions([]Example{{Kind: ExampleFinding, Code: "x := 1", Issue: "示例问题"}})}
+	if _, err := p.system(sample); err != nil {
+		return nil, fmt.Errorf("提示文件 %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// DefaultPromptFile 返回内置提示对应的提示文件内容，可作为自定义的起点
+func DefaultPromptFile() ([]byte, error) {
+	return yaml.Marshal(promptFile{System: systemPromptTemplate, Levels: levelDescriptions, Languages: defaultLanguageGuides()})
+}
+
+// WithPrompt 使用自定义的系统提示模板与级别描述
+func WithPrompt(p *Prompt) ClientOption {
+	return func(c *Client) {
+		c.prompt = p
+	}
+}
+
+// parsePrompt 解析系统提示模板，引用不存在的变量时渲染报错
+func parsePrompt(text string, levels map[int]string) (*Prompt, error) {
+	tmpl, err := template.New("system").Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Prompt{
+		tmpl:     tmpl,
+		levels:   levels,
+		format:   strings.Contains(text, ".OutputFormat"),
+		focus:    strings.Contains(text, ".Focus"),
+		language: strings.Contains(text, ".LanguageGuide"),
+		rules:    strings.Contains(text, ".Conventions"),
+		ruleSet:  strings.Contains(text, ".Rules"),
+		examples: strings.Contains(text, ".Examples"),
+	}, nil
+}
+
+// mustParsePrompt 解析内置模板，失败时 panic
+func mustParsePrompt(text string, levels map[int]string) *Prompt {
+	p, err := parsePrompt(text, levels)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// levelDescription 返回级别描述，优先使用自定义描述
+func (p *Prompt) levelDescription(level int) string {
+	if p != nil {
+		if desc, ok := p.levels[level]; ok {
+			return desc
+		}
+	}
+	return getLevelDescription(level)
+}
+
+// system 渲染系统提示，data 中的级别描述与输出格式由 system 填充，p 为空时使用内置模板
+func (p *Prompt) system(data PromptData) (string, error) {
+	if p == nil {
+		p = defaultPrompt
+	}
+
+	data.LevelDescription = p.levelDescription(data.Level)
+	data.OutputFormat = outputFormat
+	var b bytes.Buffer
+	if err := p.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("渲染系统提示失败: %w", err)
+	}
+	if !p.focus && data.Focus != "" {
+		b.WriteString("\n\n" + data.Focus)
+	}
+	if !p.language && data.LanguageGuide != "" {
+		b.WriteString("\n\n" + data.LanguageGuide)
+	}
+	if !p.rules && data.Conventions != "" {
+		b.WriteString("\n\n" + data.Conventions)
+	}
+	if !p.ruleSet && data.Rules != "" {
+		b.WriteString("\n\n" + data.Rules)
+	}
+	if !p.examples && data.Examples != "" {
+		b.WriteString("\n\n" + data.Examples)
+	}
+	if !p.format {
+		b.WriteString("\n\n" + outputFormat)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go-ai-reviewer/pkg/utils"
+)
+
+// 修复提示：模型输出不是合法 JSON 或不符合格式约定时，将原始输出发回给模型修复一次
+const repairPrompt = `你是 JSON 修复工具。用户消息是另一个模型的输出，本应是一个 JSON 对象，但格式错误、被截断或字段不符合格式约定。
+请只返回修复后的合法 JSON 对象：保留原有的字段与内容，修正引号、转义、逗号与括号；被截断的最后一项可以删除，但不要删除其他字段。
+如果消息末尾列出了不符合约定的字段，请按约定修正：score 为 0-100 的整数，importance 与 confidence 为 0.0-1.0 的浮点数，
+summary 为字符串，issues 为以 [critical|major|minor] 标注开头的字符串数组，severity 为 critical/major/minor 之一。
+不要添加任何解释，不要使用 Markdown 代码块。`
+
+// 修复的限制
+const (
+	maxRepairInput     = 20000 // 发回模型修复的输出最大长度（字符），避免修复请求本身过大
+	maxRecoverAttempts = 200   // 本地修复截断 JSON 时最多尝试的截断位置数
+)
+
+// repair 将无法解析的输出发回给模型修复一次，返回修复后解析得到的结果
+// 解析失败的原因是不符合 Schema 时附上违规的字段；修复后仍越界的数值修正到范围内
+func (c *Client) repair(ctx context.Context, content string, cause error) (*ReviewResult, error) {
+	input := utils.Truncate(content, maxRepairInput)
+	var se *schemaError
+	if errors.As(cause, &se) {
+		input += "\n\n不符合约定的字段:\n- " + strings.Join(se.violations, "\n- ")
+	}
+	fixed, err := c.chat(ctx, repairPrompt, input)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse(fixed, true)
+}
+
+// recoverJSON 在本地修复常见的格式问题：未闭合的代码块、JSON 前后的多余文字，
+// 以及输出被截断（补全未闭合的字符串与括号，必要时丢弃最后一个不完整的成员）
+func recoverJSON(content string) (string, bool) {
+	s := strings.TrimSpace(content)
+	if strings.HasPrefix(s, "```") {
+		_, s, _ = strings.Cut(s, "\n")
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return "", false
+	}
+	s = s[start:]
+
+	// 1. 完整的 JSON 值后面跟着多余内容
+	var raw json.RawMessage
+	if err := json.NewDecoder(strings.NewReader(s)).Decode(&raw); err == nil {
+		return string(raw), true
+	}
+
+	// 2. 输出被截断：从末尾开始寻找可以补全的位置，保留尽可能多的内容
+	if fixed := closeJSON(s); json.Valid([]byte(fixed)) {
+		return fixed, true
+	}
+	attempts := 0
+	for i := len(s) - 1; i > 0 && attempts < maxRecoverAttempts; i-- {
+		var prefix string
+		switch s[i] {
+		case ',':
+			prefix = s[:i]
+		case '{', '[':
+			prefix = s[:i+1]
+		default:
+			continue
+		}
+		attempts++
+		if fixed := closeJSON(prefix); json.Valid([]byte(fixed)) {
+			return fixed, true
+		}
+	}
+	return "", false
+}
+
+// closeJSON 补全 s 中未闭合的字符串与括号
+func closeJSON(s string) string {
+	var stack []byte
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{':
+			stack = append(stack, '}')
+		case c == '[':
+			stack = append(stack, ']')
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	var b strings.Builder
+	if escaped {
+		// 截断在转义符之后：去掉不完整的转义
+		s = s[:len(s)-1]
+	}
+	b.WriteString(s)
+	if inString {
+		b.WriteByte('"')
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		b.WriteByte(stack[i])
+	}
+	return b.String()
+}
+
+// decodeRecovered 解析本地修复后的 JSON，修复结果必须包含 required 中的字段，
+// 避免截断后只剩部分字段的结果（如缺少评分）被当作有效结论
+func decodeRecovered(content string, v any, required []string) error {
+	fixed, ok := recoverJSON(content)
+	if !ok {
+		return fmt.Errorf("无法修复")
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(fixed), &fields); err != nil {
+		return err
+	}
+	for _, key := range required {
+		if _, ok := fields[key]; !ok {
+			return fmt.Errorf("修复后缺少 %s 字段", key)
+		}
+	}
+	if err := json.Unmarshal([]byte(fixed), v); err != nil {
+		return err
+	}
+	slog.Debug("已在本地修复格式错误的 JSON 响应", "original", len(content), "recovered", len(fixed))
+	return nil
+}
@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Rule 是团队定义的审查规则：只注入适用文件的系统提示，违反规则的问题以 [<规则编号>] 标注，
+// 便于在报告中按规则统计，或按规则抑制
+type Rule struct {
+	ID           string   // 规则编号，如 SEC-001
+	Description  string   // 一句话说明
+	Paths        []string // 适用的路径模式（由调用方匹配，语法同 importance），为空时适用于所有文件
+	Instructions string   // 注入系统提示的检查要求，为空时只使用 Description
+	Severity     string   // 违反规则时的默认严重程度，为空时由模型判断
+}
+
+// ruleIDPattern 是规则编号的格式：字母开头，由字母、数字、_、.、- 组成
+var ruleIDPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// issueRulePattern 匹配问题文本开头（严重程度标注之后）的 [<规则编号>]
+var issueRulePattern = regexp.MustCompile(`^\s*\[([A-Za-z][A-Za-z0-9_.-]*)\]`)
+
+// ValidateRules 校验规则：编号格式正确且不重复（不区分大小写），说明与检查要求至少有一项，
+// 严重程度与路径模式有效
+func ValidateRules(rules []Rule) error {
+	seen := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		if !ruleIDPattern.MatchString(r.ID) {
+			return fmt.Errorf("第 %d 条规则的 id %q 无效 (应以字母开头，由字母、数字、_、.、- 组成)", i+1, r.ID)
+		}
+		key := strings.ToUpper(r.ID)
+		if seen[key] {
+			return fmt.Errorf("规则 %s 重复", r.ID)
+		}
+		seen[key] = true
+		if strings.TrimSpace(r.Description) == "" && strings.TrimSpace(r.Instructions) == "" {
+			return fmt.Errorf("规则 %s 缺少 description 或 instructions", r.ID)
+		}
+		if _, err := ParseSeverity(r.Severity); err != nil {
+			return fmt.Errorf("规则 %s: %w", r.ID, err)
+		}
+		for _, pattern := range r.Paths {
+			if strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("规则 %s 的路径模式不能为空", r.ID)
+			}
+			for _, seg := range strings.Split(pattern, "/") {
+				if _, err := path.Match(seg, ""); err != nil {
+					return fmt.Errorf("规则 %s 的路径模式 %q 无效: %w", r.ID, pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rulesInstructions 构建系统提示中的团队规则部分，没有适用的规则时返回空字符串
+func rulesInstructions(rules []Rule) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## 团队规则\n\n以下规则适用于当前文件。发现违反规则的代码时，在严重程度标注后注明规则编号，" +
+		"如 \"[major] [" + rules[0].ID + "] 第 42 行: <问题>\"；与规则无关的问题不要标注规则编号。\n")
+	for _, r := range rules {
+		fmt.Fprintf(&b, "\n- **%s**", r.ID)
+		if s, _ := ParseSeverity(r.Severity); s != SeverityUnknown {
+			fmt.Fprintf(&b, " (违反时一般为 [%s])", s)
+		}
+		if desc := strings.TrimSpace(r.Description); desc != "" {
+			b.WriteString(": " + desc)
+		}
+		if instructions := strings.TrimSpace(r.Instructions); instructions != "" {
+			b.WriteString("\n  " + strings.ReplaceAll(instructions, "\n", "\n  "))
+		}
+	}
+	return b.String()
+}
+
+// IssueRule 返回问题引用的规则编号（严重程度标注之后的 [<规则编号>]），未引用规则时返回空字符串
+func IssueRule(issue string) string {
+	_, text := SplitIssue(issue)
+	if m := issueRulePattern.FindStringSubmatch(text); m != nil {
+		return m[1]
+	}
+	return ""
+}
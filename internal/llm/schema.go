@@ -0,0 +1,257 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-ai-reviewer/pkg/utils"
+)
+
+// reviewSchemaJSON 是审查结果（ReviewResult）的 JSON Schema，每个响应在使用前都按它校验，
+// 避免 score=150、importance=3.0 之类的越界值混入综合评分与排序
+// 校验只支持其中用到的关键字：type、required、properties、items、enum、pattern、minimum、maximum
+const reviewSchemaJSON = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "ReviewResult",
+  "description": "单个文件的代码审查结果",
+  "type": "object",
+  "required": ["score", "importance", "summary"],
+  "properties": {
+    "score": {"type": "integer", "minimum": 0, "maximum": 100, "description": "评分"},
+    "importance": {"type": "number", "minimum": 0, "maximum": 1, "description": "文件在项目中的重要性"},
+    "confidence": {"type": "number", "minimum": 0, "maximum": 1, "description": "审查结论的置信度"},
+    "summary": {"type": "string", "description": "一句话总结"},
+    "pros": {"type": "array", "items": {"type": "string"}},
+    "issues": {
+      "type": "array",
+      "description": "问题列表，以 [critical|major|minor] 或 [<严重程度>|<置信度>] 标注开头",
+      "items": {"type": "string", "pattern": "^\\s*([^\\s\\[]|\\[(?i:critical|major|minor)(\\|[^\\]]*)?\\])"}
+    },
+    "suggestion": {"type": "string"},
+    "patch": {"type": "string", "description": "修复问题的 unified diff"},
+    "findings": {
+      "type": "array",
+      "description": "安全审计发现的漏洞",
+      "items": {
+        "type": "object",
+        "required": ["severity"],
+        "properties": {
+          "cwe": {"type": "string"},
+          "title": {"type": "string"},
+          "severity": {"type": "string", "enum": ["critical", "major", "minor"]},
+          "line": {"type": "integer", "minimum": 0},
+          "exploitability": {"type": "string", "enum": ["high", "medium", "low", ""]},
+          "confidence": {"type": "number", "minimum": 0, "maximum": 1},
+          "description": {"type": "string"},
+          "remediation": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
+
+// jsonSchema 是 JSON Schema 中校验用到的部分
+type jsonSchema struct {
+	Type       string                 `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Items      *jsonSchema            `json:"items"`
+	Enum       []string               `json:"enum"`
+	Pattern    string                 `json:"pattern"`
+	Minimum    *float64               `json:"minimum"`
+	Maximum    *float64               `json:"maximum"`
+
+	pattern *regexp.Regexp
+}
+
+// reviewSchema 是解析后的审查结果 Schema
+var reviewSchema = mustParseSchema(reviewSchemaJSON)
+
+// ReviewSchema 返回审查结果的 JSON Schema，供自定义提示与外部工具参考
+func ReviewSchema() string {
+	return reviewSchemaJSON
+}
+
+// mustParseSchema 解析 Schema 并编译其中的正则表达式
+func mustParseSchema(text string) *jsonSchema {
+	var s jsonSchema
+	if err := json.Unmarshal([]byte(text), &s); err != nil {
+		panic(fmt.Sprintf("审查结果 Schema 格式错误: %v", err))
+	}
+	s.compile()
+	return &s
+}
+
+// compile 编译 Schema 及其子 Schema 中的 pattern
+func (s *jsonSchema) compile() {
+	if s.Pattern != "" {
+		s.pattern = regexp.MustCompile(s.Pattern)
+	}
+	for _, p := range s.Properties {
+		p.compile()
+	}
+	if s.Items != nil {
+		s.Items.compile()
+	}
+}
+
+// schemaError 表示响应不符合审查结果的 Schema
+type schemaError struct {
+	violations []string
+}
+
+func (e *schemaError) Error() string {
+	return "审查结果不符合格式约定: " + strings.Join(e.violations, "; ")
+}
+
+// schemaValidator 按 Schema 校验解码后的 JSON 值
+// clamp 为 true 时将越界的数值修正到范围内、将小数评分取整，不符合枚举或格式的标注只记录警告
+// （后续按未标注处理，如未知的严重程度视为 major），只有缺少字段与类型错误仍判定为违规
+type schemaValidator struct {
+	clamp      bool
+	violations []string
+	warnings   []string
+}
+
+// soft 记录可以容忍的违规：clamp 为 true 时只记录警告
+func (v *schemaValidator) soft(msg string) {
+	if v.clamp {
+		v.warnings = append(v.warnings, msg)
+	} else {
+		v.violations = append(v.violations, msg)
+	}
+}
+
+// validateReview 按 Schema 校验审查结果的原始 JSON，返回（可能经过修正的）JSON
+// clamp 为 false 时任何违规都返回 *schemaError；为 true 时只有缺少字段与类型错误才返回错误
+func validateReview(raw []byte, clamp bool) ([]byte, error) {
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	v := schemaValidator{clamp: clamp}
+	doc = v.check(reviewSchema, doc, "")
+	if len(v.violations) > 0 {
+		return nil, &schemaError{violations: v.violations}
+	}
+	if len(v.warnings) == 0 {
+		return raw, nil
+	}
+	slog.Warn("审查结果不符合格式约定，已修正", "fields", strings.Join(v.warnings, "; "))
+	return json.Marshal(doc)
+}
+
+// check 校验 value 并返回（修正后的）值，path 为字段路径（如 findings[0].severity）
+func (v *schemaValidator) check(s *jsonSchema, value any, path string) any {
+	name := path
+	if name == "" {
+		name = "响应"
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			v.violations = append(v.violations, fmt.Sprintf("%s 应为对象", name))
+			return value
+		}
+		for _, key := range s.Required {
+			if obj[key] == nil {
+				v.violations = append(v.violations, fmt.Sprintf("缺少 %s 字段", joinPath(path, key)))
+			}
+		}
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			// null 视为未给出
+			if val, ok := obj[key]; ok && val != nil {
+				obj[key] = v.check(s.Properties[key], val, joinPath(path, key))
+			}
+		}
+		return obj
+
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			v.violations = append(v.violations, fmt.Sprintf("%s 应为数组", name))
+			return value
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				arr[i] = v.check(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+		return arr
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.violations = append(v.violations, fmt.Sprintf("%s 应为字符串", name))
+			return value
+		}
+		// 枚举比较忽略大小写与首尾空白，与 ParseSeverity 一致
+		if len(s.Enum) > 0 && !containsFold(s.Enum, strings.TrimSpace(str)) {
+			v.soft(fmt.Sprintf("%s 为 %q，应为 %s 之一", name, str, strings.Join(s.Enum, "/")))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(str) {
+			v.soft(fmt.Sprintf("%s 格式不正确: %q", name, utils.Truncate(str, 40)))
+		}
+		return str
+
+	case "number", "integer":
+		num, ok := value.(float64)
+		if !ok {
+			v.violations = append(v.violations, fmt.Sprintf("%s 应为数值", name))
+			return value
+		}
+		fixed := num
+		var problems []string
+		if s.Type == "integer" && num != math.Trunc(num) {
+			problems = append(problems, "应为整数")
+			fixed = math.Round(fixed)
+		}
+		if s.Minimum != nil && fixed < *s.Minimum {
+			problems = append(problems, fmt.Sprintf("小于 %g", *s.Minimum))
+			fixed = *s.Minimum
+		}
+		if s.Maximum != nil && fixed > *s.Maximum {
+			problems = append(problems, fmt.Sprintf("大于 %g", *s.Maximum))
+			fixed = *s.Maximum
+		}
+		if len(problems) == 0 {
+			return num
+		}
+		if !v.clamp {
+			v.violations = append(v.violations, fmt.Sprintf("%s 为 %g，%s", name, num, strings.Join(problems, "且")))
+			return num
+		}
+		v.warnings = append(v.warnings, fmt.Sprintf("%s: %g -> %g", name, num, fixed))
+		return fixed
+	}
+	return value
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// containsFold 判断 list 中是否有与 s 忽略大小写相等的项
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package llm
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity 表示问题的严重程度，数值越大越严重
+type Severity int
+
+// 严重程度定义，模型在每条问题开头以 [critical] / [major] / [minor] 标注
+const (
+	SeverityUnknown  Severity = iota // 未标注（按 major 处理）
+	SeverityMinor                    // 代码风格、命名、可读性
+	SeverityMajor                    // 逻辑错误、资源泄漏、错误处理缺失
+	SeverityCritical                 // 运行时崩溃、安全漏洞、数据损坏
+)
+
+// severityNames 是严重程度的标签名称，与提示词中的标注一致
+var severityNames = map[Severity]string{
+	SeverityMinor:    "minor",
+	SeverityMajor:    "major",
+	SeverityCritical: "critical",
+}
+
+// severityLabels 是严重程度在报告中的显示文本
+var severityLabels = map[Severity]string{
+	SeverityMinor:    "🟡 一般",
+	SeverityMajor:    "🟠 重要",
+	SeverityCritical: "🔴 严重",
+}
+
+// String 返回严重程度的标签名称，未标注时返回空字符串
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// Label 返回严重程度在报告中的显示文本，未标注时返回空字符串
+func (s Severity) Label() string {
+	return severityLabels[s]
+}
+
+// ParseSeverity 解析 critical / major / minor（不区分大小写），空字符串表示不过滤
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return SeverityUnknown, nil
+	}
+	for s, n := range severityNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return SeverityUnknown, fmt.Errorf("无效的严重程度 %q，可选值: critical, major, minor", name)
+}
+
+// SplitIssue 拆分问题开头的严重程度标注（含置信度），未标注时返回 SeverityUnknown 与原文
+func SplitIssue(issue string) (Severity, string) {
+	s, _, text := parseIssue(issue)
+	return s, text
+}
+
+// IssueConfidence 返回问题标注中的置信度 (0.0-1.0)，未标注时返回 0
+func IssueConfidence(issue string) float64 {
+	_, confidence, _ := parseIssue(issue)
+	return confidence
+}
+
+// parseIssue 解析问题开头的 [严重程度] 或 [严重程度|置信度] 标注
+func parseIssue(issue string) (Severity, float64, string) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(issue), "[")
+	if !ok {
+		return SeverityUnknown, 0, issue
+	}
+	tag, text, ok := strings.Cut(rest, "]")
+	if !ok {
+		return SeverityUnknown, 0, issue
+	}
+	tag, conf, _ := strings.Cut(tag, "|")
+	s, err := ParseSeverity(tag)
+	if err != nil || s == SeverityUnknown {
+		return SeverityUnknown, 0, issue
+	}
+	confidence, err := strconv.ParseFloat(strings.TrimSpace(conf), 64)
+	if err != nil {
+		confidence = 0
+	}
+	return s, normalizeConfidence(confidence), strings.TrimSpace(text)
+}
+
+// normalizeConfidence 将置信度规范化到 0.0-1.0，兼容模型以百分数给出的写法（如 85），无效值视为未标注
+func normalizeConfidence(confidence float64) float64 {
+	if confidence > 1 && confidence <= 100 {
+		confidence /= 100
+	}
+	if confidence <= 0 || confidence > 1 || math.IsNaN(confidence) {
+		return 0
+	}
+	return math.Round(confidence*100) / 100
+}
+
+// FormatIssue 为问题文本加上严重程度标注，与 SplitIssue 互逆
+func FormatIssue(s Severity, text string) string {
+	return FormatIssueWithConfidence(s, 0, text)
+}
+
+// FormatIssueWithConfidence 为问题文本加上严重程度与置信度标注（如 [major|0.8]），
+// 置信度为 0 时只标注严重程度；未标注严重程度但有置信度时按 major 标注
+func FormatIssueWithConfidence(s Severity, confidence float64, text string) string {
+	confidence = normalizeConfidence(confidence)
+	if confidence == 0 {
+		if s == SeverityUnknown {
+			return text
+		}
+		return "[" + s.String() + "] " + text
+	}
+	if s == SeverityUnknown {
+		s = SeverityMajor
+	}
+	return "[" + s.String() + "|" + strconv.FormatFloat(confidence, 'f', -1, 64) + "] " + text
+}
+
+// FilterIssues 返回严重程度不低于 minimum 的问题，未标注的问题按 major 处理
+func FilterIssues(issues []string, minimum Severity) []string {
+	if minimum <= SeverityMinor {
+		return issues
+	}
+	var kept []string
+	for _, issue := range issues {
+		s, _ := SplitIssue(issue)
+		if s == SeverityUnknown {
+			s = SeverityMajor
+		}
+		if s >= minimum {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// FilterConfidentIssues 返回置信度不低于 minimum 的问题，未标注置信度的问题总是保留
+func FilterConfidentIssues(issues []string, minimum float64) []string {
+	if minimum <= 0 {
+		return issues
+	}
+	var kept []string
+	for _, issue := range issues {
+		if c := IssueConfidence(issue); c == 0 || c >= minimum {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// issueLineRegex 匹配问题文本中引用的行号：第 N 行（含 第 N-M 行 等范围写法）、line N、L42
+var issueLineRegex = regexp.MustCompile(`(?i)第\s*(\d+)\s*(?:[-~～至到]\s*\d+\s*)?行|\blines?\s+(\d+)|\bL(\d+)\b`)
+
+// IssueLine 返回问题文本中第一个引用的行号，未引用行号时返回 0
+func IssueLine(issue string) int {
+	m := issueLineRegex.FindStringSubmatch(issue)
+	for _, group := range m[min(len(m), 1):] {
+		if n, err := strconv.Atoi(group); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package llm
+
+import "context"
+
+// 执行摘要提示词：根据整个运行的汇总数据生成摘要与行动项
+const summaryPrompt = `你是一位技术负责人。下面是一次代码审查的汇总数据（综合评分、问题统计、得分最低的文件与主要问题），请为管理者与开发团队写一份执行摘要，并给出按优先级排列的行动项。
+要求：
+1. 摘要 5-10 句话：整体质量、最主要的风险、问题集中的模块或文件、值得肯定的方面；不要逐条复述问题列表。
+2. 行动项 3-7 条，按优先级从高到低排列：先处理严重的安全与正确性问题，再处理影响面广或重复出现的问题；每条写明要做什么、涉及哪些文件。
+3. 只根据给定的数据作答，不要编造未出现的文件或问题。
+你的输出必须是一个严格的 JSON 对象，不要包含任何 Markdown 格式。
+请使用中文回答。
+
+格式：
+{
+  "summary": "<5-10 句话的执行摘要>",
+  "actions": [
+    {
+      "title": "<行动项>",
+      "reason": "<为什么优先处理，涉及的文件>"
+    }
+  ]
+}`
+
+// ActionItem 是执行摘要中的行动项
+type ActionItem struct {
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// ExecutiveSummary 表示整个运行的执行摘要
+type ExecutiveSummary struct {
+	Summary string       `json:"summary"`
+	Actions []ActionItem `json:"actions"` // 按优先级从高到低排列
+}
+
+// SummarizeReview 根据审查结果的汇总数据生成执行摘要与行动项
+func (c *Client) SummarizeReview(ctx context.Context, digest string) (*ExecutiveSummary, error) {
+	raw, err := c.chat(ctx, summaryPrompt, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ExecutiveSummary
+	if err := decodeJSON(raw, &result, "summary", "actions"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// TestSuggestionMaxContent 是询问测试重点时发送的最大代码长度（字节）
+const TestSuggestionMaxContent = 32 * 1024
+
+// 测试重点提示词：找出文件中最需要补充测试的函数
+const testSuggestionPrompt = `你是一位测试专家。给定的文件目前没有任何测试，请找出其中最需要补充单元测试的函数或方法（最多 5 个），按优先级从高到低排列。
+优先考虑：分支与边界条件多的逻辑、错误处理路径、解析与转换、并发与状态变更、被广泛调用的核心函数；忽略简单的 getter/setter 与纯转发。
+你的输出必须是一个严格的 JSON 对象，不要包含任何 Markdown 格式。
+请使用中文回答。
+
+格式：
+{
+  "functions": [
+    {
+      "name": "<函数或方法名，方法写作 Type.Method>",
+      "reason": "<一句话说明为什么需要测试，以及应覆盖的关键场景>"
+    }
+  ]
+}`
+
+// TestTarget 是模型建议优先测试的函数
+type TestTarget struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// TestSuggestionResult 表示测试重点建议
+type TestSuggestionResult struct {
+	Functions []TestTarget `json:"functions"`
+}
+
+// SuggestTests 询问模型没有测试的文件中最需要补充测试的函数
+func (c *Client) SuggestTests(ctx context.Context, req ReviewRequest) (*TestSuggestionResult, error) {
+	content := req.Content
+	if len(content) > TestSuggestionMaxContent {
+		content = content[:TestSuggestionMaxContent] + "\n... (已截断)"
+	}
+	raw, err := c.chat(ctx, testSuggestionPrompt, fmt.Sprintf("File: %s\n\nCode:\n%s", req.FilePath, content))
+	if err != nil {
+		return nil, err
+	}
+
+	var result TestSuggestionResult
+	if err := decodeJSON(raw, &result, "functions"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"cmp"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSFiles 是访问企业内部网关所需的证书文件，均为 PEM 格式
+type TLSFiles struct {
+	CAFile   string // 额外信任的 CA 证书（可包含多个），与系统根证书一起使用
+	CertFile string // 双向 TLS (mTLS) 的客户端证书，可以同时包含私钥
+	KeyFile  string // 客户端证书的私钥，为空时从 CertFile 中读取
+}
+
+// Empty 判断是否没有配置任何证书文件
+func (f TLSFiles) Empty() bool {
+	return f.CAFile == "" && f.CertFile == "" && f.KeyFile == ""
+}
+
+// LoadTLSConfig 读取证书文件并生成 TLS 配置，没有配置证书文件时返回 nil
+func LoadTLSConfig(files TLSFiles) (*tls.Config, error) {
+	if files.Empty() {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if files.CAFile != "" {
+		data, err := os.ReadFile(files.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+		}
+		// 系统根证书不可用时（如部分精简容器镜像）只信任配置的 CA
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("CA 证书 %s 中没有 PEM 格式的证书", files.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if files.CertFile == "" {
+		if files.KeyFile != "" {
+			return nil, errors.New("设置了客户端私钥但没有设置客户端证书")
+		}
+		return cfg, nil
+	}
+	cert, err := tls.LoadX509KeyPair(files.CertFile, cmp.Or(files.KeyFile, files.CertFile))
+	if err != nil {
+		if files.KeyFile == "" {
+			return nil, fmt.Errorf("读取客户端证书失败 (私钥不在证书文件中时需单独指定私钥文件): %w", err)
+		}
+		return nil, fmt.Errorf("读取客户端证书失败: %w", err)
+	}
+	cfg.Certificates = []tls.Certificate{cert}
+	return cfg, nil
+}
+
+// WithTLSConfig 使用自定义 TLS 配置访问接口（私有 CA、双向 TLS），为空时使用默认配置
+// 代理等其他设置与默认的 HTTP 客户端相同
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		if cfg == nil {
+			return
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = cfg
+		c.httpClient = &http.Client{Transport: transport}
+	}
+}
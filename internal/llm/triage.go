@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// TriageMaxContent 是初筛时发送的最大代码长度（字节），初筛只需要粗略判断
+const TriageMaxContent = 8 * 1024
+
+// 初筛提示词：只评估重要性与风险，不做详细审查
+const triagePrompt = `你是一位代码审查调度员。请快速浏览给定代码（可能被截断），粗略评估它在项目中的重要性以及存在缺陷或安全问题的风险，用于决定是否需要专家深度审查。
+你的输出必须是一个严格的 JSON 对象，不要包含任何 Markdown 格式。
+请使用中文回答。
+
+格式：
+{
+  "importance": <0.0-1.0 的浮点数，核心业务逻辑/入口=0.9~1.0，辅助工具=0.5，配置文件/简单模型=0.3>,
+  "risk": <0.0-1.0 的浮点数，表示存在缺陷或安全问题的可能性>,
+  "reason": "<一句话说明判断依据>"
+}`
+
+// TriageResult 表示初筛结果
+type TriageResult struct {
+	Importance float64 `json:"importance"` // 重要性 (0.0-1.0)
+	Risk       float64 `json:"risk"`       // 风险 (0.0-1.0)
+	Reason     string  `json:"reason"`     // 判断依据
+}
+
+// Priority 返回初筛优先级（重要性与风险的乘积）
+func (t *TriageResult) Priority() float64 {
+	return t.Importance * t.Risk
+}
+
+// TriageCode 使用廉价模型快速评估文件的重要性与风险
+func (c *Client) TriageCode(ctx context.Context, req ReviewRequest) (*TriageResult, error) {
+	content := req.Content
+	if len(content) > TriageMaxContent {
+		content = content[:TriageMaxContent] + "\n... (已截断)"
+	}
+
+	raw, err := c.chat(ctx, triagePrompt, fmt.Sprintf("File: %s\n\nCode:\n%s", req.FilePath, content))
+	if err != nil {
+		return nil, err
+	}
+
+	var result TriageResult
+	if err := decodeJSON(raw, &result, "importance", "risk"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
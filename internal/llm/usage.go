@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"sync/atomic"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Usage 累计 Token 消耗，可在多个客户端（初筛与深度审查）之间共享，并发安全
+type Usage struct {
+	prompt     atomic.Int64
+	completion atomic.Int64
+	parent     *Usage // 同时累加到上级统计（例如整个运行的总消耗）
+}
+
+// NewUsage 创建 Token 统计，parent 非空时消耗会同时累加到 parent
+func NewUsage(parent *Usage) *Usage {
+	return &Usage{parent: parent}
+}
+
+// add 累加一次请求的 Token 消耗
+func (u *Usage) add(usage openai.Usage) {
+	u.prompt.Add(int64(usage.PromptTokens))
+	u.completion.Add(int64(usage.CompletionTokens))
+	if u.parent != nil {
+		u.parent.add(usage)
+	}
+}
+
+// PromptTokens 返回累计的输入 Token 数
+func (u *Usage) PromptTokens() int64 {
+	return u.prompt.Load()
+}
+
+// CompletionTokens 返回累计的输出 Token 数
+func (u *Usage) CompletionTokens() int64 {
+	return u.completion.Load()
+}
+
+// Total 返回累计的 Token 总数
+func (u *Usage) Total() int64 {
+	return u.PromptTokens() + u.CompletionTokens()
+}
+
+// ClientOption 定义 Client 的配置选项
+type ClientOption func(*Client)
+
+// WithUsage 将 Token 消耗累计到共享的 Usage 中
+func WithUsage(u *Usage) ClientOption {
+	return func(c *Client) {
+		if u != nil {
+			c.usage = u
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package logging 基于 log/slog 提供分级日志：终端输出简洁的提示，日志文件记录完整的诊断信息
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Options 是日志配置
+type Options struct {
+	Verbosity int    // 0 只输出警告与错误，1 增加信息，2 及以上增加调试信息
+	Quiet     bool   // 只输出错误，优先于 Verbosity
+	File      string // 日志文件路径（追加写入），记录全部级别的日志，留空表示不写文件
+}
+
+// consoleLevel 返回终端输出的最低级别
+func (o Options) consoleLevel() slog.Level {
+	switch {
+	case o.Quiet:
+		return slog.LevelError
+	case o.Verbosity >= 2:
+		return slog.LevelDebug
+	case o.Verbosity == 1:
+		return slog.LevelInfo
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// console 是终端输出，TUI 运行期间暂存日志，退出后再输出，避免破坏界面
+var console = &pausableWriter{out: os.Stderr}
+
+// Setup 按配置初始化默认 logger
+// 日志文件在进程生命周期内保持打开；打开失败时仍然启用终端输出并返回错误
+func Setup(opts Options) error {
+	handlers := fanoutHandler{newConsoleHandler(console, opts.consoleLevel())}
+	defer func() { slog.SetDefault(slog.New(handlers)) }()
+
+	if opts.File == "" {
+		return nil
+	}
+	f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	handlers = append(handlers, slog.NewTextHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return nil
+}
+
+// Pause 暂停终端日志输出（TUI 启动前调用），期间的日志暂存在内存中
+func Pause() {
+	console.pause()
+}
+
+// Resume 恢复终端日志输出（TUI 退出后调用），并输出暂停期间暂存的日志
+func Resume() {
+	console.resume()
+}
+
+// pausableWriter 是可暂停的输出，暂停期间写入的内容在恢复时一并输出
+type pausableWriter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	paused bool
+	buf    bytes.Buffer
+}
+
+func (w *pausableWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.paused {
+		return w.buf.Write(p)
+	}
+	return w.out.Write(p)
+}
+
+func (w *pausableWriter) pause() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused = true
+}
+
+func (w *pausableWriter) resume() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused = false
+	if w.buf.Len() > 0 {
+		w.out.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// levelIcons 是终端输出中各级别的前缀
+var levelIcons = map[slog.Level]string{
+	slog.LevelDebug: "🔍",
+	slog.LevelInfo:  "ℹ️",
+	slog.LevelWarn:  "⚠️",
+	slog.LevelError: "❌",
+}
+
+// consoleHandler 以 "图标 消息: 错误 (key=value ...)" 的简洁格式输出到终端
+type consoleHandler struct {
+	w     io.Writer
+	level slog.Level
+	attrs []slog.Attr
+}
+
+func newConsoleHandler(w io.Writer, level slog.Level) *consoleHandler {
+	return &consoleHandler{w: w, level: level}
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var errText string
+	var fields []string
+	add := func(a slog.Attr) bool {
+		if a.Key == "err" {
+			errText = a.Value.String()
+		} else if a.Key != "" {
+			fields = append(fields, a.Key+"="+a.Value.String())
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(add)
+
+	var b strings.Builder
+	b.WriteString(levelIcons[r.Level])
+	b.WriteString(" ")
+	b.WriteString(r.Message)
+	if errText != "" {
+		b.WriteString(": ")
+		b.WriteString(errText)
+	}
+	if len(fields) > 0 {
+		b.WriteString(" (" + strings.Join(fields, ", ") + ")")
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup 终端输出不区分分组
+func (h *consoleHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// fanoutHandler 将日志分发给多个 handler
+type fanoutHandler []slog.Handler
+
+func (f fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanoutHandler) WithGroup(name string) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
@@ -0,0 +1,344 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taskNameStyle 是批量界面中任务名称的样式
+var taskNameStyle = lipgloss.NewStyle().Bold(true)
+
+// batchRecentShown 是批量界面的任务详情中显示的最近完成文件数
+const batchRecentShown = 5
+
+// TaskStartMsg 表示某个批量任务完成扫描并开始审查
+type TaskStartMsg struct {
+	Task  int // 任务序号
+	Total int // 待审查文件数
+}
+
+// TaskProgressMsg 表示某个批量任务完成了一个文件
+type TaskProgressMsg struct {
+	Task int
+	FileResultMsg
+}
+
+// TaskDoneMsg 表示某个批量任务已结束
+type TaskDoneMsg struct {
+	Task        int
+	ReportPath  string
+	Duration    time.Duration
+	IssuesCount int
+	Err         error
+	Note        string // 无需审查等附加说明
+}
+
+// taskState 是单个批量任务的显示状态
+type taskState struct {
+	name        string
+	progress    progress.Model
+	started     bool
+	total       int
+	completed   int
+	currentFile string
+	recent      []FileResultMsg // 最近完成的文件，在当前任务详情中显示
+	failures    []FileResultMsg // 审查失败的文件
+	scoreSum    int             // 已审查文件的得分之和，用于计算均分
+	reviewed    int             // 已审查（有得分）的文件数
+	done        TaskDoneMsg
+	finished    bool
+}
+
+// running 判断任务是否正在审查
+func (t taskState) running() bool {
+	return t.started && !t.finished
+}
+
+// BatchModel 是批量任务的汇总 TUI 模型：上方为任务总览（每个任务一行），下方为当前任务的详情
+type BatchModel struct {
+	spinner     spinner.Model
+	tasks       []taskState
+	finished    int
+	active      int             // 显示详情的任务序号
+	pinned      bool            // 用户用 ↑/↓ 选择了任务，不再自动跟随正在运行的任务
+	hideErrors  bool            // 详情中的错误面板是否已折叠（按 e 切换）
+	reviewed    []FileResultMsg // 全部任务已审查的文件，全部完成后显示得分分布
+	usage       UsageMsg        // 全部任务累计的 Token 消耗与费用
+	counters    runCounters     // 全部任务的重试、失败、跳过与复用的文件数
+	control     Control         // 暂停与并发调整（所有任务共享），为空时不响应对应按键
+	width       int             // 终端宽度，0 表示尚未收到窗口大小
+	confirming  bool            // 用户按下 q 或 Ctrl+C，正在等待确认中断
+	interrupted bool            // 用户确认中断审查
+	showHelp    bool            // 正在显示按键说明（按 ? 打开，任意键关闭）
+	pausedUntil time.Time       // 配额耗尽暂停的恢复时间，零值表示未暂停
+}
+
+// NewBatchModel 创建批量任务模型，names 为各任务的显示名称，control 为空时不支持暂停与调整并发
+func NewBatchModel(names []string, control Control) BatchModel {
+	tasks := make([]taskState, len(names))
+	for i, name := range names {
+		tasks[i] = taskState{
+			name:     name,
+			progress: newProgress(ProgressBarWidth / 2),
+		}
+	}
+
+	return BatchModel{spinner: newSpinner(), tasks: tasks, control: control}
+}
+
+// Init 实现 tea.Model 接口，返回初始命令
+func (m BatchModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// Update 实现 tea.Model 接口，处理消息并更新状态
+func (m BatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// 按键说明打开时任意键关闭；Ctrl+C 关闭后继续进入中断确认
+		if m.showHelp {
+			m.showHelp = false
+			if msg.Type != tea.KeyCtrlC {
+				return m, nil
+			}
+		}
+		// 中断需要确认：确认后停止所有任务并生成部分报告
+		if m.confirming {
+			m.confirming = false
+			if confirmQuit(msg) {
+				m.interrupted = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		switch key := msg.String(); {
+		case isQuitKey(msg):
+			m.confirming = true
+		case isHelpKey(msg):
+			m.showHelp = true
+		case handleControlKey(m.control, key):
+		case key == "up" || key == "k":
+			m.active, m.pinned = max(m.active-1, 0), true
+		case key == "down" || key == "j":
+			m.active, m.pinned = min(m.active+1, len(m.tasks)-1), true
+		case key == "e":
+			m.hideErrors = !m.hideErrors
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		if msg.Width <= 0 {
+			return m, nil
+		}
+		m.width = msg.Width
+		for i := range m.tasks {
+			m.tasks[i].progress.Width = progressWidth(msg.Width, ProgressBarWidth/2)
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case PausedMsg:
+		m.pausedUntil = msg.Until
+		return m, nil
+
+	case ResumedMsg:
+		m.pausedUntil = time.Time{}
+		return m, nil
+
+	case UsageMsg:
+		m.usage = msg
+		return m, nil
+
+	case RetryMsg:
+		m.counters.retries++
+		return m, nil
+
+	case TaskStartMsg:
+		if t := m.task(msg.Task); t != nil {
+			t.started = true
+			t.total = msg.Total
+			m.follow(msg.Task)
+		}
+		return m, nil
+
+	case TaskProgressMsg:
+		if t := m.task(msg.Task); t != nil {
+			t.completed++
+			t.currentFile = msg.Path
+			t.recent = appendRecent(t.recent, msg.FileResultMsg)
+			m.counters.add(msg.FileResultMsg)
+			switch msg.Status {
+			case FileReviewed:
+				t.scoreSum += msg.Score
+				t.reviewed++
+				m.reviewed = append(m.reviewed, msg.FileResultMsg)
+			case FileFailed:
+				t.failures = append(t.failures, msg.FileResultMsg)
+			}
+			m.follow(msg.Task)
+		}
+		return m, nil
+
+	case TaskDoneMsg:
+		if t := m.task(msg.Task); t != nil && !t.finished {
+			t.finished = true
+			t.done = msg
+			m.finished++
+		}
+		if m.finished == len(m.tasks) {
+			return m, tea.Quit
+		}
+		return m, nil
+
+	default:
+		return m, nil
+	}
+}
+
+// follow 在用户未手动选择任务、且当前任务不在运行时，将详情切换到有进展的任务 i
+func (m *BatchModel) follow(i int) {
+	if !m.pinned && !m.tasks[m.active].running() {
+		m.active = i
+	}
+}
+
+// task 返回指定序号的任务状态，序号越界时返回 nil
+func (m *BatchModel) task(i int) *taskState {
+	if i < 0 || i >= len(m.tasks) {
+		return nil
+	}
+	return &m.tasks[i]
+}
+
+// Interrupted 返回用户是否在全部任务完成前中断
+func (m BatchModel) Interrupted() bool {
+	return m.interrupted && m.finished < len(m.tasks)
+}
+
+// View 实现 tea.Model 接口，渲染界面
+func (m BatchModel) View() string {
+	if m.showHelp && m.finished < len(m.tasks) {
+		return helpView("批量任务界面按键", batchKeys, m.width)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n 批量任务: %d/%d 已完成  %s\n\n", m.finished, len(m.tasks), m.counters.line()))
+
+	for i, t := range m.tasks {
+		cursor := "  "
+		if i == m.active && len(m.tasks) > 1 {
+			cursor = selectCursorStyle.Render("› ")
+		}
+		b.WriteString(cursor + taskNameStyle.Render(t.name) + "\n")
+		b.WriteString("    " + t.statusLine(m.spinner.View(), viewWidth(m.width)-4) + "\n")
+	}
+	if m.finished < len(m.tasks) {
+		b.WriteString(m.details())
+	} else if summary := scoreSummary(m.reviewed, viewWidth(m.width)-2); summary != "" {
+		// 全部任务完成：以全部任务的得分概览代替单个任务的详情
+		b.WriteString("\n" + indentLines(summary, " "))
+	}
+
+	completed, total := m.fileCounts()
+	if line := usageLine(m.usage, completed, total); line != "" {
+		b.WriteString("\n " + line)
+	}
+	if line := pausedLine(m.pausedUntil); line != "" {
+		b.WriteString("\n " + line + "\n")
+	}
+	if m.finished < len(m.tasks) {
+		if m.confirming {
+			b.WriteString("\n " + confirmLine() + "\n")
+		} else if line := controlLine(m.control); line != "" {
+			b.WriteString("\n " + line + "\n")
+		}
+		b.WriteString(taskMutedStyle.Render("\n ↑/↓ 切换任务详情 · ? 按键说明 · q 或 Ctrl+C 中断并生成部分报告") + "\n")
+	}
+	// 按终端宽度折行，避免过长的任务名与提示在窄终端上破坏布局
+	return lipgloss.NewStyle().Width(viewWidth(m.width)).Render(b.String())
+}
+
+// details 渲染当前任务的详情：最近完成的文件与失败文件面板
+func (m BatchModel) details() string {
+	t := m.tasks[m.active]
+	if len(t.recent) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + taskMutedStyle.Render(" ── 任务详情: ") + taskNameStyle.Render(t.name) + taskMutedStyle.Render(" ──") + "\n")
+	b.WriteString(recentList(t.recent[max(len(t.recent)-batchRecentShown, 0):], t.completed))
+	if panel := errorPanel(t.failures, m.hideErrors); panel != "" {
+		b.WriteString(panel)
+	}
+	return b.String()
+}
+
+// indentLines 为多行文本的每个非空行添加前缀
+func indentLines(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fileCounts 返回全部任务已完成与待审查的文件数；仍有任务未开始时总数未知，返回 0
+func (m BatchModel) fileCounts() (completed, total int) {
+	known := true
+	for _, t := range m.tasks {
+		completed += t.completed
+		total += t.total
+		known = known && (t.started || t.finished)
+	}
+	if !known {
+		total = 0
+	}
+	return completed, total
+}
+
+// statusLine 渲染单个任务的状态行，当前文件名超出 width 时截断
+func (t taskState) statusLine(spin string, width int) string {
+	switch {
+	case t.finished && t.done.Err != nil:
+		return taskErrorStyle.Render("❌ " + t.done.Err.Error())
+	case t.finished && t.done.Note != "":
+		return "🎉 " + t.done.Note
+	case t.finished:
+		return fmt.Sprintf("✨ 完成，耗时 %s，发现问题 %d 个%s",
+			t.done.Duration.Round(time.Millisecond), t.done.IssuesCount, t.scoreText("，"))
+	case !t.started:
+		return taskMutedStyle.Render(spin + " 等待中...")
+	}
+
+	pct := 0.0
+	if t.total > 0 {
+		pct = float64(t.completed) / float64(t.total)
+	}
+	line := fmt.Sprintf("%s %s %d/%d%s", spin, t.progress.ViewAs(pct), t.completed, t.total, t.scoreText(" "))
+	if t.currentFile != "" {
+		line += " " + currentFileStyle.Render(filepath.Base(t.currentFile))
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
+
+// scoreText 返回已审查文件的均分，以 sep 开头；尚无得分时返回空字符串
+func (t taskState) scoreText(sep string) string {
+	if t.reviewed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s均分 %d", sep, t.scoreSum/t.reviewed)
+}
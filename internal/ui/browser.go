@@ -0,0 +1,376 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"go-ai-reviewer/internal/llm"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// 终端高度未知时结果浏览界面的默认行数
+const defaultBrowserRows = 20
+
+// browserFilters 是按 s 键循环切换的问题过滤条件（minor 及以上等同于不过滤）
+var browserFilters = []llm.Severity{llm.SeverityUnknown, llm.SeverityMajor, llm.SeverityCritical}
+
+// EditorFunc 返回在编辑器中打开 path 第 line 行的命令，line 为 0 表示不定位到具体行
+type EditorFunc func(path string, line int) (*exec.Cmd, error)
+
+// editorDoneMsg 表示编辑器已退出
+type editorDoneMsg struct {
+	err error
+}
+
+// BrowseFile 是结果浏览界面中的一个文件
+type BrowseFile struct {
+	FileResultMsg
+	Summary string   // 一句话总结，仅已审查的文件有效
+	Issues  []string // 问题列表（带严重程度标注），按界面中的过滤条件显示
+}
+
+// BrowserModel 是审查完成后浏览结果的界面：按文件列出得分，展开查看问题，按严重程度过滤
+type BrowserModel struct {
+	files      []BrowseFile
+	expanded   []bool
+	reportPath string
+	filter     llm.Severity // 只显示不低于该严重程度的问题
+
+	editor EditorFunc // 为空时不支持在编辑器中打开文件
+	status string     // 打开编辑器失败等提示
+
+	cursor     int
+	issue      int // 光标所在的问题（过滤后的序号），-1 表示光标在文件行上
+	offset     int // 渲染行的滚动偏移
+	width      int // 终端宽度，0 表示未知
+	height     int // 终端高度，0 表示未知
+	openReport bool
+	showHelp   bool // 正在显示按键说明（按 ? 打开，任意键关闭）
+}
+
+// NewBrowserModel 创建结果浏览模型：失败的文件排在最前，其余已审查文件按得分从低到高排列
+// minSeverity 为初始的问题过滤条件，通常与报告的 min_severity 一致；editor 为空时不支持打开编辑器
+func NewBrowserModel(files []BrowseFile, reportPath string, minSeverity llm.Severity, editor EditorFunc) BrowserModel {
+	sorted := slices.Clone(files)
+	slices.SortStableFunc(sorted, func(a, b BrowseFile) int {
+		if c := cmp.Compare(browseRank(a), browseRank(b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Score, b.Score)
+	})
+
+	filter := llm.SeverityUnknown
+	if minSeverity > llm.SeverityMinor {
+		filter = minSeverity
+	}
+	return BrowserModel{
+		files:      sorted,
+		expanded:   make([]bool, len(sorted)),
+		reportPath: reportPath,
+		filter:     filter,
+		editor:     editor,
+		issue:      -1,
+	}
+}
+
+// browseRank 返回文件在列表中的分组顺序：失败、已审查、其余（初筛、跳过）
+func browseRank(f BrowseFile) int {
+	switch f.Status {
+	case FileFailed:
+		return 0
+	case FileReviewed:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Init 实现 tea.Model 接口
+func (m BrowserModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update 实现 tea.Model 接口，处理按键并更新浏览状态
+func (m BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+		return m, nil
+
+	case editorDoneMsg:
+		m.status = ""
+		if msg.err != nil {
+			m.status = "编辑器运行失败: " + msg.err.Error()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		m.status = ""
+		// 按键说明打开时任意键关闭
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		var cmd tea.Cmd
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "o":
+			m.openReport = true
+			return m, tea.Quit
+		case "?":
+			m.showHelp = true
+		case "up", "k":
+			m.moveUp()
+		case "down", "j":
+			m.moveDown()
+		case "enter", " ":
+			switch {
+			case len(m.files) == 0:
+			case m.issue >= 0:
+				cmd = m.openEditor()
+			default:
+				m.expanded[m.cursor] = !m.expanded[m.cursor]
+			}
+		case "e":
+			if len(m.files) > 0 {
+				cmd = m.openEditor()
+			}
+		case "right", "l":
+			if len(m.files) > 0 {
+				m.expanded[m.cursor] = true
+			}
+		case "left", "h":
+			if len(m.files) > 0 {
+				m.expanded[m.cursor] = false
+				m.issue = -1
+			}
+		case "s":
+			i := slices.Index(browserFilters, m.filter)
+			m.filter = browserFilters[(i+1)%len(browserFilters)]
+			if len(m.files) > 0 {
+				m.issue = min(m.issue, len(m.issues(m.cursor))-1)
+			}
+		}
+		m.scroll()
+		return m, cmd
+	}
+	return m, nil
+}
+
+// issues 返回第 i 个文件按当前过滤条件显示的问题
+func (m BrowserModel) issues(i int) []string {
+	return llm.FilterIssues(m.files[i].Issues, m.filter)
+}
+
+// moveDown 将光标移到下一行：展开的文件先逐条经过其问题，再到下一个文件
+func (m *BrowserModel) moveDown() {
+	if len(m.files) == 0 {
+		return
+	}
+	if m.expanded[m.cursor] && m.issue < len(m.issues(m.cursor))-1 {
+		m.issue++
+		return
+	}
+	if m.cursor < len(m.files)-1 {
+		m.cursor, m.issue = m.cursor+1, -1
+	}
+}
+
+// moveUp 将光标移到上一行：上一个文件已展开时停在它的最后一条问题上
+func (m *BrowserModel) moveUp() {
+	switch {
+	case m.issue >= 0:
+		m.issue--
+	case m.cursor > 0:
+		m.cursor--
+		m.issue = -1
+		if m.expanded[m.cursor] {
+			m.issue = len(m.issues(m.cursor)) - 1
+		}
+	}
+}
+
+// openEditor 暂停界面并在编辑器中打开光标所在的文件，光标在问题上时定位到问题引用的行号
+func (m *BrowserModel) openEditor() tea.Cmd {
+	if m.editor == nil {
+		return nil
+	}
+	line := 0
+	if m.issue >= 0 {
+		line = llm.IssueLine(m.issues(m.cursor)[m.issue])
+	}
+	c, err := m.editor(m.files[m.cursor].Path, line)
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorDoneMsg{err: err}
+	})
+}
+
+// OpenReport 返回用户是否选择退出后打开报告文件
+func (m BrowserModel) OpenReport() bool {
+	return m.openReport
+}
+
+// pageSize 返回列表区域可显示的行数
+func (m BrowserModel) pageSize() int {
+	if m.height <= 0 {
+		return defaultBrowserRows
+	}
+	return max(m.height-7, 1)
+}
+
+// scroll 调整滚动偏移，保证光标所在文件（含展开的问题）尽量完整可见
+func (m *BrowserModel) scroll() {
+	_, start, end := m.lines()
+	page := m.pageSize()
+	if end >= m.offset+page {
+		m.offset = end - page + 1
+	}
+	if start < m.offset {
+		m.offset = start
+	}
+}
+
+// lines 渲染全部文件行，返回各行以及光标所在文件的起止行号
+func (m BrowserModel) lines() (lines []string, start, end int) {
+	width := viewWidth(m.width)
+	for i, f := range m.files {
+		if i == m.cursor {
+			start = len(lines)
+		}
+		lines = append(lines, m.fileLine(i, f, width))
+
+		selected := -1
+		if i == m.cursor {
+			selected = m.issue
+		}
+		var details []string
+		var from, to int
+		if m.expanded[i] {
+			details, from, to = m.details(f, width, selected)
+		}
+		switch {
+		case i == m.cursor && selected >= 0:
+			// 光标在问题上时只需保证该问题可见
+			start, end = len(lines)+from, len(lines)+to
+		case i == m.cursor:
+			end = len(lines) + len(details) - 1
+		}
+		lines = append(lines, details...)
+	}
+	return lines, start, end
+}
+
+// fileLine 渲染文件行：光标、展开标记、得分与过滤后的问题数
+func (m BrowserModel) fileLine(i int, f BrowseFile, width int) string {
+	cursor := "  "
+	if i == m.cursor && m.issue < 0 {
+		cursor = selectCursorStyle.Render("› ")
+	}
+	arrow := "▸ "
+	if m.expanded[i] {
+		arrow = "▾ "
+	}
+	r := f.FileResultMsg
+	r.Issues = len(llm.FilterIssues(f.Issues, m.filter))
+	return lipgloss.NewStyle().MaxWidth(width).Render(cursor + arrow + r.line())
+}
+
+// details 渲染展开后的总结与问题列表，长文本按终端宽度折行并缩进
+// selected 为光标所在问题的序号（-1 表示无），返回各行以及该问题的起止行号
+func (m BrowserModel) details(f BrowseFile, width, selected int) (out []string, from, to int) {
+	const indent = "      "
+	wrap := lipgloss.NewStyle().Width(max(width-len(indent), 20))
+
+	add := func(text string, marked bool) {
+		for i, line := range strings.Split(wrap.Render(text), "\n") {
+			prefix := indent
+			if marked && i == 0 {
+				prefix = "    " + selectCursorStyle.Render("› ")
+			}
+			out = append(out, prefix+strings.TrimRight(line, " "))
+		}
+	}
+
+	if f.Status != FileReviewed {
+		add(fileNoteStyle.Render(f.Note), false)
+		return out, 0, 0
+	}
+	if f.Summary != "" {
+		add(fileNoteStyle.Render(f.Summary), false)
+	}
+	issues := llm.FilterIssues(f.Issues, m.filter)
+	if len(issues) == 0 {
+		add(fileNoteStyle.Render("（没有符合过滤条件的问题）"), false)
+	}
+	for i, issue := range issues {
+		severity, text := llm.SplitIssue(issue)
+		if confidence := llm.IssueConfidence(issue); confidence > 0 {
+			text += fmt.Sprintf(" (置信度 %.2g)", confidence)
+		}
+		label := severity.Label()
+		if label == "" {
+			label = "•"
+		}
+		if i == selected {
+			from = len(out)
+		}
+		add(label+" "+text, i == selected)
+		if i == selected {
+			to = len(out) - 1
+		}
+	}
+	return out, from, to
+}
+
+// filterName 返回当前过滤条件的显示名称
+func (m BrowserModel) filterName() string {
+	switch m.filter {
+	case llm.SeverityMajor:
+		return "重要及以上"
+	case llm.SeverityCritical:
+		return "仅严重"
+	default:
+		return "全部"
+	}
+}
+
+// View 实现 tea.Model 接口，渲染界面
+func (m BrowserModel) View() string {
+	if m.showHelp {
+		return helpView("结果浏览界面按键", browserKeys(m.editor != nil), m.width)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n 审查结果: %d 个文件 · 问题过滤: %s\n", len(m.files), m.filterName()))
+	b.WriteString(taskMutedStyle.Render(" 📄 报告: "+m.reportPath) + "\n\n")
+
+	lines, _, _ := m.lines()
+	end := min(m.offset+m.pageSize(), len(lines))
+	for _, line := range lines[min(m.offset, end):end] {
+		b.WriteString(line + "\n")
+	}
+	if len(m.files) == 0 {
+		b.WriteString(fileNoteStyle.Render("  没有审查结果") + "\n")
+	}
+
+	if m.status != "" {
+		b.WriteString("\n " + errorStyle.Render(m.status) + "\n")
+	}
+	help := "\n ↑/↓ 移动  Enter 展开/收起  s 切换严重程度过滤  o 打开报告  ? 帮助  q 退出"
+	if m.editor != nil {
+		help = "\n ↑/↓ 移动  Enter 展开/收起（在问题上打开编辑器）  e 在编辑器中打开  s 切换过滤  o 打开报告  ? 帮助  q 退出"
+	}
+	b.WriteString(taskMutedStyle.Render(help) + "\n")
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Control 是进度界面调整运行状态的接口，由审查引擎的限流器实现
+type Control interface {
+	SetPaused(paused bool)
+	Paused() bool
+	SetLimit(n int) int
+	Limit() int
+	MaxLimit() int
+}
+
+// handleControlKey 处理暂停（p）与调整并发（+/-）的按键，返回按键是否已处理
+func handleControlKey(c Control, key string) bool {
+	if c == nil {
+		return false
+	}
+	switch key {
+	case "p":
+		c.SetPaused(!c.Paused())
+	case "+", "=":
+		c.SetLimit(c.Limit() + 1)
+	case "-", "_":
+		c.SetLimit(c.Limit() - 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// controlLine 渲染当前并发与暂停状态及对应按键提示，c 为空时返回空字符串
+func controlLine(c Control) string {
+	if c == nil {
+		return ""
+	}
+	if c.Paused() {
+		return pausedStyle.Render("⏸  已暂停派发新文件，进行中的请求会继续完成；按 p 恢复")
+	}
+	return fileNoteStyle.Render(fmt.Sprintf("⚙  并发 %d/%d · p 暂停 · +/- 调整并发", c.Limit(), c.MaxLimit()))
+}
+
+// isQuitKey 判断是否为中断审查的按键（q 或 Ctrl+C）
+func isQuitKey(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlC || msg.String() == "q"
+}
+
+// confirmQuit 处理中断确认提示下的按键：y 或再次按下 Ctrl+C 确认中断，其余按键取消
+func confirmQuit(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlC || msg.String() == "y" || msg.String() == "Y"
+}
+
+// confirmLine 渲染中断确认提示
+func confirmLine() string {
+	return pausedStyle.Render("⚠️  确认中断审查？进行中的请求将被取消，已完成的结果生成部分报告 (y 确认 / 其他键取消)")
+}
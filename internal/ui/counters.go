@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// runCounters 是进度条旁实时显示的运行状况计数，便于一眼判断审查是否顺利
+type runCounters struct {
+	retries int // 因暂时性错误进入重试队列的次数
+	failed  int // 审查失败的文件数
+	skipped int // 跳过的文件数（文件过大、预算耗尽、运行超时等）
+	reused  int // 内容重复、复用其他文件结果的文件数
+}
+
+// add 按文件结果更新计数
+func (c *runCounters) add(msg FileResultMsg) {
+	switch msg.Status {
+	case FileFailed:
+		c.failed++
+	case FileSkipped:
+		c.skipped++
+	}
+	if msg.Reused {
+		c.reused++
+	}
+}
+
+// line 渲染计数：重试与失败不为 0 时高亮，其余以弱化样式显示
+func (c runCounters) line() string {
+	parts := []string{
+		counterText("重试", c.retries, pausedStyle),
+		counterText("失败", c.failed, errorStyle),
+		counterText("跳过", c.skipped, fileNoteStyle),
+		counterText("复用", c.reused, fileNoteStyle),
+	}
+	return strings.Join(parts, fileNoteStyle.Render(" · "))
+}
+
+// counterText 渲染单个计数，n 大于 0 时使用 style，否则使用弱化样式
+func counterText(label string, n int, style lipgloss.Style) string {
+	text := fmt.Sprintf("%s %d", label, n)
+	if n == 0 {
+		return fileNoteStyle.Render(text)
+	}
+	return style.Render(text)
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpBoxStyle 是按键说明浮层的边框样式
+var helpBoxStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+
+// 按键说明浮层的最大宽度
+const helpMaxWidth = 72
+
+// keyHelp 是按键说明中的一行
+type keyHelp struct {
+	keys string
+	desc string
+}
+
+// progressKeys 是进度界面的按键说明
+var progressKeys = []keyHelp{
+	{"p", "暂停/恢复派发新文件（进行中的请求会继续完成）"},
+	{"+ / -", "增加/减少并发数（1 到 max_concurrency）"},
+	{"e", "展开/收起错误面板"},
+	{"q / Ctrl+C", "中断审查，按 y 确认后取消进行中的请求并生成部分报告"},
+	{"?", "显示/关闭按键说明"},
+}
+
+// batchKeys 是批量任务汇总界面的按键说明
+var batchKeys = append([]keyHelp{{"↑ / ↓", "切换查看的任务详情"}}, progressKeys...)
+
+// selectKeys 是文件选择界面的按键说明
+var selectKeys = []keyHelp{
+	{"↑ / ↓", "移动光标"},
+	{"PgUp / PgDn", "翻页"},
+	{"Home / End", "跳到开头/结尾"},
+	{"空格 / x", "选择/取消文件（在目录上时作用于整个目录）"},
+	{"a", "全选/全不选"},
+	{"← / →", "折叠/展开目录"},
+	{"Enter", "开始审查选中的文件"},
+	{"q / Esc", "取消审查"},
+	{"?", "显示/关闭按键说明"},
+}
+
+// browserKeys 返回结果浏览界面的按键说明，withEditor 为 false 时不列出编辑器相关按键
+func browserKeys(withEditor bool) []keyHelp {
+	keys := []keyHelp{{"↑ / ↓", "移动光标（展开的文件会逐条经过其问题）"}}
+	if withEditor {
+		keys = append(keys,
+			keyHelp{"Enter", "展开/收起文件；在问题上时在编辑器中打开并定位到问题所在的行"},
+			keyHelp{"e", "在编辑器中打开光标所在的文件"},
+		)
+	} else {
+		keys = append(keys, keyHelp{"Enter", "展开/收起文件"})
+	}
+	return append(keys,
+		keyHelp{"← / →", "收起/展开文件"},
+		keyHelp{"s", "切换问题过滤：全部 → 重要及以上 → 仅严重"},
+		keyHelp{"o", "退出并在浏览器中打开报告"},
+		keyHelp{"q / Esc", "退出"},
+		keyHelp{"?", "显示/关闭按键说明"},
+	)
+}
+
+// isHelpKey 判断是否为打开按键说明的按键
+func isHelpKey(msg tea.KeyMsg) bool {
+	return msg.String() == "?"
+}
+
+// helpView 渲染按键说明浮层，width 为终端宽度（0 表示未知）
+func helpView(title string, keys []keyHelp, width int) string {
+	keyWidth := 0
+	for _, k := range keys {
+		keyWidth = max(keyWidth, lipgloss.Width(k.keys))
+	}
+
+	var b strings.Builder
+	b.WriteString(taskNameStyle.Render(title) + "\n\n")
+	for _, k := range keys {
+		padding := strings.Repeat(" ", keyWidth-lipgloss.Width(k.keys))
+		b.WriteString(currentFileStyle.Render(k.keys) + padding + "  " + k.desc + "\n")
+	}
+	b.WriteString("\n" + fileNoteStyle.Render("按任意键关闭"))
+
+	box := helpBoxStyle.Width(max(min(viewWidth(width)-4, helpMaxWidth), 20)).Render(b.String())
+	return "\n " + strings.ReplaceAll(box, "\n", "\n ") + "\n"
+}
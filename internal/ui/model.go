@@ -14,18 +14,55 @@ import (
 
 // 常量定义
 const (
-	DefaultTerminalWidth = 80 // 默认终端宽度
-	ProgressBarWidth     = 40 // 进度条宽度
+	DefaultTerminalWidth = 80 // 默认终端宽度（尚未收到窗口大小时使用）
+	ProgressBarWidth     = 40 // 进度条宽度，终端较窄时自动缩短
+	minProgressBarWidth  = 10 // 进度条的最小宽度
+	RecentFilesShown     = 8  // 进度界面中显示的最近完成文件数
+	FailuresShown        = 5  // 错误面板展开时显示的最近失败文件数
 )
 
-// 样式定义
-var (
-	currentFileStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("211"))
-	doneStyle        = lipgloss.NewStyle().Margin(1, 2)
+// doneStyle 是完成界面的样式（颜色相关的样式见 theme.go）
+var doneStyle = lipgloss.NewStyle().Margin(1, 2)
+
+// FileStatus 是单个文件的处理结果
+type FileStatus int
+
+const (
+	FileReviewed FileStatus = iota // 已审查
+	FileFailed                     // 审查失败
+	FileSkipped                    // 未审查（文件过大、超时、预算耗尽等）
+	FileTriaged                    // 仅经过初筛
 )
 
-// CurrentFileMsg 表示当前正在处理的文件
-type CurrentFileMsg string
+// FileResultMsg 表示一个文件已处理完毕
+type FileResultMsg struct {
+	Path   string
+	Status FileStatus
+	Icon   string // 已审查时为得分 emoji，其余为状态图标
+	Score  int    // 审查得分，仅 FileReviewed 有效
+	Issues int    // 问题数（已按 min_severity 过滤），仅 FileReviewed 有效
+	Note   string // 失败原因、跳过原因等说明
+	Reused bool   // 内容与其他文件完全相同，复用了其审查结果（去重）
+}
+
+// UsageMsg 是截至目前的累计 Token 消耗，每个文件完成时发送
+type UsageMsg struct {
+	Prompt     int64   // 输入 Token
+	Completion int64   // 输出 Token
+	Cost       float64 // 已产生的费用，仅 Priced 时有效
+	Priced     bool    // 是否配置了模型单价
+}
+
+// PausedMsg 表示 API 配额耗尽、审查暂停，Until 为预计恢复时间
+type PausedMsg struct {
+	Until time.Time
+}
+
+// ResumedMsg 表示冷却结束、审查恢复
+type ResumedMsg struct{}
+
+// RetryMsg 表示一个文件因暂时性错误失败，已加入重试队列
+type RetryMsg struct{}
 
 // DoneMsg 表示审查完成的消息
 type DoneMsg struct {
@@ -40,31 +77,35 @@ type Model struct {
 	progress    progress.Model
 	total       int
 	completed   int
-	currentFile string
+	recent      []FileResultMsg // 最近完成的文件，最多 RecentFilesShown 个
+	failures    []FileResultMsg // 审查失败的文件（API 错误、解析失败、超时等）
+	reviewed    []FileResultMsg // 已审查的文件，用于完成界面的得分分布
+	hideErrors  bool            // 错误面板是否已折叠（按 e 切换）
+	usage       UsageMsg        // 累计 Token 消耗与费用
+	counters    runCounters     // 重试、失败、跳过与复用的文件数
+	control     Control         // 暂停与并发调整，为空时不响应对应按键
 	done        bool
 	reportPath  string
 	duration    time.Duration
 	issuesCount int
+	width       int       // 终端宽度，0 表示尚未收到窗口大小
+	confirming  bool      // 用户按下 q 或 Ctrl+C，正在等待确认中断
+	interrupted bool      // 用户确认中断审查
+	showHelp    bool      // 正在显示按键说明（按 ? 打开，任意键关闭）
+	offerBrowse bool      // 完成后提示按 Enter 浏览结果，而不是直接退出
+	browse      bool      // 用户选择浏览结果
+	pausedUntil time.Time // 配额耗尽暂停的恢复时间，零值表示未暂停
 }
 
-// NewModel 创建一个新的 TUI 模型
-func NewModel(totalFiles int) Model {
-	// 初始化进度条
-	p := progress.New(
-		progress.WithDefaultGradient(),
-		progress.WithWidth(ProgressBarWidth),
-		progress.WithoutPercentage(),
-	)
-
-	// 初始化 Spinner
-	s := spinner.New()
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("63"))
-	s.Spinner = spinner.Dot
-
+// NewModel 创建一个新的 TUI 模型，control 为空时不支持暂停与调整并发
+// offerBrowse 为 true 时审查完成后不立即退出，提示用户进入结果浏览界面
+func NewModel(totalFiles int, control Control, offerBrowse bool) Model {
 	return Model{
-		spinner:  s,
-		progress: p,
-		total:    totalFiles,
+		spinner:     newSpinner(),
+		progress:    newProgress(ProgressBarWidth),
+		total:       totalFiles,
+		control:     control,
+		offerBrowse: offerBrowse,
 	}
 }
 
@@ -77,8 +118,46 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// 任意按键退出
-		return m, tea.Quit
+		// 审查已完成：Enter 进入结果浏览界面，其余按键退出
+		if m.done {
+			m.browse = msg.Type == tea.KeyEnter
+			m.offerBrowse = false
+			return m, tea.Quit
+		}
+		// 按键说明打开时任意键关闭；Ctrl+C 关闭后继续进入中断确认
+		if m.showHelp {
+			m.showHelp = false
+			if msg.Type != tea.KeyCtrlC {
+				return m, nil
+			}
+		}
+		// 中断需要确认：确认后停止引擎并生成部分报告
+		if m.confirming {
+			m.confirming = false
+			if confirmQuit(msg) {
+				m.interrupted = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		switch {
+		case isQuitKey(msg):
+			m.confirming = true
+		case isHelpKey(msg):
+			m.showHelp = true
+		case handleControlKey(m.control, msg.String()):
+		case msg.String() == "e" && len(m.failures) > 0:
+			m.hideErrors = !m.hideErrors
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		if msg.Width <= 0 {
+			return m, nil
+		}
+		m.width = msg.Width
+		m.progress.Width = progressWidth(msg.Width, ProgressBarWidth)
+		return m, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -92,8 +171,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, cmd
 
-	case CurrentFileMsg:
-		m.currentFile = string(msg)
+	case FileResultMsg:
+		m.recent = appendRecent(m.recent, msg)
+		m.counters.add(msg)
+		switch msg.Status {
+		case FileReviewed:
+			m.reviewed = append(m.reviewed, msg)
+		case FileFailed:
+			m.failures = append(m.failures, msg)
+		}
 		m.completed++
 		// 计算进度百分比（防止除零）
 		if m.total > 0 {
@@ -102,11 +188,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case UsageMsg:
+		m.usage = msg
+		return m, nil
+
+	case RetryMsg:
+		m.counters.retries++
+		return m, nil
+
+	case PausedMsg:
+		m.pausedUntil = msg.Until
+		return m, nil
+
+	case ResumedMsg:
+		m.pausedUntil = time.Time{}
+		return m, nil
+
 	case DoneMsg:
 		m.done = true
 		m.duration = msg.Duration
 		m.reportPath = msg.ReportPath
 		m.issuesCount = msg.IssuesCount
+		m.confirming = false
+		if m.offerBrowse {
+			return m, nil
+		}
 		return m, tea.Quit
 
 	default:
@@ -114,31 +220,157 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// Browse 返回用户是否在审查完成后选择浏览结果
+func (m Model) Browse() bool {
+	return m.browse
+}
+
+// Interrupted 返回用户是否在审查完成前中断
+func (m Model) Interrupted() bool {
+	return m.interrupted && !m.done
+}
+
 // View 实现 tea.Model 接口，渲染界面
 func (m Model) View() string {
 	// 完成状态
+	wrap := lipgloss.NewStyle().Width(viewWidth(m.width))
 	if m.done {
-		return doneStyle.Render(fmt.Sprintf(
+		text := fmt.Sprintf(
 			"✨ 审查完成！耗时 %s\n📋 发现问题: %d 个\n📄 报告路径: %s\n",
 			m.duration.Round(time.Millisecond),
 			m.issuesCount,
 			m.reportPath,
-		))
+		)
+		// 先展示得分概览，用户无需打开报告即可了解结果
+		if summary := scoreSummary(m.reviewed, viewWidth(m.width)-4); summary != "" {
+			text += "\n" + summary + "\n"
+		}
+		if m.offerBrowse {
+			text += fileNoteStyle.Render("按 Enter 浏览审查结果，其他键退出") + "\n"
+		}
+		return wrap.Render(doneStyle.Render(text))
+	}
+
+	if m.showHelp {
+		return helpView("进度界面按键", progressKeys, m.width)
 	}
 
 	// 处理中状态
-	spin := m.spinner.View() + " "
-	prog := m.progress.View()
+	blocks := []string{
+		fmt.Sprintf("\n %s 正在审查...\n", m.spinner.View()),
+		m.progress.View(),
+		fmt.Sprintf("已处理: %d/%d 个文件  %s\n", m.completed, m.total, m.counters.line()),
+	}
+	if line := usageLine(m.usage, m.completed, m.total); line != "" {
+		blocks = append(blocks, line)
+	}
+	if list := recentList(m.recent, m.completed); list != "" {
+		blocks = append(blocks, list)
+	}
+	if panel := errorPanel(m.failures, m.hideErrors); panel != "" {
+		blocks = append(blocks, panel)
+	}
+	if line := pausedLine(m.pausedUntil); line != "" {
+		blocks = append(blocks, line)
+	}
+	if m.confirming {
+		blocks = append(blocks, confirmLine())
+	} else if line := controlLine(m.control); line != "" {
+		blocks = append(blocks, line)
+	}
+	blocks = append(blocks, fileNoteStyle.Render("按 ? 查看按键说明 · q 或 Ctrl+C 中断并生成部分报告"))
 
-	fileName := currentFileStyle.Render(m.currentFile)
-	info := lipgloss.NewStyle().MaxWidth(DefaultTerminalWidth).Render("正在分析: " + fileName)
+	// 按终端宽度折行，避免过长的路径在窄终端上破坏布局
+	return wrap.Render(strings.Join(blocks, "\n"))
+}
 
-	// 构建显示块
-	blocks := []string{
-		fmt.Sprintf("\n %s%s\n", spin, info),
-		prog,
-		fmt.Sprintf("已处理: %d/%d 个文件\n", m.completed, m.total),
+// viewWidth 返回渲染使用的宽度，尚未收到窗口大小时使用 DefaultTerminalWidth
+func viewWidth(width int) int {
+	if width <= 0 {
+		return DefaultTerminalWidth
+	}
+	return width
+}
+
+// progressWidth 根据终端宽度计算进度条宽度：不超过 preferred，并为两侧留出边距
+func progressWidth(termWidth, preferred int) int {
+	return max(min(preferred, termWidth-4), minProgressBarWidth)
+}
+
+// appendRecent 追加最近完成的文件，超过 RecentFilesShown 时丢弃最早的
+func appendRecent(recent []FileResultMsg, msg FileResultMsg) []FileResultMsg {
+	recent = append(recent, msg)
+	if len(recent) > RecentFilesShown {
+		recent = recent[len(recent)-RecentFilesShown:]
+	}
+	return recent
+}
+
+// recentList 渲染最近完成的文件列表（最新的在最下方），completed 为已完成的文件总数
+func recentList(recent []FileResultMsg, completed int) string {
+	if len(recent) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if hidden := completed - len(recent); hidden > 0 {
+		b.WriteString(fileNoteStyle.Render(fmt.Sprintf("  … 更早完成的 %d 个文件", hidden)) + "\n")
+	}
+	for _, r := range recent {
+		b.WriteString("  " + r.line() + "\n")
+	}
+	return b.String()
+}
+
+// line 渲染单个文件的结果行
+func (r FileResultMsg) line() string {
+	if r.Status == FileReviewed {
+		return fmt.Sprintf("%s %3d 分 %3d 个问题  %s", r.Icon, r.Score, r.Issues, currentFileStyle.Render(r.Path))
 	}
+	return fmt.Sprintf("%s %s  %s", r.Icon, currentFileStyle.Render(r.Path), fileNoteStyle.Render(r.Note))
+}
 
-	return strings.Join(blocks, "\n")
+// errorPanel 渲染失败文件面板：展开时列出最近 FailuresShown 个失败文件及原因，折叠时只显示数量
+func errorPanel(failures []FileResultMsg, collapsed bool) string {
+	if len(failures) == 0 {
+		return ""
+	}
+	if collapsed {
+		return errorStyle.Render(fmt.Sprintf("❌ 失败 %d 个文件", len(failures))) + fileNoteStyle.Render(" · 按 e 展开") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(errorStyle.Render(fmt.Sprintf("❌ 失败 %d 个文件", len(failures))) + fileNoteStyle.Render(" · 按 e 收起") + "\n")
+	shown := failures[max(len(failures)-FailuresShown, 0):]
+	if hidden := len(failures) - len(shown); hidden > 0 {
+		b.WriteString(fileNoteStyle.Render(fmt.Sprintf("  … 更早失败的 %d 个文件", hidden)) + "\n")
+	}
+	for _, f := range shown {
+		b.WriteString(fmt.Sprintf("  %s  %s\n", currentFileStyle.Render(f.Path), errorStyle.Render(f.Note)))
+	}
+	return b.String()
+}
+
+// usageLine 渲染累计 Token 与费用；按已完成文件的平均费用推算全部文件的预计费用
+func usageLine(u UsageMsg, completed, total int) string {
+	if u.Prompt+u.Completion == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("🪙 Token: 输入 %d / 输出 %d", u.Prompt, u.Completion)
+	if u.Priced {
+		text += fmt.Sprintf("，费用 ~%.4f", u.Cost)
+		if completed > 0 && completed < total {
+			text += fmt.Sprintf("（预计全部 ~%.4f）", u.Cost/float64(completed)*float64(total))
+		}
+	}
+	return fileNoteStyle.Render(text) + "\n"
+}
+
+// pausedLine 渲染配额暂停的倒计时，未暂停时返回空字符串
+func pausedLine(until time.Time) string {
+	if until.IsZero() {
+		return ""
+	}
+	remaining := max(time.Until(until), 0).Round(time.Second)
+	return pausedStyle.Render(fmt.Sprintf("⏸  API 配额耗尽，%s 后自动恢复", remaining))
 }
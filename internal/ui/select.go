@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// 终端高度未知时的默认列表行数
+const defaultSelectRows = 20
+
+// selectRow 是选择列表中的一行（目录或文件）
+type selectRow struct {
+	name      string // 显示名称（目录带 / 后缀）
+	depth     int
+	dir       bool
+	files     []int // 该行覆盖的文件下标，文件行只包含自身
+	collapsed bool  // 目录是否折叠
+}
+
+// SelectModel 是审查前的文件选择界面，按目录分组展示扫描到的文件，默认全部选中
+type SelectModel struct {
+	files    []string
+	selected []bool
+	rows     []selectRow
+
+	cursor    int // 光标在可见行中的位置
+	offset    int // 可见行的滚动偏移
+	height    int // 终端高度，0 表示未知
+	warning   string
+	confirmed bool
+	showHelp  bool // 正在显示按键说明（按 ? 打开，任意键关闭）
+}
+
+// NewSelectModel 创建文件选择模型，文件按相对 root 的路径组织为目录树
+func NewSelectModel(root string, files []string) SelectModel {
+	rels := make([]string, len(files))
+	for i, file := range files {
+		rel, err := filepath.Rel(root, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = file
+		}
+		rels[i] = filepath.ToSlash(rel)
+	}
+
+	// 按相对路径排序，同一目录下的文件在排序后必然相邻
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return strings.Compare(rels[a], rels[b]) })
+
+	m := SelectModel{files: files, selected: make([]bool, len(files))}
+	var stack []string // 当前所在的目录路径
+	var stackRows []int
+	for _, i := range order {
+		m.selected[i] = true
+		parts := strings.Split(rels[i], "/")
+		dirs, name := parts[:len(parts)-1], parts[len(parts)-1]
+
+		// 退出与当前文件不同的目录，再依次进入新目录
+		common := 0
+		for common < len(stack) && common < len(dirs) && stack[common] == dirs[common] {
+			common++
+		}
+		stack, stackRows = stack[:common], stackRows[:common]
+		for _, dir := range dirs[common:] {
+			stackRows = append(stackRows, len(m.rows))
+			m.rows = append(m.rows, selectRow{name: dir + "/", depth: len(stack), dir: true})
+			stack = append(stack, dir)
+		}
+
+		for _, row := range stackRows {
+			m.rows[row].files = append(m.rows[row].files, i)
+		}
+		m.rows = append(m.rows, selectRow{name: name, depth: len(stack), files: []int{i}})
+	}
+	return m
+}
+
+// Init 实现 tea.Model 接口
+func (m SelectModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update 实现 tea.Model 接口，处理按键并更新选择状态
+func (m SelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.scroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		m.warning = ""
+		// 按键说明打开时任意键关闭
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		visible := m.visibleRows()
+
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "?":
+			m.showHelp = true
+		case "enter":
+			if m.count() == 0 {
+				m.warning = "请至少选择一个文件"
+				return m, nil
+			}
+			m.confirmed = true
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			m.cursor = min(m.cursor+1, len(visible)-1)
+		case "pgup":
+			m.cursor = max(m.cursor-m.pageSize(), 0)
+		case "pgdown":
+			m.cursor = min(m.cursor+m.pageSize(), len(visible)-1)
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = len(visible) - 1
+		case " ", "x":
+			if len(visible) > 0 {
+				m.toggle(m.rows[visible[m.cursor]].files)
+			}
+		case "a":
+			all := make([]int, len(m.files))
+			for i := range all {
+				all[i] = i
+			}
+			m.toggle(all)
+		case "left", "h":
+			m.collapse(visible)
+		case "right", "l":
+			if len(visible) > 0 && m.rows[visible[m.cursor]].dir {
+				m.rows[visible[m.cursor]].collapsed = false
+			}
+		}
+		m.scroll()
+		return m, nil
+
+	default:
+		return m, nil
+	}
+}
+
+// toggle 切换一组文件的选中状态：全部已选中时取消选择，否则全部选中
+func (m *SelectModel) toggle(files []int) {
+	value := slices.ContainsFunc(files, func(i int) bool { return !m.selected[i] })
+	for _, i := range files {
+		m.selected[i] = value
+	}
+}
+
+// collapse 折叠光标所在目录；光标在文件或已折叠目录上时跳到上级目录
+func (m *SelectModel) collapse(visible []int) {
+	if len(visible) == 0 {
+		return
+	}
+	row := &m.rows[visible[m.cursor]]
+	if row.dir && !row.collapsed {
+		row.collapsed = true
+		return
+	}
+	for c := m.cursor - 1; c >= 0; c-- {
+		if m.rows[visible[c]].depth < row.depth {
+			m.cursor = c
+			return
+		}
+	}
+}
+
+// visibleRows 返回未被折叠目录隐藏的行下标
+func (m SelectModel) visibleRows() []int {
+	visible := make([]int, 0, len(m.rows))
+	hiddenBelow := -1 // 折叠目录的深度，深度更大的后续行被隐藏
+	for i, row := range m.rows {
+		if hiddenBelow >= 0 {
+			if row.depth > hiddenBelow {
+				continue
+			}
+			hiddenBelow = -1
+		}
+		visible = append(visible, i)
+		if row.dir && row.collapsed {
+			hiddenBelow = row.depth
+		}
+	}
+	return visible
+}
+
+// pageSize 返回列表区域可显示的行数（扣除标题、提示与帮助行）
+func (m SelectModel) pageSize() int {
+	if m.height <= 0 {
+		return defaultSelectRows
+	}
+	return max(m.height-6, 1)
+}
+
+// scroll 调整滚动偏移，保证光标可见
+func (m *SelectModel) scroll() {
+	page := m.pageSize()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+page {
+		m.offset = m.cursor - page + 1
+	}
+}
+
+// count 返回已选中的文件数
+func (m SelectModel) count() int {
+	n := 0
+	for _, ok := range m.selected {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// Confirmed 返回用户是否确认了选择（按 q/Esc/Ctrl+C 取消时为 false）
+func (m SelectModel) Confirmed() bool {
+	return m.confirmed
+}
+
+// Selected 返回选中的文件，保持传入时的顺序
+func (m SelectModel) Selected() []string {
+	files := make([]string, 0, m.count())
+	for i, file := range m.files {
+		if m.selected[i] {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// View 实现 tea.Model 接口，渲染界面
+func (m SelectModel) View() string {
+	if m.confirmed {
+		return ""
+	}
+
+	if m.showHelp {
+		return helpView("文件选择界面按键", selectKeys, 0)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n 选择要审查的文件 (已选 %d/%d)\n\n", m.count(), len(m.files)))
+
+	visible := m.visibleRows()
+	end := min(m.offset+m.pageSize(), len(visible))
+	for c := m.offset; c < end; c++ {
+		b.WriteString(m.renderRow(m.rows[visible[c]], c == m.cursor) + "\n")
+	}
+
+	if m.warning != "" {
+		b.WriteString(pausedStyle.Render(" ⚠️  "+m.warning) + "\n")
+	}
+	b.WriteString(taskMutedStyle.Render("\n ↑/↓ 移动  空格 选择/取消  a 全选  ←/→ 折叠/展开  Enter 开始审查  ? 帮助  q 取消") + "\n")
+	return b.String()
+}
+
+// renderRow 渲染单行：复选框、缩进与名称，目录显示部分选中状态
+func (m SelectModel) renderRow(row selectRow, current bool) string {
+	n := 0
+	for _, i := range row.files {
+		if m.selected[i] {
+			n++
+		}
+	}
+	box := "[-]"
+	switch n {
+	case 0:
+		box = "[ ]"
+	case len(row.files):
+		box = "[x]"
+	}
+
+	name := row.name
+	if row.dir {
+		arrow := "▾ "
+		if row.collapsed {
+			arrow = "▸ "
+		}
+		name = selectDirStyle.Render(arrow+name) + taskMutedStyle.Render(fmt.Sprintf(" %d/%d", n, len(row.files)))
+	}
+
+	cursor := "  "
+	if current {
+		cursor = selectCursorStyle.Render("› ")
+	}
+	return cursor + box + " " + strings.Repeat("  ", row.depth) + name
+}
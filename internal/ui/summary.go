@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	LowestShown       = 5  // 完成界面中列出的得分最低文件数
+	histogramBarWidth = 20 // 得分分布柱状图的最大长度
+)
+
+// scoreBuckets 是得分分布的分组（从低到高），与已审查文件的得分 emoji 一致
+var scoreBuckets = []string{"🔴", "🟡", "🟢"}
+
+// scoreSummary 渲染完成界面的得分分布柱状图与得分最低的文件，没有已审查的文件时返回空字符串
+func scoreSummary(reviewed []FileResultMsg, width int) string {
+	if len(reviewed) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int, len(scoreBuckets))
+	for _, r := range reviewed {
+		counts[r.Icon]++
+	}
+	most := 0
+	for _, icon := range scoreBuckets {
+		most = max(most, counts[icon])
+	}
+
+	var b strings.Builder
+	b.WriteString("📊 得分分布\n")
+	barWidth := max(min(histogramBarWidth, width-12), 1)
+	for _, icon := range scoreBuckets {
+		n := counts[icon]
+		bar := ""
+		if n > 0 {
+			// 向上取整，保证数量不为 0 的分组至少有一格
+			bar = strings.Repeat("█", (n*barWidth+most-1)/most) + " "
+		}
+		b.WriteString(fmt.Sprintf("  %s %s%d\n", icon, bar, n))
+	}
+
+	lowest := slices.Clone(reviewed)
+	slices.SortStableFunc(lowest, func(a, b FileResultMsg) int {
+		return cmp.Compare(a.Score, b.Score)
+	})
+	b.WriteString("\n📉 得分最低的文件\n")
+	for _, r := range lowest[:min(LowestShown, len(lowest))] {
+		b.WriteString("  " + r.line() + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme 是界面使用的配色，颜色为 ANSI 256 色号（如 "211"）或十六进制（如 "#ff8800"）
+type Theme struct {
+	Accent        string // 文件路径、光标等强调内容
+	Muted         string // 说明文字与按键提示
+	Warning       string // 暂停、中断确认等提示
+	Error         string // 失败文件与错误信息
+	Highlight     string // Spinner 与目录名
+	GradientStart string // 进度条渐变起始色
+	GradientEnd   string // 进度条渐变结束色
+}
+
+// 内置主题：dark 适合深色背景（默认），light 适合浅色背景，颜色更深以保证对比度
+var themes = map[string]Theme{
+	"dark": {
+		Accent:        "211",
+		Muted:         "241",
+		Warning:       "214",
+		Error:         "203",
+		Highlight:     "63",
+		GradientStart: "#5A56E0",
+		GradientEnd:   "#EE6FF8",
+	},
+	"light": {
+		Accent:        "162",
+		Muted:         "240",
+		Warning:       "130",
+		Error:         "160",
+		Highlight:     "25",
+		GradientStart: "#3B37B8",
+		GradientEnd:   "#A3149A",
+	},
+}
+
+// DefaultTheme 是未配置 theme 时使用的主题
+const DefaultTheme = "dark"
+
+// colorRegex 匹配 ANSI 256 色号或十六进制颜色
+var colorRegex = regexp.MustCompile(`^(\d{1,3}|#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6})$`)
+
+// 界面样式，由 ApplyTheme 按主题生成
+var (
+	currentFileStyle  lipgloss.Style
+	fileNoteStyle     lipgloss.Style
+	pausedStyle       lipgloss.Style
+	errorStyle        lipgloss.Style
+	spinnerStyle      lipgloss.Style
+	taskErrorStyle    lipgloss.Style
+	taskMutedStyle    lipgloss.Style
+	selectCursorStyle lipgloss.Style
+	selectDirStyle    lipgloss.Style
+	gradient          [2]string
+)
+
+func init() {
+	ApplyTheme(themes[DefaultTheme])
+}
+
+// ThemeNames 返回内置主题名称
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ThemeColorKeys 返回 theme_colors 中可以覆盖的颜色名称
+func ThemeColorKeys() []string {
+	return []string{"accent", "muted", "warning", "error", "highlight", "gradient_start", "gradient_end"}
+}
+
+// ValidColor 判断颜色写法是否有效（ANSI 256 色号或十六进制）
+func ValidColor(color string) bool {
+	return colorRegex.MatchString(color)
+}
+
+// LoadTheme 返回名为 name 的内置主题（空字符串为默认主题），并用 colors 覆盖其中的颜色
+func LoadTheme(name string, colors map[string]string) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	t, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("未知主题 %q，可选: %s", name, strings.Join(ThemeNames(), ", "))
+	}
+
+	for key, color := range colors {
+		if !ValidColor(color) {
+			return Theme{}, fmt.Errorf("theme_colors.%s 的颜色 %q 无效 (应为 ANSI 色号如 211，或十六进制如 #ff8800)", key, color)
+		}
+		switch key {
+		case "accent":
+			t.Accent = color
+		case "muted":
+			t.Muted = color
+		case "warning":
+			t.Warning = color
+		case "error":
+			t.Error = color
+		case "highlight":
+			t.Highlight = color
+		case "gradient_start":
+			t.GradientStart = color
+		case "gradient_end":
+			t.GradientEnd = color
+		default:
+			return Theme{}, fmt.Errorf("theme_colors 包含未知颜色 %s，可选: %s", key, strings.Join(ThemeColorKeys(), ", "))
+		}
+	}
+	return t, nil
+}
+
+// ApplyTheme 按主题重新生成界面样式，需在创建界面模型之前调用
+func ApplyTheme(t Theme) {
+	color := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+	}
+	currentFileStyle = color(t.Accent)
+	fileNoteStyle = color(t.Muted)
+	pausedStyle = color(t.Warning)
+	errorStyle = color(t.Error)
+	spinnerStyle = color(t.Highlight)
+	taskErrorStyle = color(t.Error)
+	taskMutedStyle = color(t.Muted)
+	selectCursorStyle = color(t.Accent).Bold(true)
+	selectDirStyle = color(t.Highlight)
+	gradient = [2]string{t.GradientStart, t.GradientEnd}
+}
+
+// DisableColor 关闭所有颜色输出（NO_COLOR 环境变量或 --no-color），保留粗体等文字样式
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// newProgress 按当前主题创建进度条；关闭颜色时进度条同样不输出颜色
+func newProgress(width int) progress.Model {
+	return progress.New(
+		progress.WithGradient(gradient[0], gradient[1]),
+		progress.WithColorProfile(lipgloss.ColorProfile()),
+		progress.WithWidth(width),
+		progress.WithoutPercentage(),
+	)
+}
+
+// newSpinner 按当前主题创建 Spinner
+func newSpinner() spinner.Model {
+	s := spinner.New()
+	s.Style = spinnerStyle
+	s.Spinner = spinner.Dot
+	return s
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// maxErrorDetail 是错误响应无法解析时保留的最大字符数
+const maxErrorDetail = 200
+
+// ErrorDetail 提取 HTTP 错误响应中的错误信息：message 字段（GitHub、Confluence 等），
+// 或 errorMessages 与 errors 字段（Jira）；无法解析时返回截断的原文
+func ErrorDetail(data []byte) string {
+	var resp struct {
+		Message       string            `json:"message"`
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err == nil {
+		msgs := append([]string{}, resp.ErrorMessages...)
+		// 按字段名排序，保证同一响应的错误信息每次相同
+		for _, field := range slices.Sorted(maps.Keys(resp.Errors)) {
+			msgs = append(msgs, field+": "+resp.Errors[field])
+		}
+		if resp.Message != "" {
+			msgs = append([]string{resp.Message}, msgs...)
+		}
+		if len(msgs) > 0 {
+			return strings.Join(msgs, "; ")
+		}
+	}
+	return Truncate(strings.TrimSpace(string(data)), maxErrorDetail)
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorDetail(t *testing.T) {
+	long := strings.Repeat("错", maxErrorDetail+10)
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"message 字段", `{"message":"Bad credentials"}`, "Bad credentials"},
+		{"Jira errorMessages", `{"errorMessages":["项目不存在","没有权限"]}`, "项目不存在; 没有权限"},
+		{"Jira errors 按字段名排序", `{"errors":{"summary":"太长","issuetype":"无效","labels":"含空格"}}`,
+			"issuetype: 无效; labels: 含空格; summary: 太长"},
+		{"errorMessages 与 errors", `{"errorMessages":["失败"],"errors":{"b":"2","a":"1"}}`, "失败; a: 1; b: 2"},
+		{"message 在前", `{"message":"无效请求","errorMessages":["缺少字段"]}`, "无效请求; 缺少字段"},
+		{"没有错误字段的 JSON", `{"status":500}`, `{"status":500}`},
+		{"纯文本", "  Internal Server Error\n", "Internal Server Error"},
+		{"过长的原文截断", long, strings.Repeat("错", maxErrorDetail) + "…"},
+		{"空响应", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorDetail([]byte(tt.data)); got != tt.want {
+				t.Errorf("ErrorDetail(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorDetailStable(t *testing.T) {
+	data := []byte(`{"errors":{"e":"5","d":"4","c":"3","b":"2","a":"1"}}`)
+	want := ErrorDetail(data)
+	for range 20 {
+		if got := ErrorDetail(data); got != want {
+			t.Fatalf("ErrorDetail 结果不稳定: %q != %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package utils 提供各模块共用的工具函数
+package utils
+
+// Truncate 将文本截断到 n 个字符（按 rune 计算，不会截断多字节字符），截断时以省略号结尾；n 小于 0 时按 0 处理
+func Truncate(s string, n int) string {
+	n = max(n, 0)
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
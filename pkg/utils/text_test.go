@@ -0,0 +1,30 @@
+package utils
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"短于上限", "hello", 10, "hello"},
+		{"等于上限", "hello", 5, "hello"},
+		{"超过上限", "hello world", 5, "hello…"},
+		{"多字节字符", "代码审查工具", 4, "代码审查…"},
+		{"多字节字符未超过上限", "代码审查", 4, "代码审查"},
+		{"混合字符", "a代b码c", 3, "a代b…"},
+		{"上限为 0", "hello", 0, "…"},
+		{"上限为负数", "hello", -1, "…"},
+		{"空字符串", "", 0, ""},
+		{"空字符串上限为负数", "", -5, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.s, tt.n); got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
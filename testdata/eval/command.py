@@ -0,0 +1,8 @@
+import os
+
+
+def archive(directory):
+    """将目录打包为 tar.gz，返回归档文件名。"""
+    name = directory.rstrip("/") + ".tar.gz"
+    os.system("tar czf " + name + " " + directory)
+    return name
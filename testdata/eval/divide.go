@@ -0,0 +1,10 @@
+package stats
+
+// Average 返回整数切片的平均值
+func Average(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / len(values)
+}
@@ -0,0 +1,14 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// FindUser 按用户名查询用户 ID
+func FindUser(db *sql.DB, name string) (int, error) {
+	var id int
+	query := fmt.Sprintf("SELECT id FROM users WHERE name = '%s'", name)
+	err := db.QueryRow(query).Scan(&id)
+	return id, err
+}